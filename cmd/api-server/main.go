@@ -1,45 +1,196 @@
 package main
 
 import (
+	"cmp"
+	"crypto/subtle"
+	"encoding/csv"
+	"errors"
 	"fmt"
 	"log"
+	"math"
 	"net/http"
 	"os"
+	"slices"
+	"strconv"
+	"strings"
 
+	"stockgame/internal/card"
 	"stockgame/internal/dataaccess"
 	"stockgame/internal/database"
+	"stockgame/internal/indicators"
 	"stockgame/internal/logic"
 	"stockgame/internal/model"
+	"stockgame/internal/room"
 	"stockgame/internal/service"
 	"stockgame/internal/util"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/joho/godotenv"
+	"golang.org/x/net/websocket"
 )
 
 type SolutionHandler struct {
-	StockService service.StockService
-	ScoringLogic logic.ScoringLogic
+	StockService          service.StockService
+	ScoringLogic          logic.ScoringLogic
+	IndicatorLogic        indicators.IndicatorLogic
+	CandleScoringLogic    logic.CandleScoringLogic
+	DirectionScoringLogic logic.DirectionScoringLogic
+	BandScoringLogic      logic.BandScoringLogic
+	BotLogic              logic.BotLogic
+	DifficultyLogic       logic.DifficultyLogic
+	RoundService          service.RoundService
+	AnalyticsService      service.AnalyticsService
+	CardLogic             card.CardLogic
+	RoomHub               room.Hub
+	TournamentService     service.TournamentService
+	TradingService        service.TradingService
+	TickerService         service.TickerService
+	ScenarioService       service.ScenarioService
+	ServedRoundService    service.ServedRoundService
+	BasketService         service.BasketService
+	ProgressionService    service.ProgressionService
+	CardCache             *card.CardCache         // Nil renders the cards at every request
+	BollingerConfig       model.BollingerConfig   // Zero fields use model.DefaultBollingerConfig
+	RoundTiming           model.RoundTimingConfig // Zero value does not time the rounds
+	AdminToken            string                  // Empty disables the admin endpoints
 }
 
-func (h *SolutionHandler) getStocks(c *gin.Context) {
+// Header identifying the player, generated and kept by the browser
+const playerIdHeader = "X-Player-ID"
+
+const maxPlayerIdLength = 64
 
-	stock := h.StockService.GetRandomStockWithRandomDayRange(model.Number_initial_stock_shown)
-	c.IndentedJSON(http.StatusOK, stock)
+// getPlayerId returns the player id sent by the client, empty for an anonymous player
+func getPlayerId(c *gin.Context) string {
+	return validPlayerId(c.GetHeader(playerIdHeader))
 }
 
-func (h *SolutionHandler) postSolution(c *gin.Context) {
-	// Read the body of the request
-	// Bind JSON directly to a struct
-	userSolution := model.UserSolutionRequest{}
-	if err := c.BindJSON(&userSolution); err != nil {
-		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON"})
+// validPlayerId returns the player id without spaces, empty when it is too long
+func validPlayerId(playerId string) string {
+	playerId = strings.TrimSpace(playerId)
+	if len(playerId) > maxPlayerIdLength {
+		return ""
+	}
+	return playerId
+}
+
+func (h *SolutionHandler) getStocks(c *gin.Context) {
+	difficulty := strings.ToLower(c.Query("difficulty"))
+	if difficulty != "" && !logic.IsValidDifficulty(difficulty) {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid difficulty %q, must be easy, medium or hard", difficulty)})
 		return
 	}
-	// Extract values from the struct
-	symbolUUID := userSolution.SymbolUUID
-	afterDate := userSolution.AfterDate
-	dayPrice := userSolution.DayPrice
+	var window model.StockWindow
+	metadata := model.RoundMetadata{}
+	if id := c.Query("scenario"); id != "" {
+		scenario, err := h.ScenarioService.GetScenario(id)
+		if err != nil {
+			c.IndentedJSON(scenarioErrorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+		window = h.StockService.GetScenarioWindow(scenario, model.Number_initial_stock_shown, difficulty)
+		metadata.Scenario = scenario.Name
+	} else {
+		window = h.StockService.GetRandomWindow(model.Number_initial_stock_shown, difficulty)
+	}
+	metadata.DifficultyRating = window.Rating
+	// The solutions are only accepted for a round served, so the window is not sent without it
+	if len(window.Stocks) > 0 {
+		served, err := h.ServedRoundService.ServeRound(getPlayerId(c), window.Stocks)
+		if err != nil {
+			fmt.Println("getStocks Error saving served round: ", err)
+			c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Cannot serve the round"})
+			return
+		}
+		metadata.Id = served.Id
+		if len(h.RoundTiming.Limits) > 0 {
+			metadata.TimeLimits = h.RoundTiming.Limits
+		}
+	}
+	c.IndentedJSON(http.StatusOK, model.StocksResponse{
+		Round:  metadata,
+		Stocks: window.Stocks,
+	})
+}
+
+func (h *SolutionHandler) bollingerConfig() model.BollingerConfig {
+	return h.BollingerConfig.WithDefaults()
+}
+
+// solutionData is the real prices around the date the player had to guess from
+type solutionData struct {
+	Info   model.StockInfo
+	Before []model.Stock // Chronological order, the last one is at the date the player guessed from
+	After  []model.Stock // The days the player had to guess
+}
+
+// Full returns the days before and after the date in chronological order
+func (d solutionData) Full() []model.Stock {
+	return append(slices.Clone(d.Before), d.After...)
+}
+
+// LastClose is the close at the date the player guessed from, zero when it is unknown
+func (d solutionData) LastClose() float64 {
+	if len(d.Before) == 0 {
+		return 0
+	}
+	return d.Before[len(d.Before)-1].Close
+}
+
+// loadServedRound solves the round served for the solution so only one solution in time is
+// accepted for it. Without a round id, the round is the last one served to the player for
+// the window. A window that was not served to the player is refused, so the future prices
+// of a window are only revealed for a round served. It writes the error response and
+// returns false when the solution is refused.
+func (h *SolutionHandler) loadServedRound(c *gin.Context, mode string, roundId string, symbolUUID string, afterDate string) (served *model.ServedRound, speedBonus int, ok bool) {
+	limit := h.RoundTiming.Limits[mode]
+	if roundId == "" && limit > 0 {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{
+			"error": fmt.Sprintf("The %s mode is timed, roundId is required", mode),
+			"code":  model.Error_round_required,
+		})
+		return nil, 0, false
+	}
+	round, err := h.ServedRoundService.SolveServedRound(roundId, getPlayerId(c), symbolUUID, afterDate, limit)
+	switch {
+	case roundId == "" && errors.Is(err, dataaccess.ErrServedRoundNotFound):
+		c.IndentedJSON(http.StatusNotFound, gin.H{
+			"error": "The window was not served to the player",
+			"code":  model.Error_round_not_served,
+		})
+		return nil, 0, false
+	case errors.Is(err, logic.ErrRoundExpired):
+		c.IndentedJSON(http.StatusUnprocessableEntity, gin.H{
+			"error": fmt.Sprintf("The solution was due %d seconds after the round was served", limit),
+			"code":  model.Error_round_expired,
+		})
+		return nil, 0, false
+	case errors.Is(err, service.ErrServedRoundSolved):
+		c.IndentedJSON(http.StatusConflict, gin.H{"error": err.Error(), "code": model.Error_round_solved})
+		return nil, 0, false
+	case err != nil:
+		c.IndentedJSON(servedRoundErrorStatus(err), gin.H{"error": err.Error()})
+		return nil, 0, false
+	}
+	// The deadline was checked when the round was solved
+	speedBonus, _ = logic.GetSpeedBonus(round.SolvedAt.Sub(round.ServedAt), limit, h.RoundTiming.SpeedBonus)
+	return &round, speedBonus, true
+}
+
+// adjustTotal removes the points of the hints requested for the served round from the total
+// of a score and adds the speed bonus. It returns the total and the points removed.
+func (h *SolutionHandler) adjustTotal(total int, served *model.ServedRound, speedBonus int) (adjusted int, hintPenalty int) {
+	adjusted = total
+	if len(served.Hints) > 0 {
+		adjusted, hintPenalty = h.ScoringLogic.ApplyHintPenalty(total, served.Hints)
+	}
+	return adjusted + speedBonus, hintPenalty
+}
+
+// loadSolutionData fetches the stock information and the prices around the date.
+// It writes the error response and returns false when the solution cannot be scored.
+func (h *SolutionHandler) loadSolutionData(c *gin.Context, symbolUUID string, afterDate string) (data solutionData, ok bool) {
 	if symbolUUID == "" || afterDate == "" {
 		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "symbolUUID and afterDate are required query parameters"})
 		return
@@ -50,21 +201,1043 @@ func (h *SolutionHandler) postSolution(c *gin.Context) {
 		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Cannot find the stock information"})
 		return
 	}
-	realStocksBeforeDate := h.StockService.GetStocksBeforeEqualDate(real.Symbol, afterDate)
-	realStocksAfterDate := h.StockService.GetStocksAfterDate(real.Symbol, afterDate)
-	fullList := append(realStocksBeforeDate, realStocksAfterDate...) // To calculuate Bollinger Bands we need the price before and after the date
+	data.Info = real
+	// The stocks before the date are sorted from the most recent, the bands and indicators need them in chronological order
+	data.Before = slices.Clone(h.StockService.GetStocksBeforeEqualDate(real.Symbol, afterDate))
+	slices.Reverse(data.Before)
+	data.After = h.StockService.GetStocksAfterDate(real.Symbol, afterDate)
+	return data, true
+}
+
+// validateDayPrices checks the guessed prices against the days to guess and the last close
+// shown. It writes the field errors and returns false when the guess cannot be scored.
+func validateDayPrices(c *gin.Context, prices []model.DayPrice, data solutionData) bool {
+	// A window without every day to guess cannot be scored, whatever the guess
+	if len(data.After) < model.User_stock_to_guess {
+		c.IndentedJSON(http.StatusUnprocessableEntity, gin.H{
+			"error": fmt.Sprintf("The window has %d days to guess instead of %d", len(data.After), model.User_stock_to_guess),
+		})
+		return false
+	}
+	return writeFieldErrors(c, "Invalid estimated prices", logic.ValidateDayPrices(prices, data.LastClose()))
+}
+
+// validateDayBands checks the optional bands like validateDayPrices checks the prices
+func validateDayBands(c *gin.Context, bands []model.DayBand, data solutionData) bool {
+	if len(bands) == 0 {
+		return true
+	}
+	return writeFieldErrors(c, "Invalid estimated bands", logic.ValidateDayBands(bands, data.LastClose()))
+}
+
+// writeFieldErrors writes the validation error response and returns false when a field is invalid
+func writeFieldErrors(c *gin.Context, message string, fields []model.FieldError) bool {
+	if len(fields) == 0 {
+		return true
+	}
+	c.IndentedJSON(http.StatusBadRequest, model.ValidationErrorResponse{
+		Error:  message,
+		Code:   model.Error_invalid_solution,
+		Fields: fields,
+	})
+	return false
+}
+
+func (h *SolutionHandler) postSolution(c *gin.Context) {
+	// Read the body of the request
+	// Bind JSON directly to a struct
+	userSolution := model.UserSolutionRequest{}
+	if err := c.BindJSON(&userSolution); err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON"})
+		return
+	}
+	data, ok := h.loadSolutionData(c, userSolution.SymbolUUID, userSolution.AfterDate)
+	if !ok || !validateDayPrices(c, userSolution.DayPrice, data) || !validateDayBands(c, userSolution.DayBands, data) {
+		return
+	}
+	fullList := data.Full() // To calculuate Bollinger Bands we need the price before and after the date
 	// Score
-	bollinger20Days := h.ScoringLogic.CalculateBollingerBands(fullList, 20)
-	score := h.ScoringLogic.GetScore(dayPrice, realStocksAfterDate, bollinger20Days)
+	bollingerBands := h.ScoringLogic.CalculateBollingerBandsWithConfig(fullList, h.bollingerConfig())
+	score := h.ScoringLogic.GetScore(userSolution.DayPrice, data.After, bollingerBands)
+	var overlays map[string]model.IndicatorOverlay
+	if len(userSolution.Indicators) > 0 {
+		var err error
+		overlays, err = h.IndicatorLogic.GetOverlays(fullList, userSolution.Indicators)
+		if err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid indicators: %v", err)})
+			return
+		}
+	}
+	// The served round is solved last so a solution refused for its content can be sent again
+	served, speedBonus, ok := h.loadServedRound(c, model.Mode_price, userSolution.RoundId, userSolution.SymbolUUID, userSolution.AfterDate)
+	if !ok {
+		return
+	}
+	// The bots have neither hints nor speed, they are compared with the score of the guess
+	guessScore := score
+	score.Total, score.HintPenalty = h.adjustTotal(score.Total, served, speedBonus)
+	score.SpeedBonus = speedBonus
+	// Naive forecasters scored with the same logic give some context to the score
+	bots := []model.BotScore{}
+	for _, forecast := range h.BotLogic.GetBotForecasts(data.Before, len(data.After)) {
+		bots = append(bots, model.BotScore{
+			Name:   forecast.Name,
+			Score:  h.ScoringLogic.GetScore(forecast.Prices, data.After, bollingerBands),
+			Prices: forecast.Prices,
+		})
+	}
 	solutionResponse := model.UserSolutionResponse{
-		Symbol: real.Symbol,
-		Name:   real.Name,
-		Score:  score,
-		Stocks: realStocksAfterDate,
-		BB20:   bollinger20Days,
+		Symbol:     data.Info.Symbol,
+		Name:       data.Info.Name,
+		Score:      score,
+		Stocks:     data.After,
+		BB20:       bollingerBands,
+		Bots:       bots,
+		BotSummary: logic.GetBotSummary(guessScore, bots),
+		Indicators: overlays,
+	}
+	if len(userSolution.DayBands) > 0 {
+		bandScore := h.BandScoringLogic.GetBandScore(userSolution.DayBands, data.After)
+		solutionResponse.BandScore = &bandScore
+		solutionResponse.Bands = userSolution.DayBands // Echo back the bands for the chart
+	}
+	// The difficulty is rated again from the real prices instead of trusting the client
+	rating := h.DifficultyLogic.RateDifficulty(
+		logic.StocksToPublic(data.Before, data.Info.SymbolUUID),
+		logic.StocksToPublic(data.After, data.Info.SymbolUUID),
+	)
+	solutionResponse.Difficulty = rating.Difficulty
+	// Only the solution that solved the served round is saved, so the ranking and the
+	// progression count a window once
+	round := newPriceRound(getPlayerId(c), userSolution.AfterDate, data, rating.Difficulty, score, userSolution.DayPrice)
+	round.Hints = served.Hints
+	round.ServedAt = &served.ServedAt
+	// A round that cannot be saved is still scored, only the ranking is missing
+	round, unlocked, err := h.RoundService.SaveRound(round)
+	if err != nil {
+		fmt.Println("postSolution Error saving round: ", err)
+	} else {
+		solutionResponse.RoundId = round.Id
+		percentiles := h.RoundService.GetPercentiles(round)
+		solutionResponse.Percentiles = &percentiles
+		solutionResponse.Achievements = unlocked
 	}
 	c.IndentedJSON(http.StatusOK, solutionResponse)
 }
+
+// newPriceRound is the round saved for a guess of the prices
+func newPriceRound(playerId string, afterDate string, data solutionData, difficulty string, score model.UserScoreResponse, dayPrice []model.DayPrice) model.Round {
+	round := model.Round{
+		PlayerId:   playerId,
+		Mode:       model.Mode_price,
+		Symbol:     data.Info.Symbol,
+		SymbolUUID: data.Info.SymbolUUID,
+		AfterDate:  afterDate,
+		Difficulty: difficulty,
+		Score:      score,
+		DayPrice:   dayPrice,
+	}
+	if len(data.Before) > 0 {
+		round.HistoryStartDate = data.Before[0].Date
+		round.LastClose = data.Before[len(data.Before)-1].Close
+	}
+	if len(data.After) > 0 {
+		round.FutureEndDate = data.After[len(data.After)-1].Date
+	}
+	for _, stock := range data.After {
+		round.Actual = append(round.Actual, stock.Close)
+	}
+	return round
+}
+
+func (h *SolutionHandler) postCandleSolution(c *gin.Context) {
+	userSolution := model.UserCandleSolutionRequest{}
+	if err := c.BindJSON(&userSolution); err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON"})
+		return
+	}
+	data, ok := h.loadSolutionData(c, userSolution.SymbolUUID, userSolution.AfterDate)
+	if !ok {
+		return
+	}
+	served, speedBonus, ok := h.loadServedRound(c, model.Mode_candle, userSolution.RoundId, userSolution.SymbolUUID, userSolution.AfterDate)
+	if !ok {
+		return
+	}
+	score := h.CandleScoringLogic.GetCandleScore(userSolution.DayCandles, data.After)
+	score.Total, score.HintPenalty = h.adjustTotal(score.Total, served, speedBonus)
+	score.SpeedBonus = speedBonus
+	c.IndentedJSON(http.StatusOK, model.UserCandleSolutionResponse{
+		Symbol: data.Info.Symbol,
+		Name:   data.Info.Name,
+		Score:  score,
+		Stocks: data.After,
+	})
+}
+
+func (h *SolutionHandler) postDirectionSolution(c *gin.Context) {
+	userSolution := model.UserDirectionSolutionRequest{}
+	if err := c.BindJSON(&userSolution); err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON"})
+		return
+	}
+	for i, dayDirection := range userSolution.DayDirections {
+		userSolution.DayDirections[i].Direction = strings.ToLower(dayDirection.Direction)
+		if !logic.IsValidDirection(userSolution.DayDirections[i].Direction) {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid direction %q, must be up, down or flat", dayDirection.Direction)})
+			return
+		}
+	}
+	data, ok := h.loadSolutionData(c, userSolution.SymbolUUID, userSolution.AfterDate)
+	if !ok {
+		return
+	}
+	// The first day is compared to the last close the player saw
+	lastClose := 0.0
+	if len(data.Before) > 0 {
+		lastClose = data.Before[len(data.Before)-1].Close
+	} else if len(data.After) > 0 {
+		lastClose = data.After[0].Open
+	}
+	served, speedBonus, ok := h.loadServedRound(c, model.Mode_direction, userSolution.RoundId, userSolution.SymbolUUID, userSolution.AfterDate)
+	if !ok {
+		return
+	}
+	score := h.DirectionScoringLogic.GetDirectionScore(userSolution.DayDirections, lastClose, data.After)
+	score.Total, score.HintPenalty = h.adjustTotal(score.Total, served, speedBonus)
+	score.SpeedBonus = speedBonus
+	c.IndentedJSON(http.StatusOK, model.UserDirectionSolutionResponse{
+		Symbol:     data.Info.Symbol,
+		Name:       data.Info.Name,
+		Score:      score,
+		Stocks:     data.After,
+		Directions: h.DirectionScoringLogic.GetActualDirections(lastClose, data.After),
+	})
+}
+
+// Number of symbols of a basket when the player does not ask for one
+const defaultBasketSymbols = 4
+
+func (h *SolutionHandler) getBasket(c *gin.Context) {
+	size, err := queryPositiveInt(c, "symbols", defaultBasketSymbols)
+	if err != nil || size < model.Basket_min_symbols || size > model.Basket_max_symbols {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("symbols must be between %d and %d", model.Basket_min_symbols, model.Basket_max_symbols)})
+		return
+	}
+	basket := h.StockService.GetRandomBasket(size)
+	if len(basket.Stocks) == 0 {
+		c.IndentedJSON(http.StatusServiceUnavailable, gin.H{"error": "No basket available"})
+		return
+	}
+	// The solution is only accepted for a basket served, so the basket is not sent without it
+	served, err := h.BasketService.ServeBasket(getPlayerId(c), basket)
+	if err != nil {
+		fmt.Println("getBasket Error saving served basket: ", err)
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Cannot serve the basket"})
+		return
+	}
+	basket.Id = served.Id
+	c.IndentedJSON(http.StatusOK, basket)
+}
+
+// basketErrorStatus is the HTTP status of an error of the basket service
+func basketErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, dataaccess.ErrServedBasketNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrServedBasketSymbols):
+		return http.StatusBadRequest
+	case errors.Is(err, service.ErrServedBasketSolved):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// writeBasketError writes the error response of an error of the basket service
+func writeBasketError(c *gin.Context, err error) {
+	if errors.Is(err, service.ErrServedBasketSolved) {
+		c.IndentedJSON(http.StatusConflict, gin.H{"error": err.Error(), "code": model.Error_round_solved})
+		return
+	}
+	c.IndentedJSON(basketErrorStatus(err), gin.H{"error": err.Error()})
+}
+
+// postBasketSolution scores a ranking or an allocation of the symbols of a served basket on
+// their returns from the last close shown to the last day to guess. Only the first solution
+// of the basket is accepted, the basket is solved once the solution is scored so a failure
+// to load the prices does not use it up.
+func (h *SolutionHandler) postBasketSolution(c *gin.Context) {
+	userSolution := model.BasketSolutionRequest{}
+	if err := c.BindJSON(&userSolution); err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON"})
+		return
+	}
+	if err := logic.ValidateBasketSolution(userSolution); err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if userSolution.BasketId == "" {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "basketId is required", "code": model.Error_round_required})
+		return
+	}
+	symbols := logic.BasketSymbols(userSolution)
+	basket, err := h.BasketService.GetBasket(userSolution.BasketId, getPlayerId(c), symbols)
+	if err != nil {
+		writeBasketError(c, err)
+		return
+	}
+	returns := map[string]float64{}
+	results := []model.BasketResult{}
+	for _, symbolUUID := range symbols {
+		data, ok := h.loadSolutionData(c, symbolUUID, basket.AfterDate)
+		if !ok {
+			return
+		}
+		if len(data.Before) == 0 || len(data.After) == 0 || data.Before[len(data.Before)-1].Close == 0 {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("No prices around %s for %s", basket.AfterDate, symbolUUID)})
+			return
+		}
+		lastClose := data.Before[len(data.Before)-1].Close
+		returns[symbolUUID] = (data.After[len(data.After)-1].Close/lastClose - 1) * 100
+		results = append(results, model.BasketResult{
+			SymbolUUID: symbolUUID,
+			Symbol:     data.Info.Symbol,
+			Name:       data.Info.Name,
+			Return:     math.Round(returns[symbolUUID]*100) / 100,
+			Stocks:     data.After,
+		})
+	}
+	score, err := logic.GetBasketScore(userSolution, returns)
+	if err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if _, err := h.BasketService.SolveBasket(basket.Id, getPlayerId(c), symbols); err != nil {
+		writeBasketError(c, err)
+		return
+	}
+	slices.SortStableFunc(results, func(a, b model.BasketResult) int {
+		return cmp.Compare(returns[b.SymbolUUID], returns[a.SymbolUUID])
+	})
+	for i := range results {
+		results[i].Rank = i + 1
+	}
+	c.IndentedJSON(http.StatusOK, model.BasketSolutionResponse{Score: score, Results: results})
+}
+
+const defaultRoundsPageSize = 20
+
+const maxRoundsPageSize = 100
+
+// requirePlayerId returns the player id of the request. It writes the error response and
+// returns false for an anonymous player.
+func requirePlayerId(c *gin.Context) (string, bool) {
+	playerId := getPlayerId(c)
+	if playerId == "" {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Missing or invalid %s header", playerIdHeader)})
+		return "", false
+	}
+	return playerId, true
+}
+
+// queryPositiveInt reads an optional positive integer from the query string
+func queryPositiveInt(c *gin.Context, name string, defaultValue int) (int, error) {
+	value := c.Query(name)
+	if value == "" {
+		return defaultValue, nil
+	}
+	number, err := strconv.Atoi(value)
+	if err != nil || number < 1 {
+		return 0, fmt.Errorf("invalid %s %q, must be a positive integer", name, value)
+	}
+	return number, nil
+}
+
+func (h *SolutionHandler) getMyRounds(c *gin.Context) {
+	playerId, ok := requirePlayerId(c)
+	if !ok {
+		return
+	}
+	page, err := queryPositiveInt(c, "page", 1)
+	if err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	pageSize, err := queryPositiveInt(c, "pageSize", defaultRoundsPageSize)
+	if err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	pageSize = min(pageSize, maxRoundsPageSize)
+	rounds, err := h.RoundService.GetPlayerRounds(playerId, page, pageSize)
+	if err != nil {
+		fmt.Println("getMyRounds Error: ", err)
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Cannot load the rounds"})
+		return
+	}
+	c.IndentedJSON(http.StatusOK, rounds)
+}
+
+func (h *SolutionHandler) getMyStats(c *gin.Context) {
+	playerId, ok := requirePlayerId(c)
+	if !ok {
+		return
+	}
+	stats, err := h.RoundService.GetPlayerStats(playerId)
+	if err != nil {
+		fmt.Println("getMyStats Error: ", err)
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Cannot load the stats"})
+		return
+	}
+	c.IndentedJSON(http.StatusOK, stats)
+}
+
+func (h *SolutionHandler) getMyCalibration(c *gin.Context) {
+	playerId, ok := requirePlayerId(c)
+	if !ok {
+		return
+	}
+	calibration, err := h.AnalyticsService.GetPlayerCalibration(playerId)
+	if err != nil {
+		fmt.Println("getMyCalibration Error: ", err)
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Cannot load the calibration"})
+		return
+	}
+	c.IndentedJSON(http.StatusOK, calibration)
+}
+
+func (h *SolutionHandler) getMyProgress(c *gin.Context) {
+	playerId, ok := requirePlayerId(c)
+	if !ok {
+		return
+	}
+	progress, err := h.ProgressionService.GetProgress(playerId)
+	if err != nil {
+		fmt.Println("getMyProgress Error: ", err)
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Cannot load the progress"})
+		return
+	}
+	c.IndentedJSON(http.StatusOK, progress)
+}
+
+func (h *SolutionHandler) getAchievements(c *gin.Context) {
+	c.IndentedJSON(http.StatusOK, h.ProgressionService.ListAchievements())
+}
+
+// getCalibration returns the calibration of every player together, without the players
+func (h *SolutionHandler) getCalibration(c *gin.Context) {
+	report, err := h.AnalyticsService.GetCalibrationReport()
+	if err != nil {
+		fmt.Println("getCalibration Error: ", err)
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Cannot load the calibration"})
+		return
+	}
+	c.IndentedJSON(http.StatusOK, report.Global)
+}
+
+// requireAdmin only lets through the requests with the admin token as bearer token
+func (h *SolutionHandler) requireAdmin(c *gin.Context) {
+	if h.AdminToken == "" {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "Admin endpoints are disabled"})
+		return
+	}
+	token, found := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+	if !found || subtle.ConstantTimeCompare([]byte(token), []byte(h.AdminToken)) != 1 {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid admin token"})
+		return
+	}
+	c.Next()
+}
+
+// getCalibrationCSV exports the calibration of each player, the first row is every player together
+func (h *SolutionHandler) getCalibrationCSV(c *gin.Context) {
+	report, err := h.AnalyticsService.GetCalibrationReport()
+	if err != nil {
+		fmt.Println("getCalibrationCSV Error: ", err)
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Cannot load the calibration"})
+		return
+	}
+	c.Header("Content-Type", "text/csv")
+	c.Header("Content-Disposition", `attachment; filename="calibration.csv"`)
+	writer := csv.NewWriter(c.Writer)
+	writer.Write([]string{"player_id", "rounds", "days", "bias", "guess_volatility", "realized_volatility", "dispersion_ratio"})
+	writer.Write(calibrationRecord("all", report.Global))
+	for _, player := range report.Players {
+		writer.Write(calibrationRecord(player.PlayerId, player.Calibration))
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		fmt.Println("getCalibrationCSV Error writing: ", err)
+	}
+}
+
+func calibrationRecord(playerId string, calibration model.Calibration) []string {
+	dispersionRatio := ""
+	if calibration.DispersionRatio != nil {
+		dispersionRatio = strconv.FormatFloat(*calibration.DispersionRatio, 'f', -1, 64)
+	}
+	return []string{
+		playerId,
+		strconv.Itoa(calibration.Rounds),
+		strconv.Itoa(calibration.Days),
+		strconv.FormatFloat(calibration.Bias, 'f', -1, 64),
+		strconv.FormatFloat(calibration.GuessVolatility, 'f', -1, 64),
+		strconv.FormatFloat(calibration.RealizedVolatility, 'f', -1, 64),
+		dispersionRatio,
+	}
+}
+
+// roomErrorStatus is the HTTP status of an error of the room hub
+func roomErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, room.ErrRoomNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, room.ErrTooManyRooms):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func (h *SolutionHandler) postRoom(c *gin.Context) {
+	playerId, ok := requirePlayerId(c)
+	if !ok {
+		return
+	}
+	// The body is optional, the player gets a default name without it
+	request := model.CreateRoomRequest{}
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON"})
+			return
+		}
+	}
+	state, err := h.RoomHub.CreateRoom(playerId, request.Name)
+	if err != nil {
+		c.IndentedJSON(roomErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.IndentedJSON(http.StatusCreated, state)
+}
+
+func (h *SolutionHandler) getRoom(c *gin.Context) {
+	state, err := h.RoomHub.GetRoom(c.Param("code"))
+	if err != nil {
+		c.IndentedJSON(roomErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.IndentedJSON(http.StatusOK, state)
+}
+
+// getRoomSocket joins the room over a WebSocket, the name is a query parameter
+func (h *SolutionHandler) getRoomSocket(c *gin.Context) {
+	code := c.Param("code")
+	playerId, ok := requirePlayerId(c)
+	if !ok {
+		return
+	}
+	// Checked before the upgrade to answer with a status, Serve reports the later errors as events
+	if _, err := h.RoomHub.GetRoom(code); err != nil {
+		c.IndentedJSON(roomErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	name := c.Query("name")
+	server := websocket.Server{Handler: func(ws *websocket.Conn) {
+		room.Serve(h.RoomHub, ws, code, playerId, name)
+	}}
+	server.ServeHTTP(c.Writer, c.Request)
+}
+
+// tournamentErrorStatus is the HTTP status of an error of the tournament service
+func tournamentErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, dataaccess.ErrTournamentNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrInvalidTournament), errors.Is(err, service.ErrWrongPosition):
+		return http.StatusBadRequest
+	case errors.Is(err, service.ErrNotEntered):
+		return http.StatusForbidden
+	case errors.Is(err, service.ErrTournamentNotStarted), errors.Is(err, service.ErrTournamentEnded),
+		errors.Is(err, service.ErrAlreadyEntered), errors.Is(err, service.ErrTournamentCompleted),
+		errors.Is(err, service.ErrRoundAlreadyPlayed):
+		return http.StatusConflict
+	case errors.Is(err, service.ErrNoTournamentWindow):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func (h *SolutionHandler) postTournament(c *gin.Context) {
+	request := model.CreateTournamentRequest{}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON"})
+		return
+	}
+	tournament, err := h.TournamentService.CreateTournament(request)
+	if err != nil {
+		c.IndentedJSON(tournamentErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.IndentedJSON(http.StatusCreated, tournament)
+}
+
+func (h *SolutionHandler) getTournaments(c *gin.Context) {
+	tournaments, err := h.TournamentService.ListTournaments()
+	if err != nil {
+		fmt.Println("getTournaments Error: ", err)
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Cannot load the tournaments"})
+		return
+	}
+	c.IndentedJSON(http.StatusOK, tournaments)
+}
+
+func (h *SolutionHandler) getTournament(c *gin.Context) {
+	tournament, err := h.TournamentService.GetTournament(c.Param("id"))
+	if err != nil {
+		c.IndentedJSON(tournamentErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.IndentedJSON(http.StatusOK, tournament)
+}
+
+func (h *SolutionHandler) postTournamentEntry(c *gin.Context) {
+	playerId, ok := requirePlayerId(c)
+	if !ok {
+		return
+	}
+	// The body is optional, the player gets a default name without it
+	request := model.JoinTournamentRequest{}
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON"})
+			return
+		}
+	}
+	if err := h.TournamentService.JoinTournament(c.Param("id"), playerId, request.Name); err != nil {
+		c.IndentedJSON(tournamentErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *SolutionHandler) getNextTournamentRound(c *gin.Context) {
+	playerId, ok := requirePlayerId(c)
+	if !ok {
+		return
+	}
+	round, err := h.TournamentService.GetNextRound(c.Param("id"), playerId)
+	if err != nil {
+		c.IndentedJSON(tournamentErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.IndentedJSON(http.StatusOK, round)
+}
+
+// postTournamentSolution scores the next round of the player. The window comes from the
+// tournament, the client only sends the guessed prices.
+func (h *SolutionHandler) postTournamentSolution(c *gin.Context) {
+	playerId, ok := requirePlayerId(c)
+	if !ok {
+		return
+	}
+	id := c.Param("id")
+	position, err := strconv.Atoi(c.Param("position"))
+	if err != nil || position < 1 {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "position must be a positive integer"})
+		return
+	}
+	userSolution := model.TournamentSolutionRequest{}
+	if err := c.BindJSON(&userSolution); err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON"})
+		return
+	}
+	window, err := h.TournamentService.GetRoundWindow(id, playerId, position)
+	if err != nil {
+		c.IndentedJSON(tournamentErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	data, ok := h.loadSolutionData(c, window.SymbolUUID, window.AfterDate)
+	if !ok || !validateDayPrices(c, userSolution.DayPrice, data) {
+		return
+	}
+	bollingerBands := h.ScoringLogic.CalculateBollingerBandsWithConfig(data.Full(), h.bollingerConfig())
+	score := h.ScoringLogic.GetScore(userSolution.DayPrice, data.After, bollingerBands)
+	rating := h.DifficultyLogic.RateDifficulty(
+		logic.StocksToPublic(data.Before, data.Info.SymbolUUID),
+		logic.StocksToPublic(data.After, data.Info.SymbolUUID),
+	)
+	// The position is recorded first so only one submission of it is saved. The round
+	// is saved with the id the tournament points to, the position still counts without it.
+	round := newPriceRound(playerId, window.AfterDate, data, rating.Difficulty, score, userSolution.DayPrice)
+	round.Id = uuid.New().String()
+	if err := h.TournamentService.RecordRound(id, playerId, position, round.Id, score); err != nil {
+		c.IndentedJSON(tournamentErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	round, unlocked, err := h.RoundService.SaveRound(round)
+	if err != nil {
+		fmt.Println("postTournamentSolution Error saving round: ", err)
+	}
+	c.IndentedJSON(http.StatusOK, model.TournamentSolutionResponse{
+		Position:     position,
+		RoundId:      round.Id,
+		Symbol:       data.Info.Symbol,
+		Name:         data.Info.Name,
+		Score:        score,
+		Stocks:       data.After,
+		Achievements: unlocked,
+	})
+}
+
+func (h *SolutionHandler) getTournamentStandings(c *gin.Context) {
+	standings, err := h.TournamentService.GetStandings(c.Param("id"))
+	if err != nil {
+		c.IndentedJSON(tournamentErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.IndentedJSON(http.StatusOK, standings)
+}
+
+// tradingErrorStatus is the HTTP status of an error of the trading service
+func tradingErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, dataaccess.ErrTradingSessionNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, logic.ErrInvalidTrade):
+		return http.StatusBadRequest
+	case errors.Is(err, service.ErrTradingSessionFinished), errors.Is(err, service.ErrTradeConflict):
+		return http.StatusConflict
+	case errors.Is(err, service.ErrNoTradingWindow):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func (h *SolutionHandler) postTradingSession(c *gin.Context) {
+	playerId, ok := requirePlayerId(c)
+	if !ok {
+		return
+	}
+	// The body is optional, every difficulty is drawn without it
+	request := model.StartTradingRequest{}
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON"})
+			return
+		}
+	}
+	difficulty := strings.ToLower(request.Difficulty)
+	if difficulty != "" && !logic.IsValidDifficulty(difficulty) {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid difficulty %q, must be easy, medium or hard", difficulty)})
+		return
+	}
+	response, err := h.TradingService.StartSession(playerId, difficulty)
+	if err != nil {
+		c.IndentedJSON(tradingErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.IndentedJSON(http.StatusCreated, response)
+}
+
+func (h *SolutionHandler) getTradingSession(c *gin.Context) {
+	playerId, ok := requirePlayerId(c)
+	if !ok {
+		return
+	}
+	response, err := h.TradingService.GetSession(c.Param("id"), playerId)
+	if err != nil {
+		c.IndentedJSON(tradingErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.IndentedJSON(http.StatusOK, response)
+}
+
+// postTrade executes the decision of the player and reveals the next day
+func (h *SolutionHandler) postTrade(c *gin.Context) {
+	playerId, ok := requirePlayerId(c)
+	if !ok {
+		return
+	}
+	trade := model.TradeRequest{}
+	if err := c.ShouldBindJSON(&trade); err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON"})
+		return
+	}
+	response, err := h.TradingService.Trade(c.Param("id"), playerId, trade)
+	if err != nil {
+		c.IndentedJSON(tradingErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.IndentedJSON(http.StatusOK, response)
+}
+
+// tickerErrorStatus is the HTTP status of an error of the ticker service
+func tickerErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, dataaccess.ErrTickerChallengeNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrInvalidTickerAnswer):
+		return http.StatusBadRequest
+	case errors.Is(err, service.ErrTickerAlreadyAnswered):
+		return http.StatusConflict
+	case errors.Is(err, service.ErrNoTickerChallenge):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func (h *SolutionHandler) postTickerChallenge(c *gin.Context) {
+	playerId, ok := requirePlayerId(c)
+	if !ok {
+		return
+	}
+	challenge, err := h.TickerService.NewChallenge(playerId)
+	if err != nil {
+		c.IndentedJSON(tickerErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.IndentedJSON(http.StatusCreated, challenge)
+}
+
+func (h *SolutionHandler) postTickerAnswer(c *gin.Context) {
+	playerId, ok := requirePlayerId(c)
+	if !ok {
+		return
+	}
+	answer := model.TickerAnswerRequest{}
+	if err := c.ShouldBindJSON(&answer); err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON"})
+		return
+	}
+	response, err := h.TickerService.Answer(c.Param("id"), playerId, answer.OptionId)
+	if err != nil {
+		c.IndentedJSON(tickerErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.IndentedJSON(http.StatusOK, response)
+}
+
+// servedRoundErrorStatus is the HTTP status of an error of the served round service
+func servedRoundErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, dataaccess.ErrServedRoundNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrInvalidHint), errors.Is(err, service.ErrServedRoundWindow):
+		return http.StatusBadRequest
+	case errors.Is(err, service.ErrServedRoundSolved):
+		return http.StatusConflict
+	case errors.Is(err, service.ErrHintUnavailable):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// postHint reveals a hint of the round served by GET /stocks, its penalty is removed from
+// the score of the solution of the round in every mode
+func (h *SolutionHandler) postHint(c *gin.Context) {
+	request := model.HintRequest{}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON"})
+		return
+	}
+	response, err := h.ServedRoundService.RequestHint(c.Param("roundId"), getPlayerId(c), request.Type)
+	if err != nil {
+		c.IndentedJSON(servedRoundErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.IndentedJSON(http.StatusOK, response)
+}
+
+// scenarioErrorStatus is the HTTP status of an error of the scenario service
+func scenarioErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, dataaccess.ErrScenarioNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrInvalidScenario):
+		return http.StatusBadRequest
+	case errors.Is(err, dataaccess.ErrScenarioExists):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func (h *SolutionHandler) getScenarios(c *gin.Context) {
+	scenarios, err := h.ScenarioService.ListScenarios()
+	if err != nil {
+		fmt.Println("getScenarios Error: ", err)
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Cannot load the scenarios"})
+		return
+	}
+	c.IndentedJSON(http.StatusOK, scenarios)
+}
+
+func (h *SolutionHandler) postScenario(c *gin.Context) {
+	request := model.ScenarioRequest{}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON"})
+		return
+	}
+	scenario, err := h.ScenarioService.CreateScenario(request)
+	if err != nil {
+		c.IndentedJSON(scenarioErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.IndentedJSON(http.StatusCreated, scenario)
+}
+
+func (h *SolutionHandler) putScenario(c *gin.Context) {
+	request := model.ScenarioRequest{}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.IndentedJSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON"})
+		return
+	}
+	scenario, err := h.ScenarioService.UpdateScenario(c.Param("id"), request)
+	if err != nil {
+		c.IndentedJSON(scenarioErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.IndentedJSON(http.StatusOK, scenario)
+}
+
+func (h *SolutionHandler) deleteScenario(c *gin.Context) {
+	if err := h.ScenarioService.DeleteScenario(c.Param("id")); err != nil {
+		c.IndentedJSON(scenarioErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// isRoundId returns true for an id that can be a saved round. The id is also the name of
+// the cached cards so it must be checked before reading the cache.
+func isRoundId(id string) bool {
+	_, err := uuid.Parse(id)
+	return err == nil
+}
+
+// loadRound fetches a saved round and the prices it was played on. It writes the
+// error response and returns false when the round cannot be loaded.
+func (h *SolutionHandler) loadRound(c *gin.Context) (round model.Round, data solutionData, ok bool) {
+	id := c.Param("id")
+	if !isRoundId(id) {
+		c.IndentedJSON(http.StatusNotFound, gin.H{"error": "Round not found"})
+		return
+	}
+	round, err := h.RoundService.GetRound(id)
+	if errors.Is(err, dataaccess.ErrRoundNotFound) {
+		c.IndentedJSON(http.StatusNotFound, gin.H{"error": "Round not found"})
+		return
+	}
+	if err != nil {
+		fmt.Println("loadRound Error: ", err)
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Cannot load the round"})
+		return
+	}
+	info, err := h.StockService.GetStockInfo(round.SymbolUUID)
+	if err != nil {
+		c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Cannot find the stock information"})
+		return
+	}
+	data.Info = info
+	if round.HistoryStartDate != "" && round.FutureEndDate != "" {
+		prices := h.StockService.GetStockPriceForTimeRange(round.Symbol, round.HistoryStartDate, round.FutureEndDate)
+		data.Before, data.After = logic.SplitAtDate(prices, round.AfterDate)
+	} else {
+		// The range was not recorded, the prices are found the same way as when the round was scored
+		data.Before = slices.Clone(h.StockService.GetStocksBeforeEqualDate(round.Symbol, round.AfterDate))
+		slices.Reverse(data.Before)
+		data.After = h.StockService.GetStocksAfterDate(round.Symbol, round.AfterDate)
+	}
+	return round, data, true
+}
+
+// getRound replays a saved round: the prices shown, the guesses, the real prices and the score
+func (h *SolutionHandler) getRound(c *gin.Context) {
+	round, data, ok := h.loadRound(c)
+	if !ok {
+		return
+	}
+	fullList := data.Full()
+	response := model.RoundReplayResponse{
+		Id:         round.Id,
+		Mode:       round.Mode,
+		Symbol:     data.Info.Symbol,
+		Name:       data.Info.Name,
+		AfterDate:  round.AfterDate,
+		Difficulty: round.Difficulty,
+		CreatedAt:  round.CreatedAt,
+		History:    data.Before,
+		Stocks:     data.After,
+		DayPrice:   round.DayPrice,
+		Score:      round.Score,
+		BB20:       h.ScoringLogic.CalculateBollingerBandsWithConfig(fullList, h.bollingerConfig()),
+	}
+	if specs := c.Query("indicators"); specs != "" {
+		overlays, err := h.IndicatorLogic.GetOverlays(fullList, strings.Split(specs, ","))
+		if err != nil {
+			c.IndentedJSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid indicators: %v", err)})
+			return
+		}
+		response.Indicators = overlays
+	}
+	c.IndentedJSON(http.StatusOK, response)
+}
+
+// getRoundCard renders the shareable result card of a round in PNG or SVG
+func (h *SolutionHandler) getRoundCard(format string) gin.HandlerFunc {
+	contentTypes := map[string]string{"png": "image/png", "svg": "image/svg+xml"}
+	return func(c *gin.Context) {
+		if h.CardCache != nil && isRoundId(c.Param("id")) {
+			if cached, found := h.CardCache.Load(c.Param("id"), format); found {
+				c.Data(http.StatusOK, contentTypes[format], cached)
+				return
+			}
+		}
+		round, data, ok := h.loadRound(c)
+		if !ok {
+			return
+		}
+		roundCard := model.RoundCard{
+			Symbol:     data.Info.Symbol,
+			Name:       data.Info.Name,
+			AfterDate:  round.AfterDate,
+			Difficulty: round.Difficulty,
+			Score:      round.Score,
+		}
+		for _, stock := range data.Before {
+			roundCard.History = append(roundCard.History, stock.Close)
+		}
+		for _, stock := range data.After {
+			roundCard.Actual = append(roundCard.Actual, stock.Close)
+		}
+		for _, dayPrice := range round.DayPrice {
+			roundCard.Guess = append(roundCard.Guess, dayPrice.Price)
+		}
+		var rendered []byte
+		if format == "png" {
+			var err error
+			if rendered, err = h.CardLogic.RenderPNG(roundCard); err != nil {
+				fmt.Println("getRoundCard Error rendering: ", err)
+				c.IndentedJSON(http.StatusInternalServerError, gin.H{"error": "Cannot render the card"})
+				return
+			}
+		} else {
+			rendered = h.CardLogic.RenderSVG(roundCard)
+		}
+		if h.CardCache != nil {
+			if err := h.CardCache.Store(round.Id, format, rendered); err != nil {
+				fmt.Println("getRoundCard Error caching: ", err)
+			}
+		}
+		c.Data(http.StatusOK, contentTypes[format], rendered)
+	}
+}
+
 func main() {
 	env := os.Getenv("GO_ENV")
 	println("env", env)
@@ -88,16 +1261,85 @@ func main() {
 	stockDataAccess := &dataaccess.StockDataAccessImpl{
 		DB: database.GetDB(),
 	}
-	stockLogic := &logic.StockLogicImpl{}
+	if err := database.CreateGameTables(database.GetRawDB()); err != nil {
+		panic(err)
+	}
+	roundDataAccess := &dataaccess.RoundDataAccessImpl{DB: database.GetDB()}
+	progressionService := &service.ProgressionServiceImpl{
+		ProgressionDataAccess: &dataaccess.ProgressionDataAccessImpl{DB: database.GetDB()},
+	}
+	roundService := &service.RoundServiceImpl{
+		RoundDataAccess:    roundDataAccess,
+		ProgressionService: progressionService,
+	}
+	stockLogic := &logic.StockLogicImpl{
+		Eligibility: util.GetEligibilityEnv(),
+	}
+	difficultyLogic := &logic.DifficultyLogicImpl{}
+	scoringLogic := &logic.ScoringLogicImpl{HintPenalties: util.GetHintPenaltiesEnv()}
+	indicatorLogic := &indicators.IndicatorLogicImpl{}
 	stockService := &service.StockServiceImpl{
 		StockDataAccess: stockDataAccess,
 		StockLogic:      stockLogic,
+		DifficultyLogic: difficultyLogic,
+	}
+	roomHub := &room.HubImpl{
+		Game: &room.GameImpl{
+			StockService:    stockService,
+			ScoringLogic:    scoringLogic,
+			BollingerConfig: util.GetBollingerEnv(),
+		},
+	}
+	tournamentService := &service.TournamentServiceImpl{
+		TournamentDataAccess: &dataaccess.TournamentDataAccessImpl{DB: database.GetDB()},
+		StockService:         stockService,
+	}
+	tradingService := &service.TradingServiceImpl{
+		TradingDataAccess: &dataaccess.TradingDataAccessImpl{DB: database.GetDB()},
+		StockService:      stockService,
+	}
+	tickerService := &service.TickerServiceImpl{
+		TickerDataAccess: &dataaccess.TickerDataAccessImpl{DB: database.GetDB()},
+		StockService:     stockService,
+	}
+	scenarioService := &service.ScenarioServiceImpl{
+		ScenarioDataAccess: &dataaccess.ScenarioDataAccessImpl{DB: database.GetDB()},
+	}
+	servedRoundService := &service.ServedRoundServiceImpl{
+		ServedRoundDataAccess: &dataaccess.ServedRoundDataAccessImpl{DB: database.GetDB()},
+		StockService:          stockService,
+		IndicatorLogic:        indicatorLogic,
+		ScoringLogic:          scoringLogic,
+	}
+	basketService := &service.BasketServiceImpl{
+		BasketDataAccess: &dataaccess.BasketDataAccessImpl{DB: database.GetDB()},
 	}
 
 	// Use stockService in your handler initialization
 	handler := &SolutionHandler{
-		StockService: stockService,
-		ScoringLogic: &logic.ScoringLogicImpl{},
+		StockService:          stockService,
+		ScoringLogic:          scoringLogic,
+		IndicatorLogic:        indicatorLogic,
+		CandleScoringLogic:    &logic.CandleScoringLogicImpl{},
+		DirectionScoringLogic: &logic.DirectionScoringLogicImpl{},
+		BandScoringLogic:      &logic.BandScoringLogicImpl{},
+		BotLogic:              &logic.BotLogicImpl{},
+		DifficultyLogic:       difficultyLogic,
+		RoundService:          roundService,
+		AnalyticsService:      &service.AnalyticsServiceImpl{RoundDataAccess: roundDataAccess},
+		CardLogic:             &card.CardLogicImpl{},
+		RoomHub:               roomHub,
+		TournamentService:     tournamentService,
+		TradingService:        tradingService,
+		TickerService:         tickerService,
+		ScenarioService:       scenarioService,
+		ServedRoundService:    servedRoundService,
+		BasketService:         basketService,
+		ProgressionService:    progressionService,
+		CardCache:             &card.CardCache{Dir: util.GetCardCacheEnv()},
+		BollingerConfig:       util.GetBollingerEnv(),
+		RoundTiming:           util.GetRoundTimingEnv(),
+		AdminToken:            util.GetAdminTokenEnv(),
 	}
 
 	router := SetupRouter(handler, isProduction)
@@ -120,8 +1362,8 @@ func SetupRouter(handler *SolutionHandler, isProduction bool) *gin.Engine {
 	router.Use(func(c *gin.Context) {
 		c.Header("Access-Control-Allow-Origin", "*")
 		c.Header("Access-Control-Allow-Credentials", "true")
-		c.Header("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization")
+		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
+		c.Header("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, "+playerIdHeader)
 		c.Header("Cache-Control", "no-cache, no-store, must-revalidate")
 		c.Header("Pragma", "no-cache")
 		c.Header("Expires", "0")
@@ -133,7 +1375,45 @@ func SetupRouter(handler *SolutionHandler, isProduction bool) *gin.Engine {
 	})
 
 	router.GET("/stocks", handler.getStocks)
+	router.POST("/stocks/:roundId/hints", handler.postHint)
 	router.POST("/solution", handler.postSolution)
+	router.POST("/solution/candle", handler.postCandleSolution)
+	router.POST("/solution/direction", handler.postDirectionSolution)
+	router.GET("/basket", handler.getBasket)
+	router.POST("/solution/basket", handler.postBasketSolution)
+	router.GET("/rounds/:id", handler.getRound)
+	router.GET("/rounds/:id/card.png", handler.getRoundCard("png"))
+	router.GET("/rounds/:id/card.svg", handler.getRoundCard("svg"))
+	router.GET("/me/rounds", handler.getMyRounds)
+	router.GET("/me/stats", handler.getMyStats)
+	router.GET("/me/calibration", handler.getMyCalibration)
+	router.GET("/me/progress", handler.getMyProgress)
+	router.GET("/achievements", handler.getAchievements)
+	router.GET("/analytics/calibration", handler.getCalibration)
+
+	router.POST("/rooms", handler.postRoom)
+	router.GET("/rooms/:code", handler.getRoom)
+	router.GET("/rooms/:code/ws", handler.getRoomSocket)
+
+	router.GET("/tournaments", handler.getTournaments)
+	router.GET("/tournaments/:id", handler.getTournament)
+	router.POST("/tournaments/:id/join", handler.postTournamentEntry)
+	router.GET("/tournaments/:id/rounds/next", handler.getNextTournamentRound)
+	router.POST("/tournaments/:id/rounds/:position", handler.postTournamentSolution)
+	router.GET("/tournaments/:id/standings", handler.getTournamentStandings)
+	router.POST("/trading", handler.postTradingSession)
+	router.GET("/trading/:id", handler.getTradingSession)
+	router.POST("/trading/:id/trades", handler.postTrade)
+	router.POST("/ticker/challenges", handler.postTickerChallenge)
+	router.POST("/ticker/challenges/:id/answer", handler.postTickerAnswer)
+	router.GET("/scenarios", handler.getScenarios)
+
+	admin := router.Group("/admin", handler.requireAdmin)
+	admin.GET("/analytics/calibration.csv", handler.getCalibrationCSV)
+	admin.POST("/tournaments", handler.postTournament)
+	admin.POST("/scenarios", handler.postScenario)
+	admin.PUT("/scenarios/:id", handler.putScenario)
+	admin.DELETE("/scenarios/:id", handler.deleteScenario)
 	router.Static("/assets", path+"assets")
 	router.GET("/", func(c *gin.Context) {
 
@@ -1,16 +1,28 @@
 package main
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"slices"
+	"stockgame/internal/card"
+	"stockgame/internal/dataaccess"
+	"stockgame/internal/indicators"
+	"stockgame/internal/logic"
 	"stockgame/internal/model"
+	"stockgame/internal/room"
+	"stockgame/internal/service"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"golang.org/x/net/websocket"
 )
 
 const isProduction = false
@@ -27,6 +39,18 @@ func (m *StockServiceMockImpl) GetRandomStockWithRandomDayRange(n int) []model.S
 	args := m.Called(n)
 	return args.Get(0).([]model.StockPublic)
 }
+func (m *StockServiceMockImpl) GetRandomWindow(n int, difficulty string) model.StockWindow {
+	args := m.Called(n, difficulty)
+	return args.Get(0).(model.StockWindow)
+}
+func (m *StockServiceMockImpl) GetRandomBasket(size int) model.BasketWindow {
+	args := m.Called(size)
+	return args.Get(0).(model.BasketWindow)
+}
+func (m *StockServiceMockImpl) GetScenarioWindow(scenario model.Scenario, numberOfDays int, difficulty string) model.StockWindow {
+	args := m.Called(scenario, numberOfDays, difficulty)
+	return args.Get(0).(model.StockWindow)
+}
 func (m *StockServiceMockImpl) GetRandomStock(symbol []string) string {
 	args := m.Called()
 	return args.Get(0).(string)
@@ -58,11 +82,254 @@ func (m *ScoringLogicMockImpl) CalculateBollingerBands(stockInfo []model.Stock,
 	args := m.Called(stockInfo, day)
 	return args.Get(0).(map[string]model.BollingerBand)
 }
+func (m *ScoringLogicMockImpl) CalculateBollingerBandsWithConfig(stockInfo []model.Stock, config model.BollingerConfig) map[string]model.BollingerBand {
+	args := m.Called(stockInfo, config)
+	return args.Get(0).(map[string]model.BollingerBand)
+}
+func (m *ScoringLogicMockImpl) GetHintPenalty(hints []string) int {
+	args := m.Called(hints)
+	return args.Int(0)
+}
+func (m *ScoringLogicMockImpl) ApplyHintPenalty(total int, hints []string) (int, int) {
+	args := m.Called(total, hints)
+	return args.Int(0), args.Int(1)
+}
 func (m *ScoringLogicMockImpl) GetScore(userPrices []model.DayPrice, actualStockInfo []model.Stock, bollinger20Days map[string]model.BollingerBand) model.UserScoreResponse {
 	args := m.Called(userPrices, actualStockInfo, bollinger20Days)
 	return args.Get(0).(model.UserScoreResponse)
 }
 
+type RoundServiceMockImpl struct {
+	mock.Mock
+}
+
+func (m *RoundServiceMockImpl) SaveRound(round model.Round) (model.Round, []model.Achievement, error) {
+	args := m.Called(round)
+	return args.Get(0).(model.Round), args.Get(1).([]model.Achievement), args.Error(2)
+}
+func (m *RoundServiceMockImpl) GetRound(id string) (model.Round, error) {
+	args := m.Called(id)
+	return args.Get(0).(model.Round), args.Error(1)
+}
+func (m *RoundServiceMockImpl) GetPercentiles(round model.Round) model.SolutionPercentiles {
+	args := m.Called(round)
+	return args.Get(0).(model.SolutionPercentiles)
+}
+func (m *RoundServiceMockImpl) GetPlayerRounds(playerId string, page int, pageSize int) (model.PlayerRoundsResponse, error) {
+	args := m.Called(playerId, page, pageSize)
+	return args.Get(0).(model.PlayerRoundsResponse), args.Error(1)
+}
+func (m *RoundServiceMockImpl) GetPlayerStats(playerId string) (model.PlayerStats, error) {
+	args := m.Called(playerId)
+	return args.Get(0).(model.PlayerStats), args.Error(1)
+}
+
+type AnalyticsServiceMockImpl struct {
+	mock.Mock
+}
+
+func (m *AnalyticsServiceMockImpl) GetPlayerCalibration(playerId string) (model.Calibration, error) {
+	args := m.Called(playerId)
+	return args.Get(0).(model.Calibration), args.Error(1)
+}
+func (m *AnalyticsServiceMockImpl) GetCalibrationReport() (model.CalibrationReport, error) {
+	args := m.Called()
+	return args.Get(0).(model.CalibrationReport), args.Error(1)
+}
+
+type TournamentServiceMockImpl struct {
+	mock.Mock
+}
+
+func (m *TournamentServiceMockImpl) CreateTournament(request model.CreateTournamentRequest) (model.Tournament, error) {
+	args := m.Called(request)
+	return args.Get(0).(model.Tournament), args.Error(1)
+}
+func (m *TournamentServiceMockImpl) ListTournaments() ([]model.Tournament, error) {
+	args := m.Called()
+	return args.Get(0).([]model.Tournament), args.Error(1)
+}
+func (m *TournamentServiceMockImpl) GetTournament(id string) (model.Tournament, error) {
+	args := m.Called(id)
+	return args.Get(0).(model.Tournament), args.Error(1)
+}
+func (m *TournamentServiceMockImpl) JoinTournament(id string, playerId string, name string) error {
+	args := m.Called(id, playerId, name)
+	return args.Error(0)
+}
+func (m *TournamentServiceMockImpl) GetNextRound(id string, playerId string) (model.TournamentRoundResponse, error) {
+	args := m.Called(id, playerId)
+	return args.Get(0).(model.TournamentRoundResponse), args.Error(1)
+}
+func (m *TournamentServiceMockImpl) GetRoundWindow(id string, playerId string, position int) (model.TournamentWindow, error) {
+	args := m.Called(id, playerId, position)
+	return args.Get(0).(model.TournamentWindow), args.Error(1)
+}
+func (m *TournamentServiceMockImpl) RecordRound(id string, playerId string, position int, roundId string, score model.UserScoreResponse) error {
+	args := m.Called(id, playerId, position, roundId, score)
+	return args.Error(0)
+}
+func (m *TournamentServiceMockImpl) GetStandings(id string) ([]model.TournamentStanding, error) {
+	args := m.Called(id)
+	return args.Get(0).([]model.TournamentStanding), args.Error(1)
+}
+
+type TradingServiceMockImpl struct {
+	mock.Mock
+}
+
+func (m *TradingServiceMockImpl) StartSession(playerId string, difficulty string) (model.TradingResponse, error) {
+	args := m.Called(playerId, difficulty)
+	return args.Get(0).(model.TradingResponse), args.Error(1)
+}
+func (m *TradingServiceMockImpl) GetSession(id string, playerId string) (model.TradingResponse, error) {
+	args := m.Called(id, playerId)
+	return args.Get(0).(model.TradingResponse), args.Error(1)
+}
+func (m *TradingServiceMockImpl) Trade(id string, playerId string, trade model.TradeRequest) (model.TradingResponse, error) {
+	args := m.Called(id, playerId, trade)
+	return args.Get(0).(model.TradingResponse), args.Error(1)
+}
+
+type TickerServiceMockImpl struct {
+	mock.Mock
+}
+
+type ServedRoundServiceMockImpl struct {
+	mock.Mock
+}
+
+func (m *ServedRoundServiceMockImpl) ServeRound(playerId string, stocks []model.StockPublic) (model.ServedRound, error) {
+	args := m.Called(playerId, stocks)
+	return args.Get(0).(model.ServedRound), args.Error(1)
+}
+func (m *ServedRoundServiceMockImpl) SolveServedRound(id string, playerId string, symbolUUID string, afterDate string, limit int) (model.ServedRound, error) {
+	args := m.Called(id, playerId, symbolUUID, afterDate, limit)
+	return args.Get(0).(model.ServedRound), args.Error(1)
+}
+func (m *ServedRoundServiceMockImpl) RequestHint(id string, playerId string, hint string) (model.HintResponse, error) {
+	args := m.Called(id, playerId, hint)
+	return args.Get(0).(model.HintResponse), args.Error(1)
+}
+
+type BasketServiceMockImpl struct {
+	mock.Mock
+}
+
+func (m *BasketServiceMockImpl) ServeBasket(playerId string, basket model.BasketWindow) (model.ServedBasket, error) {
+	args := m.Called(playerId, basket)
+	return args.Get(0).(model.ServedBasket), args.Error(1)
+}
+func (m *BasketServiceMockImpl) GetBasket(id string, playerId string, symbolUUIDs []string) (model.ServedBasket, error) {
+	args := m.Called(id, playerId, symbolUUIDs)
+	return args.Get(0).(model.ServedBasket), args.Error(1)
+}
+func (m *BasketServiceMockImpl) SolveBasket(id string, playerId string, symbolUUIDs []string) (model.ServedBasket, error) {
+	args := m.Called(id, playerId, symbolUUIDs)
+	return args.Get(0).(model.ServedBasket), args.Error(1)
+}
+
+type ProgressionServiceMockImpl struct {
+	mock.Mock
+}
+
+func (m *ProgressionServiceMockImpl) RecordRound(round model.Round) ([]model.Achievement, error) {
+	args := m.Called(round)
+	return args.Get(0).([]model.Achievement), args.Error(1)
+}
+func (m *ProgressionServiceMockImpl) GetProgress(playerId string) (model.ProgressResponse, error) {
+	args := m.Called(playerId)
+	return args.Get(0).(model.ProgressResponse), args.Error(1)
+}
+func (m *ProgressionServiceMockImpl) ListAchievements() []model.Achievement {
+	args := m.Called()
+	return args.Get(0).([]model.Achievement)
+}
+
+type ScenarioServiceMockImpl struct {
+	mock.Mock
+}
+
+func (m *ScenarioServiceMockImpl) CreateScenario(request model.ScenarioRequest) (model.Scenario, error) {
+	args := m.Called(request)
+	return args.Get(0).(model.Scenario), args.Error(1)
+}
+func (m *ScenarioServiceMockImpl) UpdateScenario(id string, request model.ScenarioRequest) (model.Scenario, error) {
+	args := m.Called(id, request)
+	return args.Get(0).(model.Scenario), args.Error(1)
+}
+func (m *ScenarioServiceMockImpl) DeleteScenario(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+func (m *ScenarioServiceMockImpl) GetScenario(id string) (model.Scenario, error) {
+	args := m.Called(id)
+	return args.Get(0).(model.Scenario), args.Error(1)
+}
+func (m *ScenarioServiceMockImpl) ListScenarios() ([]model.Scenario, error) {
+	args := m.Called()
+	return args.Get(0).([]model.Scenario), args.Error(1)
+}
+
+func (m *TickerServiceMockImpl) NewChallenge(playerId string) (model.TickerChallengeResponse, error) {
+	args := m.Called(playerId)
+	return args.Get(0).(model.TickerChallengeResponse), args.Error(1)
+}
+func (m *TickerServiceMockImpl) Answer(id string, playerId string, optionId string) (model.TickerAnswerResponse, error) {
+	args := m.Called(id, playerId, optionId)
+	return args.Get(0).(model.TickerAnswerResponse), args.Error(1)
+}
+
+// testServedAt is the time every window is served by newServedRoundServiceMock
+var testServedAt = time.Date(2023, 10, 2, 9, 0, 0, 0, time.UTC)
+
+// newServedRoundServiceMock has served every window without hint, the solutions are saved
+func newServedRoundServiceMock() *ServedRoundServiceMockImpl {
+	mockServedRoundService := new(ServedRoundServiceMockImpl)
+	mockServedRoundService.On("SolveServedRound", mock.Anything, mock.Anything, mock.Anything, mock.Anything, 0).Return(newSolvedRound(testServedAt, time.Minute), nil)
+	return mockServedRoundService
+}
+
+// newSolvedRound is the round served at the time and solved after the elapsed time
+func newSolvedRound(servedAt time.Time, elapsed time.Duration) model.ServedRound {
+	solvedAt := servedAt.Add(elapsed)
+	return model.ServedRound{Id: "served-1", SymbolUUID: "uuid-aapl", AfterDate: "2023-10-02", ServedAt: servedAt, SolvedAt: &solvedAt}
+}
+
+// daysToGuess repeats the stock for every day to guess, one day apart from its date
+func daysToGuess(stock model.Stock) []model.Stock {
+	date, _ := time.Parse(time.DateOnly, stock.Date)
+	days := []model.Stock{}
+	for i := range model.User_stock_to_guess {
+		stock.Date = date.AddDate(0, 0, i).Format(time.DateOnly)
+		days = append(days, stock)
+	}
+	return days
+}
+
+// guessPrices is a guess for every day to guess, from the first price moving by step each day
+func guessPrices(first float64, step float64) []model.DayPrice {
+	prices := []model.DayPrice{}
+	for i := range model.User_stock_to_guess {
+		prices = append(prices, model.DayPrice{Day: model.Number_initial_stock_shown + i, Price: first + step*float64(i)})
+	}
+	return prices
+}
+
+// toJSON is the JSON of a value written in a request body
+func toJSON(value any) string {
+	data, _ := json.Marshal(value)
+	return string(data)
+}
+
+// newRoundServiceMock saves every round without ranking them
+func newRoundServiceMock() *RoundServiceMockImpl {
+	mockRoundService := new(RoundServiceMockImpl)
+	mockRoundService.On("SaveRound", mock.Anything).Return(model.Round{Id: "round-1"}, []model.Achievement{}, nil)
+	mockRoundService.On("GetPercentiles", mock.Anything).Return(model.SolutionPercentiles{})
+	return mockRoundService
+}
+
 func TestApiServerRequestGetStocks(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	t.Run("GetStocks", func(t *testing.T) {
@@ -91,11 +358,22 @@ func TestApiServerRequestGetStocks(t *testing.T) {
 			},
 		}
 
-		mockService.On("GetRandomStockWithRandomDayRange", 40).Return(expectedStocks)
+		mockService.On("GetRandomWindow", 40, "").Return(model.StockWindow{
+			Stocks: expectedStocks,
+			Rating: model.DifficultyRating{
+				Difficulty: model.Difficulty_medium,
+				Score:      2.5,
+				Features:   model.DifficultyFeatures{RealizedVolatility: 0.02, FutureVolatility: 0.0123},
+			},
+		})
+
+		mockServedRoundService := new(ServedRoundServiceMockImpl)
+		mockServedRoundService.On("ServeRound", "", expectedStocks).Return(model.ServedRound{Id: "served-1"}, nil)
 
 		handler := &SolutionHandler{
-			StockService: mockService,
-			ScoringLogic: nil, // Assuming you don't need this for the test
+			StockService:       mockService,
+			ScoringLogic:       nil, // Assuming you don't need this for the test
+			ServedRoundService: mockServedRoundService,
 		}
 
 		w := httptest.NewRecorder()
@@ -113,9 +391,216 @@ func TestApiServerRequestGetStocks(t *testing.T) {
 		body := w.Body.String()
 		assert.Contains(t, body, "AAPL")
 		assert.Contains(t, body, "GOOG")
+		response := model.StocksResponse{}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, expectedStocks, response.Stocks)
+		assert.Equal(t, model.Difficulty_medium, response.Round.Difficulty)
+		assert.Equal(t, "served-1", response.Round.Id)
+		// The volatility of the days to guess, and the score weighting it, stay on the server
+		assert.Equal(t, 0.02, response.Round.Features.RealizedVolatility)
+		assert.NotContains(t, body, "futureVolatility")
+		assert.NotContains(t, body, "0.0123")
+		assert.NotContains(t, body, `"score"`)
+
+		mockService.AssertExpectations(t)
+	})
+	t.Run("ServedRoundNotSaved", func(t *testing.T) {
+		stocks := []model.StockPublic{{Date: "2023-10-01", SymbolUUID: "uuid-aapl"}}
+		mockService := new(StockServiceMockImpl)
+		mockService.On("GetRandomWindow", 40, "").Return(model.StockWindow{Stocks: stocks})
+		mockServedRoundService := new(ServedRoundServiceMockImpl)
+		mockServedRoundService.On("ServeRound", "player-1", stocks).Return(model.ServedRound{}, errors.New("db error"))
+		handler := &SolutionHandler{StockService: mockService, ServedRoundService: mockServedRoundService}
+		w := httptest.NewRecorder()
+		router := SetupRouter(handler, isProduction)
+		req, _ := http.NewRequest(http.MethodGet, "/stocks", nil)
+		req.Header.Set(playerIdHeader, "player-1")
+
+		router.ServeHTTP(w, req)
+
+		// A window that cannot be solved is not sent
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.NotContains(t, w.Body.String(), "uuid-aapl")
+	})
+	t.Run("Timed", func(t *testing.T) {
+		stocks := []model.StockPublic{{Date: "2023-10-01", SymbolUUID: "uuid-aapl"}}
+		limits := map[string]int{model.Mode_price: 60}
+		mockService := new(StockServiceMockImpl)
+		mockService.On("GetRandomWindow", 40, "").Return(model.StockWindow{Stocks: stocks})
+		mockServedRoundService := new(ServedRoundServiceMockImpl)
+		mockServedRoundService.On("ServeRound", "player-1", stocks).Return(model.ServedRound{Id: "served-1"}, nil).Once()
+		mockServedRoundService.On("ServeRound", "player-1", stocks).Return(model.ServedRound{}, errors.New("db error"))
+		handler := &SolutionHandler{
+			StockService:       mockService,
+			ServedRoundService: mockServedRoundService,
+			RoundTiming:        model.RoundTimingConfig{Limits: limits},
+		}
+		router := SetupRouter(handler, isProduction)
+		get := func() *httptest.ResponseRecorder {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, "/stocks", nil)
+			req.Header.Set(playerIdHeader, "player-1")
+			router.ServeHTTP(w, req)
+			return w
+		}
+
+		w := get()
+		assert.Equal(t, http.StatusOK, w.Code)
+		response := model.StocksResponse{}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, limits, response.Round.TimeLimits)
+		// A timed round that cannot be saved could not be submitted
+		assert.Equal(t, http.StatusInternalServerError, get().Code)
+	})
+	t.Run("WithDifficulty", func(t *testing.T) {
+		mockService := new(StockServiceMockImpl)
+		mockService.On("GetRandomWindow", 40, model.Difficulty_hard).Return(model.StockWindow{Stocks: []model.StockPublic{}})
+		handler := &SolutionHandler{StockService: mockService}
+		w := httptest.NewRecorder()
+		router := SetupRouter(handler, isProduction)
+		req, _ := http.NewRequest(http.MethodGet, "/stocks?difficulty=HARD", nil)
 
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
 		mockService.AssertExpectations(t)
 	})
+	t.Run("InvalidDifficulty", func(t *testing.T) {
+		mockService := new(StockServiceMockImpl)
+		handler := &SolutionHandler{StockService: mockService}
+		w := httptest.NewRecorder()
+		router := SetupRouter(handler, isProduction)
+		req, _ := http.NewRequest(http.MethodGet, "/stocks?difficulty=extreme", nil)
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "Invalid difficulty")
+		mockService.AssertNotCalled(t, "GetRandomWindow", mock.Anything, mock.Anything)
+	})
+	t.Run("WithScenario", func(t *testing.T) {
+		scenario := model.Scenario{Id: "covid-2020", Name: "COVID drop", StartDate: "2020-02-19", EndDate: "2020-03-23"}
+		mockScenarioService := new(ScenarioServiceMockImpl)
+		mockScenarioService.On("GetScenario", "covid-2020").Return(scenario, nil)
+		mockService := new(StockServiceMockImpl)
+		mockService.On("GetScenarioWindow", scenario, 40, model.Difficulty_easy).Return(model.StockWindow{
+			Stocks: []model.StockPublic{{Date: "2020-03-02", SymbolUUID: "uuid-aapl"}},
+			Rating: model.DifficultyRating{Difficulty: model.Difficulty_easy},
+		})
+		mockServedRoundService := new(ServedRoundServiceMockImpl)
+		mockServedRoundService.On("ServeRound", "", mock.Anything).Return(model.ServedRound{Id: "served-1"}, nil)
+		handler := &SolutionHandler{StockService: mockService, ScenarioService: mockScenarioService, ServedRoundService: mockServedRoundService}
+		w := httptest.NewRecorder()
+		router := SetupRouter(handler, isProduction)
+		req, _ := http.NewRequest(http.MethodGet, "/stocks?scenario=covid-2020&difficulty=easy", nil)
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		response := model.StocksResponse{}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "COVID drop", response.Round.Scenario)
+		assert.Len(t, response.Stocks, 1)
+		mockService.AssertNotCalled(t, "GetRandomWindow", mock.Anything, mock.Anything)
+	})
+	t.Run("UnknownScenario", func(t *testing.T) {
+		mockScenarioService := new(ScenarioServiceMockImpl)
+		mockScenarioService.On("GetScenario", "unknown").Return(model.Scenario{}, dataaccess.ErrScenarioNotFound)
+		handler := &SolutionHandler{StockService: new(StockServiceMockImpl), ScenarioService: mockScenarioService}
+		w := httptest.NewRecorder()
+		router := SetupRouter(handler, isProduction)
+		req, _ := http.NewRequest(http.MethodGet, "/stocks?scenario=unknown", nil)
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestApiServerRequestScenarios(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Run("List", func(t *testing.T) {
+		mockScenarioService := new(ScenarioServiceMockImpl)
+		mockScenarioService.On("ListScenarios").Return([]model.Scenario{{Id: "crash-2008", Name: "2008 crash"}}, nil)
+		router := SetupRouter(&SolutionHandler{ScenarioService: mockScenarioService}, isProduction)
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/scenarios", nil)
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "crash-2008")
+	})
+	t.Run("CreateRequiresAdmin", func(t *testing.T) {
+		mockScenarioService := new(ScenarioServiceMockImpl)
+		router := SetupRouter(&SolutionHandler{ScenarioService: mockScenarioService, AdminToken: "secret"}, isProduction)
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/admin/scenarios", strings.NewReader(`{"id": "crash-2008"}`))
+		req.Header.Set("Content-Type", "application/json")
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		mockScenarioService.AssertNotCalled(t, "CreateScenario", mock.Anything)
+	})
+	t.Run("Create", func(t *testing.T) {
+		request := model.ScenarioRequest{Id: "crash-2008", Name: "2008 crash", StartDate: "2008-09-01", EndDate: "2009-03-09", Symbols: []string{"SPY"}}
+		mockScenarioService := new(ScenarioServiceMockImpl)
+		mockScenarioService.On("CreateScenario", request).Return(model.Scenario{Id: "crash-2008", Name: "2008 crash"}, nil)
+		router := SetupRouter(&SolutionHandler{ScenarioService: mockScenarioService, AdminToken: "secret"}, isProduction)
+		w := httptest.NewRecorder()
+		body := `{"id": "crash-2008", "name": "2008 crash", "startDate": "2008-09-01", "endDate": "2009-03-09", "symbols": ["SPY"]}`
+		req, _ := http.NewRequest(http.MethodPost, "/admin/scenarios", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer secret")
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		mockScenarioService.AssertExpectations(t)
+	})
+	t.Run("CreateExisting", func(t *testing.T) {
+		mockScenarioService := new(ScenarioServiceMockImpl)
+		mockScenarioService.On("CreateScenario", mock.Anything).Return(model.Scenario{}, dataaccess.ErrScenarioExists)
+		router := SetupRouter(&SolutionHandler{ScenarioService: mockScenarioService, AdminToken: "secret"}, isProduction)
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/admin/scenarios", strings.NewReader(`{"id": "crash-2008"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer secret")
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+	t.Run("UpdateInvalid", func(t *testing.T) {
+		mockScenarioService := new(ScenarioServiceMockImpl)
+		mockScenarioService.On("UpdateScenario", "crash-2008", mock.Anything).Return(model.Scenario{}, fmt.Errorf("%w: endDate must be after startDate", service.ErrInvalidScenario))
+		router := SetupRouter(&SolutionHandler{ScenarioService: mockScenarioService, AdminToken: "secret"}, isProduction)
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPut, "/admin/scenarios/crash-2008", strings.NewReader(`{"name": "2008 crash"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer secret")
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "endDate must be after startDate")
+	})
+	t.Run("Delete", func(t *testing.T) {
+		mockScenarioService := new(ScenarioServiceMockImpl)
+		mockScenarioService.On("DeleteScenario", "crash-2008").Return(nil)
+		mockScenarioService.On("DeleteScenario", "unknown").Return(dataaccess.ErrScenarioNotFound)
+		router := SetupRouter(&SolutionHandler{ScenarioService: mockScenarioService, AdminToken: "secret"}, isProduction)
+		for path, status := range map[string]int{"/admin/scenarios/crash-2008": http.StatusNoContent, "/admin/scenarios/unknown": http.StatusNotFound} {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodDelete, path, nil)
+			req.Header.Set("Authorization", "Bearer secret")
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, status, w.Code, path)
+		}
+	})
 }
 
 func TestApiServerRequestPostSolution(t *testing.T) {
@@ -248,19 +733,17 @@ func TestApiServerRequestPostSolution(t *testing.T) {
 				Volume:   1000,
 			},
 		})
-		mockService.On("GetStocksAfterDate", "AAPL", "2023-10-01").Return([]model.Stock{
-			{
-				Symbol:   "AAPL",
-				Date:     "2023-10-01",
-				Open:     150.0,
-				High:     155.0,
-				Low:      148.0,
-				Close:    152.0,
-				AdjClose: 152.0,
-				Volume:   1000,
-			},
-		})
-		mockScoringLogic.On("CalculateBollingerBands", mock.Anything, 20).Return(map[string]model.BollingerBand{
+		mockService.On("GetStocksAfterDate", "AAPL", "2023-10-01").Return(daysToGuess(model.Stock{
+			Symbol:   "AAPL",
+			Date:     "2023-10-01",
+			Open:     150.0,
+			High:     155.0,
+			Low:      148.0,
+			Close:    152.0,
+			AdjClose: 152.0,
+			Volume:   1000,
+		}))
+		mockScoringLogic.On("CalculateBollingerBandsWithConfig", mock.Anything, model.DefaultBollingerConfig).Return(map[string]model.BollingerBand{
 			"2023-10-01": {
 				LowerBand: 145.0,
 				UpperBand: 155.0,
@@ -275,8 +758,11 @@ func TestApiServerRequestPostSolution(t *testing.T) {
 		})
 
 		handler := &SolutionHandler{
-			StockService: mockService,
-			ScoringLogic: mockScoringLogic,
+			StockService:    mockService,
+			ScoringLogic:    mockScoringLogic,
+			BotLogic:        &logic.BotLogicImpl{},
+			DifficultyLogic: &logic.DifficultyLogicImpl{},
+			RoundService:    newRoundServiceMock(),
 		}
 		body := `{"symbolUUID": "AAPL", "afterDate": "2023-10-01", "estimatedDayPrices": []}`
 
@@ -287,12 +773,1793 @@ func TestApiServerRequestPostSolution(t *testing.T) {
 		c.Request = req
 		c.Request.Header.Set("Content-Type", "application/json")
 		handler.postSolution(c)
+		// The days to guess are missing
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		response := model.ValidationErrorResponse{}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, model.Error_invalid_solution, response.Code)
+		assert.Equal(t, []model.FieldError{{Field: "estimatedDayPrices", Message: fmt.Sprintf("expected %d days, 0 received", model.User_stock_to_guess)}}, response.Fields)
+		mockScoringLogic.AssertNotCalled(t, "GetScore", mock.Anything, mock.Anything, mock.Anything)
+	})
+	t.Run("InvalidEstimatedPrices", func(t *testing.T) {
+		mockService := new(StockServiceMockImpl)
+		mockService.On("GetStockInfo", "AAPL").Return(model.StockInfo{Symbol: "AAPL"}, nil)
+		mockService.On("GetStocksBeforeEqualDate", "AAPL", "2023-10-02").Return([]model.Stock{
+			{Symbol: "AAPL", Date: "2023-10-02", Close: 100.0},
+		})
+		mockService.On("GetStocksAfterDate", "AAPL", "2023-10-02").Return(daysToGuess(model.Stock{Symbol: "AAPL", Date: "2023-10-03", Close: 110.0}))
+		mockRoundService := newRoundServiceMock()
+		handler := &SolutionHandler{StockService: mockService, RoundService: mockRoundService}
+		prices := guessPrices(115, 0)
+		prices[1].Day, prices[1].Price = prices[0].Day, 1000
+		body := `{"symbolUUID": "AAPL", "afterDate": "2023-10-02", "estimatedDayPrices": ` + toJSON(prices) + `}`
+		w := httptest.NewRecorder()
+		router := SetupRouter(handler, isProduction)
+		req, _ := http.NewRequest(http.MethodPost, "/solution", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		response := model.ValidationErrorResponse{}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, []model.FieldError{
+			{Field: "estimatedDayPrices[1].day", Message: "day 40 is repeated"},
+			{Field: "estimatedDayPrices[1].price", Message: "price must be between 20.00 and 500.00"},
+		}, response.Fields)
+		mockRoundService.AssertNotCalled(t, "SaveRound", mock.Anything)
+	})
+	t.Run("IncompleteWindow", func(t *testing.T) {
+		mockService := new(StockServiceMockImpl)
+		mockService.On("GetStockInfo", "AAPL").Return(model.StockInfo{Symbol: "AAPL"}, nil)
+		mockService.On("GetStocksBeforeEqualDate", "AAPL", "2023-10-02").Return([]model.Stock{
+			{Symbol: "AAPL", Date: "2023-10-02", Close: 100.0},
+		})
+		// The date is too close to the last day loaded
+		mockService.On("GetStocksAfterDate", "AAPL", "2023-10-02").Return([]model.Stock{
+			{Symbol: "AAPL", Date: "2023-10-03", Close: 110.0},
+		})
+		mockScoringLogic := new(ScoringLogicMockImpl)
+		handler := &SolutionHandler{StockService: mockService, ScoringLogic: mockScoringLogic, RoundService: newRoundServiceMock()}
+		for _, prices := range [][]model.DayPrice{{}, guessPrices(100, 0)[:1], guessPrices(100, 0)} {
+			body := `{"symbolUUID": "AAPL", "afterDate": "2023-10-02", "estimatedDayPrices": ` + toJSON(prices) + `}`
+			w := httptest.NewRecorder()
+			router := SetupRouter(handler, isProduction)
+			req, _ := http.NewRequest(http.MethodPost, "/solution", strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusUnprocessableEntity, w.Code, body)
+			assert.NotContains(t, w.Body.String(), "110", body)
+		}
+		mockScoringLogic.AssertNotCalled(t, "GetScore", mock.Anything, mock.Anything, mock.Anything)
+	})
+	t.Run("BeatBots", func(t *testing.T) {
+		mockService := new(StockServiceMockImpl)
+		mockService.On("GetStockInfo", "AAPL").Return(model.StockInfo{
+			Symbol: "AAPL",
+			Name:   "Apple Inc.",
+		}, nil)
+		mockService.On("GetStocksBeforeEqualDate", "AAPL", "2023-10-02").Return([]model.Stock{
+			{Symbol: "AAPL", Date: "2023-10-02", Open: 100.0, High: 101.0, Low: 99.0, Close: 100.0, Volume: 1000},
+			{Symbol: "AAPL", Date: "2023-10-01", Open: 100.0, High: 101.0, Low: 99.0, Close: 100.0, Volume: 1000},
+		})
+		// The price jumps and keeps rising by 10 every day
+		after := daysToGuess(model.Stock{Symbol: "AAPL", Date: "2023-10-03", Volume: 1000})
+		for i := range after {
+			move := float64(10 * i)
+			after[i].Open, after[i].High, after[i].Low, after[i].Close = 110+move, 121+move, 109+move, 120+move
+		}
+		mockService.On("GetStocksAfterDate", "AAPL", "2023-10-02").Return(after)
+		handler := &SolutionHandler{
+			ServedRoundService: newServedRoundServiceMock(),
+			StockService:       mockService,
+			ScoringLogic:       &logic.ScoringLogicImpl{},
+			BotLogic:           &logic.BotLogicImpl{},
+			DifficultyLogic:    &logic.DifficultyLogicImpl{},
+			RoundService:       newRoundServiceMock(),
+		}
+		body := `{"symbolUUID": "AAPL", "afterDate": "2023-10-02", "estimatedDayPrices": ` + toJSON(guessPrices(115, 10)) + `}`
+		w := httptest.NewRecorder()
+		router := SetupRouter(handler, isProduction)
+		req, _ := http.NewRequest(http.MethodPost, "/solution", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		router.ServeHTTP(w, req)
+
 		assert.Equal(t, http.StatusOK, w.Code)
-		responseBody := w.Body.String()
-		assert.Contains(t, responseBody, "score")
-		assert.Contains(t, responseBody, "total")
-		assert.Contains(t, responseBody, "inLowHigh")
-		assert.Contains(t, responseBody, "inOpenClose")
-		assert.Contains(t, responseBody, "inDirection")
+		response := model.UserSolutionResponse{}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Len(t, response.Bots, 4)
+		// The history is flat so every bot stays around 100 and misses the jump
+		assert.Equal(t, model.BotSummary{Beaten: 4, Total: 4}, response.BotSummary)
+	})
+}
+
+func TestApiServerRequestPostSolutionHints(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	newHandler := func(served model.ServedRound, err error) (*SolutionHandler, *RoundServiceMockImpl) {
+		mockService := new(StockServiceMockImpl)
+		mockService.On("GetStockInfo", "uuid-aapl").Return(model.StockInfo{Symbol: "AAPL", SymbolUUID: "uuid-aapl"}, nil)
+		mockService.On("GetStocksBeforeEqualDate", "AAPL", "2023-10-02").Return([]model.Stock{
+			{Symbol: "AAPL", Date: "2023-10-02", Open: 100.0, High: 101.0, Low: 99.0, Close: 100.0, Volume: 1000},
+		})
+		mockService.On("GetStocksAfterDate", "AAPL", "2023-10-02").Return(daysToGuess(model.Stock{Symbol: "AAPL", Date: "2023-10-03", Open: 100.0, High: 101.0, Low: 99.0, Close: 100.0, Volume: 1000}))
+		mockServedRoundService := new(ServedRoundServiceMockImpl)
+		mockServedRoundService.On("SolveServedRound", mock.Anything, "player-1", "uuid-aapl", "2023-10-02", 0).Return(served, err)
+		mockRoundService := newRoundServiceMock()
+		return &SolutionHandler{
+			StockService:          mockService,
+			ScoringLogic:          &logic.ScoringLogicImpl{},
+			DirectionScoringLogic: &logic.DirectionScoringLogicImpl{},
+			BotLogic:              &logic.BotLogicImpl{},
+			DifficultyLogic:       &logic.DifficultyLogicImpl{},
+			RoundService:          mockRoundService,
+			ServedRoundService:    mockServedRoundService,
+		}, mockRoundService
+	}
+	post := func(handler *SolutionHandler, path string, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router := SetupRouter(handler, isProduction)
+		req, _ := http.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(playerIdHeader, "player-1")
+		router.ServeHTTP(w, req)
+		return w
+	}
+	priceBody := `{"symbolUUID": "uuid-aapl", "afterDate": "2023-10-02", "roundId": "served-1", "estimatedDayPrices": ` + toJSON(guessPrices(100, 0)) + `}`
+	served := newSolvedRound(testServedAt, time.Minute)
+	served.Hints = []string{model.Hint_next_close}
+	t.Run("PenaltyDeducted", func(t *testing.T) {
+		handler, mockRoundService := newHandler(served, nil)
+
+		w := post(handler, "/solution", priceBody)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		response := model.UserSolutionResponse{}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, 15, response.Score.HintPenalty)
+		mockRoundService.AssertCalled(t, "SaveRound", mock.MatchedBy(func(round model.Round) bool {
+			return slices.Equal(round.Hints, []string{model.Hint_next_close}) && round.Score.HintPenalty == 15
+		}))
+	})
+	t.Run("WithoutRoundId", func(t *testing.T) {
+		handler, _ := newHandler(served, nil)
+
+		w := post(handler, "/solution", `{"symbolUUID": "uuid-aapl", "afterDate": "2023-10-02", "estimatedDayPrices": `+toJSON(guessPrices(100, 0))+`}`)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		response := model.UserSolutionResponse{}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, 15, response.Score.HintPenalty)
+		handler.ServedRoundService.(*ServedRoundServiceMockImpl).AssertCalled(t, "SolveServedRound", "", "player-1", "uuid-aapl", "2023-10-02", 0)
+	})
+	t.Run("BotsBeforePenalty", func(t *testing.T) {
+		handler, _ := newHandler(served, nil)
+		// The guess beats every bot by 10 points but the penalty takes 15
+		mockScoringLogic := new(ScoringLogicMockImpl)
+		mockScoringLogic.On("CalculateBollingerBandsWithConfig", mock.Anything, model.DefaultBollingerConfig).Return(map[string]model.BollingerBand{})
+		mockScoringLogic.On("GetScore", guessPrices(100, 0), mock.Anything, mock.Anything).Return(model.UserScoreResponse{Total: 50}).Once()
+		mockScoringLogic.On("GetScore", mock.Anything, mock.Anything, mock.Anything).Return(model.UserScoreResponse{Total: 40})
+		mockScoringLogic.On("ApplyHintPenalty", 50, served.Hints).Return(35, 15)
+		handler.ScoringLogic = mockScoringLogic
+
+		w := post(handler, "/solution", priceBody)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		response := model.UserSolutionResponse{}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, 35, response.Score.Total)
+		assert.NotEmpty(t, response.Bots)
+		assert.Equal(t, len(response.Bots), response.BotSummary.Beaten)
+	})
+	t.Run("DirectionMode", func(t *testing.T) {
+		handler, _ := newHandler(served, nil)
+
+		w := post(handler, "/solution/direction", `{"symbolUUID": "uuid-aapl", "afterDate": "2023-10-02", "roundId": "served-1", "estimatedDayDirections": [{"day": 40, "direction": "flat"}]}`)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		response := model.UserDirectionSolutionResponse{}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, 15, response.Score.HintPenalty)
+	})
+	t.Run("OtherWindow", func(t *testing.T) {
+		handler, mockRoundService := newHandler(model.ServedRound{}, service.ErrServedRoundWindow)
+
+		w := post(handler, "/solution", priceBody)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockRoundService.AssertNotCalled(t, "SaveRound", mock.Anything)
+	})
+	t.Run("AlreadySolved", func(t *testing.T) {
+		handler, mockRoundService := newHandler(model.ServedRound{}, service.ErrServedRoundSolved)
+
+		w := post(handler, "/solution", priceBody)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		mockRoundService.AssertNotCalled(t, "SaveRound", mock.Anything)
+	})
+	t.Run("UnknownRound", func(t *testing.T) {
+		handler, _ := newHandler(model.ServedRound{}, dataaccess.ErrServedRoundNotFound)
+
+		w := post(handler, "/solution", priceBody)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestApiServerRequestPostSolutionTimed(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	timing := model.RoundTimingConfig{Limits: map[string]int{model.Mode_price: 60, model.Mode_direction: 30}, SpeedBonus: 20}
+	newHandler := func(served model.ServedRound, err error) (*SolutionHandler, *RoundServiceMockImpl) {
+		mockService := new(StockServiceMockImpl)
+		mockService.On("GetStockInfo", "uuid-aapl").Return(model.StockInfo{Symbol: "AAPL", SymbolUUID: "uuid-aapl"}, nil)
+		mockService.On("GetStocksBeforeEqualDate", "AAPL", "2023-10-02").Return([]model.Stock{
+			{Symbol: "AAPL", Date: "2023-10-02", Open: 100.0, High: 101.0, Low: 99.0, Close: 100.0, Volume: 1000},
+		})
+		mockService.On("GetStocksAfterDate", "AAPL", "2023-10-02").Return(daysToGuess(model.Stock{Symbol: "AAPL", Date: "2023-10-03", Open: 100.0, High: 101.0, Low: 99.0, Close: 100.0, Volume: 1000}))
+		mockServedRoundService := new(ServedRoundServiceMockImpl)
+		mockServedRoundService.On("SolveServedRound", "served-1", "player-1", "uuid-aapl", "2023-10-02", mock.Anything).Return(served, err)
+		mockRoundService := newRoundServiceMock()
+		return &SolutionHandler{
+			StockService:          mockService,
+			ScoringLogic:          &logic.ScoringLogicImpl{},
+			DirectionScoringLogic: &logic.DirectionScoringLogicImpl{},
+			BotLogic:              &logic.BotLogicImpl{},
+			DifficultyLogic:       &logic.DifficultyLogicImpl{},
+			RoundService:          mockRoundService,
+			ServedRoundService:    mockServedRoundService,
+			RoundTiming:           timing,
+		}, mockRoundService
+	}
+	post := func(handler *SolutionHandler, path string, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router := SetupRouter(handler, isProduction)
+		req, _ := http.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(playerIdHeader, "player-1")
+		router.ServeHTTP(w, req)
+		return w
+	}
+	priceBody := `{"symbolUUID": "uuid-aapl", "afterDate": "2023-10-02", "roundId": "served-1", "estimatedDayPrices": ` + toJSON(guessPrices(100, 0)) + `}`
+	t.Run("InTime", func(t *testing.T) {
+		handler, mockRoundService := newHandler(newSolvedRound(testServedAt, 15*time.Second), nil)
+
+		w := post(handler, "/solution", priceBody)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		response := model.UserSolutionResponse{}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, 15, response.Score.SpeedBonus)
+		mockRoundService.AssertCalled(t, "SaveRound", mock.MatchedBy(func(round model.Round) bool {
+			return round.ServedAt != nil && round.Score.SpeedBonus == 15
+		}))
+		handler.ServedRoundService.(*ServedRoundServiceMockImpl).AssertCalled(t, "SolveServedRound", "served-1", "player-1", "uuid-aapl", "2023-10-02", 60)
+	})
+	t.Run("Late", func(t *testing.T) {
+		handler, mockRoundService := newHandler(model.ServedRound{}, logic.ErrRoundExpired)
+
+		w := post(handler, "/solution", priceBody)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		assert.Contains(t, w.Body.String(), model.Error_round_expired)
+		mockRoundService.AssertNotCalled(t, "SaveRound", mock.Anything)
+	})
+	t.Run("SecondSubmission", func(t *testing.T) {
+		handler, mockRoundService := newHandler(model.ServedRound{}, service.ErrServedRoundSolved)
+
+		w := post(handler, "/solution", priceBody)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Contains(t, w.Body.String(), model.Error_round_solved)
+		mockRoundService.AssertNotCalled(t, "SaveRound", mock.Anything)
+	})
+	t.Run("WithoutRound", func(t *testing.T) {
+		handler, _ := newHandler(newSolvedRound(testServedAt, time.Second), nil)
+
+		w := post(handler, "/solution/direction", `{"symbolUUID": "uuid-aapl", "afterDate": "2023-10-02", "estimatedDayDirections": [{"day": 41, "direction": "up"}]}`)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), model.Error_round_required)
+	})
+	t.Run("ModeNotTimed", func(t *testing.T) {
+		handler, _ := newHandler(newSolvedRound(testServedAt, time.Hour), nil)
+		handler.CandleScoringLogic = &logic.CandleScoringLogicImpl{}
+
+		w := post(handler, "/solution/candle", `{"symbolUUID": "uuid-aapl", "afterDate": "2023-10-02", "roundId": "served-1", "estimatedDayCandles": []}`)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		response := model.UserCandleSolutionResponse{}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, 0, response.Score.SpeedBonus)
+	})
+}
+
+func TestApiServerRequestPostHint(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Run("Reveal", func(t *testing.T) {
+		nextClose := 101.5
+		mockServedRoundService := new(ServedRoundServiceMockImpl)
+		mockServedRoundService.On("RequestHint", "served-1", "player-1", model.Hint_next_close).Return(model.HintResponse{
+			Type:      model.Hint_next_close,
+			NextClose: &nextClose,
+			Penalty:   15,
+			Hints:     []string{model.Hint_next_close},
+		}, nil)
+		router := SetupRouter(&SolutionHandler{ServedRoundService: mockServedRoundService}, isProduction)
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/stocks/served-1/hints", strings.NewReader(`{"type": "next_close"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(playerIdHeader, "player-1")
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"nextClose": 101.5`)
+	})
+	t.Run("Errors", func(t *testing.T) {
+		mockServedRoundService := new(ServedRoundServiceMockImpl)
+		mockServedRoundService.On("RequestHint", "served-1", "", "answer").Return(model.HintResponse{}, service.ErrInvalidHint)
+		mockServedRoundService.On("RequestHint", "unknown", "", model.Hint_rsi).Return(model.HintResponse{}, dataaccess.ErrServedRoundNotFound)
+		router := SetupRouter(&SolutionHandler{ServedRoundService: mockServedRoundService}, isProduction)
+		cases := map[string]int{
+			`{"type": "answer"}`: http.StatusBadRequest,
+			`{"type": "rsi"}`:    http.StatusNotFound,
+		}
+		for body, status := range cases {
+			path := "/stocks/served-1/hints"
+			if status == http.StatusNotFound {
+				path = "/stocks/unknown/hints"
+			}
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodPost, path, strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, status, w.Code, body)
+		}
+	})
+}
+
+func TestApiServerRequestPostSolutionBands(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(StockServiceMockImpl)
+	mockScoringLogic := new(ScoringLogicMockImpl)
+	mockService.On("GetStockInfo", "AAPL").Return(model.StockInfo{
+		Symbol: "AAPL",
+		Name:   "Apple Inc.",
+	}, nil)
+	mockService.On("GetStocksBeforeEqualDate", "AAPL", "2023-10-01").Return([]model.Stock{
+		{Symbol: "AAPL", Date: "2023-10-01", Open: 150.0, High: 155.0, Low: 148.0, Close: 150.0, Volume: 1000},
+	})
+	mockService.On("GetStocksAfterDate", "AAPL", "2023-10-01").Return(daysToGuess(model.Stock{Symbol: "AAPL", Date: "2023-10-02", Open: 150.0, High: 158.0, Low: 150.0, Close: 100.0, Volume: 1000}))
+	mockScoringLogic.On("CalculateBollingerBandsWithConfig", mock.Anything, model.DefaultBollingerConfig).Return(map[string]model.BollingerBand{})
+	mockScoringLogic.On("GetScore", mock.Anything, mock.Anything, mock.Anything).Return(model.UserScoreResponse{})
+	handler := &SolutionHandler{
+		ServedRoundService: newServedRoundServiceMock(),
+		StockService:       mockService,
+		ScoringLogic:       mockScoringLogic,
+		BandScoringLogic:   &logic.BandScoringLogicImpl{},
+		BotLogic:           &logic.BotLogicImpl{},
+		DifficultyLogic:    &logic.DifficultyLogicImpl{},
+		RoundService:       newRoundServiceMock(),
+	}
+	bands := []model.DayBand{}
+	for _, price := range guessPrices(100, 0) {
+		bands = append(bands, model.DayBand{Day: price.Day, Lower: 99, Upper: 101})
+	}
+	body := `{"symbolUUID": "AAPL", "afterDate": "2023-10-01", "estimatedDayPrices": ` + toJSON(guessPrices(100, 0)) + `, "estimatedDayBands": ` + toJSON(bands) + `}`
+	w := httptest.NewRecorder()
+	router := SetupRouter(handler, isProduction)
+	req, _ := http.NewRequest(http.MethodPost, "/solution", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	response := model.UserSolutionResponse{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, bands, response.Bands)
+	// Every close is inside its band, each one scoring 9
+	if assert.NotNil(t, response.BandScore) {
+		assert.Equal(t, 9*model.User_stock_to_guess, response.BandScore.Total)
+		assert.Equal(t, model.User_stock_to_guess, response.BandScore.InBand)
+	}
+}
+
+func TestApiServerRequestPostSolutionInvalidBands(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(StockServiceMockImpl)
+	mockScoringLogic := new(ScoringLogicMockImpl)
+	mockService.On("GetStockInfo", "AAPL").Return(model.StockInfo{Symbol: "AAPL"}, nil)
+	mockService.On("GetStocksBeforeEqualDate", "AAPL", "2023-10-01").Return([]model.Stock{
+		{Symbol: "AAPL", Date: "2023-10-01", Open: 150.0, High: 155.0, Low: 148.0, Close: 150.0, Volume: 1000},
+	})
+	mockService.On("GetStocksAfterDate", "AAPL", "2023-10-01").Return(daysToGuess(model.Stock{Symbol: "AAPL", Date: "2023-10-02", Open: 150.0, High: 158.0, Low: 150.0, Close: 100.0, Volume: 1000}))
+	handler := &SolutionHandler{
+		ServedRoundService: newServedRoundServiceMock(),
+		StockService:       mockService,
+		ScoringLogic:       mockScoringLogic,
+		BandScoringLogic:   &logic.BandScoringLogicImpl{},
+		BotLogic:           &logic.BotLogicImpl{},
+		DifficultyLogic:    &logic.DifficultyLogicImpl{},
+		RoundService:       newRoundServiceMock(),
+	}
+	// The lower bound is above the upper one
+	bands := []model.DayBand{}
+	for _, price := range guessPrices(100, 0) {
+		bands = append(bands, model.DayBand{Day: price.Day, Lower: 99, Upper: 101})
+	}
+	bands[0].Lower, bands[0].Upper = 101, 99
+	body := `{"symbolUUID": "AAPL", "afterDate": "2023-10-01", "estimatedDayPrices": ` + toJSON(guessPrices(100, 0)) + `, "estimatedDayBands": ` + toJSON(bands) + `}`
+	w := httptest.NewRecorder()
+	router := SetupRouter(handler, isProduction)
+	req, _ := http.NewRequest(http.MethodPost, "/solution", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	response := model.ValidationErrorResponse{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, model.Error_invalid_solution, response.Code)
+	assert.Equal(t, []model.FieldError{{Field: "estimatedDayBands[0].upper", Message: "upper must not be below lower"}}, response.Fields)
+	mockScoringLogic.AssertNotCalled(t, "GetScore", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestBollingerConfigDefaults(t *testing.T) {
+	handler := &SolutionHandler{}
+	assert.Equal(t, model.DefaultBollingerConfig, handler.bollingerConfig())
+	// A partial configuration keeps its fields and takes the others from the default
+	handler.BollingerConfig = model.BollingerConfig{Multiplier: 3, IncludeCurrentDay: true}
+	assert.Equal(t, model.BollingerConfig{Period: 20, Multiplier: 3, IncludeCurrentDay: true}, handler.bollingerConfig())
+}
+
+func TestApiServerRequestPostSolutionPercentiles(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	setupMocks := func() (*StockServiceMockImpl, *ScoringLogicMockImpl) {
+		mockService := new(StockServiceMockImpl)
+		mockScoringLogic := new(ScoringLogicMockImpl)
+		mockService.On("GetStockInfo", "uuid-aapl").Return(model.StockInfo{
+			Symbol:     "AAPL",
+			Name:       "Apple Inc.",
+			SymbolUUID: "uuid-aapl",
+		}, nil)
+		mockService.On("GetStocksBeforeEqualDate", "AAPL", "2023-10-01").Return([]model.Stock{})
+		mockService.On("GetStocksAfterDate", "AAPL", "2023-10-01").Return(daysToGuess(model.Stock{Symbol: "AAPL", Date: "2023-10-02", Close: 100}))
+		mockScoringLogic.On("CalculateBollingerBandsWithConfig", mock.Anything, model.DefaultBollingerConfig).Return(map[string]model.BollingerBand{})
+		mockScoringLogic.On("GetScore", mock.Anything, mock.Anything, mock.Anything).Return(model.UserScoreResponse{Total: 42})
+		return mockService, mockScoringLogic
+	}
+	// The window has no day shown so any positive price is accepted
+	body := `{"symbolUUID": "uuid-aapl", "afterDate": "2023-10-01", "estimatedDayPrices": ` + toJSON(guessPrices(100, 0)) + `}`
+	t.Run("SavedAndRanked", func(t *testing.T) {
+		mockService, mockScoringLogic := setupMocks()
+		mockRoundService := new(RoundServiceMockImpl)
+		expectedRound := model.Round{
+			PlayerId:      "player-1",
+			Mode:          model.Mode_price,
+			Symbol:        "AAPL",
+			SymbolUUID:    "uuid-aapl",
+			AfterDate:     "2023-10-01",
+			Difficulty:    model.Difficulty_easy, // The prices do not move
+			FutureEndDate: "2023-10-11",
+			Score:         model.UserScoreResponse{Total: 42},
+			DayPrice:      guessPrices(100, 0),
+			Actual:        slices.Repeat([]float64{100}, model.User_stock_to_guess),
+			ServedAt:      &testServedAt,
+		}
+		savedRound := expectedRound
+		savedRound.Id = "round-1"
+		unlocked := []model.Achievement{{Id: "first_round", Name: "First guess", Target: 1}}
+		mockRoundService.On("SaveRound", expectedRound).Return(savedRound, unlocked, nil)
+		mockRoundService.On("GetPercentiles", savedRound).Return(model.SolutionPercentiles{
+			Window: &model.PercentileRank{Percentile: 73, Players: 100},
+		})
+		handler := &SolutionHandler{
+			ServedRoundService: newServedRoundServiceMock(),
+			StockService:       mockService,
+			ScoringLogic:       mockScoringLogic,
+			BotLogic:           &logic.BotLogicImpl{},
+			DifficultyLogic:    &logic.DifficultyLogicImpl{},
+			RoundService:       mockRoundService,
+		}
+		w := httptest.NewRecorder()
+		router := SetupRouter(handler, isProduction)
+		req, _ := http.NewRequest(http.MethodPost, "/solution", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Player-ID", "player-1")
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		response := model.UserSolutionResponse{}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "round-1", response.RoundId)
+		if assert.NotNil(t, response.Percentiles) && assert.NotNil(t, response.Percentiles.Window) {
+			assert.Equal(t, 73.0, response.Percentiles.Window.Percentile)
+		}
+		assert.Equal(t, unlocked, response.Achievements)
+		mockRoundService.AssertExpectations(t)
+	})
+	t.Run("NotServed", func(t *testing.T) {
+		mockService, mockScoringLogic := setupMocks()
+		mockRoundService := newRoundServiceMock()
+		mockServedRoundService := new(ServedRoundServiceMockImpl)
+		mockServedRoundService.On("SolveServedRound", "", "player-1", "uuid-aapl", "2023-10-01", 0).Return(model.ServedRound{}, dataaccess.ErrServedRoundNotFound)
+		handler := &SolutionHandler{
+			ServedRoundService: mockServedRoundService,
+			StockService:       mockService,
+			ScoringLogic:       mockScoringLogic,
+			BotLogic:           &logic.BotLogicImpl{},
+			DifficultyLogic:    &logic.DifficultyLogicImpl{},
+			RoundService:       mockRoundService,
+		}
+		w := httptest.NewRecorder()
+		router := SetupRouter(handler, isProduction)
+		req, _ := http.NewRequest(http.MethodPost, "/solution", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Player-ID", "player-1")
+
+		router.ServeHTTP(w, req)
+
+		// The future prices of a window are only revealed for a round served
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Contains(t, w.Body.String(), model.Error_round_not_served)
+		assert.NotContains(t, w.Body.String(), "stocks")
+		mockRoundService.AssertNotCalled(t, "SaveRound", mock.Anything)
+	})
+	t.Run("SaveError", func(t *testing.T) {
+		mockService, mockScoringLogic := setupMocks()
+		mockRoundService := new(RoundServiceMockImpl)
+		mockRoundService.On("SaveRound", mock.Anything).Return(model.Round{}, []model.Achievement(nil), errors.New("db error"))
+		handler := &SolutionHandler{
+			ServedRoundService: newServedRoundServiceMock(),
+			StockService:       mockService,
+			ScoringLogic:       mockScoringLogic,
+			BotLogic:           &logic.BotLogicImpl{},
+			DifficultyLogic:    &logic.DifficultyLogicImpl{},
+			RoundService:       mockRoundService,
+		}
+		w := httptest.NewRecorder()
+		router := SetupRouter(handler, isProduction)
+		req, _ := http.NewRequest(http.MethodPost, "/solution", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		router.ServeHTTP(w, req)
+
+		// The score is still returned without the ranking
+		assert.Equal(t, http.StatusOK, w.Code)
+		response := model.UserSolutionResponse{}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, 42, response.Score.Total)
+		assert.Empty(t, response.RoundId)
+		assert.Nil(t, response.Percentiles)
+		mockRoundService.AssertNotCalled(t, "GetPercentiles", mock.Anything)
+	})
+}
+
+func TestApiServerRequestPostSolutionIndicators(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	setupMocks := func() (*StockServiceMockImpl, *ScoringLogicMockImpl) {
+		mockService := new(StockServiceMockImpl)
+		mockScoringLogic := new(ScoringLogicMockImpl)
+		mockService.On("GetStockInfo", "AAPL").Return(model.StockInfo{
+			Symbol: "AAPL",
+			Name:   "Apple Inc.",
+		}, nil)
+		mockService.On("GetStocksBeforeEqualDate", "AAPL", "2023-10-02").Return([]model.Stock{
+			{Symbol: "AAPL", Date: "2023-10-02", Open: 151.0, High: 155.0, Low: 148.0, Close: 152.0, Volume: 1000},
+			{Symbol: "AAPL", Date: "2023-10-01", Open: 150.0, High: 155.0, Low: 148.0, Close: 150.0, Volume: 1000},
+		})
+		mockService.On("GetStocksAfterDate", "AAPL", "2023-10-02").Return(daysToGuess(model.Stock{Symbol: "AAPL", Date: "2023-10-03", Open: 152.0, High: 158.0, Low: 150.0, Close: 157.0, Volume: 1000}))
+		mockScoringLogic.On("CalculateBollingerBandsWithConfig", mock.Anything, model.DefaultBollingerConfig).Return(map[string]model.BollingerBand{})
+		mockScoringLogic.On("GetScore", mock.Anything, mock.Anything, mock.Anything).Return(model.UserScoreResponse{})
+		return mockService, mockScoringLogic
+	}
+	t.Run("WithIndicators", func(t *testing.T) {
+		mockService, mockScoringLogic := setupMocks()
+		handler := &SolutionHandler{
+			ServedRoundService: newServedRoundServiceMock(),
+			StockService:       mockService,
+			ScoringLogic:       mockScoringLogic,
+			IndicatorLogic:     &indicators.IndicatorLogicImpl{},
+			BotLogic:           &logic.BotLogicImpl{},
+			DifficultyLogic:    &logic.DifficultyLogicImpl{},
+			RoundService:       newRoundServiceMock(),
+		}
+		body := `{"symbolUUID": "AAPL", "afterDate": "2023-10-02", "estimatedDayPrices": ` + toJSON(guessPrices(155, 0)) + `, "indicators": ["sma:2"]}`
+		w := httptest.NewRecorder()
+		router := SetupRouter(handler, isProduction)
+		req, _ := http.NewRequest(http.MethodPost, "/solution", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		response := model.UserSolutionResponse{}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		sma := response.Indicators["sma:2"].Lines["sma"]
+		// The history is sent back in chronological order before the days to guess
+		if assert.Len(t, sma, 1+model.User_stock_to_guess) {
+			assert.Equal(t, []model.IndicatorPoint{
+				{Date: "2023-10-02", Value: 151},
+				{Date: "2023-10-03", Value: 154.5},
+				{Date: "2023-10-04", Value: 157},
+			}, sma[:3])
+		}
+	})
+	t.Run("InvalidIndicator", func(t *testing.T) {
+		mockService, mockScoringLogic := setupMocks()
+		handler := &SolutionHandler{
+			StockService:    mockService,
+			ScoringLogic:    mockScoringLogic,
+			IndicatorLogic:  &indicators.IndicatorLogicImpl{},
+			BotLogic:        &logic.BotLogicImpl{},
+			DifficultyLogic: &logic.DifficultyLogicImpl{},
+			RoundService:    newRoundServiceMock(),
+		}
+		body := `{"symbolUUID": "AAPL", "afterDate": "2023-10-02", "estimatedDayPrices": ` + toJSON(guessPrices(155, 0)) + `, "indicators": ["unknown"]}`
+		w := httptest.NewRecorder()
+		router := SetupRouter(handler, isProduction)
+		req, _ := http.NewRequest(http.MethodPost, "/solution", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "Invalid indicators")
+	})
+}
+
+func TestApiServerRequestPostCandleSolution(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Run("InvalidBody", func(t *testing.T) {
+		handler := &SolutionHandler{
+			StockService:       new(StockServiceMockImpl),
+			CandleScoringLogic: &logic.CandleScoringLogicImpl{},
+		}
+		w := httptest.NewRecorder()
+		router := SetupRouter(handler, isProduction)
+		req, _ := http.NewRequest(http.MethodPost, "/solution/candle", strings.NewReader("{invalid json}"))
+		req.Header.Set("Content-Type", "application/json")
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "Invalid JSON")
+	})
+	t.Run("ScoreCandles", func(t *testing.T) {
+		mockService := new(StockServiceMockImpl)
+		mockService.On("GetStockInfo", "AAPL").Return(model.StockInfo{
+			Symbol: "AAPL",
+			Name:   "Apple Inc.",
+		}, nil)
+		mockService.On("GetStocksBeforeEqualDate", "AAPL", "2023-10-01").Return([]model.Stock{
+			{Symbol: "AAPL", Date: "2023-10-01", Open: 150.0, High: 155.0, Low: 148.0, Close: 152.0, Volume: 1000},
+		})
+		mockService.On("GetStocksAfterDate", "AAPL", "2023-10-01").Return([]model.Stock{
+			{Symbol: "AAPL", Date: "2023-10-02", Open: 152.0, High: 158.0, Low: 150.0, Close: 157.0, Volume: 1000},
+		})
+		handler := &SolutionHandler{
+			ServedRoundService: newServedRoundServiceMock(),
+			StockService:       mockService,
+			CandleScoringLogic: &logic.CandleScoringLogicImpl{},
+		}
+		body := `{"symbolUUID": "AAPL", "afterDate": "2023-10-01", "estimatedDayCandles": [{"day": 41, "open": 152, "high": 158, "low": 150, "close": 157}]}`
+		w := httptest.NewRecorder()
+		router := SetupRouter(handler, isProduction)
+		req, _ := http.NewRequest(http.MethodPost, "/solution/candle", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		response := model.UserCandleSolutionResponse{}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "AAPL", response.Symbol)
+		assert.Equal(t, 25, response.Score.Total)
+		assert.Equal(t, 5, response.Score.InColor)
+		mockService.AssertExpectations(t)
+	})
+}
+
+func TestApiServerRequestPostDirectionSolution(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Run("InvalidDirection", func(t *testing.T) {
+		handler := &SolutionHandler{
+			StockService:          new(StockServiceMockImpl),
+			DirectionScoringLogic: &logic.DirectionScoringLogicImpl{},
+		}
+		body := `{"symbolUUID": "AAPL", "afterDate": "2023-10-01", "estimatedDayDirections": [{"day": 41, "direction": "sideways"}]}`
+		w := httptest.NewRecorder()
+		router := SetupRouter(handler, isProduction)
+		req, _ := http.NewRequest(http.MethodPost, "/solution/direction", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "Invalid direction")
+	})
+	t.Run("ScoreDirections", func(t *testing.T) {
+		mockService := new(StockServiceMockImpl)
+		mockService.On("GetStockInfo", "AAPL").Return(model.StockInfo{
+			Symbol: "AAPL",
+			Name:   "Apple Inc.",
+		}, nil)
+		mockService.On("GetStocksBeforeEqualDate", "AAPL", "2023-10-01").Return([]model.Stock{
+			{Symbol: "AAPL", Date: "2023-10-01", Open: 150.0, High: 155.0, Low: 148.0, Close: 150.0, Volume: 1000},
+		})
+		mockService.On("GetStocksAfterDate", "AAPL", "2023-10-01").Return([]model.Stock{
+			{Symbol: "AAPL", Date: "2023-10-02", Open: 150.0, High: 158.0, Low: 150.0, Close: 157.0, Volume: 1000},
+			{Symbol: "AAPL", Date: "2023-10-03", Open: 157.0, High: 158.0, Low: 150.0, Close: 151.0, Volume: 1000},
+		})
+		handler := &SolutionHandler{
+			ServedRoundService:    newServedRoundServiceMock(),
+			StockService:          mockService,
+			DirectionScoringLogic: &logic.DirectionScoringLogicImpl{},
+		}
+		body := `{"symbolUUID": "AAPL", "afterDate": "2023-10-01", "estimatedDayDirections": [{"day": 41, "direction": "UP"}, {"day": 42, "direction": "down"}]}`
+		w := httptest.NewRecorder()
+		router := SetupRouter(handler, isProduction)
+		req, _ := http.NewRequest(http.MethodPost, "/solution/direction", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		response := model.UserDirectionSolutionResponse{}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, []string{model.Direction_up, model.Direction_down}, response.Directions)
+		assert.Equal(t, 2, response.Score.Correct)
+		assert.Equal(t, 22, response.Score.Total)
+	})
+}
+
+// Ids of saved rounds are UUID
+const roundId = "6f1c2a7e-3b4d-4e5f-8a9b-0c1d2e3f4a5b"
+const unknownRoundId = "00000000-0000-4000-8000-000000000000"
+
+func TestApiServerRequestGetRound(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	round := model.Round{
+		Id:               roundId,
+		Mode:             model.Mode_price,
+		Symbol:           "AAPL",
+		SymbolUUID:       "uuid-aapl",
+		AfterDate:        "2023-10-02",
+		HistoryStartDate: "2023-10-01",
+		FutureEndDate:    "2023-10-03",
+		Difficulty:       model.Difficulty_medium,
+		Score:            model.UserScoreResponse{Total: 42},
+		DayPrice:         []model.DayPrice{{Day: 41, Price: 155}},
+	}
+	prices := []model.Stock{
+		{Symbol: "AAPL", Date: "2023-10-01T00:00:00Z", Open: 150.0, High: 155.0, Low: 148.0, Close: 150.0, Volume: 1000},
+		{Symbol: "AAPL", Date: "2023-10-02T00:00:00Z", Open: 151.0, High: 155.0, Low: 148.0, Close: 152.0, Volume: 1000},
+		{Symbol: "AAPL", Date: "2023-10-03T00:00:00Z", Open: 152.0, High: 158.0, Low: 150.0, Close: 157.0, Volume: 1000},
+	}
+	t.Run("Replay", func(t *testing.T) {
+		mockService := new(StockServiceMockImpl)
+		mockService.On("GetStockInfo", "uuid-aapl").Return(model.StockInfo{Symbol: "AAPL", Name: "Apple Inc.", SymbolUUID: "uuid-aapl"}, nil)
+		mockService.On("GetStockPriceForTimeRange", "AAPL", "2023-10-01", "2023-10-03").Return(prices)
+		mockRoundService := new(RoundServiceMockImpl)
+		mockRoundService.On("GetRound", roundId).Return(round, nil)
+		handler := &SolutionHandler{
+			StockService:   mockService,
+			ScoringLogic:   &logic.ScoringLogicImpl{},
+			IndicatorLogic: &indicators.IndicatorLogicImpl{},
+			RoundService:   mockRoundService,
+		}
+		w := httptest.NewRecorder()
+		router := SetupRouter(handler, isProduction)
+		req, _ := http.NewRequest(http.MethodGet, "/rounds/"+roundId+"?indicators=sma:2", nil)
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		response := model.RoundReplayResponse{}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "Apple Inc.", response.Name)
+		assert.Equal(t, prices[:2], response.History)
+		assert.Equal(t, prices[2:], response.Stocks)
+		assert.Equal(t, round.DayPrice, response.DayPrice)
+		assert.Equal(t, 42, response.Score.Total)
+		assert.Len(t, response.Indicators["sma:2"].Lines["sma"], 2)
+	})
+	t.Run("NotFound", func(t *testing.T) {
+		mockRoundService := new(RoundServiceMockImpl)
+		mockRoundService.On("GetRound", unknownRoundId).Return(model.Round{}, dataaccess.ErrRoundNotFound)
+		handler := &SolutionHandler{
+			StockService: new(StockServiceMockImpl),
+			RoundService: mockRoundService,
+		}
+		w := httptest.NewRecorder()
+		router := SetupRouter(handler, isProduction)
+		req, _ := http.NewRequest(http.MethodGet, "/rounds/"+unknownRoundId, nil)
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Contains(t, w.Body.String(), "Round not found")
+	})
+	t.Run("InvalidId", func(t *testing.T) {
+		mockRoundService := new(RoundServiceMockImpl)
+		handler := &SolutionHandler{RoundService: mockRoundService}
+		w := httptest.NewRecorder()
+		router := SetupRouter(handler, isProduction)
+		req, _ := http.NewRequest(http.MethodGet, "/rounds/..%2F..%2Fetc/card.png", nil)
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		mockRoundService.AssertNotCalled(t, "GetRound", mock.Anything)
+	})
+}
+
+func TestApiServerRequestGetRoundCard(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	round := model.Round{
+		Id:         roundId,
+		Mode:       model.Mode_price,
+		Symbol:     "AAPL",
+		SymbolUUID: "uuid-aapl",
+		AfterDate:  "2023-10-02",
+		Score:      model.UserScoreResponse{Total: 42},
+		DayPrice:   []model.DayPrice{{Day: 41, Price: 155}},
+	}
+	setupHandler := func() (*SolutionHandler, *RoundServiceMockImpl) {
+		mockService := new(StockServiceMockImpl)
+		mockService.On("GetStockInfo", "uuid-aapl").Return(model.StockInfo{Symbol: "AAPL", Name: "Apple Inc.", SymbolUUID: "uuid-aapl"}, nil)
+		mockService.On("GetStocksBeforeEqualDate", "AAPL", "2023-10-02").Return([]model.Stock{
+			{Symbol: "AAPL", Date: "2023-10-02", Close: 152.0},
+			{Symbol: "AAPL", Date: "2023-10-01", Close: 150.0},
+		})
+		mockService.On("GetStocksAfterDate", "AAPL", "2023-10-02").Return([]model.Stock{
+			{Symbol: "AAPL", Date: "2023-10-03", Close: 157.0},
+		})
+		mockRoundService := new(RoundServiceMockImpl)
+		mockRoundService.On("GetRound", roundId).Return(round, nil)
+		return &SolutionHandler{
+			StockService: mockService,
+			RoundService: mockRoundService,
+			CardLogic:    &card.CardLogicImpl{},
+			CardCache:    &card.CardCache{Dir: t.TempDir()},
+		}, mockRoundService
+	}
+	t.Run("PngCached", func(t *testing.T) {
+		handler, mockRoundService := setupHandler()
+		router := SetupRouter(handler, isProduction)
+		for i := 0; i < 2; i++ {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, "/rounds/"+roundId+"/card.png", nil)
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, "image/png", w.Header().Get("Content-Type"))
+			assert.True(t, bytes.HasPrefix(w.Body.Bytes(), []byte("\x89PNG")))
+		}
+		// The second card comes from the cache
+		mockRoundService.AssertNumberOfCalls(t, "GetRound", 1)
+	})
+	t.Run("Svg", func(t *testing.T) {
+		handler, _ := setupHandler()
+		w := httptest.NewRecorder()
+		router := SetupRouter(handler, isProduction)
+		req, _ := http.NewRequest(http.MethodGet, "/rounds/"+roundId+"/card.svg", nil)
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "image/svg+xml", w.Header().Get("Content-Type"))
+		assert.Contains(t, w.Body.String(), "SCORE 42")
+	})
+}
+
+func TestApiServerRequestGetMyRounds(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Run("Paginated", func(t *testing.T) {
+		mockRoundService := new(RoundServiceMockImpl)
+		mockRoundService.On("GetPlayerRounds", "player-1", 3, maxRoundsPageSize).Return(model.PlayerRoundsResponse{
+			Rounds:   []model.Round{{Id: "round-1"}},
+			Page:     3,
+			PageSize: maxRoundsPageSize,
+			Total:    201,
+		}, nil)
+		handler := &SolutionHandler{RoundService: mockRoundService}
+		w := httptest.NewRecorder()
+		router := SetupRouter(handler, isProduction)
+		req, _ := http.NewRequest(http.MethodGet, "/me/rounds?page=3&pageSize=500", nil)
+		req.Header.Set("X-Player-ID", "player-1")
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		response := model.PlayerRoundsResponse{}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, 201, response.Total)
+		assert.Equal(t, "round-1", response.Rounds[0].Id)
+		mockRoundService.AssertExpectations(t)
+	})
+	t.Run("DefaultPage", func(t *testing.T) {
+		mockRoundService := new(RoundServiceMockImpl)
+		mockRoundService.On("GetPlayerRounds", "player-1", 1, defaultRoundsPageSize).Return(model.PlayerRoundsResponse{}, nil)
+		handler := &SolutionHandler{RoundService: mockRoundService}
+		w := httptest.NewRecorder()
+		router := SetupRouter(handler, isProduction)
+		req, _ := http.NewRequest(http.MethodGet, "/me/rounds", nil)
+		req.Header.Set("X-Player-ID", "player-1")
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockRoundService.AssertExpectations(t)
+	})
+	t.Run("InvalidPage", func(t *testing.T) {
+		mockRoundService := new(RoundServiceMockImpl)
+		handler := &SolutionHandler{RoundService: mockRoundService}
+		w := httptest.NewRecorder()
+		router := SetupRouter(handler, isProduction)
+		req, _ := http.NewRequest(http.MethodGet, "/me/rounds?page=0", nil)
+		req.Header.Set("X-Player-ID", "player-1")
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "invalid page")
+	})
+	t.Run("MissingPlayer", func(t *testing.T) {
+		mockRoundService := new(RoundServiceMockImpl)
+		handler := &SolutionHandler{RoundService: mockRoundService}
+		w := httptest.NewRecorder()
+		router := SetupRouter(handler, isProduction)
+		req, _ := http.NewRequest(http.MethodGet, "/me/rounds", nil)
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "X-Player-ID")
+		mockRoundService.AssertNotCalled(t, "GetPlayerRounds", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestApiServerRequestGetMyStats(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Run("Stats", func(t *testing.T) {
+		mape := 3.2
+		mockRoundService := new(RoundServiceMockImpl)
+		mockRoundService.On("GetPlayerStats", "player-1").Return(model.PlayerStats{Rounds: 4, MAPE: &mape}, nil)
+		handler := &SolutionHandler{RoundService: mockRoundService}
+		w := httptest.NewRecorder()
+		router := SetupRouter(handler, isProduction)
+		req, _ := http.NewRequest(http.MethodGet, "/me/stats", nil)
+		req.Header.Set("X-Player-ID", "player-1")
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		response := model.PlayerStats{}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, 4, response.Rounds)
+		assert.Equal(t, 3.2, *response.MAPE)
+	})
+	t.Run("Error", func(t *testing.T) {
+		mockRoundService := new(RoundServiceMockImpl)
+		mockRoundService.On("GetPlayerStats", "player-1").Return(model.PlayerStats{}, errors.New("db error"))
+		handler := &SolutionHandler{RoundService: mockRoundService}
+		w := httptest.NewRecorder()
+		router := SetupRouter(handler, isProduction)
+		req, _ := http.NewRequest(http.MethodGet, "/me/stats", nil)
+		req.Header.Set("X-Player-ID", "player-1")
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
+
+func TestApiServerRequestGetMyProgress(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Run("Progress", func(t *testing.T) {
+		mockProgressionService := new(ProgressionServiceMockImpl)
+		mockProgressionService.On("GetProgress", "player-1").Return(model.ProgressResponse{XP: 120, Level: 2, CurrentStreak: 3}, nil)
+		handler := &SolutionHandler{ProgressionService: mockProgressionService}
+		w := httptest.NewRecorder()
+		router := SetupRouter(handler, isProduction)
+		req, _ := http.NewRequest(http.MethodGet, "/me/progress", nil)
+		req.Header.Set("X-Player-ID", "player-1")
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		response := model.ProgressResponse{}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, 2, response.Level)
+		assert.Equal(t, 3, response.CurrentStreak)
+	})
+	t.Run("WithoutPlayer", func(t *testing.T) {
+		handler := &SolutionHandler{ProgressionService: new(ProgressionServiceMockImpl)}
+		w := httptest.NewRecorder()
+		router := SetupRouter(handler, isProduction)
+		req, _ := http.NewRequest(http.MethodGet, "/me/progress", nil)
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+	t.Run("Achievements", func(t *testing.T) {
+		mockProgressionService := new(ProgressionServiceMockImpl)
+		mockProgressionService.On("ListAchievements").Return([]model.Achievement{{Id: "first_round", Target: 1}})
+		handler := &SolutionHandler{ProgressionService: mockProgressionService}
+		w := httptest.NewRecorder()
+		router := SetupRouter(handler, isProduction)
+		req, _ := http.NewRequest(http.MethodGet, "/achievements", nil)
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		response := []model.Achievement{}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "first_round", response[0].Id)
+	})
+}
+
+func TestApiServerRequestGetCalibration(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ratio := 0.5
+	report := model.CalibrationReport{
+		Global: model.Calibration{Rounds: 3, Days: 30, Bias: 1.5, GuessVolatility: 1, RealizedVolatility: 2, DispersionRatio: &ratio},
+		Players: []model.PlayerCalibration{
+			{PlayerId: "player-1", Calibration: model.Calibration{Rounds: 2, Days: 20, Bias: -0.25}},
+		},
+	}
+	t.Run("Player", func(t *testing.T) {
+		mockAnalyticsService := new(AnalyticsServiceMockImpl)
+		mockAnalyticsService.On("GetPlayerCalibration", "player-1").Return(model.Calibration{Days: 10, Bias: 2}, nil)
+		handler := &SolutionHandler{AnalyticsService: mockAnalyticsService}
+		w := httptest.NewRecorder()
+		router := SetupRouter(handler, isProduction)
+		req, _ := http.NewRequest(http.MethodGet, "/me/calibration", nil)
+		req.Header.Set("X-Player-ID", "player-1")
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		response := model.Calibration{}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, 2.0, response.Bias)
+	})
+	t.Run("GlobalWithoutPlayers", func(t *testing.T) {
+		mockAnalyticsService := new(AnalyticsServiceMockImpl)
+		mockAnalyticsService.On("GetCalibrationReport").Return(report, nil)
+		handler := &SolutionHandler{AnalyticsService: mockAnalyticsService}
+		w := httptest.NewRecorder()
+		router := SetupRouter(handler, isProduction)
+		req, _ := http.NewRequest(http.MethodGet, "/analytics/calibration", nil)
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.NotContains(t, w.Body.String(), "player-1")
+		response := model.Calibration{}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, 30, response.Days)
+	})
+	t.Run("AdminCSV", func(t *testing.T) {
+		mockAnalyticsService := new(AnalyticsServiceMockImpl)
+		mockAnalyticsService.On("GetCalibrationReport").Return(report, nil)
+		handler := &SolutionHandler{AnalyticsService: mockAnalyticsService, AdminToken: "secret"}
+		w := httptest.NewRecorder()
+		router := SetupRouter(handler, isProduction)
+		req, _ := http.NewRequest(http.MethodGet, "/admin/analytics/calibration.csv", nil)
+		req.Header.Set("Authorization", "Bearer secret")
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "text/csv", w.Header().Get("Content-Type"))
+		assert.Equal(t, "player_id,rounds,days,bias,guess_volatility,realized_volatility,dispersion_ratio\n"+
+			"all,3,30,1.5,1,2,0.5\n"+
+			"player-1,2,20,-0.25,0,0,\n", w.Body.String())
+	})
+	t.Run("AdminInvalidToken", func(t *testing.T) {
+		mockAnalyticsService := new(AnalyticsServiceMockImpl)
+		handler := &SolutionHandler{AnalyticsService: mockAnalyticsService, AdminToken: "secret"}
+		w := httptest.NewRecorder()
+		router := SetupRouter(handler, isProduction)
+		req, _ := http.NewRequest(http.MethodGet, "/admin/analytics/calibration.csv", nil)
+		req.Header.Set("Authorization", "Bearer wrong")
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		mockAnalyticsService.AssertNotCalled(t, "GetCalibrationReport")
+	})
+	t.Run("AdminDisabled", func(t *testing.T) {
+		mockAnalyticsService := new(AnalyticsServiceMockImpl)
+		handler := &SolutionHandler{AnalyticsService: mockAnalyticsService}
+		w := httptest.NewRecorder()
+		router := SetupRouter(handler, isProduction)
+		req, _ := http.NewRequest(http.MethodGet, "/admin/analytics/calibration.csv", nil)
+		req.Header.Set("Authorization", "Bearer ")
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		mockAnalyticsService.AssertNotCalled(t, "GetCalibrationReport")
+	})
+}
+
+func TestApiServerRequestRooms(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Run("Create", func(t *testing.T) {
+		handler := &SolutionHandler{RoomHub: &room.HubImpl{}}
+		w := httptest.NewRecorder()
+		router := SetupRouter(handler, isProduction)
+		req, _ := http.NewRequest(http.MethodPost, "/rooms", strings.NewReader(`{"name": "Alice"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Player-ID", "player-1")
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		response := model.RoomState{}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Len(t, response.Code, 6)
+		assert.Equal(t, model.Room_state_lobby, response.State)
+		assert.Equal(t, "Alice", response.Players[0].Name)
+		assert.NotContains(t, w.Body.String(), "player-1")
+	})
+	t.Run("CreateWithoutBody", func(t *testing.T) {
+		handler := &SolutionHandler{RoomHub: &room.HubImpl{}}
+		w := httptest.NewRecorder()
+		router := SetupRouter(handler, isProduction)
+		req, _ := http.NewRequest(http.MethodPost, "/rooms", nil)
+		req.Header.Set("X-Player-ID", "player-1")
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Contains(t, w.Body.String(), "Player 1")
+	})
+	t.Run("CreateMissingPlayer", func(t *testing.T) {
+		handler := &SolutionHandler{RoomHub: &room.HubImpl{}}
+		w := httptest.NewRecorder()
+		router := SetupRouter(handler, isProduction)
+		req, _ := http.NewRequest(http.MethodPost, "/rooms", strings.NewReader(`{"name": "Alice"}`))
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+	t.Run("NotFound", func(t *testing.T) {
+		handler := &SolutionHandler{RoomHub: &room.HubImpl{}}
+		router := SetupRouter(handler, isProduction)
+		for _, path := range []string{"/rooms/NOPE00", "/rooms/NOPE00/ws"} {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, path, nil)
+			req.Header.Set(playerIdHeader, "player-1")
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusNotFound, w.Code, path)
+		}
+	})
+	t.Run("SocketMissingPlayer", func(t *testing.T) {
+		hub := &room.HubImpl{}
+		state, _ := hub.CreateRoom("player-1", "Alice")
+		handler := &SolutionHandler{RoomHub: hub}
+		w := httptest.NewRecorder()
+		router := SetupRouter(handler, isProduction)
+		req, _ := http.NewRequest(http.MethodGet, "/rooms/"+state.Code+"/ws?playerId=player-2", nil)
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+	t.Run("SocketJoin", func(t *testing.T) {
+		hub := &room.HubImpl{}
+		state, _ := hub.CreateRoom("player-1", "Alice")
+		server := httptest.NewServer(SetupRouter(&SolutionHandler{RoomHub: hub}, isProduction))
+		defer server.Close()
+		url := "ws" + strings.TrimPrefix(server.URL, "http") + "/rooms/" + state.Code + "/ws?name=Bob"
+		config, err := websocket.NewConfig(url, server.URL)
+		if !assert.NoError(t, err) {
+			return
+		}
+		config.Header.Set(playerIdHeader, "player-2")
+		ws, err := websocket.DialConfig(config)
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer ws.Close()
+		ws.SetReadDeadline(time.Now().Add(time.Second))
+
+		event := model.RoomEvent{}
+		assert.NoError(t, websocket.JSON.Receive(ws, &event))
+		assert.Equal(t, model.Room_event_state, event.Type)
+		if assert.Len(t, event.Room.Players, 2) {
+			assert.Equal(t, "Bob", event.Room.Players[1].Name)
+			assert.True(t, event.Room.Players[1].Connected)
+		}
+	})
+}
+
+func TestApiServerRequestTournaments(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Run("CreateRequiresAdmin", func(t *testing.T) {
+		handler := &SolutionHandler{TournamentService: new(TournamentServiceMockImpl), AdminToken: "secret"}
+		w := httptest.NewRecorder()
+		router := SetupRouter(handler, isProduction)
+		req, _ := http.NewRequest(http.MethodPost, "/admin/tournaments", strings.NewReader(`{"name": "Spring cup"}`))
+		req.Header.Set("Content-Type", "application/json")
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+	t.Run("Create", func(t *testing.T) {
+		mockTournamentService := new(TournamentServiceMockImpl)
+		startsAt := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+		request := model.CreateTournamentRequest{Name: "Spring cup", StartsAt: startsAt, EndsAt: startsAt.Add(time.Hour), Rounds: 3}
+		mockTournamentService.On("CreateTournament", request).Return(model.Tournament{
+			Id:      "tournament-1",
+			Name:    "Spring cup",
+			Windows: []model.TournamentWindow{{SymbolUUID: "uuid-aapl", AfterDate: "2020-01-31"}},
+			Rounds:  3,
+		}, nil)
+		handler := &SolutionHandler{TournamentService: mockTournamentService, AdminToken: "secret"}
+		w := httptest.NewRecorder()
+		router := SetupRouter(handler, isProduction)
+		body := `{"name": "Spring cup", "startsAt": "2024-05-01T00:00:00Z", "endsAt": "2024-05-01T01:00:00Z", "rounds": 3}`
+		req, _ := http.NewRequest(http.MethodPost, "/admin/tournaments", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer secret")
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Contains(t, w.Body.String(), "tournament-1")
+		assert.NotContains(t, w.Body.String(), "uuid-aapl", "The windows reveal the stocks")
+	})
+	t.Run("CreateInvalid", func(t *testing.T) {
+		mockTournamentService := new(TournamentServiceMockImpl)
+		mockTournamentService.On("CreateTournament", mock.Anything).Return(model.Tournament{}, fmt.Errorf("%w: rounds must be between 1 and 20", service.ErrInvalidTournament))
+		handler := &SolutionHandler{TournamentService: mockTournamentService, AdminToken: "secret"}
+		w := httptest.NewRecorder()
+		router := SetupRouter(handler, isProduction)
+		req, _ := http.NewRequest(http.MethodPost, "/admin/tournaments", strings.NewReader(`{"name": "Spring cup"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer secret")
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "rounds must be between 1 and 20")
+	})
+	t.Run("NotFound", func(t *testing.T) {
+		mockTournamentService := new(TournamentServiceMockImpl)
+		mockTournamentService.On("GetTournament", "unknown").Return(model.Tournament{}, dataaccess.ErrTournamentNotFound)
+		mockTournamentService.On("GetStandings", "unknown").Return([]model.TournamentStanding(nil), dataaccess.ErrTournamentNotFound)
+		router := SetupRouter(&SolutionHandler{TournamentService: mockTournamentService}, isProduction)
+		for _, path := range []string{"/tournaments/unknown", "/tournaments/unknown/standings"} {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, path, nil)
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusNotFound, w.Code, path)
+		}
+	})
+	t.Run("Join", func(t *testing.T) {
+		mockTournamentService := new(TournamentServiceMockImpl)
+		mockTournamentService.On("JoinTournament", "tournament-1", "player-1", "Alice").Return(nil)
+		mockTournamentService.On("JoinTournament", "tournament-1", "player-2", "").Return(service.ErrAlreadyEntered)
+		router := SetupRouter(&SolutionHandler{TournamentService: mockTournamentService}, isProduction)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/tournaments/tournament-1/join", strings.NewReader(`{"name": "Alice"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Player-ID", "player-1")
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusNoContent, w.Code)
+
+		w = httptest.NewRecorder()
+		req, _ = http.NewRequest(http.MethodPost, "/tournaments/tournament-1/join", nil)
+		req.Header.Set("X-Player-ID", "player-2")
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+	t.Run("NextRoundNotEntered", func(t *testing.T) {
+		mockTournamentService := new(TournamentServiceMockImpl)
+		mockTournamentService.On("GetNextRound", "tournament-1", "player-1").Return(model.TournamentRoundResponse{}, service.ErrNotEntered)
+		router := SetupRouter(&SolutionHandler{TournamentService: mockTournamentService}, isProduction)
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/tournaments/tournament-1/rounds/next", nil)
+		req.Header.Set("X-Player-ID", "player-1")
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+	t.Run("Solution", func(t *testing.T) {
+		mockService := new(StockServiceMockImpl)
+		mockService.On("GetStockInfo", "uuid-aapl").Return(model.StockInfo{Symbol: "AAPL", Name: "Apple Inc.", SymbolUUID: "uuid-aapl"}, nil)
+		mockService.On("GetStocksBeforeEqualDate", "AAPL", "2023-10-01").Return([]model.Stock{{Symbol: "AAPL", Date: "2023-10-01", Close: 150}})
+		mockService.On("GetStocksAfterDate", "AAPL", "2023-10-01").Return(daysToGuess(model.Stock{Symbol: "AAPL", Date: "2023-10-02", Close: 152}))
+		mockScoringLogic := new(ScoringLogicMockImpl)
+		mockScoringLogic.On("CalculateBollingerBandsWithConfig", mock.Anything, model.DefaultBollingerConfig).Return(map[string]model.BollingerBand{})
+		score := model.UserScoreResponse{Total: 30, InOpenClose: 30}
+		mockScoringLogic.On("GetScore", guessPrices(151, 0), mock.Anything, mock.Anything).Return(score)
+		mockRoundService := newRoundServiceMock()
+		mockTournamentService := new(TournamentServiceMockImpl)
+		mockTournamentService.On("GetRoundWindow", "tournament-1", "player-1", 2).Return(model.TournamentWindow{SymbolUUID: "uuid-aapl", AfterDate: "2023-10-01"}, nil)
+		mockTournamentService.On("RecordRound", "tournament-1", "player-1", 2, mock.AnythingOfType("string"), score).Return(nil)
+		handler := &SolutionHandler{
+			StockService:      mockService,
+			ScoringLogic:      mockScoringLogic,
+			DifficultyLogic:   &logic.DifficultyLogicImpl{},
+			RoundService:      mockRoundService,
+			TournamentService: mockTournamentService,
+		}
+		w := httptest.NewRecorder()
+		router := SetupRouter(handler, isProduction)
+		req, _ := http.NewRequest(http.MethodPost, "/tournaments/tournament-1/rounds/2", strings.NewReader(`{"estimatedDayPrices": `+toJSON(guessPrices(151, 0))+`}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Player-ID", "player-1")
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		response := model.TournamentSolutionResponse{}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, 2, response.Position)
+		assert.Equal(t, "round-1", response.RoundId)
+		assert.Equal(t, score, response.Score)
+		mockTournamentService.AssertExpectations(t)
+		saved := mockRoundService.Calls[0].Arguments.Get(0).(model.Round)
+		assert.Equal(t, "player-1", saved.PlayerId)
+		assert.Equal(t, slices.Repeat([]float64{152}, model.User_stock_to_guess), saved.Actual)
+		assert.Equal(t, mockTournamentService.Calls[1].Arguments.String(3), saved.Id)
+	})
+	t.Run("SolutionAlreadyPlayed", func(t *testing.T) {
+		mockService := new(StockServiceMockImpl)
+		mockService.On("GetStockInfo", "uuid-aapl").Return(model.StockInfo{Symbol: "AAPL", Name: "Apple Inc.", SymbolUUID: "uuid-aapl"}, nil)
+		mockService.On("GetStocksBeforeEqualDate", "AAPL", "2023-10-01").Return([]model.Stock{{Symbol: "AAPL", Date: "2023-10-01", Close: 150}})
+		mockService.On("GetStocksAfterDate", "AAPL", "2023-10-01").Return(daysToGuess(model.Stock{Symbol: "AAPL", Date: "2023-10-02", Close: 152}))
+		mockScoringLogic := new(ScoringLogicMockImpl)
+		mockScoringLogic.On("CalculateBollingerBandsWithConfig", mock.Anything, model.DefaultBollingerConfig).Return(map[string]model.BollingerBand{})
+		mockScoringLogic.On("GetScore", mock.Anything, mock.Anything, mock.Anything).Return(model.UserScoreResponse{Total: 30})
+		mockRoundService := newRoundServiceMock()
+		mockTournamentService := new(TournamentServiceMockImpl)
+		mockTournamentService.On("GetRoundWindow", "tournament-1", "player-1", 2).Return(model.TournamentWindow{SymbolUUID: "uuid-aapl", AfterDate: "2023-10-01"}, nil)
+		mockTournamentService.On("RecordRound", "tournament-1", "player-1", 2, mock.Anything, mock.Anything).Return(service.ErrRoundAlreadyPlayed)
+		handler := &SolutionHandler{
+			StockService:      mockService,
+			ScoringLogic:      mockScoringLogic,
+			DifficultyLogic:   &logic.DifficultyLogicImpl{},
+			RoundService:      mockRoundService,
+			TournamentService: mockTournamentService,
+		}
+		w := httptest.NewRecorder()
+		router := SetupRouter(handler, isProduction)
+		req, _ := http.NewRequest(http.MethodPost, "/tournaments/tournament-1/rounds/2", strings.NewReader(`{"estimatedDayPrices": `+toJSON(guessPrices(151, 0))+`}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Player-ID", "player-1")
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		mockRoundService.AssertNotCalled(t, "SaveRound", mock.Anything)
+	})
+	t.Run("SolutionOutOfOrder", func(t *testing.T) {
+		mockTournamentService := new(TournamentServiceMockImpl)
+		mockTournamentService.On("GetRoundWindow", "tournament-1", "player-1", 3).Return(model.TournamentWindow{}, service.ErrWrongPosition)
+		router := SetupRouter(&SolutionHandler{TournamentService: mockTournamentService}, isProduction)
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/tournaments/tournament-1/rounds/3", strings.NewReader(`{"estimatedDayPrices": []}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Player-ID", "player-1")
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "played in order")
+	})
+	t.Run("Standings", func(t *testing.T) {
+		mockTournamentService := new(TournamentServiceMockImpl)
+		mockTournamentService.On("GetStandings", "tournament-1").Return([]model.TournamentStanding{
+			{Rank: 1, Entry: 2, Name: "Bob", Rounds: 3, Score: model.UserScoreResponse{Total: 90}},
+			{Rank: 2, Entry: 1, Name: "Alice", Rounds: 3, Score: model.UserScoreResponse{Total: 60}},
+		}, nil)
+		router := SetupRouter(&SolutionHandler{TournamentService: mockTournamentService}, isProduction)
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/tournaments/tournament-1/standings", nil)
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		standings := []model.TournamentStanding{}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &standings))
+		assert.Equal(t, "Bob", standings[0].Name)
+	})
+}
+
+func TestApiServerRequestTrading(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Run("Start", func(t *testing.T) {
+		mockTradingService := new(TradingServiceMockImpl)
+		mockTradingService.On("StartSession", "player-1", "hard").Return(model.TradingResponse{
+			Session: model.TradingSession{Id: "session-1", PlayerId: "player-1", Days: 10},
+			Stocks:  []model.StockPublic{{Date: "2020-01-31", Close: 100}},
+		}, nil)
+		router := SetupRouter(&SolutionHandler{TradingService: mockTradingService}, isProduction)
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/trading", strings.NewReader(`{"difficulty": "Hard"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Player-ID", "player-1")
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Contains(t, w.Body.String(), "session-1")
+		assert.NotContains(t, w.Body.String(), "player-1")
+	})
+	t.Run("StartInvalidDifficulty", func(t *testing.T) {
+		router := SetupRouter(&SolutionHandler{TradingService: new(TradingServiceMockImpl)}, isProduction)
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/trading", strings.NewReader(`{"difficulty": "extreme"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Player-ID", "player-1")
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+	t.Run("StartMissingPlayer", func(t *testing.T) {
+		router := SetupRouter(&SolutionHandler{TradingService: new(TradingServiceMockImpl)}, isProduction)
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/trading", nil)
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+	t.Run("Trade", func(t *testing.T) {
+		mockTradingService := new(TradingServiceMockImpl)
+		trade := model.TradeRequest{Action: model.Trade_buy, Quantity: 5}
+		mockTradingService.On("Trade", "session-1", "player-1", trade).Return(model.TradingResponse{
+			Session:  model.TradingSession{Id: "session-1", Day: 1, Days: 10},
+			Revealed: []model.Stock{{Date: "2020-02-03", Close: 110}},
+		}, nil)
+		router := SetupRouter(&SolutionHandler{TradingService: mockTradingService}, isProduction)
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/trading/session-1/trades", strings.NewReader(`{"action": "buy", "quantity": 5}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Player-ID", "player-1")
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		response := model.TradingResponse{}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Len(t, response.Revealed, 1)
+		assert.False(t, response.Finished)
+	})
+	t.Run("TradeErrors", func(t *testing.T) {
+		errorCases := map[error]int{
+			fmt.Errorf("%w: 2 shares held", logic.ErrInvalidTrade): http.StatusBadRequest,
+			dataaccess.ErrTradingSessionNotFound:                   http.StatusNotFound,
+			service.ErrTradingSessionFinished:                      http.StatusConflict,
+		}
+		for err, status := range errorCases {
+			mockTradingService := new(TradingServiceMockImpl)
+			mockTradingService.On("Trade", "session-1", "player-1", mock.Anything).Return(model.TradingResponse{}, err)
+			router := SetupRouter(&SolutionHandler{TradingService: mockTradingService}, isProduction)
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodPost, "/trading/session-1/trades", strings.NewReader(`{"action": "sell", "quantity": 3}`))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("X-Player-ID", "player-1")
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, status, w.Code, err.Error())
+		}
+	})
+	t.Run("Get", func(t *testing.T) {
+		mockTradingService := new(TradingServiceMockImpl)
+		mockTradingService.On("GetSession", "session-1", "player-1").Return(model.TradingResponse{
+			Session:  model.TradingSession{Id: "session-1", Day: 10, Days: 10},
+			Finished: true,
+			Symbol:   "AAPL",
+			Result:   &model.TradingResult{Strategy: model.TradingPerformance{FinalValue: 11000}},
+		}, nil)
+		router := SetupRouter(&SolutionHandler{TradingService: mockTradingService}, isProduction)
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/trading/session-1", nil)
+		req.Header.Set("X-Player-ID", "player-1")
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "buyAndHold")
+	})
+}
+
+func TestApiServerRequestTicker(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Run("Challenge", func(t *testing.T) {
+		mockTickerService := new(TickerServiceMockImpl)
+		mockTickerService.On("NewChallenge", "player-1").Return(model.TickerChallengeResponse{
+			Id:         "challenge-1",
+			Stocks:     []model.StockPublic{{Date: "2020-01-31", Close: 100}},
+			Candidates: []model.TickerCandidate{{Id: "option-1", Symbol: "AAPL", Name: "Apple"}},
+		}, nil)
+		router := SetupRouter(&SolutionHandler{TickerService: mockTickerService}, isProduction)
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/ticker/challenges", nil)
+		req.Header.Set("X-Player-ID", "player-1")
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		response := model.TickerChallengeResponse{}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "challenge-1", response.Id)
+		assert.Len(t, response.Candidates, 1)
+	})
+	t.Run("ChallengeMissingPlayer", func(t *testing.T) {
+		router := SetupRouter(&SolutionHandler{TickerService: new(TickerServiceMockImpl)}, isProduction)
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/ticker/challenges", nil)
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+	t.Run("Answer", func(t *testing.T) {
+		mockTickerService := new(TickerServiceMockImpl)
+		mockTickerService.On("Answer", "challenge-1", "player-1", "option-1").Return(model.TickerAnswerResponse{
+			Answer: model.TickerCandidate{Id: "option-1", Symbol: "AAPL"},
+			Score:  model.TickerScore{Correct: true, Seconds: 3, Speed: 45, Total: 95},
+		}, nil)
+		router := SetupRouter(&SolutionHandler{TickerService: mockTickerService}, isProduction)
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/ticker/challenges/challenge-1/answer", strings.NewReader(`{"optionId": "option-1"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Player-ID", "player-1")
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		response := model.TickerAnswerResponse{}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, 95, response.Score.Total)
+	})
+	t.Run("AnswerErrors", func(t *testing.T) {
+		errorCases := map[error]int{
+			service.ErrInvalidTickerAnswer:        http.StatusBadRequest,
+			service.ErrTickerAlreadyAnswered:      http.StatusConflict,
+			dataaccess.ErrTickerChallengeNotFound: http.StatusNotFound,
+		}
+		for err, status := range errorCases {
+			mockTickerService := new(TickerServiceMockImpl)
+			mockTickerService.On("Answer", "challenge-1", "player-1", "option-9").Return(model.TickerAnswerResponse{}, err)
+			router := SetupRouter(&SolutionHandler{TickerService: mockTickerService}, isProduction)
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodPost, "/ticker/challenges/challenge-1/answer", strings.NewReader(`{"optionId": "option-9"}`))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("X-Player-ID", "player-1")
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, status, w.Code, err.Error())
+		}
+	})
+}
+
+func TestApiServerRequestBasket(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Run("Get", func(t *testing.T) {
+		window := model.BasketWindow{
+			AfterDate: "2023-10-01",
+			Stocks:    []model.BasketStock{{SymbolUUID: "uuid-aapl"}, {SymbolUUID: "uuid-msft"}, {SymbolUUID: "uuid-goog"}},
+		}
+		mockService := new(StockServiceMockImpl)
+		mockService.On("GetRandomBasket", 3).Return(window)
+		mockBasketService := new(BasketServiceMockImpl)
+		mockBasketService.On("ServeBasket", "player-1", window).Return(model.ServedBasket{Id: "basket-1"}, nil)
+		router := SetupRouter(&SolutionHandler{StockService: mockService, BasketService: mockBasketService}, isProduction)
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/basket?symbols=3", nil)
+		req.Header.Set(playerIdHeader, "player-1")
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		basket := model.BasketWindow{}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &basket))
+		assert.Len(t, basket.Stocks, 3)
+		assert.Equal(t, "basket-1", basket.Id)
+	})
+	t.Run("GetNotServed", func(t *testing.T) {
+		mockService := new(StockServiceMockImpl)
+		mockService.On("GetRandomBasket", 3).Return(model.BasketWindow{
+			AfterDate: "2023-10-01",
+			Stocks:    []model.BasketStock{{SymbolUUID: "uuid-aapl"}, {SymbolUUID: "uuid-msft"}, {SymbolUUID: "uuid-goog"}},
+		})
+		mockBasketService := new(BasketServiceMockImpl)
+		mockBasketService.On("ServeBasket", "", mock.Anything).Return(model.ServedBasket{}, errors.New("db error"))
+		router := SetupRouter(&SolutionHandler{StockService: mockService, BasketService: mockBasketService}, isProduction)
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/basket?symbols=3", nil)
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.NotContains(t, w.Body.String(), "uuid-aapl")
+	})
+	t.Run("GetInvalidSize", func(t *testing.T) {
+		router := SetupRouter(&SolutionHandler{StockService: new(StockServiceMockImpl)}, isProduction)
+		for _, path := range []string{"/basket?symbols=2", "/basket?symbols=6", "/basket?symbols=many"} {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, path, nil)
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code, path)
+		}
+	})
+	t.Run("GetNoBasket", func(t *testing.T) {
+		mockService := new(StockServiceMockImpl)
+		mockService.On("GetRandomBasket", defaultBasketSymbols).Return(model.BasketWindow{Stocks: []model.BasketStock{}})
+		router := SetupRouter(&SolutionHandler{StockService: mockService}, isProduction)
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/basket", nil)
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	})
+
+	// AAPL goes up 10%, MSFT stays flat and GOOG goes down 5%
+	basketService := func() *StockServiceMockImpl {
+		mockService := new(StockServiceMockImpl)
+		for symbol, lastClose := range map[string]float64{"AAPL": 110, "MSFT": 100, "GOOG": 95} {
+			mockService.On("GetStockInfo", "uuid-"+strings.ToLower(symbol)).Return(model.StockInfo{Symbol: symbol, SymbolUUID: "uuid-" + strings.ToLower(symbol)}, nil)
+			mockService.On("GetStocksBeforeEqualDate", symbol, "2023-10-01").Return([]model.Stock{{Symbol: symbol, Date: "2023-10-01", Close: 100}})
+			mockService.On("GetStocksAfterDate", symbol, "2023-10-01").Return([]model.Stock{{Symbol: symbol, Date: "2023-10-02", Close: 105}, {Symbol: symbol, Date: "2023-10-03", Close: lastClose}})
+		}
+		return mockService
+	}
+	servedBasket := func(symbols []string) *BasketServiceMockImpl {
+		mockBasketService := new(BasketServiceMockImpl)
+		mockBasketService.On("GetBasket", "basket-1", "", symbols).Return(model.ServedBasket{Id: "basket-1", AfterDate: "2023-10-01"}, nil)
+		mockBasketService.On("SolveBasket", "basket-1", "", symbols).Return(model.ServedBasket{Id: "basket-1", AfterDate: "2023-10-01"}, nil)
+		return mockBasketService
+	}
+	t.Run("Ranking", func(t *testing.T) {
+		handler := &SolutionHandler{StockService: basketService(), BasketService: servedBasket([]string{"uuid-aapl", "uuid-goog", "uuid-msft"})}
+		router := SetupRouter(handler, isProduction)
+		w := httptest.NewRecorder()
+		body := `{"basketId": "basket-1", "ranking": ["uuid-aapl", "uuid-goog", "uuid-msft"]}`
+		req, _ := http.NewRequest(http.MethodPost, "/solution/basket", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		response := model.BasketSolutionResponse{}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, model.Basket_method_rank, response.Score.Method)
+		assert.Equal(t, 0.5, *response.Score.Spearman)
+		assert.Equal(t, 75, response.Score.Total)
+		if assert.Len(t, response.Results, 3) {
+			assert.Equal(t, "AAPL", response.Results[0].Symbol)
+			assert.Equal(t, 10.0, response.Results[0].Return)
+			assert.Equal(t, "GOOG", response.Results[2].Symbol)
+			assert.Equal(t, 3, response.Results[2].Rank)
+		}
+	})
+	t.Run("Weights", func(t *testing.T) {
+		handler := &SolutionHandler{StockService: basketService(), BasketService: servedBasket([]string{"uuid-aapl", "uuid-msft", "uuid-goog"})}
+		router := SetupRouter(handler, isProduction)
+		w := httptest.NewRecorder()
+		body := `{"basketId": "basket-1", "weights": [{"symbolUUID": "uuid-aapl", "weight": 3}, {"symbolUUID": "uuid-msft", "weight": 1}, {"symbolUUID": "uuid-goog", "weight": 0}]}`
+		req, _ := http.NewRequest(http.MethodPost, "/solution/basket", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		response := model.BasketSolutionResponse{}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, 7.5, *response.Score.PortfolioReturn)
+		assert.Equal(t, 83, response.Score.Total)
+	})
+	t.Run("TooFewSymbols", func(t *testing.T) {
+		router := SetupRouter(&SolutionHandler{StockService: new(StockServiceMockImpl)}, isProduction)
+		w := httptest.NewRecorder()
+		body := `{"basketId": "basket-1", "ranking": ["uuid-aapl", "uuid-msft"]}`
+		req, _ := http.NewRequest(http.MethodPost, "/solution/basket", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "a basket has 3 to 5 symbols")
+	})
+	t.Run("MissingBasket", func(t *testing.T) {
+		router := SetupRouter(&SolutionHandler{BasketService: new(BasketServiceMockImpl)}, isProduction)
+		w := httptest.NewRecorder()
+		body := `{"ranking": ["uuid-aapl", "uuid-goog", "uuid-msft"]}`
+		req, _ := http.NewRequest(http.MethodPost, "/solution/basket", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), model.Error_round_required)
+	})
+	t.Run("BasketErrors", func(t *testing.T) {
+		errorCases := map[error]int{
+			dataaccess.ErrServedBasketNotFound: http.StatusNotFound,
+			service.ErrServedBasketSymbols:     http.StatusBadRequest,
+			service.ErrServedBasketSolved:      http.StatusConflict,
+		}
+		for err, status := range errorCases {
+			mockBasketService := new(BasketServiceMockImpl)
+			mockBasketService.On("GetBasket", "basket-1", "player-1", mock.Anything).Return(model.ServedBasket{}, err)
+			router := SetupRouter(&SolutionHandler{BasketService: mockBasketService}, isProduction)
+			w := httptest.NewRecorder()
+			body := `{"basketId": "basket-1", "ranking": ["uuid-aapl", "uuid-goog", "uuid-tsla"]}`
+			req, _ := http.NewRequest(http.MethodPost, "/solution/basket", strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set(playerIdHeader, "player-1")
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, status, w.Code, err.Error())
+			assert.NotContains(t, w.Body.String(), "results", err.Error())
+			mockBasketService.AssertNotCalled(t, "SolveBasket", mock.Anything, mock.Anything, mock.Anything)
+		}
+	})
+	t.Run("LoadError", func(t *testing.T) {
+		// The basket is not used up when the prices of a symbol cannot be read
+		mockService := new(StockServiceMockImpl)
+		mockService.On("GetStockInfo", "uuid-aapl").Return(model.StockInfo{}, errors.New("db error"))
+		mockBasketService := new(BasketServiceMockImpl)
+		mockBasketService.On("GetBasket", "basket-1", "", mock.Anything).Return(model.ServedBasket{Id: "basket-1", AfterDate: "2023-10-01"}, nil)
+		router := SetupRouter(&SolutionHandler{StockService: mockService, BasketService: mockBasketService}, isProduction)
+		w := httptest.NewRecorder()
+		body := `{"basketId": "basket-1", "ranking": ["uuid-aapl", "uuid-goog", "uuid-msft"]}`
+		req, _ := http.NewRequest(http.MethodPost, "/solution/basket", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockBasketService.AssertNotCalled(t, "SolveBasket", mock.Anything, mock.Anything, mock.Anything)
+	})
+	t.Run("SolvedMeanwhile", func(t *testing.T) {
+		symbols := []string{"uuid-aapl", "uuid-goog", "uuid-msft"}
+		mockBasketService := new(BasketServiceMockImpl)
+		mockBasketService.On("GetBasket", "basket-1", "", symbols).Return(model.ServedBasket{Id: "basket-1", AfterDate: "2023-10-01"}, nil)
+		mockBasketService.On("SolveBasket", "basket-1", "", symbols).Return(model.ServedBasket{}, service.ErrServedBasketSolved)
+		router := SetupRouter(&SolutionHandler{StockService: basketService(), BasketService: mockBasketService}, isProduction)
+		w := httptest.NewRecorder()
+		body := `{"basketId": "basket-1", "ranking": ["uuid-aapl", "uuid-goog", "uuid-msft"]}`
+		req, _ := http.NewRequest(http.MethodPost, "/solution/basket", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Contains(t, w.Body.String(), model.Error_round_solved)
+		assert.NotContains(t, w.Body.String(), "results")
 	})
 }
@@ -1,8 +1,11 @@
 package main
 
 import (
+	"bufio"
 	"database/sql"
 	"encoding/csv"
+	"errors"
+	"flag"
 	"fmt"
 	"io"
 	"log"
@@ -11,6 +14,7 @@ import (
 	"runtime"
 	"stockgame/internal/database"
 	"stockgame/internal/util"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -22,6 +26,95 @@ var maxWorkers = runtime.NumCPU() * 2 // Double the CPU cores
 const tableNameStocks = "stocks"
 const tableNameStocksInfo = "stocks_info"
 const maxFieldCVS = 12
+const stockDirPath = "./data/raw/stocks/"
+const companyInfoPath = "./data/raw/symbols_valid_meta.csv"
+const sectorPath = "./data/raw/sectors.csv" // Optional, columns: Symbol, Sector
+const checkpointPath = "./data/raw/.data-loader.checkpoint"
+
+// Checkpoint keys for the steps that are not per file
+const stepCreateTables = "step:create-tables"
+const stepCompanyInfo = "step:company-info"
+const stepSectors = "step:sectors"
+const stepIndex = "step:index"
+
+var dryRun = flag.Bool("dry-run", false, "Parse and validate every CSV file without writing to the database")
+var resume = flag.Bool("resume", false, "Continue a previous load from the checkpoint instead of recreating the tables")
+
+// checkpoint keeps track of what was committed to the database. Every completed
+// step is appended (and synced) to the checkpoint file so a rerun with -resume
+// can continue where the previous run stopped.
+type checkpoint struct {
+	mu   sync.Mutex
+	path string
+	file *os.File
+	done map[string]bool
+}
+
+// openCheckpoint reads the existing checkpoint file when resuming or starts a new
+// one otherwise.
+func openCheckpoint(path string, isResume bool) (*checkpoint, error) {
+	cp := &checkpoint{path: path, done: make(map[string]bool)}
+	if isResume {
+		file, err := os.Open(path)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+		if err == nil {
+			scanner := bufio.NewScanner(file)
+			for scanner.Scan() {
+				line := strings.TrimSpace(scanner.Text())
+				if line != "" {
+					cp.done[line] = true
+				}
+			}
+			file.Close()
+			if err := scanner.Err(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	flags := os.O_CREATE | os.O_WRONLY | os.O_APPEND
+	if !isResume {
+		flags |= os.O_TRUNC
+	}
+	file, err := os.OpenFile(path, flags, 0644)
+	if err != nil {
+		return nil, err
+	}
+	cp.file = file
+	return cp, nil
+}
+
+func (c *checkpoint) isDone(key string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.done[key]
+}
+
+func (c *checkpoint) markDone(key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, err := fmt.Fprintln(c.file, key); err != nil {
+		return err
+	}
+	if err := c.file.Sync(); err != nil {
+		return err
+	}
+	c.done[key] = true
+	return nil
+}
+
+// complete removes the checkpoint file once the whole load succeeded
+func (c *checkpoint) complete() {
+	c.file.Close()
+	if err := os.Remove(c.path); err != nil {
+		fmt.Printf("Cannot remove checkpoint file %s: %v\n", c.path, err)
+	}
+}
+
+func fileCheckpointKey(filePath string) string {
+	return "file:" + filepath.Base(filePath)
+}
 
 func createTables(db *sql.DB) {
 	startTime := time.Now()
@@ -62,7 +155,10 @@ func createTables(db *sql.DB) {
         id SERIAL PRIMARY KEY,
         symbol VARCHAR NOT NULL,
         name VARCHAR NOT NULL,
-        symbol_uuid VARCHAR NOT NULL
+        symbol_uuid VARCHAR NOT NULL,
+        exchange VARCHAR NOT NULL DEFAULT '',
+        is_etf BOOLEAN NOT NULL DEFAULT FALSE,
+        sector VARCHAR NOT NULL DEFAULT ''
     );`, tableNameStocksInfo)
 
 	_, err = db.Exec(createStocksInfoQuery)
@@ -75,8 +171,7 @@ func createTables(db *sql.DB) {
 }
 
 func insertCompanyInfo(db *sql.DB) {
-	relativePath := "./data/raw/symbols_valid_meta.csv"
-	absolutePath := filepath.Join(util.GetProjectRoot(), relativePath)
+	absolutePath := filepath.Join(util.GetProjectRoot(), companyInfoPath)
 	startTime := time.Now()
 
 	// Open the file and read the data line by line
@@ -98,7 +193,7 @@ func insertCompanyInfo(db *sql.DB) {
 	}
 
 	// Use fmt.Sprintf for the prepared statement
-	insertQuery := fmt.Sprintf("INSERT INTO %s (symbol, name, symbol_uuid) VALUES ($1, $2, $3)", tableNameStocksInfo)
+	insertQuery := fmt.Sprintf("INSERT INTO %s (symbol, name, symbol_uuid, exchange, is_etf) VALUES ($1, $2, $3, $4, $5)", tableNameStocksInfo)
 	stmt, err := tx.Prepare(insertQuery)
 	if err != nil {
 		log.Fatal(err)
@@ -111,19 +206,27 @@ func insertCompanyInfo(db *sql.DB) {
 		log.Fatal(err)
 	}
 
-	for {
+	// The rows are checked like in the dry run so both accept the same file
+	symbols := make(map[string]bool)
+	for line := 2; ; line++ {
 		row, err := reader.Read()
 		if err == io.EOF {
 			break
 		}
 		if err != nil {
+			tx.Rollback()
 			log.Fatal(err)
 		}
+		if err := checkCompanyRow(row, symbols); err != nil {
+			tx.Rollback()
+			log.Fatalf("Error with file %s: line %d: %v", companyInfoPath, line, err)
+		}
 
 		// Generate a UUID for the symbol
 		uuid := uuid.New()
 
-		_, err = stmt.Exec(row[1], row[2], uuid)
+		// Columns: Nasdaq Traded, Symbol, Security Name, Listing Exchange, Market Category, ETF, ...
+		_, err = stmt.Exec(row[1], row[2], uuid, row[3], row[5] == "Y")
 		if err != nil {
 			tx.Rollback()
 			log.Fatal(err)
@@ -137,19 +240,94 @@ func insertCompanyInfo(db *sql.DB) {
 	}
 	fmt.Printf("Time to insert data into %s table: %v\n", tableNameStocksInfo, time.Since(startTime))
 }
-func insertStocksParallel(db *sql.DB) {
-	dirPath := "./data/raw/stocks/"
+
+// insertSectors sets the sector of the companies listed in the optional sector file.
+// The companies missing from the file keep an empty sector.
+func insertSectors(db *sql.DB) {
+	absolutePath := filepath.Join(util.GetProjectRoot(), sectorPath)
 	startTime := time.Now()
 
-	files, err := os.ReadDir(dirPath)
+	file, err := os.Open(absolutePath)
+	if errors.Is(err, os.ErrNotExist) {
+		fmt.Println("No sector file found, skipping sectors")
+		return
+	}
 	if err != nil {
-		log.Fatal("Error reading directory:", err)
+		log.Fatal(err)
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = 2
+	reader.TrimLeadingSpace = true
+
+	tx, err := db.Begin()
+	if err != nil {
+		log.Fatal(err)
+	}
+	updateQuery := fmt.Sprintf("UPDATE %s SET sector = $1 WHERE symbol = $2", tableNameStocksInfo)
+	stmt, err := tx.Prepare(updateQuery)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer stmt.Close()
+
+	// Skip the header
+	_, err = reader.Read()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	count := 0
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			tx.Rollback()
+			log.Fatal(err)
+		}
+		_, err = stmt.Exec(strings.TrimSpace(row[1]), strings.TrimSpace(row[0]))
+		if err != nil {
+			tx.Rollback()
+			log.Fatal(err)
+		}
+		count++
 	}
-	fmt.Println("Found", len(files), "files in directory")
 
+	err = tx.Commit()
+	if err != nil {
+		tx.Rollback()
+		log.Fatal(err)
+	}
+	fmt.Printf("Time to set the sector of %d companies: %v\n", count, time.Since(startTime))
+}
+func insertStocksParallel(db *sql.DB, cp *checkpoint) {
+	startTime := time.Now()
+
+	stockFiles := listStockFiles()
+	fmt.Println("Found", len(stockFiles), "files in directory")
+
+	var files []string
+	for _, file := range stockFiles {
+		if cp.isDone(fileCheckpointKey(file)) {
+			continue
+		}
+		files = append(files, file)
+	}
+	if len(files) != len(stockFiles) {
+		fmt.Println("Resuming:", len(stockFiles)-len(files), "files already committed,", len(files), "remaining")
+	}
+
+	type cleanedFile struct {
+		sourcePath  string
+		cleanedPath string
+	}
 	fileChan := make(chan string, len(files))
-	// Shared slice to store cleaned file paths
-	var cleanedFiles []string
+	// Shared slices to store cleaned file paths and the files that cannot be cleaned
+	var cleanedFiles []cleanedFile
+	var insertErrors []string
 
 	var wg sync.WaitGroup
 	var mu sync.Mutex
@@ -162,12 +340,15 @@ func insertStocksParallel(db *sql.DB) {
 			for filePath := range fileChan {
 				cleanedFilePath, err := preprocessCSV(filepath.Join(util.GetProjectRoot(), filePath), getSymbol(filePath))
 				if err != nil {
-					fmt.Printf("Error preprocessing file %s: %v\n", filePath, err)
+					// The file is reported with the insert errors so the run fails and keeps the checkpoint
+					mu.Lock()
+					insertErrors = append(insertErrors, fmt.Sprintf("Error preprocessing file %s: %v", filePath, err))
+					mu.Unlock()
 					continue
 				}
 
 				mu.Lock()
-				cleanedFiles = append(cleanedFiles, cleanedFilePath)
+				cleanedFiles = append(cleanedFiles, cleanedFile{sourcePath: filePath, cleanedPath: cleanedFilePath})
 				mu.Unlock()
 			}
 		}()
@@ -175,9 +356,7 @@ func insertStocksParallel(db *sql.DB) {
 
 	// Feed the file paths
 	for _, file := range files {
-		if !file.IsDir() && strings.HasSuffix(file.Name(), ".csv") {
-			fileChan <- filepath.Join(dirPath, file.Name())
-		}
+		fileChan <- file
 	}
 	close(fileChan)
 	wg.Wait()
@@ -185,7 +364,7 @@ func insertStocksParallel(db *sql.DB) {
 	buckInsertTime := time.Now()
 
 	// Performance
-	_, err = db.Exec(fmt.Sprintf("ALTER TABLE %s SET (autovacuum_enabled = false)", tableNameStocks))
+	_, err := db.Exec(fmt.Sprintf("ALTER TABLE %s SET (autovacuum_enabled = false)", tableNameStocks))
 	if err != nil {
 		log.Fatal("Failed to disable autovacuum:", err)
 	}
@@ -196,8 +375,8 @@ func insertStocksParallel(db *sql.DB) {
 
 	// Create a channel for file insertion tasks and error reporting
 	type insertResult struct {
-		filePath string
-		err      error
+		file cleanedFile
+		err  error
 	}
 	resultChan := make(chan insertResult, len(cleanedFiles))
 
@@ -210,9 +389,9 @@ func insertStocksParallel(db *sql.DB) {
 	sem := make(chan struct{}, concurrentTxLimit)
 
 	// Launch workers to insert files in parallel
-	for _, cleanedFile := range cleanedFiles {
+	for _, cleaned := range cleanedFiles {
 		sem <- struct{}{} // Acquire semaphore
-		go func(file string) {
+		go func(file cleanedFile) {
 			defer func() { <-sem }() // Release semaphore when done
 
 			// Create transaction for this file
@@ -222,7 +401,7 @@ func insertStocksParallel(db *sql.DB) {
 				return
 			}
 
-			err = bulkInsertStockFile(file, tx)
+			err = bulkInsertStockFile(file.cleanedPath, tx)
 			if err != nil {
 				tx.Rollback()
 				resultChan <- insertResult{file, err}
@@ -234,29 +413,27 @@ func insertStocksParallel(db *sql.DB) {
 				return
 			}
 
-			_ = os.Remove(file)
+			// Record the commit right away so a failure in another file does not lose it. A resume
+			// without the record would insert the file twice, so the run fails once the settings
+			// are restored.
+			if err := cp.markDone(fileCheckpointKey(file.sourcePath)); err != nil {
+				resultChan <- insertResult{file, fmt.Errorf("committed but cannot be recorded in %s: %v. Add its key %q to the checkpoint before resuming", checkpointPath, err, fileCheckpointKey(file.sourcePath))}
+				return
+			}
+			_ = os.Remove(file.cleanedPath)
 			resultChan <- insertResult{file, nil}
-		}(cleanedFile)
+		}(cleaned)
 	}
 
 	// Collect results
-	var insertErrors []string
 	for i := 0; i < len(cleanedFiles); i++ {
 		result := <-resultChan
 		if result.err != nil {
-			insertErrors = append(insertErrors, fmt.Sprintf("Error with file %s: %v", result.filePath, result.err))
+			insertErrors = append(insertErrors, fmt.Sprintf("Error with file %s: %v", result.file.sourcePath, result.err))
 		}
 	}
 
-	// Check for any errors
-	if len(insertErrors) > 0 {
-		for _, err := range insertErrors {
-			fmt.Println(err)
-		}
-		log.Fatal("Failed to insert one or more files")
-	}
-
-	// Performance - restore settings
+	// Performance - restore settings (also when some files failed, the committed ones stay in the table)
 	_, err = db.Exec(fmt.Sprintf("ALTER TABLE %s SET (autovacuum_enabled = true)", tableNameStocks))
 	if err != nil {
 		log.Fatal("Failed to enable autovacuum:", err)
@@ -266,6 +443,14 @@ func insertStocksParallel(db *sql.DB) {
 		log.Fatal("Failed to enable triggers:", err)
 	}
 
+	// Check for any errors
+	if len(insertErrors) > 0 {
+		for _, err := range insertErrors {
+			fmt.Println(err)
+		}
+		log.Fatalf("Failed to insert %d file(s), the committed files are recorded in %s: fix the errors and rerun with -resume", len(insertErrors), checkpointPath)
+	}
+
 	fmt.Printf("Insert - Parallel data insertion (bulk) completed in %v\n", time.Since(buckInsertTime))
 	fmt.Printf("Insert time taken: %v\n", time.Since(startTime))
 }
@@ -288,15 +473,74 @@ func getSymbol(filePath string) string {
 func addIndex(db *sql.DB) {
 	startTime := time.Now()
 	// Create indexes for the stocks table
-	_, err := db.Exec(`CREATE INDEX idx_stocks_symbol_date ON ` + tableNameStocks + ` (symbol, date DESC);`)
+	_, err := db.Exec(`CREATE INDEX IF NOT EXISTS idx_stocks_symbol_date ON ` + tableNameStocks + ` (symbol, date DESC);`)
 	if err != nil {
 		println("Cannot create index for stocks table")
 		panic(err)
 	}
+	// The baskets look for the symbols with a price on each day of a date range
+	_, err = db.Exec(`CREATE INDEX IF NOT EXISTS idx_stocks_date_symbol ON ` + tableNameStocks + ` (date, symbol);`)
+	if err != nil {
+		println("Cannot create date index for stocks table")
+		panic(err)
+	}
 	fmt.Println("Adding index completed")
 	fmt.Printf("Time adding index: %v\n", time.Since(startTime))
 }
 
+// listStockFiles returns the relative path of every stock CSV file sorted by name
+func listStockFiles() []string {
+	files, err := os.ReadDir(stockDirPath)
+	if err != nil {
+		log.Fatal("Error reading directory:", err)
+	}
+	var stockFiles []string
+	for _, file := range files {
+		if !file.IsDir() && strings.HasSuffix(file.Name(), ".csv") && !strings.Contains(file.Name(), "_cleaned") {
+			stockFiles = append(stockFiles, filepath.Join(stockDirPath, file.Name()))
+		}
+	}
+	return stockFiles
+}
+
+// cleanStockRow prepares a raw CSV row for the COPY command. Rows that are incomplete
+// are skipped (keep is false) while rows with values that cannot be parsed return an
+// error because the COPY would reject the whole file.
+func cleanStockRow(row []string, symbol string) (cleaned []string, keep bool, err error) {
+	// Ensure the row has all expected columns
+	if len(row) != 7 {
+		return nil, false, nil // Skip rows with incorrect number of columns
+	}
+
+	// Check if any required field is empty (such as "open" column)
+	if row[1] == "" || row[6] == "" {
+		// Skip rows where essential columns are missing (e.g., "open" or "volume")
+		return nil, false, nil
+	}
+
+	// Clean volume column (index 6) if necessary (convert to integer format)
+	volume := row[6]
+	if strings.Contains(volume, ".") {
+		volume = strings.Split(volume, ".")[0] // Trim decimal part
+	}
+	row[6] = volume
+
+	if _, err := time.Parse("2006-01-02", row[0]); err != nil {
+		return nil, false, fmt.Errorf("invalid date %q", row[0])
+	}
+	columns := []string{"open", "high", "low", "close", "adj_close"}
+	for i, column := range columns {
+		if _, err := strconv.ParseFloat(row[i+1], 64); err != nil {
+			return nil, false, fmt.Errorf("invalid %s %q", column, row[i+1])
+		}
+	}
+	if _, err := strconv.ParseInt(row[6], 10, 64); err != nil {
+		return nil, false, fmt.Errorf("invalid volume %q", row[6])
+	}
+
+	return append(row, symbol), true, nil // Add the symbol to the end of the row
+}
+
 func preprocessCSV(filePath string, symbol string) (string, error) {
 	tempFilePath := fmt.Sprintf("%s_cleaned.csv", filePath)
 
@@ -324,7 +568,7 @@ func preprocessCSV(filePath string, symbol string) (string, error) {
 	writer.Write(header)
 
 	// Read and process each row
-	for {
+	for line := 2; ; line++ {
 		row, err := reader.Read()
 		if err == io.EOF {
 			break
@@ -333,44 +577,194 @@ func preprocessCSV(filePath string, symbol string) (string, error) {
 			return "", err
 		}
 
-		// Ensure the row has all expected columns
-		if len(row) != 7 {
-			continue // Skip rows with incorrect number of columns
+		cleaned, keep, err := cleanStockRow(row, symbol)
+		if err != nil {
+			return "", fmt.Errorf("line %d: %v", line, err)
 		}
-
-		// Check if any required field is empty (such as "open" column)
-		if row[1] == "" || row[6] == "" {
-			// Skip rows where essential columns are missing (e.g., "open" or "volume")
+		if !keep {
 			continue
 		}
 
-		// Clean volume column (index 6) if necessary (convert to integer format)
-		volume := row[6]
-		if strings.Contains(volume, ".") {
-			volume = strings.Split(volume, ".")[0] // Trim decimal part
-		}
-		row[6] = volume
-		row = append(row, symbol) // Add an empty string to the end of the row
-
 		// Write the cleaned row to the output file
-		writer.Write(row)
+		writer.Write(cleaned)
 	}
 
 	return tempFilePath, nil
 }
 
+// validateStockCSV parses a stock file the same way preprocessCSV does but without
+// writing anything. It returns the number of rows that would be inserted and skipped.
+func validateStockCSV(filePath string, symbol string) (rows int, skipped int, err error) {
+	inputFile, err := os.Open(filePath)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer inputFile.Close()
+
+	reader := csv.NewReader(inputFile)
+	if _, err := reader.Read(); err != nil {
+		return 0, 0, fmt.Errorf("cannot read header: %v", err)
+	}
+	for line := 2; ; line++ {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return rows, skipped, err
+		}
+		_, keep, err := cleanStockRow(row, symbol)
+		if err != nil {
+			return rows, skipped, fmt.Errorf("line %d: %v", line, err)
+		}
+		if keep {
+			rows++
+		} else {
+			skipped++
+		}
+	}
+	return rows, skipped, nil
+}
+
+// validateCompanyInfo parses the company information file and checks that every
+// symbol is present only once
+func validateCompanyInfo() (rows int, err error) {
+	file, err := os.Open(filepath.Join(util.GetProjectRoot(), companyInfoPath))
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = maxFieldCVS
+	reader.Comma = ','
+	reader.LazyQuotes = true
+	if _, err := reader.Read(); err != nil {
+		return 0, fmt.Errorf("cannot read header: %v", err)
+	}
+	symbols := make(map[string]bool)
+	for line := 2; ; line++ {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return rows, err
+		}
+		if err := checkCompanyRow(row, symbols); err != nil {
+			return rows, fmt.Errorf("line %d: %v", line, err)
+		}
+		rows++
+	}
+	return rows, nil
+}
+
+// checkCompanyRow checks a row of the company information file has a symbol and a name,
+// and that the symbol is not in the symbols already read. The symbol is added to them.
+func checkCompanyRow(row []string, symbols map[string]bool) error {
+	if row[1] == "" || row[2] == "" {
+		return errors.New("missing symbol or name")
+	}
+	if symbols[row[1]] {
+		return fmt.Errorf("duplicate symbol %s", row[1])
+	}
+	symbols[row[1]] = true
+	return nil
+}
+
+// dryRunValidation parses and validates every file without touching the database.
+// It returns false when at least one file would fail the real load.
+func dryRunValidation() bool {
+	startTime := time.Now()
+	stockFiles := listStockFiles()
+	fmt.Println("Dry run: validating", len(stockFiles), "stock files")
+
+	fileChan := make(chan string, len(stockFiles))
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var validationErrors []string
+	totalRows := 0
+	totalSkipped := 0
+
+	for i := 0; i < maxWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for filePath := range fileChan {
+				rows, skipped, err := validateStockCSV(filepath.Join(util.GetProjectRoot(), filePath), getSymbol(filePath))
+				mu.Lock()
+				totalRows += rows
+				totalSkipped += skipped
+				if err != nil {
+					validationErrors = append(validationErrors, fmt.Sprintf("Error with file %s: %v", filePath, err))
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	for _, file := range stockFiles {
+		fileChan <- file
+	}
+	close(fileChan)
+	wg.Wait()
+
+	companyRows, err := validateCompanyInfo()
+	if err != nil {
+		validationErrors = append(validationErrors, fmt.Sprintf("Error with file %s: %v", companyInfoPath, err))
+	}
+
+	for _, validationError := range validationErrors {
+		fmt.Println(validationError)
+	}
+	fmt.Printf("Dry run: %d stock rows would be inserted, %d skipped, %d companies\n", totalRows, totalSkipped, companyRows)
+	fmt.Printf("Dry run: %d error(s) found in %v\n", len(validationErrors), time.Since(startTime))
+	return len(validationErrors) == 0
+}
+
 func main() {
-	// Create the SQL Lite database if it doesn't exist
-	// Create a connection to the SQL Lite database
+	flag.Parse()
 	println("Max workers: ", maxWorkers)
+	if *dryRun {
+		if !dryRunValidation() {
+			os.Exit(1)
+		}
+		return
+	}
+
+	cp, err := openCheckpoint(checkpointPath, *resume)
+	if err != nil {
+		log.Fatal("Cannot open checkpoint file:", err)
+	}
 	_, dbHost, dbPort, dbUser, dbPassword, dbName, _ := util.GetDBEnv()
 	database.ConnectDB(dbHost, dbPort, dbUser, dbPassword, dbName)
 	db := database.GetRawDB()
+	defer db.Close()
 	startTime := time.Now()
-	createTables(db)
-	insertStocksParallel(db)
-	insertCompanyInfo(db)
-	addIndex(db)
+	if !cp.isDone(stepCreateTables) {
+		createTables(db)
+		if err := cp.markDone(stepCreateTables); err != nil {
+			log.Fatal("Cannot write checkpoint:", err)
+		}
+	}
+	insertStocksParallel(db, cp)
+	if !cp.isDone(stepCompanyInfo) {
+		insertCompanyInfo(db)
+		if err := cp.markDone(stepCompanyInfo); err != nil {
+			log.Fatal("Cannot write checkpoint:", err)
+		}
+	}
+	if !cp.isDone(stepSectors) {
+		insertSectors(db)
+		if err := cp.markDone(stepSectors); err != nil {
+			log.Fatal("Cannot write checkpoint:", err)
+		}
+	}
+	if !cp.isDone(stepIndex) {
+		addIndex(db)
+		if err := cp.markDone(stepIndex); err != nil {
+			log.Fatal("Cannot write checkpoint:", err)
+		}
+	}
+	cp.complete()
 	fmt.Printf("Total time taken: %v\n", time.Since(startTime))
-	defer db.Close()
 }
@@ -2,10 +2,13 @@ import "./App.css";
 import { useMutation, useQuery } from "@tanstack/react-query";
 import { StockCanvas } from "./StockCanvas";
 import {
+  FieldError,
   SolutionDayPrice,
   SolutionRequest,
   SolutionResponse,
   StockPublic,
+  StocksResponse,
+  ValidationErrorResponse,
 } from "./model/stock";
 import { useCallback, useEffect, useMemo, useState } from "react";
 import { xPixelToDay, yPixelToPrice } from "./logic/canvasLogic";
@@ -13,14 +16,26 @@ import {
   Number_initial_stock_shown,
   User_stock_to_guess,
 } from "./dynamicConstants";
-import { getApiUrl } from "./logic/apiLogics";
+import { getApiUrl, getPlayerId } from "./logic/apiLogics";
 import { APP_CONSTANTS } from "./model/app";
 import { LoadingCanvas } from "./LoadingCanvas";
-async function getStocks(): Promise<StockPublic[]> {
-  const data = await fetch(`${getApiUrl()}/stocks`);
+async function getStocks(): Promise<StocksResponse> {
+  // The round is served to the player, only its solution is accepted
+  const data = await fetch(`${getApiUrl()}/stocks`, {
+    headers: { "X-Player-ID": getPlayerId() },
+  });
   return data.json();
 }
 
+// SolutionError carries the fields the server refused in the solution
+class SolutionError extends Error {
+  fields: FieldError[];
+  constructor(message: string, fields: FieldError[]) {
+    super(message);
+    this.fields = fields;
+  }
+}
+
 async function postUserDayPrice(
   requestData: SolutionRequest
 ): Promise<SolutionResponse> {
@@ -28,10 +43,19 @@ async function postUserDayPrice(
     method: "POST",
     headers: {
       "Content-Type": "application/json",
+      "X-Player-ID": getPlayerId(),
     },
     body: JSON.stringify(requestData),
   });
-
+  if (!data.ok) {
+    const body: Partial<ValidationErrorResponse> = await data
+      .json()
+      .catch(() => ({}));
+    throw new SolutionError(
+      body.error ?? `Submit failed (${data.status})`,
+      body.fields ?? []
+    );
+  }
   return data.json();
 }
 
@@ -40,6 +64,9 @@ function App() {
     undefined
   );
   const [responseCounter, setResponseCounter] = useState(0);
+  const [submitError, setSubmitError] = useState<SolutionError | undefined>(
+    undefined
+  );
   const [isNarrowView, setIsNarrowView] = useState(false);
   const { isPending, isError, data, error, refetch, isFetching } = useQuery({
     queryKey: ["stocks"],
@@ -59,7 +86,7 @@ function App() {
   }, []);
 
   useEffect(() => {
-    setDataToShow(data ?? []);
+    setDataToShow(data?.stocks ?? []);
   }, [data]);
   const postSolution = useMutation({
     mutationFn: (dayPrice: SolutionDayPrice[]) => {
@@ -74,6 +101,7 @@ function App() {
         symbolUUID: lastEntry.symbol_uuid,
         afterDate: lastEntry.date,
         estimatedDayPrices: dayPrice.slice(0, User_stock_to_guess),
+        roundId: data?.round.id,
       });
     },
   });
@@ -104,59 +132,86 @@ function App() {
     if (dataToShow.length === 0) {
       return [];
     }
-    let lastDay = -1;
-    const onPricePerDay: SolutionDayPrice[] = [];
-    let sumDay = 0;
-    let countDay = 0;
+    // The last point drawn on a day is its price, the days shown are ignored
+    const pricePerDay = new Map<number, number>();
     for (const dayPrice of userDrawnPoints) {
       const day = xPixelToDay(
         dayPrice.x,
         APP_CONSTANTS.canvas_width,
         Number_initial_stock_shown + User_stock_to_guess
       );
-      const price = yPixelToPrice(
-        dayPrice.y,
-        APP_CONSTANTS.canvas_height,
-        minPrice,
-        maxPrice
-      );
-      if (lastDay === -1 || lastDay === day) {
-        // First point or same day
-        lastDay = day;
-        sumDay += price;
-        countDay += 1;
-      } else {
-        // New day
-        onPricePerDay.push({ day: lastDay, price: sumDay / countDay });
-        lastDay = day;
-        sumDay = price;
-        countDay = 1;
+      if (
+        day < Number_initial_stock_shown ||
+        day >= Number_initial_stock_shown + User_stock_to_guess
+      ) {
+        continue;
       }
+      pricePerDay.set(
+        day,
+        yPixelToPrice(
+          dayPrice.y,
+          APP_CONSTANTS.canvas_height,
+          minPrice,
+          maxPrice
+        )
+      );
     }
-    // Add the last day
-    if (lastDay !== -1) {
-      onPricePerDay.push({ day: lastDay, price: sumDay / countDay });
+    const drawn = [...pricePerDay.entries()]
+      .map(([day, price]) => ({ day, price }))
+      .sort((a, b) => a.day - b.day);
+    // A day skipped by a fast stroke is interpolated between its neighbours
+    const onPricePerDay: SolutionDayPrice[] = [];
+    for (const dayPrice of drawn) {
+      const previous = onPricePerDay[onPricePerDay.length - 1];
+      if (previous !== undefined) {
+        const gap = dayPrice.day - previous.day;
+        for (let day = previous.day + 1; day < dayPrice.day; day++) {
+          const ratio = (day - previous.day) / gap;
+          onPricePerDay.push({
+            day,
+            price: previous.price + (dayPrice.price - previous.price) * ratio,
+          });
+        }
+      }
+      onPricePerDay.push(dayPrice);
     }
     return onPricePerDay;
   }, [dataToShow, maxPrice, minPrice, userDrawnPoints]);
+  // Every day to guess must be drawn, from the first to the last
+  const isDrawingComplete =
+    getUserDrawnPrices().length === User_stock_to_guess;
 
   const submitSolution = useCallback(async () => {
     const userDayPrices = getUserDrawnPrices();
-    const response = await postSolution.mutateAsync(userDayPrices);
-    setResponse(response);
-    setResponseCounter(() => responseCounter + 1);
+    if (userDayPrices.length !== User_stock_to_guess) {
+      return;
+    }
+    setSubmitError(undefined);
+    try {
+      const response = await postSolution.mutateAsync(userDayPrices);
+      setResponse(response);
+      setResponseCounter(() => responseCounter + 1);
+    } catch (e) {
+      setSubmitError(
+        e instanceof SolutionError
+          ? e
+          : new SolutionError(e instanceof Error ? e.message : String(e), [])
+      );
+    }
   }, [getUserDrawnPrices, postSolution, responseCounter]);
 
   const clear = () => {
     setUserDrawnPoints([]);
     setResponse(undefined);
+    setSubmitError(undefined);
   };
 
   if (isError) {
     return <div>Error: {error.message}</div>;
   }
   const isNewDisabled = isFetching || response === undefined;
-  const isSubmitDisabled = isFetching || response !== undefined;
+  const isSubmitDisabled =
+    isFetching || response !== undefined || !isDrawingComplete;
   return (
     <div className={"app-container" + (isNarrowView ? " narrow" : "")}>
       <h1>Guess the stock price</h1>
@@ -221,6 +276,20 @@ function App() {
           Submit
         </button>
       </div>
+      {submitError === undefined ? (
+        ""
+      ) : (
+        <div id="submit-error">
+          <div>{submitError.message}</div>
+          <ul>
+            {submitError.fields.map((field) => (
+              <li key={field.field}>
+                {field.field}: {field.message}
+              </li>
+            ))}
+          </ul>
+        </div>
+      )}
       <div id="solution">
         {response === undefined ? (
           ""
@@ -1,2 +1,43 @@
 export const Number_initial_stock_shown = 40;
 export const User_stock_to_guess = 10;
+export const Direction_up = "up";
+export const Direction_down = "down";
+export const Direction_flat = "flat";
+export const Band_coverage_percent = 80;
+export const Mode_price = "price";
+export const Mode_candle = "candle";
+export const Mode_direction = "direction";
+export const Difficulty_easy = "easy";
+export const Difficulty_medium = "medium";
+export const Difficulty_hard = "hard";
+export const Room_state_lobby = "lobby";
+export const Room_state_playing = "playing";
+export const Room_state_results = "results";
+export const Room_event_state = "state";
+export const Room_event_round_started = "round_started";
+export const Room_event_results = "results";
+export const Room_event_error = "error";
+export const Room_message_start = "start";
+export const Room_message_submit = "submit";
+export const Room_round_seconds = 60;
+export const Trade_buy = "buy";
+export const Trade_sell = "sell";
+export const Trade_hold = "hold";
+export const Trading_initial_cash = 10000;
+export const Ticker_candidates = 4;
+export const Ticker_speed_seconds = 30;
+export const Basket_min_symbols = 3;
+export const Basket_max_symbols = 5;
+export const Basket_method_rank = "rank";
+export const Basket_method_weights = "weights";
+export const Hint_sector = "sector";
+export const Hint_next_close = "next_close";
+export const Hint_rsi = "rsi";
+export const Xp_per_round = 10;
+export const Xp_per_level = 100;
+export const Error_round_required = "round_required";
+export const Error_round_expired = "round_expired";
+export const Error_round_solved = "round_solved";
+export const Error_round_not_served = "round_not_served";
+export const Error_invalid_solution = "invalid_solution";
+export const Guess_price_max_ratio = 5;
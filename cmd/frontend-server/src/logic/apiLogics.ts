@@ -1,3 +1,15 @@
 export function getApiUrl() {
   return `${import.meta.env.VITE_API_URL}:${import.meta.env.VITE_API_PORT}`;
 }
+
+const PLAYER_ID_KEY = "playerId";
+
+// The player id is generated once per browser to group the rounds of a player
+export function getPlayerId(): string {
+  let playerId = localStorage.getItem(PLAYER_ID_KEY);
+  if (playerId === null) {
+    playerId = crypto.randomUUID();
+    localStorage.setItem(PLAYER_ID_KEY, playerId);
+  }
+  return playerId;
+}
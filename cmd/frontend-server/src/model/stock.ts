@@ -25,10 +25,18 @@ export interface SolutionDayPrice {
   day: number;
   price: number;
 }
+export interface SolutionDayBand {
+  day: number;
+  lower: number;
+  upper: number;
+}
 export interface SolutionRequest {
   afterDate: string;
   symbolUUID: string;
   estimatedDayPrices: SolutionDayPrice[];
+  estimatedDayBands?: SolutionDayBand[];
+  indicators?: string[];
+  roundId?: string;
 }
 
 export interface BB20Payload {
@@ -43,11 +51,477 @@ export interface SolutionScore {
   inOpenClose: number;
   inBollinger: number;
   inDirection: number;
+  hintPenalty?: number;
+  speedBonus?: number;
+}
+export interface IndicatorPoint {
+  date: string;
+  value: number;
 }
+
+export interface IndicatorOverlay {
+  name: string;
+  lines: Record<string, IndicatorPoint[]>;
+}
+
+export interface BandSolutionScore {
+  total: number;
+  inBand: number;
+  coverage: number;
+  intervalScore: number;
+}
+
+export interface BotScore {
+  name: string;
+  score: SolutionScore;
+  prices: SolutionDayPrice[];
+}
+
+export interface BotSummary {
+  beaten: number;
+  total: number;
+}
+
 export interface SolutionResponse {
   symbol: string;
   name: string;
   stocks: StockPublic[];
   score: SolutionScore;
   bb20: Record<string, BB20Payload>;
+  bands?: SolutionDayBand[];
+  bandScore?: BandSolutionScore;
+  bots: BotScore[];
+  botSummary: BotSummary;
+  indicators?: Record<string, IndicatorOverlay>;
+  difficulty?: string;
+  roundId?: string;
+  percentiles?: SolutionPercentiles;
+  achievements?: Achievement[];
+}
+
+export interface PercentileRank {
+  percentile: number;
+  players: number;
+}
+export interface SolutionPercentiles {
+  window?: PercentileRank;
+  difficulty?: PercentileRank;
+}
+
+export interface SolutionDayCandle {
+  day: number;
+  open: number;
+  high: number;
+  low: number;
+  close: number;
+}
+export interface CandleSolutionRequest {
+  afterDate: string;
+  symbolUUID: string;
+  estimatedDayCandles: SolutionDayCandle[];
+  roundId?: string;
+}
+
+export interface CandleSolutionScore {
+  total: number;
+  inRange: number;
+  inBody: number;
+  inColor: number;
+  hintPenalty?: number;
+  speedBonus?: number;
+}
+export interface CandleSolutionResponse {
+  symbol: string;
+  name: string;
+  stocks: StockPublic[];
+  score: CandleSolutionScore;
+}
+
+export interface SolutionDayDirection {
+  day: number;
+  direction: string;
+}
+export interface DirectionSolutionRequest {
+  afterDate: string;
+  symbolUUID: string;
+  estimatedDayDirections: SolutionDayDirection[];
+  roundId?: string;
+}
+
+export interface DirectionSolutionScore {
+  total: number;
+  inDirection: number;
+  inStreak: number;
+  correct: number;
+  longestStreak: number;
+  hintPenalty?: number;
+  speedBonus?: number;
+}
+export interface DirectionSolutionResponse {
+  symbol: string;
+  name: string;
+  stocks: StockPublic[];
+  score: DirectionSolutionScore;
+  directions: string[];
+}
+
+export interface DifficultyFeatures {
+  realizedVolatility: number;
+  maxDrawdown: number;
+  gapFrequency: number;
+  trendStrength: number;
+}
+export interface RoundMetadata {
+  difficulty: string;
+  features: DifficultyFeatures;
+  id?: string;
+  scenario?: string;
+  timeLimits?: Record<string, number>;
+}
+export interface StocksResponse {
+  round: RoundMetadata;
+  stocks: StockPublic[];
+}
+
+export interface RoundReplayResponse {
+  id: string;
+  mode: string;
+  symbol: string;
+  name: string;
+  afterDate: string;
+  difficulty: string;
+  createdAt: string;
+  history: StockPublic[];
+  stocks: StockPublic[];
+  estimatedDayPrices: SolutionDayPrice[];
+  score: SolutionScore;
+  bb20: Record<string, BB20Payload>;
+  indicators?: Record<string, IndicatorOverlay>;
+}
+
+export interface Round {
+  id: string;
+  playerId: string;
+  mode: string;
+  symbol: string;
+  symbolUUID: string;
+  afterDate: string;
+  historyStartDate: string;
+  futureEndDate: string;
+  difficulty: string;
+  score: SolutionScore;
+  estimatedDayPrices: SolutionDayPrice[];
+  lastClose: number;
+  actualCloses: number[];
+  sector: string;
+  hints: string[];
+  servedAt?: string;
+  createdAt: string;
+}
+export interface PlayerRoundsResponse {
+  rounds: Round[];
+  page: number;
+  pageSize: number;
+  total: number;
+}
+
+export interface ComponentStats {
+  average: number;
+  median: number;
+}
+export interface ScoreStats {
+  total: ComponentStats;
+  inLowHigh: ComponentStats;
+  inOpenClose: ComponentStats;
+  inBollinger: ComponentStats;
+  inDirection: ComponentStats;
+}
+export interface MAPEPoint {
+  date: string;
+  mape: number;
+  rounds: number;
+}
+export interface SectorStats {
+  sector: string;
+  rounds: number;
+  averageScore: number;
+  mape?: number;
+}
+export interface PlayerStats {
+  rounds: number;
+  analyzedRounds: number;
+  score: ScoreStats;
+  directionalAccuracy?: number;
+  mape?: number;
+  mapeOverTime: MAPEPoint[];
+  sectors: SectorStats[];
+}
+
+export interface CalibrationBin {
+  label: string;
+  count: number;
+  meanPredicted: number;
+  meanActual: number;
+}
+export interface Calibration {
+  rounds: number;
+  days: number;
+  bias: number;
+  guessVolatility: number;
+  realizedVolatility: number;
+  dispersionRatio?: number;
+  curve: CalibrationBin[];
+}
+
+export interface RoomPlayer {
+  seat: number;
+  name: string;
+  host: boolean;
+  connected: boolean;
+  submitted: boolean;
+  total: number;
+}
+export interface RoomRound {
+  number: number;
+  stocks: StockPublic[];
+  deadline: string;
+}
+export interface RoomRanking {
+  rank: number;
+  seat: number;
+  name: string;
+  total: number;
+}
+export interface RoomState {
+  code: string;
+  state: string;
+  players: RoomPlayer[];
+  round?: RoomRound;
+  ranking: RoomRanking[];
+}
+export interface RoomScore {
+  seat: number;
+  name: string;
+  submitted: boolean;
+  score: SolutionScore;
+  estimatedDayPrices: SolutionDayPrice[];
+}
+export interface RoomResults {
+  round: number;
+  symbol: string;
+  name: string;
+  stocks: StockPublic[];
+  scores: RoomScore[];
+}
+export interface RoomEvent {
+  type: string;
+  room: RoomState;
+  results?: RoomResults;
+  error?: string;
+}
+export interface RoomMessage {
+  type: string;
+  estimatedDayPrices?: SolutionDayPrice[];
+}
+export interface Tournament {
+  id: string;
+  name: string;
+  startsAt: string;
+  endsAt: string;
+  rounds: number;
+  entries: number;
+  createdAt: string;
+}
+export interface TournamentRoundResponse {
+  tournamentId: string;
+  position: number;
+  rounds: number;
+  stocks: StockPublic[];
+}
+export interface TournamentSolutionRequest {
+  estimatedDayPrices: SolutionDayPrice[];
+}
+export interface TournamentSolutionResponse {
+  position: number;
+  roundId?: string;
+  symbol: string;
+  name: string;
+  score: SolutionScore;
+  stocks: StockPublic[];
+  achievements?: Achievement[];
+}
+export interface TournamentStanding {
+  rank: number;
+  entry: number;
+  name: string;
+  rounds: number;
+  score: SolutionScore;
+  lastSubmittedAt?: string;
+}
+export interface TradeRequest {
+  action: string;
+  quantity?: number;
+}
+export interface TradeDecision {
+  day: number;
+  action: string;
+  quantity: number;
+  price: number;
+  cash: number;
+  shares: number;
+}
+export interface TradingSession {
+  id: string;
+  symbolUUID: string;
+  afterDate: string;
+  days: number;
+  day: number;
+  initialCash: number;
+  decisions: TradeDecision[];
+  cash: number;
+  shares: number;
+  createdAt: string;
+}
+export interface TradingPerformance {
+  finalValue: number;
+  profitLoss: number;
+  return: number;
+  maxDrawdown: number;
+  sharpe: number | null;
+  equity: number[];
+}
+export interface TradingResult {
+  strategy: TradingPerformance;
+  buyAndHold: TradingPerformance;
+}
+export interface StartTradingRequest {
+  difficulty?: string;
+}
+export interface TradingResponse {
+  session: TradingSession;
+  stocks?: StockPublic[];
+  revealed: Stock[];
+  finished: boolean;
+  symbol?: string;
+  name?: string;
+  result?: TradingResult;
+}
+export interface TickerCandidate {
+  id: string;
+  symbol: string;
+  name: string;
+}
+export interface TickerChallengeResponse {
+  id: string;
+  stocks: StockPublic[];
+  candidates: TickerCandidate[];
+  servedAt: string;
+}
+export interface TickerAnswerRequest {
+  optionId: string;
+}
+export interface TickerScore {
+  correct: boolean;
+  seconds: number;
+  speed: number;
+  total: number;
+}
+export interface TickerAnswerResponse {
+  answer: TickerCandidate;
+  score: TickerScore;
+}
+export interface BasketStock {
+  symbolUUID: string;
+  stocks: StockPublic[];
+}
+export interface BasketWindow {
+  id: string;
+  afterDate: string;
+  stocks: BasketStock[];
+}
+export interface BasketWeight {
+  symbolUUID: string;
+  weight: number;
+}
+export interface BasketSolutionRequest {
+  basketId: string;
+  ranking?: string[];
+  weights?: BasketWeight[];
+}
+export interface BasketScore {
+  method: string;
+  spearman?: number;
+  portfolioReturn?: number;
+  equalWeightReturn: number;
+  bestReturn: number;
+  worstReturn: number;
+  total: number;
+}
+export interface BasketResult {
+  symbolUUID: string;
+  symbol: string;
+  name: string;
+  return: number;
+  rank: number;
+  stocks: Stock[];
+}
+export interface BasketSolutionResponse {
+  score: BasketScore;
+  results: BasketResult[];
+}
+
+export interface Scenario {
+  id: string;
+  name: string;
+  startDate: string;
+  endDate: string;
+  symbols: string[];
+  createdAt: string;
+}
+export interface ScenarioRequest {
+  id: string;
+  name: string;
+  startDate: string;
+  endDate: string;
+  symbols: string[];
+}
+export interface HintRequest {
+  type: string;
+}
+export interface HintResponse {
+  type: string;
+  sector?: string;
+  nextClose?: number;
+  overlay?: Record<string, IndicatorOverlay>;
+  penalty: number;
+  hints: string[];
+}
+export interface Achievement {
+  id: string;
+  name: string;
+  description: string;
+  target: number;
+}
+export interface AchievementProgress extends Achievement {
+  count: number;
+  unlockedAt?: string;
+}
+export interface ProgressResponse {
+  xp: number;
+  level: number;
+  nextLevelXP: number;
+  rounds: number;
+  currentStreak: number;
+  longestStreak: number;
+  lastPlayedDate: string;
+  achievements: AchievementProgress[];
+}
+export interface FieldError {
+  field: string;
+  message: string;
+}
+export interface ValidationErrorResponse {
+  error: string;
+  code: string;
+  fields: FieldError[];
 }
@@ -0,0 +1,54 @@
+package card
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Version of the drawing, changing it ignores the cards cached with the previous layout
+const cardVersion = 1
+
+// CardCache keeps the rendered cards on disk. A round never changes once saved so a
+// card does not expire.
+type CardCache struct {
+	Dir string
+}
+
+// Load returns the cached card, false when it was not rendered yet
+func (c *CardCache) Load(roundId string, format string) ([]byte, bool) {
+	data, err := os.ReadFile(c.path(roundId, format))
+	if err != nil {
+		return nil, false
+	}
+	return data, true
+}
+
+// Store writes the card in a temporary file renamed at the end so a concurrent Load
+// never reads a partial card
+func (c *CardCache) Store(roundId string, format string, data []byte) error {
+	if err := os.MkdirAll(c.Dir, 0o755); err != nil {
+		return fmt.Errorf("cannot create the card cache directory: %v", err)
+	}
+	file, err := os.CreateTemp(c.Dir, "card-*.tmp")
+	if err != nil {
+		return fmt.Errorf("cannot create the card file: %v", err)
+	}
+	defer os.Remove(file.Name()) // No effect once renamed
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return fmt.Errorf("cannot write the card file: %v", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("cannot write the card file: %v", err)
+	}
+	if err := os.Rename(file.Name(), c.path(roundId, format)); err != nil {
+		return fmt.Errorf("cannot move the card file: %v", err)
+	}
+	return nil
+}
+
+// path expects a round id that was validated, it is used as a file name
+func (c *CardCache) path(roundId string, format string) string {
+	return filepath.Join(c.Dir, fmt.Sprintf("%s-v%d.%s", roundId, cardVersion, format))
+}
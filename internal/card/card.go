@@ -0,0 +1,138 @@
+package card
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"stockgame/internal/model"
+	"strings"
+)
+
+type CardLogic interface {
+	RenderPNG(card model.RoundCard) ([]byte, error)
+	RenderSVG(card model.RoundCard) []byte
+}
+
+type CardLogicImpl struct {
+	CardLogic
+}
+
+// Size of the card, the usual size of a link preview
+const (
+	Width  = 1200
+	Height = 630
+)
+
+// Area of the chart inside the card
+const (
+	chartLeft   = 60
+	chartRight  = Width - 60
+	chartTop    = 170
+	chartBottom = Height - 50
+)
+
+var (
+	backgroundColor = color.RGBA{R: 0x1e, G: 0x1e, B: 0x2e, A: 0xff}
+	textColor       = color.RGBA{R: 0xee, G: 0xee, B: 0xee, A: 0xff}
+	subtleColor     = color.RGBA{R: 0x88, G: 0x88, B: 0x99, A: 0xff}
+	historyColor    = color.RGBA{R: 0xaa, G: 0xaa, B: 0xbb, A: 0xff}
+	guessColor      = color.RGBA{R: 0xff, G: 0xa5, B: 0x00, A: 0xff}
+	actualColor     = color.RGBA{R: 0x4c, G: 0xaf, B: 0x50, A: 0xff}
+)
+
+// line is a series of prices drawn as a polyline
+type line struct {
+	points [][2]float64
+	color  color.RGBA
+}
+
+// text is a line of text with its top left corner
+type text struct {
+	x, y  float64
+	size  float64 // Height of a capital letter in pixels
+	value string
+	color color.RGBA
+}
+
+// layout is what both renderers draw, in pixels
+type layout struct {
+	lines []line
+	texts []text
+}
+
+// newLayout places the price lines and the texts of the card. The guess and the real
+// prices start from the last close shown so the paths are connected to the history.
+func newLayout(card model.RoundCard) layout {
+	totalDays := len(card.History) + max(len(card.Guess), len(card.Actual))
+	minPrice, maxPrice := math.Inf(1), math.Inf(-1)
+	for _, series := range [][]float64{card.History, card.Guess, card.Actual} {
+		for _, price := range series {
+			minPrice = math.Min(minPrice, price)
+			maxPrice = math.Max(maxPrice, price)
+		}
+	}
+	if totalDays == 0 {
+		minPrice, maxPrice = 0, 1
+	}
+	padding := (maxPrice - minPrice) * 0.05
+	if padding == 0 {
+		padding = 1
+	}
+	minPrice -= padding
+	maxPrice += padding
+	x := func(day int) float64 {
+		if totalDays <= 1 {
+			return chartLeft
+		}
+		return chartLeft + float64(day)*(chartRight-chartLeft)/float64(totalDays-1)
+	}
+	y := func(price float64) float64 {
+		return chartBottom - (price-minPrice)*(chartBottom-chartTop)/(maxPrice-minPrice)
+	}
+	toLine := func(start int, prices []float64, lineColor color.RGBA) line {
+		points := [][2]float64{}
+		if start > 0 && len(prices) > 0 {
+			points = append(points, [2]float64{x(start - 1), y(card.History[start-1])})
+		}
+		for i, price := range prices {
+			points = append(points, [2]float64{x(start + i), y(price)})
+		}
+		return line{points: points, color: lineColor}
+	}
+
+	title := card.Symbol
+	if card.Name != "" {
+		title += " - " + card.Name
+	}
+	details := fmt.Sprintf("GUESSED FROM %s", card.AfterDate)
+	if card.Difficulty != "" {
+		details += " - " + strings.ToUpper(card.Difficulty)
+	}
+	score := fmt.Sprintf("SCORE %d", card.Score.Total)
+	breakdown := fmt.Sprintf("DIRECTION %d  LOW-HIGH %d  OPEN-CLOSE %d  BOLLINGER %d",
+		card.Score.InDirection, card.Score.InLowHigh, card.Score.InOpenClose, card.Score.InBollinger)
+	return layout{
+		lines: []line{
+			toLine(0, card.History, historyColor),
+			toLine(len(card.History), card.Actual, actualColor),
+			toLine(len(card.History), card.Guess, guessColor),
+		},
+		texts: []text{
+			{x: chartLeft, y: 30, size: 28, value: truncate(title, 32), color: textColor},
+			{x: chartLeft, y: 75, size: 14, value: details, color: subtleColor},
+			{x: chartLeft, y: 110, size: 14, value: breakdown, color: subtleColor},
+			{x: chartRight - textWidth(score, 28), y: 30, size: 28, value: score, color: guessColor},
+			{x: chartRight - textWidth("GUESS", 14), y: 75, size: 14, value: "GUESS", color: guessColor},
+			{x: chartRight - textWidth("ACTUAL", 14), y: 110, size: 14, value: "ACTUAL", color: actualColor},
+		},
+	}
+}
+
+// truncate keeps the first characters of the text
+func truncate(value string, length int) string {
+	runes := []rune(value)
+	if len(runes) <= length {
+		return value
+	}
+	return string(runes[:length-3]) + "..."
+}
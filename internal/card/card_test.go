@@ -0,0 +1,100 @@
+package card
+
+import (
+	"bytes"
+	"image/png"
+	"stockgame/internal/model"
+	"strings"
+	"testing"
+)
+
+func testCard() model.RoundCard {
+	return model.RoundCard{
+		Symbol:     "AAPL",
+		Name:       "Apple <Inc.>",
+		AfterDate:  "2023-10-02",
+		Difficulty: model.Difficulty_hard,
+		Score:      model.UserScoreResponse{Total: 42, InDirection: 12},
+		History:    []float64{100, 101, 102, 101},
+		Guess:      []float64{103, 105},
+		Actual:     []float64{99, 98},
+	}
+}
+
+func TestRenderPNG(t *testing.T) {
+	logic := &CardLogicImpl{}
+	data, err := logic.RenderPNG(testCard())
+	if err != nil {
+		t.Fatalf("Expected no error and not %v", err)
+	}
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Expected a valid PNG and not %v", err)
+	}
+	if img.Bounds().Dx() != Width || img.Bounds().Dy() != Height {
+		t.Errorf("Expected a %dx%d card and not %v", Width, Height, img.Bounds())
+	}
+	// The guess line goes from the last close shown to the first guess
+	cardLayout := newLayout(testCard())
+	guess := cardLayout.lines[2].points
+	middleX := int((guess[0][0] + guess[1][0]) / 2)
+	middleY := int((guess[0][1] + guess[1][1]) / 2)
+	r, g, b, _ := img.At(middleX, middleY).RGBA()
+	if uint8(r>>8) != guessColor.R || uint8(g>>8) != guessColor.G || uint8(b>>8) != guessColor.B {
+		t.Errorf("Expected the guess color in the middle of the guess line")
+	}
+}
+
+func TestRenderSVG(t *testing.T) {
+	logic := &CardLogicImpl{}
+	svg := string(logic.RenderSVG(testCard()))
+	if !strings.HasPrefix(svg, "<svg") || !strings.HasSuffix(svg, "</svg>") {
+		t.Errorf("Expected an SVG document")
+	}
+	if strings.Count(svg, "<polyline") != 3 {
+		t.Errorf("Expected the history, the guess and the real prices")
+	}
+	if !strings.Contains(svg, "Apple &lt;Inc.&gt;") || !strings.Contains(svg, "SCORE 42") {
+		t.Errorf("Expected the escaped name and the score in %s", svg)
+	}
+}
+
+func TestNewLayoutWithoutPrices(t *testing.T) {
+	cardLayout := newLayout(model.RoundCard{Symbol: "AAPL"})
+	for _, priceLine := range cardLayout.lines {
+		if len(priceLine.points) != 0 {
+			t.Errorf("Expected no point without price")
+		}
+	}
+}
+
+func TestGlyph(t *testing.T) {
+	lower, found := glyph('a')
+	upper, _ := glyph('A')
+	if !found || lower != upper {
+		t.Errorf("Expected the lower case letters to be drawn as capitals")
+	}
+	if _, found := glyph('é'); found {
+		t.Errorf("Expected the characters without glyph to be drawn as spaces")
+	}
+	if textWidth("AB", 7) != 11 {
+		t.Errorf("Expected 2 glyphs and a space of one pixel and not %f", textWidth("AB", 7))
+	}
+}
+
+func TestCardCache(t *testing.T) {
+	cache := &CardCache{Dir: t.TempDir()}
+	if _, found := cache.Load("round-1", "png"); found {
+		t.Errorf("Expected an empty cache")
+	}
+	if err := cache.Store("round-1", "png", []byte("card")); err != nil {
+		t.Fatalf("Expected no error and not %v", err)
+	}
+	data, found := cache.Load("round-1", "png")
+	if !found || string(data) != "card" {
+		t.Errorf("Expected the stored card and not %q", data)
+	}
+	if _, found := cache.Load("round-1", "svg"); found {
+		t.Errorf("Expected the formats to be cached separately")
+	}
+}
@@ -0,0 +1,68 @@
+package card
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"math"
+	"stockgame/internal/model"
+)
+
+// Width of the price lines in pixels
+const lineWidth = 4
+
+func (l *CardLogicImpl) RenderPNG(card model.RoundCard) ([]byte, error) {
+	cardLayout := newLayout(card)
+	img := image.NewRGBA(image.Rect(0, 0, Width, Height))
+	draw.Draw(img, img.Bounds(), &image.Uniform{C: backgroundColor}, image.Point{}, draw.Src)
+	for _, priceLine := range cardLayout.lines {
+		for i := 1; i < len(priceLine.points); i++ {
+			drawSegment(img, priceLine.points[i-1], priceLine.points[i], priceLine.color)
+		}
+	}
+	for _, cardText := range cardLayout.texts {
+		drawText(img, cardText)
+	}
+	var buffer bytes.Buffer
+	if err := png.Encode(&buffer, img); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// drawSegment stamps a square brush every half pixel between the two points
+func drawSegment(img *image.RGBA, from [2]float64, to [2]float64, lineColor color.RGBA) {
+	length := math.Hypot(to[0]-from[0], to[1]-from[1])
+	steps := int(math.Ceil(length*2)) + 1
+	for step := 0; step <= steps; step++ {
+		ratio := float64(step) / float64(steps)
+		x := from[0] + (to[0]-from[0])*ratio
+		y := from[1] + (to[1]-from[1])*ratio
+		fillRect(img, x-lineWidth/2, y-lineWidth/2, lineWidth, lineWidth, lineColor)
+	}
+}
+
+// drawText draws every glyph pixel as a square of size/glyphHeight pixels
+func drawText(img *image.RGBA, cardText text) {
+	pixel := cardText.size / glyphHeight
+	x := cardText.x
+	for _, char := range cardText.value {
+		if rows, found := glyph(char); found {
+			for row, bits := range rows {
+				for column, bit := range bits {
+					if bit == '1' {
+						fillRect(img, x+float64(column)*pixel, cardText.y+float64(row)*pixel, pixel, pixel, cardText.color)
+					}
+				}
+			}
+		}
+		x += (glyphWidth + glyphSpacing) * pixel
+	}
+}
+
+func fillRect(img *image.RGBA, x, y, width, height float64, fillColor color.RGBA) {
+	rect := image.Rect(int(math.Round(x)), int(math.Round(y)), int(math.Round(x+width)), int(math.Round(y+height)))
+	draw.Draw(img, rect.Intersect(img.Bounds()), &image.Uniform{C: fillColor}, image.Point{}, draw.Src)
+}
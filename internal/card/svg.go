@@ -0,0 +1,42 @@
+package card
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"image/color"
+	"stockgame/internal/model"
+	"strings"
+)
+
+// RenderSVG draws the same layout as RenderPNG with the real text instead of the bitmap font
+func (l *CardLogicImpl) RenderSVG(card model.RoundCard) []byte {
+	cardLayout := newLayout(card)
+	var buffer bytes.Buffer
+	fmt.Fprintf(&buffer, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`, Width, Height, Width, Height)
+	fmt.Fprintf(&buffer, `<rect width="100%%" height="100%%" fill="%s"/>`, hexColor(backgroundColor))
+	for _, priceLine := range cardLayout.lines {
+		if len(priceLine.points) < 2 {
+			continue
+		}
+		points := make([]string, len(priceLine.points))
+		for i, point := range priceLine.points {
+			points[i] = fmt.Sprintf("%.1f,%.1f", point[0], point[1])
+		}
+		fmt.Fprintf(&buffer, `<polyline points="%s" fill="none" stroke="%s" stroke-width="%d" stroke-linejoin="round"/>`,
+			strings.Join(points, " "), hexColor(priceLine.color), lineWidth)
+	}
+	for _, cardText := range cardLayout.texts {
+		// The y of an SVG text is its baseline, the bottom of the capitals
+		fmt.Fprintf(&buffer, `<text x="%.1f" y="%.1f" font-family="monospace" font-size="%.1f" fill="%s">`,
+			cardText.x, cardText.y+cardText.size, cardText.size*1.4, hexColor(cardText.color))
+		xml.EscapeText(&buffer, []byte(cardText.value))
+		buffer.WriteString(`</text>`)
+	}
+	buffer.WriteString(`</svg>`)
+	return buffer.Bytes()
+}
+
+func hexColor(value color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", value.R, value.G, value.B)
+}
@@ -0,0 +1,81 @@
+package dataaccess
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"stockgame/internal/database"
+	"stockgame/internal/model"
+	"time"
+)
+
+// ErrServedBasketNotFound is returned when no served basket has the requested id
+var ErrServedBasketNotFound = errors.New("served basket not found")
+
+type BasketDataAccess interface {
+	CreateServedBasket(ctx context.Context, basket model.ServedBasket) error
+	GetServedBasket(ctx context.Context, id string) (model.ServedBasket, error)
+	SolveServedBasket(ctx context.Context, id string, solvedAt time.Time) (bool, error)
+}
+
+type BasketDataAccessImpl struct {
+	DB database.DBInterface
+	BasketDataAccess
+}
+
+func (s *BasketDataAccessImpl) CreateServedBasket(ctx context.Context, basket model.ServedBasket) error {
+	symbolUUIDs, err := json.Marshal(basket.SymbolUUIDs)
+	if err != nil {
+		return fmt.Errorf("error serializing basket symbols: %v", err)
+	}
+	query := `
+		INSERT INTO served_baskets (id, player_id, after_date, symbol_uuids, served_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+	_, err = s.DB.ExecContext(ctx, query, basket.Id, basket.PlayerId, basket.AfterDate, string(symbolUUIDs), basket.ServedAt)
+	if err != nil {
+		return fmt.Errorf("error inserting served basket: %v", err)
+	}
+	return nil
+}
+
+func (s *BasketDataAccessImpl) GetServedBasket(ctx context.Context, id string) (model.ServedBasket, error) {
+	query := `
+		SELECT id, player_id, TO_CHAR(after_date, 'YYYY-MM-DD'), symbol_uuids, served_at, solved_at
+		FROM served_baskets
+		WHERE id = $1
+	`
+	var basket model.ServedBasket
+	var symbolUUIDs []byte
+	var solvedAt sql.NullTime
+	err := s.DB.QueryRowContext(ctx, query, id).Scan(&basket.Id, &basket.PlayerId, &basket.AfterDate, &symbolUUIDs, &basket.ServedAt, &solvedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return basket, ErrServedBasketNotFound
+	}
+	if err != nil {
+		return basket, fmt.Errorf("error querying served basket %s: %v", id, err)
+	}
+	if err := json.Unmarshal(symbolUUIDs, &basket.SymbolUUIDs); err != nil {
+		return basket, fmt.Errorf("error reading symbols of served basket %s: %v", id, err)
+	}
+	if solvedAt.Valid {
+		basket.SolvedAt = &solvedAt.Time
+	}
+	return basket, nil
+}
+
+// SolveServedBasket returns false when the basket was already solved
+func (s *BasketDataAccessImpl) SolveServedBasket(ctx context.Context, id string, solvedAt time.Time) (bool, error) {
+	query := `
+		UPDATE served_baskets
+		SET solved_at = $2
+		WHERE id = $1 AND solved_at IS NULL
+	`
+	result, err := s.DB.ExecContext(ctx, query, id, solvedAt)
+	if err != nil {
+		return false, fmt.Errorf("error solving served basket: %v", err)
+	}
+	return isAffected(result)
+}
@@ -0,0 +1,81 @@
+package dataaccess
+
+import (
+	"database/sql"
+	"stockgame/internal/model"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateServedBasket(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	servedAt := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	mock.ExpectExec("INSERT INTO served_baskets").
+		WithArgs("basket-1", "player-1", "2023-10-01", `["uuid-aapl","uuid-msft","uuid-goog"]`, servedAt).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	dao := &BasketDataAccessImpl{DB: db}
+	err = dao.CreateServedBasket(ctx, model.ServedBasket{
+		Id:          "basket-1",
+		PlayerId:    "player-1",
+		AfterDate:   "2023-10-01",
+		SymbolUUIDs: []string{"uuid-aapl", "uuid-msft", "uuid-goog"},
+		ServedAt:    servedAt,
+	})
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetServedBasket(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	servedAt := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	mock.ExpectQuery("SELECT id, player_id").
+		WithArgs("basket-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "player_id", "after_date", "symbol_uuids", "served_at", "solved_at"}).
+			AddRow("basket-1", "player-1", "2023-10-01", []byte(`["uuid-aapl","uuid-msft","uuid-goog"]`), servedAt, nil))
+	mock.ExpectQuery("SELECT id, player_id").
+		WithArgs("unknown").
+		WillReturnError(sql.ErrNoRows)
+
+	dao := &BasketDataAccessImpl{DB: db}
+	basket, err := dao.GetServedBasket(ctx, "basket-1")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"uuid-aapl", "uuid-msft", "uuid-goog"}, basket.SymbolUUIDs)
+	assert.Equal(t, "2023-10-01", basket.AfterDate)
+	assert.Nil(t, basket.SolvedAt)
+
+	_, err = dao.GetServedBasket(ctx, "unknown")
+	assert.ErrorIs(t, err, ErrServedBasketNotFound)
+}
+
+func TestSolveServedBasket(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	solvedAt := time.Date(2024, 5, 1, 0, 1, 0, 0, time.UTC)
+	mock.ExpectExec("UPDATE served_baskets").
+		WithArgs("basket-1", solvedAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE served_baskets").
+		WithArgs("basket-1", solvedAt).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	dao := &BasketDataAccessImpl{DB: db}
+	solved, err := dao.SolveServedBasket(ctx, "basket-1", solvedAt)
+	assert.NoError(t, err)
+	assert.True(t, solved)
+	solved, err = dao.SolveServedBasket(ctx, "basket-1", solvedAt)
+	assert.NoError(t, err)
+	assert.False(t, solved, "Expected the basket to be solved once")
+}
@@ -0,0 +1,84 @@
+package dataaccess
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"stockgame/internal/database"
+	"stockgame/internal/model"
+	"time"
+)
+
+type ProgressionDataAccess interface {
+	GetProgress(ctx context.Context, playerId string) (model.PlayerProgress, error)
+	SaveProgress(ctx context.Context, playerId string, progress model.PlayerProgress) (bool, error)
+}
+
+type ProgressionDataAccessImpl struct {
+	DB database.DBInterface
+	ProgressionDataAccess
+}
+
+// GetProgress returns an empty progress at version 0 when the player has not saved any round
+func (s *ProgressionDataAccessImpl) GetProgress(ctx context.Context, playerId string) (model.PlayerProgress, error) {
+	query := `
+		SELECT xp, rounds, current_streak, longest_streak, last_played_date, counters, unlocked, version
+		FROM player_progress
+		WHERE player_id = $1
+	`
+	progress := model.PlayerProgress{Counters: map[string]int{}, Unlocked: map[string]time.Time{}}
+	var counters, unlocked []byte
+	err := s.DB.QueryRowContext(ctx, query, playerId).Scan(&progress.XP, &progress.Rounds, &progress.CurrentStreak,
+		&progress.LongestStreak, &progress.LastPlayedDate, &counters, &unlocked, &progress.Version)
+	if errors.Is(err, sql.ErrNoRows) {
+		return progress, nil
+	}
+	if err != nil {
+		return progress, fmt.Errorf("error querying progress of player %s: %v", playerId, err)
+	}
+	if err := json.Unmarshal(counters, &progress.Counters); err != nil {
+		return progress, fmt.Errorf("error reading counters of player %s: %v", playerId, err)
+	}
+	if err := json.Unmarshal(unlocked, &progress.Unlocked); err != nil {
+		return progress, fmt.Errorf("error reading achievements of player %s: %v", playerId, err)
+	}
+	return progress, nil
+}
+
+// SaveProgress saves the progress only if it is still at the version it was read at. It
+// returns false when another round of the player was saved first.
+func (s *ProgressionDataAccessImpl) SaveProgress(ctx context.Context, playerId string, progress model.PlayerProgress) (bool, error) {
+	counters, err := json.Marshal(progress.Counters)
+	if err != nil {
+		return false, fmt.Errorf("error serializing counters: %v", err)
+	}
+	unlocked, err := json.Marshal(progress.Unlocked)
+	if err != nil {
+		return false, fmt.Errorf("error serializing achievements: %v", err)
+	}
+	query := `
+		INSERT INTO player_progress (player_id, xp, rounds, current_streak, longest_streak, last_played_date, counters, unlocked, version)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, 1)
+		ON CONFLICT (player_id) DO NOTHING
+	`
+	if progress.Version > 0 {
+		query = `
+			UPDATE player_progress
+			SET xp = $2, rounds = $3, current_streak = $4, longest_streak = $5, last_played_date = $6,
+				counters = $7, unlocked = $8, version = version + 1, updated_at = NOW()
+			WHERE player_id = $1 AND version = $9
+		`
+	}
+	args := []any{playerId, progress.XP, progress.Rounds, progress.CurrentStreak, progress.LongestStreak,
+		progress.LastPlayedDate, string(counters), string(unlocked)}
+	if progress.Version > 0 {
+		args = append(args, progress.Version)
+	}
+	result, err := s.DB.ExecContext(ctx, query, args...)
+	if err != nil {
+		return false, fmt.Errorf("error saving progress: %v", err)
+	}
+	return isAffected(result)
+}
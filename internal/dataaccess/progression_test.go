@@ -0,0 +1,76 @@
+package dataaccess
+
+import (
+	"database/sql"
+	"stockgame/internal/model"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetProgress(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"xp", "rounds", "current_streak", "longest_streak", "last_played_date", "counters", "unlocked", "version"}).
+		AddRow(120, 3, 2, 4, "2024-05-01", []byte(`{"first_round":1}`), []byte(`{"first_round":"2024-04-01T00:00:00Z"}`), 3)
+	mock.ExpectQuery("SELECT xp, rounds").
+		WithArgs("player-1").
+		WillReturnRows(rows)
+
+	dao := &ProgressionDataAccessImpl{DB: db}
+	progress, err := dao.GetProgress(ctx, "player-1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 120, progress.XP)
+	assert.Equal(t, 3, progress.Version)
+	assert.Equal(t, map[string]int{"first_round": 1}, progress.Counters)
+	assert.True(t, progress.Unlocked["first_round"].Equal(time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)))
+}
+
+func TestGetProgress_NoRound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT xp, rounds").
+		WithArgs("player-1").
+		WillReturnError(sql.ErrNoRows)
+
+	dao := &ProgressionDataAccessImpl{DB: db}
+	progress, err := dao.GetProgress(ctx, "player-1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, progress.Version)
+	assert.NotNil(t, progress.Counters)
+}
+
+func TestSaveProgress(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	progress := model.PlayerProgress{XP: 20, Rounds: 1, CurrentStreak: 1, LongestStreak: 1, LastPlayedDate: "2024-05-01",
+		Counters: map[string]int{"first_round": 1}, Unlocked: map[string]time.Time{}}
+	mock.ExpectExec("INSERT INTO player_progress").
+		WithArgs("player-1", 20, 1, 1, 1, "2024-05-01", `{"first_round":1}`, `{}`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	progress.Version = 2
+	mock.ExpectExec("UPDATE player_progress").
+		WithArgs("player-1", 20, 1, 1, 1, "2024-05-01", `{"first_round":1}`, `{}`, 2).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	dao := &ProgressionDataAccessImpl{DB: db}
+	first := progress
+	first.Version = 0
+	saved, err := dao.SaveProgress(ctx, "player-1", first)
+	assert.NoError(t, err)
+	assert.False(t, saved, "Expected the first progress to be skipped when another one was inserted")
+	saved, err = dao.SaveProgress(ctx, "player-1", progress)
+	assert.NoError(t, err)
+	assert.True(t, saved)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
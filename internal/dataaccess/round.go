@@ -0,0 +1,214 @@
+package dataaccess
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"stockgame/internal/database"
+	"stockgame/internal/model"
+)
+
+// ErrRoundNotFound is returned when no round has the requested id
+var ErrRoundNotFound = errors.New("round not found")
+
+type RoundDataAccess interface {
+	SaveRound(ctx context.Context, round model.Round) error
+	GetRound(ctx context.Context, id string) (model.Round, error)
+	CountScoresForWindow(ctx context.Context, symbol string, afterDate string, total int) (model.ScoreCount, error)
+	CountScoresForDifficulty(ctx context.Context, difficulty string, total int) (model.ScoreCount, error)
+	GetPlayerRounds(ctx context.Context, playerId string, limit int, offset int) ([]model.Round, error)
+	CountPlayerRounds(ctx context.Context, playerId string) (int, error)
+	GetScoredRounds(ctx context.Context, limit int) ([]model.Round, error)
+}
+
+type RoundDataAccessImpl struct {
+	DB database.DBInterface
+	RoundDataAccess
+}
+
+func (s *RoundDataAccessImpl) SaveRound(ctx context.Context, round model.Round) error {
+	guesses, err := json.Marshal(round.DayPrice)
+	if err != nil {
+		return fmt.Errorf("error serializing guesses: %v", err)
+	}
+	actual := round.Actual
+	if actual == nil {
+		actual = []float64{}
+	}
+	actualCloses, err := json.Marshal(actual)
+	if err != nil {
+		return fmt.Errorf("error serializing actual closes: %v", err)
+	}
+	hints := round.Hints
+	if hints == nil {
+		hints = []string{}
+	}
+	hintsJSON, err := json.Marshal(hints)
+	if err != nil {
+		return fmt.Errorf("error serializing hints: %v", err)
+	}
+	query := `
+		INSERT INTO rounds (id, player_id, mode, symbol, symbol_uuid, after_date, difficulty, total, in_low_high, in_open_close, in_bollinger, in_direction, guesses, history_start_date, future_end_date, last_close, actual_closes, hints, hint_penalty, speed_bonus, served_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21)
+	`
+	_, err = s.DB.ExecContext(ctx, query,
+		round.Id, round.PlayerId, round.Mode, round.Symbol, round.SymbolUUID, round.AfterDate, round.Difficulty,
+		round.Score.Total, round.Score.InLowHigh, round.Score.InOpenClose, round.Score.InBollinger, round.Score.InDirection,
+		string(guesses), nullableDate(round.HistoryStartDate), nullableDate(round.FutureEndDate), round.LastClose, string(actualCloses),
+		string(hintsJSON), round.Score.HintPenalty, round.Score.SpeedBonus, round.ServedAt)
+	if err != nil {
+		return fmt.Errorf("error inserting round: %v", err)
+	}
+	return nil
+}
+
+// selectRounds reads the rounds with the sector of their stock, "Unknown" when the
+// sector was not loaded
+const selectRounds = `
+	SELECT r.id, r.player_id, r.mode, r.symbol, r.symbol_uuid, TO_CHAR(r.after_date, 'YYYY-MM-DD'), r.difficulty,
+		r.total, r.in_low_high, r.in_open_close, r.in_bollinger, r.in_direction, r.guesses,
+		COALESCE(TO_CHAR(r.history_start_date, 'YYYY-MM-DD'), ''), COALESCE(TO_CHAR(r.future_end_date, 'YYYY-MM-DD'), ''),
+		r.last_close, r.actual_closes, r.hints, r.hint_penalty, r.speed_bonus, r.served_at, COALESCE(NULLIF(si.sector, ''), 'Unknown'), r.created_at
+	FROM rounds r
+	LEFT JOIN stocks_info si ON si.symbol_uuid = r.symbol_uuid
+`
+
+func (s *RoundDataAccessImpl) GetRound(ctx context.Context, id string) (model.Round, error) {
+	query := selectRounds + `WHERE r.id = $1`
+	round, err := scanRound(s.DB.QueryRowContext(ctx, query, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return round, ErrRoundNotFound
+	}
+	if err != nil {
+		return round, fmt.Errorf("error querying round %s: %v", id, err)
+	}
+	return round, nil
+}
+
+// GetPlayerRounds returns the rounds of a player, the most recent first
+func (s *RoundDataAccessImpl) GetPlayerRounds(ctx context.Context, playerId string, limit int, offset int) ([]model.Round, error) {
+	query := selectRounds + `
+		WHERE r.player_id = $1
+		ORDER BY r.created_at DESC, r.id
+		LIMIT $2 OFFSET $3
+	`
+	return s.queryRounds(ctx, query, playerId, limit, offset)
+}
+
+// GetScoredRounds returns the most recent price rounds that recorded their actual closes
+func (s *RoundDataAccessImpl) GetScoredRounds(ctx context.Context, limit int) ([]model.Round, error) {
+	query := selectRounds + `
+		WHERE r.mode = $1
+		AND r.last_close > 0
+		ORDER BY r.created_at DESC, r.id
+		LIMIT $2
+	`
+	return s.queryRounds(ctx, query, model.Mode_price, limit)
+}
+
+func (s *RoundDataAccessImpl) queryRounds(ctx context.Context, query string, args ...interface{}) ([]model.Round, error) {
+	rows, err := s.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying rounds: %v", err)
+	}
+	defer rows.Close()
+	rounds := []model.Round{}
+	for rows.Next() {
+		round, err := scanRound(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning round: %v", err)
+		}
+		rounds = append(rounds, round)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error reading rounds: %v", err)
+	}
+	return rounds, nil
+}
+
+func (s *RoundDataAccessImpl) CountPlayerRounds(ctx context.Context, playerId string) (int, error) {
+	var count int
+	err := s.DB.QueryRowContext(ctx, `SELECT COUNT(*) FROM rounds WHERE player_id = $1`, playerId).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("error counting rounds of player: %v", err)
+	}
+	return count, nil
+}
+
+// rowScanner is implemented by *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanRound reads a row of selectRounds
+func scanRound(row rowScanner) (model.Round, error) {
+	var round model.Round
+	var guesses, actualCloses, hints []byte
+	var servedAt sql.NullTime
+	err := row.Scan(
+		&round.Id, &round.PlayerId, &round.Mode, &round.Symbol, &round.SymbolUUID, &round.AfterDate, &round.Difficulty,
+		&round.Score.Total, &round.Score.InLowHigh, &round.Score.InOpenClose, &round.Score.InBollinger, &round.Score.InDirection, &guesses,
+		&round.HistoryStartDate, &round.FutureEndDate, &round.LastClose, &actualCloses, &hints, &round.Score.HintPenalty,
+		&round.Score.SpeedBonus, &servedAt, &round.Sector, &round.CreatedAt)
+	if err != nil {
+		return round, err
+	}
+	if servedAt.Valid {
+		round.ServedAt = &servedAt.Time
+	}
+	if err := json.Unmarshal(guesses, &round.DayPrice); err != nil {
+		return round, fmt.Errorf("error reading guesses of round %s: %v", round.Id, err)
+	}
+	if err := json.Unmarshal(actualCloses, &round.Actual); err != nil {
+		return round, fmt.Errorf("error reading actual closes of round %s: %v", round.Id, err)
+	}
+	if err := json.Unmarshal(hints, &round.Hints); err != nil {
+		return round, fmt.Errorf("error reading hints of round %s: %v", round.Id, err)
+	}
+	return round, nil
+}
+
+// CountScoresForWindow counts the price rounds played on the same symbol and window. Only
+// the rounds of a served window are counted, they are the first solution of the window.
+func (s *RoundDataAccessImpl) CountScoresForWindow(ctx context.Context, symbol string, afterDate string, total int) (model.ScoreCount, error) {
+	query := `
+		SELECT COUNT(*) FILTER (WHERE total < $1), COUNT(*) FILTER (WHERE total = $1), COUNT(*)
+		FROM rounds
+		WHERE mode = $2
+		AND symbol = $3
+		AND after_date = $4
+		AND served_at IS NOT NULL
+	`
+	return s.countScores(ctx, query, total, model.Mode_price, symbol, afterDate)
+}
+
+// CountScoresForDifficulty counts the price rounds played on any served window of the same difficulty
+func (s *RoundDataAccessImpl) CountScoresForDifficulty(ctx context.Context, difficulty string, total int) (model.ScoreCount, error) {
+	query := `
+		SELECT COUNT(*) FILTER (WHERE total < $1), COUNT(*) FILTER (WHERE total = $1), COUNT(*)
+		FROM rounds
+		WHERE mode = $2
+		AND difficulty = $3
+		AND served_at IS NOT NULL
+	`
+	return s.countScores(ctx, query, total, model.Mode_price, difficulty)
+}
+
+// nullableDate stores an empty date as NULL since an empty string is not a valid DATE
+func nullableDate(date string) interface{} {
+	if date == "" {
+		return nil
+	}
+	return date
+}
+
+func (s *RoundDataAccessImpl) countScores(ctx context.Context, query string, args ...interface{}) (model.ScoreCount, error) {
+	var count model.ScoreCount
+	err := s.DB.QueryRowContext(ctx, query, args...).Scan(&count.Below, &count.Equal, &count.Total)
+	if err != nil {
+		return count, fmt.Errorf("error counting scores: %v", err)
+	}
+	return count, nil
+}
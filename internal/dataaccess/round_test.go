@@ -0,0 +1,231 @@
+package dataaccess
+
+import (
+	"database/sql"
+	"fmt"
+	"stockgame/internal/model"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+var roundColumns = []string{"id", "player_id", "mode", "symbol", "symbol_uuid", "after_date", "difficulty",
+	"total", "in_low_high", "in_open_close", "in_bollinger", "in_direction", "guesses", "history_start_date", "future_end_date",
+	"last_close", "actual_closes", "hints", "hint_penalty", "speed_bonus", "served_at", "sector", "created_at"}
+
+func TestSaveRound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	servedAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	round := model.Round{
+		Id:            "round-1",
+		PlayerId:      "player-1",
+		Mode:          model.Mode_price,
+		Symbol:        "AAPL",
+		SymbolUUID:    "uuid-123",
+		AfterDate:     "2023-01-31",
+		FutureEndDate: "2023-02-14",
+		Score:         model.UserScoreResponse{Total: 42, InLowHigh: 10, InOpenClose: 12, InBollinger: 8, InDirection: 12, HintPenalty: 5, SpeedBonus: 3},
+		DayPrice:      []model.DayPrice{{Day: 1, Price: 150.5}},
+		LastClose:     148.2,
+		Actual:        []float64{151.25},
+		Hints:         []string{model.Hint_rsi},
+		ServedAt:      &servedAt,
+	}
+	mock.ExpectExec("INSERT INTO rounds").
+		WithArgs("round-1", "player-1", model.Mode_price, "AAPL", "uuid-123", "2023-01-31", "", 42, 10, 12, 8, 12, `[{"day":1,"price":150.5}]`, nil, "2023-02-14", 148.2, `[151.25]`, `["rsi"]`, 5, 3, &servedAt).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	dao := &RoundDataAccessImpl{DB: db}
+	err = dao.SaveRound(ctx, round)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSaveRound_InsertError(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec("INSERT INTO rounds").
+		WithArgs("round-1", "", "", "", "", "", "", 0, 0, 0, 0, 0, "null", nil, nil, 0.0, "[]", "[]", 0, 0, nil).
+		WillReturnError(fmt.Errorf("db error"))
+
+	dao := &RoundDataAccessImpl{DB: db}
+	err = dao.SaveRound(ctx, model.Round{Id: "round-1"})
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "error inserting round")
+}
+
+func TestCountScoresForWindow(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"below", "equal", "total"}).AddRow(7, 2, 10)
+	mock.ExpectQuery("SELECT COUNT").
+		WithArgs(42, model.Mode_price, "AAPL", "2023-01-31").
+		WillReturnRows(rows)
+
+	dao := &RoundDataAccessImpl{DB: db}
+	count, err := dao.CountScoresForWindow(ctx, "AAPL", "2023-01-31", 42)
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.ScoreCount{Below: 7, Equal: 2, Total: 10}, count)
+}
+
+func TestCountScoresForDifficulty_QueryError(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT COUNT").
+		WithArgs(42, model.Mode_price, "hard").
+		WillReturnError(fmt.Errorf("db error"))
+
+	dao := &RoundDataAccessImpl{DB: db}
+	_, err = dao.CountScoresForDifficulty(ctx, "hard", 42)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "error counting scores")
+}
+
+func TestGetRound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	createdAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	rows := sqlmock.NewRows(roundColumns).
+		AddRow("round-1", "player-1", model.Mode_price, "AAPL", "uuid-123", "2023-01-31", model.Difficulty_hard,
+			42, 10, 12, 8, 12, []byte(`[{"day":41,"price":150.5}]`), "2022-12-01", "2023-02-14",
+			148.2, []byte(`[151.25]`), []byte(`["sector"]`), 5, 3, createdAt, "Technology", createdAt)
+	mock.ExpectQuery("SELECT r.id, r.player_id").
+		WithArgs("round-1").
+		WillReturnRows(rows)
+
+	dao := &RoundDataAccessImpl{DB: db}
+	round, err := dao.GetRound(ctx, "round-1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.Round{
+		Id:               "round-1",
+		PlayerId:         "player-1",
+		Mode:             model.Mode_price,
+		Symbol:           "AAPL",
+		SymbolUUID:       "uuid-123",
+		AfterDate:        "2023-01-31",
+		HistoryStartDate: "2022-12-01",
+		FutureEndDate:    "2023-02-14",
+		Difficulty:       model.Difficulty_hard,
+		Score:            model.UserScoreResponse{Total: 42, InLowHigh: 10, InOpenClose: 12, InBollinger: 8, InDirection: 12, HintPenalty: 5, SpeedBonus: 3},
+		DayPrice:         []model.DayPrice{{Day: 41, Price: 150.5}},
+		LastClose:        148.2,
+		Actual:           []float64{151.25},
+		Sector:           "Technology",
+		Hints:            []string{model.Hint_sector},
+		ServedAt:         &createdAt,
+		CreatedAt:        createdAt,
+	}, round)
+}
+
+func TestGetRound_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT r.id, r.player_id").
+		WithArgs("unknown").
+		WillReturnError(sql.ErrNoRows)
+
+	dao := &RoundDataAccessImpl{DB: db}
+	_, err = dao.GetRound(ctx, "unknown")
+
+	assert.ErrorIs(t, err, ErrRoundNotFound)
+}
+
+func TestGetPlayerRounds(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	createdAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	rows := sqlmock.NewRows(roundColumns).
+		AddRow("round-2", "player-1", model.Mode_price, "MSFT", "uuid-456", "2023-03-31", "",
+			20, 10, 0, 0, 10, []byte(`[]`), "", "", 0.0, []byte(`[]`), []byte(`[]`), 0, 0, nil, "Unknown", createdAt).
+		AddRow("round-1", "player-1", model.Mode_price, "AAPL", "uuid-123", "2023-01-31", model.Difficulty_easy,
+			42, 10, 12, 8, 12, []byte(`[{"day":41,"price":150.5}]`), "2022-12-01", "2023-02-14", 148.2, []byte(`[151.25]`), []byte(`[]`), 0, 0, nil, "Technology", createdAt)
+	mock.ExpectQuery("SELECT r.id, r.player_id").
+		WithArgs("player-1", 20, 40).
+		WillReturnRows(rows)
+
+	dao := &RoundDataAccessImpl{DB: db}
+	rounds, err := dao.GetPlayerRounds(ctx, "player-1", 20, 40)
+
+	assert.NoError(t, err)
+	assert.Len(t, rounds, 2)
+	assert.Equal(t, "round-2", rounds[0].Id)
+	assert.Equal(t, "Unknown", rounds[0].Sector)
+	assert.Equal(t, []float64{151.25}, rounds[1].Actual)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetPlayerRounds_InvalidGuesses(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	rows := sqlmock.NewRows(roundColumns).
+		AddRow("round-1", "player-1", model.Mode_price, "AAPL", "uuid-123", "2023-01-31", "",
+			42, 10, 12, 8, 12, []byte(`not json`), "", "", 0.0, []byte(`[]`), []byte(`[]`), 0, 0, nil, "Unknown", time.Now())
+	mock.ExpectQuery("SELECT r.id, r.player_id").WillReturnRows(rows)
+
+	dao := &RoundDataAccessImpl{DB: db}
+	_, err = dao.GetPlayerRounds(ctx, "player-1", 20, 0)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "error reading guesses")
+}
+
+func TestCountPlayerRounds(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT COUNT").
+		WithArgs("player-1").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(12))
+
+	dao := &RoundDataAccessImpl{DB: db}
+	count, err := dao.CountPlayerRounds(ctx, "player-1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 12, count)
+}
+
+func TestGetScoredRounds(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	rows := sqlmock.NewRows(roundColumns).
+		AddRow("round-1", "player-1", model.Mode_price, "AAPL", "uuid-123", "2023-01-31", "",
+			42, 10, 12, 8, 12, []byte(`[{"day":41,"price":150.5}]`), "", "", 148.2, []byte(`[151.25]`), []byte(`[]`), 0, 0, nil, "Unknown", time.Now())
+	mock.ExpectQuery("SELECT r.id, r.player_id").
+		WithArgs(model.Mode_price, 1000).
+		WillReturnRows(rows)
+
+	dao := &RoundDataAccessImpl{DB: db}
+	rounds, err := dao.GetScoredRounds(ctx, 1000)
+
+	assert.NoError(t, err)
+	assert.Len(t, rounds, 1)
+	assert.Equal(t, 148.2, rounds[0].LastClose)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,143 @@
+package dataaccess
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"stockgame/internal/database"
+	"stockgame/internal/model"
+)
+
+var (
+	// ErrScenarioNotFound is returned when no scenario has the requested id
+	ErrScenarioNotFound = errors.New("scenario not found")
+	// ErrScenarioExists is returned when a scenario is created with the id of another one
+	ErrScenarioExists = errors.New("a scenario already has this id")
+)
+
+type ScenarioDataAccess interface {
+	CreateScenario(ctx context.Context, scenario model.Scenario) error
+	UpdateScenario(ctx context.Context, scenario model.Scenario) error
+	DeleteScenario(ctx context.Context, id string) error
+	GetScenario(ctx context.Context, id string) (model.Scenario, error)
+	ListScenarios(ctx context.Context) ([]model.Scenario, error)
+}
+
+type ScenarioDataAccessImpl struct {
+	DB database.DBInterface
+	ScenarioDataAccess
+}
+
+func (s *ScenarioDataAccessImpl) CreateScenario(ctx context.Context, scenario model.Scenario) error {
+	symbols, err := json.Marshal(scenario.Symbols)
+	if err != nil {
+		return fmt.Errorf("error serializing symbols: %v", err)
+	}
+	query := `
+		INSERT INTO scenarios (id, name, start_date, end_date, symbols, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT DO NOTHING
+	`
+	result, err := s.DB.ExecContext(ctx, query, scenario.Id, scenario.Name, scenario.StartDate, scenario.EndDate, string(symbols), scenario.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("error inserting scenario: %v", err)
+	}
+	inserted, err := isAffected(result)
+	if err != nil {
+		return err
+	}
+	if !inserted {
+		return ErrScenarioExists
+	}
+	return nil
+}
+
+func (s *ScenarioDataAccessImpl) UpdateScenario(ctx context.Context, scenario model.Scenario) error {
+	symbols, err := json.Marshal(scenario.Symbols)
+	if err != nil {
+		return fmt.Errorf("error serializing symbols: %v", err)
+	}
+	query := `
+		UPDATE scenarios
+		SET name = $2, start_date = $3, end_date = $4, symbols = $5
+		WHERE id = $1
+	`
+	result, err := s.DB.ExecContext(ctx, query, scenario.Id, scenario.Name, scenario.StartDate, scenario.EndDate, string(symbols))
+	if err != nil {
+		return fmt.Errorf("error updating scenario: %v", err)
+	}
+	updated, err := isAffected(result)
+	if err != nil {
+		return err
+	}
+	if !updated {
+		return ErrScenarioNotFound
+	}
+	return nil
+}
+
+func (s *ScenarioDataAccessImpl) DeleteScenario(ctx context.Context, id string) error {
+	result, err := s.DB.ExecContext(ctx, `DELETE FROM scenarios WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("error deleting scenario: %v", err)
+	}
+	deleted, err := isAffected(result)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrScenarioNotFound
+	}
+	return nil
+}
+
+const selectScenarios = `
+	SELECT id, name, TO_CHAR(start_date, 'YYYY-MM-DD'), TO_CHAR(end_date, 'YYYY-MM-DD'), symbols, created_at
+	FROM scenarios
+`
+
+func (s *ScenarioDataAccessImpl) GetScenario(ctx context.Context, id string) (model.Scenario, error) {
+	scenario, err := scanScenario(s.DB.QueryRowContext(ctx, selectScenarios+`WHERE id = $1`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return scenario, ErrScenarioNotFound
+	}
+	if err != nil {
+		return scenario, fmt.Errorf("error querying scenario %s: %v", id, err)
+	}
+	return scenario, nil
+}
+
+func (s *ScenarioDataAccessImpl) ListScenarios(ctx context.Context) ([]model.Scenario, error) {
+	rows, err := s.DB.QueryContext(ctx, selectScenarios+`ORDER BY start_date, id`)
+	if err != nil {
+		return nil, fmt.Errorf("error querying scenarios: %v", err)
+	}
+	defer rows.Close()
+	scenarios := []model.Scenario{}
+	for rows.Next() {
+		scenario, err := scanScenario(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning scenario: %v", err)
+		}
+		scenarios = append(scenarios, scenario)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error reading scenarios: %v", err)
+	}
+	return scenarios, nil
+}
+
+func scanScenario(row rowScanner) (model.Scenario, error) {
+	var scenario model.Scenario
+	var symbols []byte
+	err := row.Scan(&scenario.Id, &scenario.Name, &scenario.StartDate, &scenario.EndDate, &symbols, &scenario.CreatedAt)
+	if err != nil {
+		return scenario, err
+	}
+	if err := json.Unmarshal(symbols, &scenario.Symbols); err != nil {
+		return scenario, fmt.Errorf("error reading symbols of scenario %s: %v", scenario.Id, err)
+	}
+	return scenario, nil
+}
@@ -0,0 +1,94 @@
+package dataaccess
+
+import (
+	"database/sql"
+	"stockgame/internal/model"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateScenario(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	createdAt := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	scenario := model.Scenario{Id: "covid-2020", Name: "COVID drop", StartDate: "2020-02-19", EndDate: "2020-03-23", Symbols: []string{"AAPL"}, CreatedAt: createdAt}
+	mock.ExpectExec("INSERT INTO scenarios").
+		WithArgs("covid-2020", "COVID drop", "2020-02-19", "2020-03-23", `["AAPL"]`, createdAt).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO scenarios").
+		WithArgs("covid-2020", "COVID drop", "2020-02-19", "2020-03-23", `["AAPL"]`, createdAt).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	dao := &ScenarioDataAccessImpl{DB: db}
+	assert.NoError(t, dao.CreateScenario(ctx, scenario))
+	assert.ErrorIs(t, dao.CreateScenario(ctx, scenario), ErrScenarioExists)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateAndDeleteScenario_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec("UPDATE scenarios").
+		WithArgs("unknown", "Unknown", "2020-02-19", "2020-03-23", `[]`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("DELETE FROM scenarios").
+		WithArgs("unknown").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	dao := &ScenarioDataAccessImpl{DB: db}
+	err = dao.UpdateScenario(ctx, model.Scenario{Id: "unknown", Name: "Unknown", StartDate: "2020-02-19", EndDate: "2020-03-23", Symbols: []string{}})
+	assert.ErrorIs(t, err, ErrScenarioNotFound)
+	assert.ErrorIs(t, dao.DeleteScenario(ctx, "unknown"), ErrScenarioNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetScenario(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	createdAt := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	mock.ExpectQuery("SELECT id, name").
+		WithArgs("covid-2020").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "start_date", "end_date", "symbols", "created_at"}).
+			AddRow("covid-2020", "COVID drop", "2020-02-19", "2020-03-23", []byte(`["AAPL","MSFT"]`), createdAt))
+	mock.ExpectQuery("SELECT id, name").
+		WithArgs("unknown").
+		WillReturnError(sql.ErrNoRows)
+
+	dao := &ScenarioDataAccessImpl{DB: db}
+	scenario, err := dao.GetScenario(ctx, "covid-2020")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"AAPL", "MSFT"}, scenario.Symbols)
+	assert.Equal(t, "2020-02-19", scenario.StartDate)
+
+	_, err = dao.GetScenario(ctx, "unknown")
+	assert.ErrorIs(t, err, ErrScenarioNotFound)
+}
+
+func TestListScenarios(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	createdAt := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	mock.ExpectQuery("SELECT id, name").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "start_date", "end_date", "symbols", "created_at"}).
+			AddRow("crash-2008", "2008 crash", "2008-09-01", "2009-03-09", []byte(`[]`), createdAt).
+			AddRow("covid-2020", "COVID drop", "2020-02-19", "2020-03-23", []byte(`["AAPL"]`), createdAt))
+
+	dao := &ScenarioDataAccessImpl{DB: db}
+	scenarios, err := dao.ListScenarios(ctx)
+	assert.NoError(t, err)
+	if assert.Len(t, scenarios, 2) {
+		assert.Equal(t, "crash-2008", scenarios[0].Id)
+		assert.Empty(t, scenarios[0].Symbols)
+	}
+}
@@ -0,0 +1,117 @@
+package dataaccess
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"stockgame/internal/database"
+	"stockgame/internal/model"
+	"time"
+)
+
+// ErrServedRoundNotFound is returned when no served round has the requested id
+var ErrServedRoundNotFound = errors.New("served round not found")
+
+type ServedRoundDataAccess interface {
+	CreateServedRound(ctx context.Context, round model.ServedRound) error
+	GetServedRound(ctx context.Context, id string) (model.ServedRound, error)
+	FindServedRound(ctx context.Context, playerId string, symbolUUID string, afterDate string) (model.ServedRound, error)
+	AddHint(ctx context.Context, id string, hint string) (bool, error)
+	SolveServedRound(ctx context.Context, id string, solvedAt time.Time, servedAfter time.Time) (bool, error)
+}
+
+type ServedRoundDataAccessImpl struct {
+	DB database.DBInterface
+	ServedRoundDataAccess
+}
+
+func (s *ServedRoundDataAccessImpl) CreateServedRound(ctx context.Context, round model.ServedRound) error {
+	query := `
+		INSERT INTO served_rounds (id, player_id, symbol_uuid, after_date, hints, served_at)
+		VALUES ($1, $2, $3, $4, '[]', $5)
+	`
+	_, err := s.DB.ExecContext(ctx, query, round.Id, round.PlayerId, round.SymbolUUID, round.AfterDate, round.ServedAt)
+	if err != nil {
+		return fmt.Errorf("error inserting served round: %v", err)
+	}
+	return nil
+}
+
+// GetServedRound reads the served round with the sector of its stock, empty when the
+// sector was not loaded
+func (s *ServedRoundDataAccessImpl) GetServedRound(ctx context.Context, id string) (model.ServedRound, error) {
+	query := `
+		SELECT r.id, r.player_id, r.symbol_uuid, TO_CHAR(r.after_date, 'YYYY-MM-DD'), r.hints, COALESCE(si.sector, ''), r.served_at, r.solved_at
+		FROM served_rounds r
+		LEFT JOIN stocks_info si ON si.symbol_uuid = r.symbol_uuid
+		WHERE r.id = $1
+	`
+	return s.queryServedRound(ctx, query, id)
+}
+
+// FindServedRound reads the last round served to the player for the window
+func (s *ServedRoundDataAccessImpl) FindServedRound(ctx context.Context, playerId string, symbolUUID string, afterDate string) (model.ServedRound, error) {
+	query := `
+		SELECT r.id, r.player_id, r.symbol_uuid, TO_CHAR(r.after_date, 'YYYY-MM-DD'), r.hints, COALESCE(si.sector, ''), r.served_at, r.solved_at
+		FROM served_rounds r
+		LEFT JOIN stocks_info si ON si.symbol_uuid = r.symbol_uuid
+		WHERE r.player_id = $1 AND r.symbol_uuid = $2 AND r.after_date = $3
+		ORDER BY r.served_at DESC
+		LIMIT 1
+	`
+	return s.queryServedRound(ctx, query, playerId, symbolUUID, afterDate)
+}
+
+func (s *ServedRoundDataAccessImpl) queryServedRound(ctx context.Context, query string, args ...interface{}) (model.ServedRound, error) {
+	var round model.ServedRound
+	var hints []byte
+	var solvedAt sql.NullTime
+	err := s.DB.QueryRowContext(ctx, query, args...).Scan(&round.Id, &round.PlayerId, &round.SymbolUUID, &round.AfterDate,
+		&hints, &round.Sector, &round.ServedAt, &solvedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return round, ErrServedRoundNotFound
+	}
+	if err != nil {
+		return round, fmt.Errorf("error querying served round: %v", err)
+	}
+	if err := json.Unmarshal(hints, &round.Hints); err != nil {
+		return round, fmt.Errorf("error reading hints of served round %s: %v", round.Id, err)
+	}
+	if solvedAt.Valid {
+		round.SolvedAt = &solvedAt.Time
+	}
+	return round, nil
+}
+
+// AddHint appends the hint to the served round. It returns false when the hint was
+// already requested so it is only counted once.
+func (s *ServedRoundDataAccessImpl) AddHint(ctx context.Context, id string, hint string) (bool, error) {
+	query := `
+		UPDATE served_rounds
+		SET hints = hints || to_jsonb($2::text)
+		WHERE id = $1 AND NOT hints ? $2
+	`
+	result, err := s.DB.ExecContext(ctx, query, id, hint)
+	if err != nil {
+		return false, fmt.Errorf("error adding hint to served round: %v", err)
+	}
+	return isAffected(result)
+}
+
+// SolveServedRound marks the round solved when it was served after the time, so the
+// deadline is checked in the same statement. It returns false when the round was already
+// solved or served before the time: only one solution in time is accepted for it.
+func (s *ServedRoundDataAccessImpl) SolveServedRound(ctx context.Context, id string, solvedAt time.Time, servedAfter time.Time) (bool, error) {
+	query := `
+		UPDATE served_rounds
+		SET solved_at = $2
+		WHERE id = $1 AND solved_at IS NULL AND served_at >= $3
+	`
+	result, err := s.DB.ExecContext(ctx, query, id, solvedAt, servedAfter)
+	if err != nil {
+		return false, fmt.Errorf("error solving served round: %v", err)
+	}
+	return isAffected(result)
+}
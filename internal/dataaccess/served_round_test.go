@@ -0,0 +1,124 @@
+package dataaccess
+
+import (
+	"database/sql"
+	"stockgame/internal/model"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateServedRound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	servedAt := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	mock.ExpectExec("INSERT INTO served_rounds").
+		WithArgs("served-1", "player-1", "uuid-aapl", "2020-01-31", servedAt).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	dao := &ServedRoundDataAccessImpl{DB: db}
+	err = dao.CreateServedRound(ctx, model.ServedRound{Id: "served-1", PlayerId: "player-1", SymbolUUID: "uuid-aapl", AfterDate: "2020-01-31", ServedAt: servedAt})
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetServedRound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	servedAt := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	mock.ExpectQuery("SELECT r.id, r.player_id").
+		WithArgs("served-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "player_id", "symbol_uuid", "after_date", "hints", "sector", "served_at", "solved_at"}).
+			AddRow("served-1", "player-1", "uuid-aapl", "2020-01-31", []byte(`["sector"]`), "Technology", servedAt, nil))
+	mock.ExpectQuery("SELECT r.id, r.player_id").
+		WithArgs("unknown").
+		WillReturnError(sql.ErrNoRows)
+
+	dao := &ServedRoundDataAccessImpl{DB: db}
+	round, err := dao.GetServedRound(ctx, "served-1")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{model.Hint_sector}, round.Hints)
+	assert.Equal(t, "Technology", round.Sector)
+	assert.Nil(t, round.SolvedAt)
+
+	_, err = dao.GetServedRound(ctx, "unknown")
+	assert.ErrorIs(t, err, ErrServedRoundNotFound)
+}
+
+func TestFindServedRound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	servedAt := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	solvedAt := servedAt.Add(time.Minute)
+	mock.ExpectQuery("SELECT r.id, r.player_id").
+		WithArgs("player-1", "uuid-aapl", "2020-01-31").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "player_id", "symbol_uuid", "after_date", "hints", "sector", "served_at", "solved_at"}).
+			AddRow("served-1", "player-1", "uuid-aapl", "2020-01-31", []byte(`[]`), "", servedAt, solvedAt))
+	mock.ExpectQuery("SELECT r.id, r.player_id").
+		WithArgs("player-1", "uuid-msft", "2020-01-31").
+		WillReturnError(sql.ErrNoRows)
+
+	dao := &ServedRoundDataAccessImpl{DB: db}
+	round, err := dao.FindServedRound(ctx, "player-1", "uuid-aapl", "2020-01-31")
+	assert.NoError(t, err)
+	assert.Equal(t, "served-1", round.Id)
+	if assert.NotNil(t, round.SolvedAt) {
+		assert.True(t, solvedAt.Equal(*round.SolvedAt))
+	}
+
+	_, err = dao.FindServedRound(ctx, "player-1", "uuid-msft", "2020-01-31")
+	assert.ErrorIs(t, err, ErrServedRoundNotFound)
+}
+
+func TestSolveServedRound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	solvedAt := time.Date(2024, 5, 1, 0, 1, 0, 0, time.UTC)
+	servedAfter := solvedAt.Add(-time.Minute)
+	mock.ExpectExec("UPDATE served_rounds").
+		WithArgs("served-1", solvedAt, servedAfter).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE served_rounds").
+		WithArgs("served-1", solvedAt, servedAfter).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	dao := &ServedRoundDataAccessImpl{DB: db}
+	solved, err := dao.SolveServedRound(ctx, "served-1", solvedAt, servedAfter)
+	assert.NoError(t, err)
+	assert.True(t, solved)
+	solved, err = dao.SolveServedRound(ctx, "served-1", solvedAt, servedAfter)
+	assert.NoError(t, err)
+	assert.False(t, solved, "Expected the round to be solved once")
+}
+
+func TestAddHint(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec("UPDATE served_rounds").
+		WithArgs("served-1", model.Hint_rsi).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE served_rounds").
+		WithArgs("served-1", model.Hint_rsi).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	dao := &ServedRoundDataAccessImpl{DB: db}
+	added, err := dao.AddHint(ctx, "served-1", model.Hint_rsi)
+	assert.NoError(t, err)
+	assert.True(t, added)
+	added, err = dao.AddHint(ctx, "served-1", model.Hint_rsi)
+	assert.NoError(t, err)
+	assert.False(t, added, "Expected the hint to be counted once")
+}
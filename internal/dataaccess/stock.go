@@ -14,6 +14,8 @@ type StockDataAccess interface {
 	GetStocksAfterDate(ctx context.Context, symbol string, afterDate string) []model.Stock
 	GetStocksBeforeEqualDate(ctx context.Context, symbol string, beforeDate string) []model.Stock
 	GetStockInfo(ctx context.Context, symbolUUID string) (model.StockInfo, error)
+	GetStockInfoBySymbol(ctx context.Context, symbol string) (model.StockInfo, error)
+	GetSymbolsWithCompleteWindow(ctx context.Context, startDate string, endDate string, days int, excludedSymbol string, limit int) []model.StockInfo
 }
 
 type StockDataAccessImpl struct {
@@ -175,7 +177,7 @@ func (s *StockDataAccessImpl) GetStockInfo(ctx context.Context, symbolUUID strin
 		Name:       "",
 	}
 	query := `
-		SELECT symbol, name, symbol_uuid
+		SELECT symbol, name, symbol_uuid, exchange, is_etf
 		FROM stocks_info
 		WHERE symbol_uuid = $1
 		LIMIT 1
@@ -191,7 +193,7 @@ func (s *StockDataAccessImpl) GetStockInfo(ctx context.Context, symbolUUID strin
 	}
 	defer rows.Close()
 	if rows.Next() {
-		err2 := rows.Scan(&result.Symbol, &result.Name, &result.SymbolUUID)
+		err2 := rows.Scan(&result.Symbol, &result.Name, &result.SymbolUUID, &result.Exchange, &result.IsEtf)
 		if err2 != nil {
 			err = fmt.Errorf("error scanning stock: %v", err2)
 		}
@@ -201,3 +203,67 @@ func (s *StockDataAccessImpl) GetStockInfo(ctx context.Context, symbolUUID strin
 		return
 	}
 }
+
+func (s *StockDataAccessImpl) GetStockInfoBySymbol(ctx context.Context, symbol string) (model.StockInfo, error) {
+	query := `
+		SELECT symbol, name, symbol_uuid, exchange, is_etf
+		FROM stocks_info
+		WHERE symbol = $1
+		LIMIT 1
+	`
+	var info model.StockInfo
+	rows, err := s.DB.QueryContext(ctx, query, symbol)
+	if err != nil {
+		return info, fmt.Errorf("error querying stock: %v", err)
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		return info, fmt.Errorf("no data found for symbol: %s", symbol)
+	}
+	if err := rows.Scan(&info.Symbol, &info.Name, &info.SymbolUUID, &info.Exchange, &info.IsEtf); err != nil {
+		return info, fmt.Errorf("error scanning stock: %v", err)
+	}
+	return info, nil
+}
+
+// GetSymbolsWithCompleteWindow returns random symbols with a price for each of the days
+// between the dates. The days are counted so the symbols share the same trading days. Only
+// the prices between the dates are read, through the index on the date, and counted before
+// the information of the symbols is joined.
+func (s *StockDataAccessImpl) GetSymbolsWithCompleteWindow(ctx context.Context, startDate string, endDate string, days int, excludedSymbol string, limit int) []model.StockInfo {
+	query := `
+		SELECT stocks_info.symbol, stocks_info.name, stocks_info.symbol_uuid, stocks_info.exchange, stocks_info.is_etf
+		FROM (
+			SELECT symbol
+			FROM stocks
+			WHERE date >= $1
+			AND date <= $2
+			AND symbol <> $3
+			GROUP BY symbol
+			HAVING COUNT(*) = $4
+		) complete
+		INNER JOIN stocks_info
+			ON complete.symbol = stocks_info.symbol
+		ORDER BY RANDOM()
+		LIMIT $5
+	`
+	rows, err := s.DB.QueryContext(ctx, query, startDate, endDate, excludedSymbol, days, limit)
+	if err != nil {
+		fmt.Println("GetSymbolsWithCompleteWindow Error querying stock: ", err, query)
+		return []model.StockInfo{}
+	}
+	defer rows.Close()
+	var infos = []model.StockInfo{}
+	for rows.Next() {
+		var info model.StockInfo
+		if err := rows.Scan(&info.Symbol, &info.Name, &info.SymbolUUID, &info.Exchange, &info.IsEtf); err != nil {
+			fmt.Println("GetSymbolsWithCompleteWindow Error scanning stock: ", err)
+			continue
+		}
+		infos = append(infos, info)
+	}
+	if err := rows.Err(); err != nil {
+		fmt.Println("Error during row iteration:", err)
+	}
+	return infos
+}
@@ -32,8 +32,8 @@ func TestGetStockInfo_QuerySuccess(t *testing.T) {
 	assert.NoError(t, err)
 	defer db.Close()
 
-	rows := sqlmock.NewRows([]string{"symbol", "name", "symbol_uuid"}).
-		AddRow("AAPL", "Apple Inc.", "uuid-123")
+	rows := sqlmock.NewRows([]string{"symbol", "name", "symbol_uuid", "exchange", "is_etf"}).
+		AddRow("AAPL", "Apple Inc.", "uuid-123", "Q", false)
 
 	mock.ExpectQuery("SELECT symbol, name, symbol_uuid").
 		WithArgs("uuid-123").
@@ -46,6 +46,8 @@ func TestGetStockInfo_QuerySuccess(t *testing.T) {
 	assert.Equal(t, "AAPL", result.Symbol)
 	assert.Equal(t, "Apple Inc.", result.Name)
 	assert.Equal(t, "uuid-123", result.SymbolUUID)
+	assert.Equal(t, "Q", result.Exchange)
+	assert.False(t, result.IsEtf)
 }
 
 func TestGetStockInfo_QueryNoRows(t *testing.T) {
@@ -53,7 +55,7 @@ func TestGetStockInfo_QueryNoRows(t *testing.T) {
 	assert.NoError(t, err)
 	defer db.Close()
 
-	rows := sqlmock.NewRows([]string{"symbol", "name", "symbol_uuid"}) // no rows
+	rows := sqlmock.NewRows([]string{"symbol", "name", "symbol_uuid", "exchange", "is_etf"}) // no rows
 
 	mock.ExpectQuery("SELECT symbol, name, symbol_uuid").
 		WithArgs("uuid-456").
@@ -83,3 +85,57 @@ func TestGetStockInfo_QueryError(t *testing.T) {
 	assert.Contains(t, err.Error(), "error querying stock")
 	assert.Equal(t, "uuid-789", result.SymbolUUID)
 }
+
+func TestGetStockInfoBySymbol(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT symbol, name, symbol_uuid").
+		WithArgs("AAPL").
+		WillReturnRows(sqlmock.NewRows([]string{"symbol", "name", "symbol_uuid", "exchange", "is_etf"}).
+			AddRow("AAPL", "Apple Inc.", "uuid-123", "Q", false))
+	mock.ExpectQuery("SELECT symbol, name, symbol_uuid").
+		WithArgs("UNKNOWN").
+		WillReturnRows(sqlmock.NewRows([]string{"symbol", "name", "symbol_uuid", "exchange", "is_etf"}))
+
+	dao := &StockDataAccessImpl{DB: db}
+	result, err := dao.GetStockInfoBySymbol(ctx, "AAPL")
+	assert.NoError(t, err)
+	assert.Equal(t, "uuid-123", result.SymbolUUID)
+
+	_, err = dao.GetStockInfoBySymbol(ctx, "UNKNOWN")
+	assert.ErrorContains(t, err, "no data found for symbol: UNKNOWN")
+}
+
+func TestGetSymbolsWithCompleteWindow(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"symbol", "name", "symbol_uuid", "exchange", "is_etf"}).
+		AddRow("MSFT", "Microsoft", "uuid-msft", "Q", false).
+		AddRow("GOOG", "Alphabet", "uuid-goog", "Q", false)
+	mock.ExpectQuery("HAVING COUNT").
+		WithArgs("2020-01-02", "2020-03-31", "AAPL", 50, 12).
+		WillReturnRows(rows)
+
+	dao := &StockDataAccessImpl{DB: db}
+	infos := dao.GetSymbolsWithCompleteWindow(ctx, "2020-01-02", "2020-03-31", 50, "AAPL", 12)
+
+	assert.Len(t, infos, 2)
+	assert.Equal(t, "uuid-goog", infos[1].SymbolUUID)
+}
+
+func TestGetSymbolsWithCompleteWindow_QueryError(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery("HAVING COUNT").WillReturnError(fmt.Errorf("database error"))
+
+	dao := &StockDataAccessImpl{DB: db}
+	infos := dao.GetSymbolsWithCompleteWindow(ctx, "2020-01-02", "2020-03-31", 50, "AAPL", 12)
+
+	assert.Empty(t, infos)
+}
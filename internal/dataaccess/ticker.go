@@ -0,0 +1,111 @@
+package dataaccess
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"stockgame/internal/database"
+	"stockgame/internal/model"
+	"time"
+)
+
+// ErrTickerChallengeNotFound is returned when no challenge has the requested id
+var ErrTickerChallengeNotFound = errors.New("ticker challenge not found")
+
+type TickerDataAccess interface {
+	GetTickerCandidates(ctx context.Context, symbolUUID string, count int) ([]model.TickerOption, error)
+	CreateTickerChallenge(ctx context.Context, challenge model.TickerChallenge) error
+	GetTickerChallenge(ctx context.Context, id string) (model.TickerChallenge, error)
+	SaveTickerAnswer(ctx context.Context, id string, answer string, answeredAt time.Time, total int) (bool, error)
+}
+
+type TickerDataAccessImpl struct {
+	DB database.DBInterface
+	TickerDataAccess
+}
+
+// GetTickerCandidates draws other companies of the same kind as the stock, preferably from
+// the same sector then from the same exchange
+func (s *TickerDataAccessImpl) GetTickerCandidates(ctx context.Context, symbolUUID string, count int) ([]model.TickerOption, error) {
+	query := `
+		SELECT c.symbol_uuid, c.symbol, c.name
+		FROM stocks_info c
+		INNER JOIN stocks_info a
+			ON a.symbol_uuid = $1
+			AND c.symbol_uuid <> a.symbol_uuid
+			AND c.symbol <> a.symbol
+			AND c.is_etf = a.is_etf
+		ORDER BY (c.sector <> '' AND c.sector = a.sector) DESC, (c.exchange = a.exchange) DESC, RANDOM()
+		LIMIT $2
+	`
+	rows, err := s.DB.QueryContext(ctx, query, symbolUUID, count)
+	if err != nil {
+		return nil, fmt.Errorf("error querying ticker candidates: %v", err)
+	}
+	defer rows.Close()
+	candidates := []model.TickerOption{}
+	for rows.Next() {
+		var candidate model.TickerOption
+		if err := rows.Scan(&candidate.SymbolUUID, &candidate.Symbol, &candidate.Name); err != nil {
+			return nil, fmt.Errorf("error scanning ticker candidate: %v", err)
+		}
+		candidates = append(candidates, candidate)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error reading ticker candidates: %v", err)
+	}
+	return candidates, nil
+}
+
+func (s *TickerDataAccessImpl) CreateTickerChallenge(ctx context.Context, challenge model.TickerChallenge) error {
+	candidates, err := json.Marshal(challenge.Candidates)
+	if err != nil {
+		return fmt.Errorf("error serializing candidates: %v", err)
+	}
+	query := `
+		INSERT INTO ticker_challenges (id, player_id, symbol_uuid, candidates, served_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+	_, err = s.DB.ExecContext(ctx, query, challenge.Id, challenge.PlayerId, challenge.SymbolUUID, string(candidates), challenge.ServedAt)
+	if err != nil {
+		return fmt.Errorf("error inserting ticker challenge: %v", err)
+	}
+	return nil
+}
+
+func (s *TickerDataAccessImpl) GetTickerChallenge(ctx context.Context, id string) (model.TickerChallenge, error) {
+	query := `
+		SELECT id, player_id, symbol_uuid, candidates, served_at
+		FROM ticker_challenges
+		WHERE id = $1
+	`
+	var challenge model.TickerChallenge
+	var candidates []byte
+	err := s.DB.QueryRowContext(ctx, query, id).Scan(&challenge.Id, &challenge.PlayerId, &challenge.SymbolUUID, &candidates, &challenge.ServedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return challenge, ErrTickerChallengeNotFound
+	}
+	if err != nil {
+		return challenge, fmt.Errorf("error querying ticker challenge %s: %v", id, err)
+	}
+	if err := json.Unmarshal(candidates, &challenge.Candidates); err != nil {
+		return challenge, fmt.Errorf("error reading candidates of ticker challenge %s: %v", id, err)
+	}
+	return challenge, nil
+}
+
+// SaveTickerAnswer returns false when the challenge was already answered
+func (s *TickerDataAccessImpl) SaveTickerAnswer(ctx context.Context, id string, answer string, answeredAt time.Time, total int) (bool, error) {
+	query := `
+		UPDATE ticker_challenges
+		SET answer = $2, answered_at = $3, total = $4
+		WHERE id = $1 AND answered_at IS NULL
+	`
+	result, err := s.DB.ExecContext(ctx, query, id, answer, answeredAt, total)
+	if err != nil {
+		return false, fmt.Errorf("error saving ticker answer: %v", err)
+	}
+	return isAffected(result)
+}
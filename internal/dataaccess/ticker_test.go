@@ -0,0 +1,100 @@
+package dataaccess
+
+import (
+	"database/sql"
+	"stockgame/internal/model"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetTickerCandidates(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"symbol_uuid", "symbol", "name"}).
+		AddRow("uuid-msft", "MSFT", "Microsoft").
+		AddRow("uuid-goog", "GOOG", "Alphabet")
+	mock.ExpectQuery("SELECT c.symbol_uuid, c.symbol, c.name").
+		WithArgs("uuid-aapl", 3).
+		WillReturnRows(rows)
+
+	dao := &TickerDataAccessImpl{DB: db}
+	candidates, err := dao.GetTickerCandidates(ctx, "uuid-aapl", 3)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []model.TickerOption{
+		{TickerCandidate: model.TickerCandidate{Symbol: "MSFT", Name: "Microsoft"}, SymbolUUID: "uuid-msft"},
+		{TickerCandidate: model.TickerCandidate{Symbol: "GOOG", Name: "Alphabet"}, SymbolUUID: "uuid-goog"},
+	}, candidates)
+}
+
+func TestCreateTickerChallenge(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	servedAt := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	mock.ExpectExec("INSERT INTO ticker_challenges").
+		WithArgs("challenge-1", "player-1", "uuid-aapl", `[{"id":"option-1","symbol":"AAPL","name":"Apple","symbolUUID":"uuid-aapl"}]`, servedAt).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	dao := &TickerDataAccessImpl{DB: db}
+	err = dao.CreateTickerChallenge(ctx, model.TickerChallenge{
+		Id:         "challenge-1",
+		PlayerId:   "player-1",
+		SymbolUUID: "uuid-aapl",
+		Candidates: []model.TickerOption{{TickerCandidate: model.TickerCandidate{Id: "option-1", Symbol: "AAPL", Name: "Apple"}, SymbolUUID: "uuid-aapl"}},
+		ServedAt:   servedAt,
+	})
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetTickerChallenge(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	servedAt := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	rows := sqlmock.NewRows([]string{"id", "player_id", "symbol_uuid", "candidates", "served_at"}).
+		AddRow("challenge-1", "player-1", "uuid-aapl", []byte(`[{"id":"option-1","symbol":"AAPL","name":"Apple","symbolUUID":"uuid-aapl"}]`), servedAt)
+	mock.ExpectQuery("SELECT id, player_id, symbol_uuid, candidates").
+		WithArgs("challenge-1").
+		WillReturnRows(rows)
+	mock.ExpectQuery("SELECT id, player_id, symbol_uuid, candidates").
+		WithArgs("unknown").
+		WillReturnError(sql.ErrNoRows)
+
+	dao := &TickerDataAccessImpl{DB: db}
+	challenge, err := dao.GetTickerChallenge(ctx, "challenge-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "AAPL", challenge.Candidates[0].Symbol)
+	assert.Equal(t, "option-1", challenge.Candidates[0].Id)
+	assert.Equal(t, "uuid-aapl", challenge.Candidates[0].SymbolUUID)
+	assert.Equal(t, servedAt, challenge.ServedAt)
+
+	_, err = dao.GetTickerChallenge(ctx, "unknown")
+	assert.ErrorIs(t, err, ErrTickerChallengeNotFound)
+}
+
+func TestSaveTickerAnswer(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	answeredAt := time.Date(2024, 5, 1, 0, 0, 10, 0, time.UTC)
+	mock.ExpectExec("UPDATE ticker_challenges").
+		WithArgs("challenge-1", "uuid-aapl", answeredAt, 83).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	dao := &TickerDataAccessImpl{DB: db}
+	saved, err := dao.SaveTickerAnswer(ctx, "challenge-1", "uuid-aapl", answeredAt, 83)
+
+	assert.NoError(t, err)
+	assert.False(t, saved, "Expected an answered challenge to be skipped")
+}
@@ -0,0 +1,196 @@
+package dataaccess
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"stockgame/internal/database"
+	"stockgame/internal/model"
+)
+
+// ErrTournamentNotFound is returned when no tournament has the requested id
+var ErrTournamentNotFound = errors.New("tournament not found")
+
+// Number of tournaments listed, the most recent first
+const maxTournamentsListed = 100
+
+type TournamentDataAccess interface {
+	CreateTournament(ctx context.Context, tournament model.Tournament) error
+	GetTournament(ctx context.Context, id string) (model.Tournament, error)
+	ListTournaments(ctx context.Context) ([]model.Tournament, error)
+	AddEntry(ctx context.Context, tournamentId string, playerId string, name string) (bool, error)
+	HasEntry(ctx context.Context, tournamentId string, playerId string) (bool, error)
+	CountPlayedRounds(ctx context.Context, tournamentId string, playerId string) (int, error)
+	SaveTournamentRound(ctx context.Context, tournamentId string, playerId string, position int, roundId string, score model.UserScoreResponse) (bool, error)
+	GetStandings(ctx context.Context, tournamentId string) ([]model.TournamentStanding, error)
+}
+
+type TournamentDataAccessImpl struct {
+	DB database.DBInterface
+	TournamentDataAccess
+}
+
+func (s *TournamentDataAccessImpl) CreateTournament(ctx context.Context, tournament model.Tournament) error {
+	windows, err := json.Marshal(tournament.Windows)
+	if err != nil {
+		return fmt.Errorf("error serializing windows: %v", err)
+	}
+	query := `
+		INSERT INTO tournaments (id, name, starts_at, ends_at, windows, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	_, err = s.DB.ExecContext(ctx, query, tournament.Id, tournament.Name, tournament.StartsAt, tournament.EndsAt, string(windows), tournament.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("error inserting tournament: %v", err)
+	}
+	return nil
+}
+
+const selectTournaments = `
+	SELECT t.id, t.name, t.starts_at, t.ends_at, t.windows, t.created_at,
+		(SELECT COUNT(*) FROM tournament_entries e WHERE e.tournament_id = t.id)
+	FROM tournaments t
+`
+
+func (s *TournamentDataAccessImpl) GetTournament(ctx context.Context, id string) (model.Tournament, error) {
+	tournament, err := scanTournament(s.DB.QueryRowContext(ctx, selectTournaments+`WHERE t.id = $1`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return tournament, ErrTournamentNotFound
+	}
+	if err != nil {
+		return tournament, fmt.Errorf("error querying tournament %s: %v", id, err)
+	}
+	return tournament, nil
+}
+
+func (s *TournamentDataAccessImpl) ListTournaments(ctx context.Context) ([]model.Tournament, error) {
+	rows, err := s.DB.QueryContext(ctx, selectTournaments+`ORDER BY t.starts_at DESC, t.id LIMIT $1`, maxTournamentsListed)
+	if err != nil {
+		return nil, fmt.Errorf("error querying tournaments: %v", err)
+	}
+	defer rows.Close()
+	tournaments := []model.Tournament{}
+	for rows.Next() {
+		tournament, err := scanTournament(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning tournament: %v", err)
+		}
+		tournaments = append(tournaments, tournament)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error reading tournaments: %v", err)
+	}
+	return tournaments, nil
+}
+
+// AddEntry returns false when the player already joined the tournament
+func (s *TournamentDataAccessImpl) AddEntry(ctx context.Context, tournamentId string, playerId string, name string) (bool, error) {
+	query := `
+		INSERT INTO tournament_entries (tournament_id, player_id, name)
+		VALUES ($1, $2, $3)
+		ON CONFLICT DO NOTHING
+	`
+	result, err := s.DB.ExecContext(ctx, query, tournamentId, playerId, name)
+	if err != nil {
+		return false, fmt.Errorf("error inserting tournament entry: %v", err)
+	}
+	return isAffected(result)
+}
+
+func (s *TournamentDataAccessImpl) HasEntry(ctx context.Context, tournamentId string, playerId string) (bool, error) {
+	var exists bool
+	query := `SELECT EXISTS (SELECT 1 FROM tournament_entries WHERE tournament_id = $1 AND player_id = $2)`
+	if err := s.DB.QueryRowContext(ctx, query, tournamentId, playerId).Scan(&exists); err != nil {
+		return false, fmt.Errorf("error querying tournament entry: %v", err)
+	}
+	return exists, nil
+}
+
+func (s *TournamentDataAccessImpl) CountPlayedRounds(ctx context.Context, tournamentId string, playerId string) (int, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM tournament_rounds WHERE tournament_id = $1 AND player_id = $2`
+	if err := s.DB.QueryRowContext(ctx, query, tournamentId, playerId).Scan(&count); err != nil {
+		return 0, fmt.Errorf("error counting tournament rounds: %v", err)
+	}
+	return count, nil
+}
+
+// SaveTournamentRound returns false when the player already played the round
+func (s *TournamentDataAccessImpl) SaveTournamentRound(ctx context.Context, tournamentId string, playerId string, position int, roundId string, score model.UserScoreResponse) (bool, error) {
+	query := `
+		INSERT INTO tournament_rounds (tournament_id, player_id, position, round_id, total, in_low_high, in_open_close, in_bollinger, in_direction)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT DO NOTHING
+	`
+	result, err := s.DB.ExecContext(ctx, query, tournamentId, playerId, position, roundId,
+		score.Total, score.InLowHigh, score.InOpenClose, score.InBollinger, score.InDirection)
+	if err != nil {
+		return false, fmt.Errorf("error inserting tournament round: %v", err)
+	}
+	return isAffected(result)
+}
+
+// GetStandings sums the scores of every player who joined the tournament, unranked
+func (s *TournamentDataAccessImpl) GetStandings(ctx context.Context, tournamentId string) ([]model.TournamentStanding, error) {
+	query := `
+		SELECT ROW_NUMBER() OVER (ORDER BY e.joined_at, e.player_id), e.name, COUNT(r.position),
+			COALESCE(SUM(r.total), 0), COALESCE(SUM(r.in_low_high), 0), COALESCE(SUM(r.in_open_close), 0),
+			COALESCE(SUM(r.in_bollinger), 0), COALESCE(SUM(r.in_direction), 0), MAX(r.submitted_at)
+		FROM tournament_entries e
+		LEFT JOIN tournament_rounds r
+			ON r.tournament_id = e.tournament_id
+			AND r.player_id = e.player_id
+		WHERE e.tournament_id = $1
+		GROUP BY e.player_id, e.name, e.joined_at
+	`
+	rows, err := s.DB.QueryContext(ctx, query, tournamentId)
+	if err != nil {
+		return nil, fmt.Errorf("error querying standings: %v", err)
+	}
+	defer rows.Close()
+	standings := []model.TournamentStanding{}
+	for rows.Next() {
+		var standing model.TournamentStanding
+		var lastSubmittedAt sql.NullTime
+		err := rows.Scan(&standing.Entry, &standing.Name, &standing.Rounds,
+			&standing.Score.Total, &standing.Score.InLowHigh, &standing.Score.InOpenClose,
+			&standing.Score.InBollinger, &standing.Score.InDirection, &lastSubmittedAt)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning standing: %v", err)
+		}
+		if lastSubmittedAt.Valid {
+			standing.LastSubmittedAt = &lastSubmittedAt.Time
+		}
+		standings = append(standings, standing)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error reading standings: %v", err)
+	}
+	return standings, nil
+}
+
+func scanTournament(row rowScanner) (model.Tournament, error) {
+	var tournament model.Tournament
+	var windows []byte
+	err := row.Scan(&tournament.Id, &tournament.Name, &tournament.StartsAt, &tournament.EndsAt, &windows, &tournament.CreatedAt, &tournament.Entries)
+	if err != nil {
+		return tournament, err
+	}
+	if err := json.Unmarshal(windows, &tournament.Windows); err != nil {
+		return tournament, fmt.Errorf("error reading windows of tournament %s: %v", tournament.Id, err)
+	}
+	tournament.Rounds = len(tournament.Windows)
+	return tournament, nil
+}
+
+// isAffected returns false when the statement skipped the row, an insert with ON CONFLICT
+// DO NOTHING or an update whose condition no longer matches
+func isAffected(result sql.Result) (bool, error) {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("error reading the affected rows: %v", err)
+	}
+	return affected > 0, nil
+}
@@ -0,0 +1,172 @@
+package dataaccess
+
+import (
+	"database/sql"
+	"fmt"
+	"stockgame/internal/model"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+var tournamentColumns = []string{"id", "name", "starts_at", "ends_at", "windows", "created_at", "entries"}
+
+func TestCreateTournament(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	startsAt := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	endsAt := startsAt.Add(7 * 24 * time.Hour)
+	tournament := model.Tournament{
+		Id:        "tournament-1",
+		Name:      "Spring cup",
+		StartsAt:  startsAt,
+		EndsAt:    endsAt,
+		Windows:   []model.TournamentWindow{{SymbolUUID: "uuid-aapl", AfterDate: "2020-01-31"}},
+		CreatedAt: startsAt,
+	}
+	mock.ExpectExec("INSERT INTO tournaments").
+		WithArgs("tournament-1", "Spring cup", startsAt, endsAt, `[{"symbolUUID":"uuid-aapl","afterDate":"2020-01-31"}]`, startsAt).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	dao := &TournamentDataAccessImpl{DB: db}
+	err = dao.CreateTournament(ctx, tournament)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetTournament(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	startsAt := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	rows := sqlmock.NewRows(tournamentColumns).
+		AddRow("tournament-1", "Spring cup", startsAt, startsAt, []byte(`[{"symbolUUID":"uuid-aapl","afterDate":"2020-01-31"},{"symbolUUID":"uuid-msft","afterDate":"2019-06-28"}]`), startsAt, 4)
+	mock.ExpectQuery("SELECT t.id, t.name").
+		WithArgs("tournament-1").
+		WillReturnRows(rows)
+
+	dao := &TournamentDataAccessImpl{DB: db}
+	tournament, err := dao.GetTournament(ctx, "tournament-1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, tournament.Rounds)
+	assert.Equal(t, 4, tournament.Entries)
+	assert.Equal(t, "uuid-msft", tournament.Windows[1].SymbolUUID)
+}
+
+func TestGetTournament_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT t.id, t.name").
+		WithArgs("unknown").
+		WillReturnError(sql.ErrNoRows)
+
+	dao := &TournamentDataAccessImpl{DB: db}
+	_, err = dao.GetTournament(ctx, "unknown")
+
+	assert.ErrorIs(t, err, ErrTournamentNotFound)
+}
+
+func TestListTournaments(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	now := time.Now()
+	rows := sqlmock.NewRows(tournamentColumns).
+		AddRow("tournament-2", "Summer cup", now, now, []byte(`[]`), now, 0).
+		AddRow("tournament-1", "Spring cup", now, now, []byte(`[]`), now, 3)
+	mock.ExpectQuery("SELECT t.id, t.name").
+		WithArgs(maxTournamentsListed).
+		WillReturnRows(rows)
+
+	dao := &TournamentDataAccessImpl{DB: db}
+	tournaments, err := dao.ListTournaments(ctx)
+
+	assert.NoError(t, err)
+	assert.Len(t, tournaments, 2)
+	assert.Equal(t, "tournament-2", tournaments[0].Id)
+}
+
+func TestAddEntry(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec("INSERT INTO tournament_entries").
+		WithArgs("tournament-1", "player-1", "Alice").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO tournament_entries").
+		WithArgs("tournament-1", "player-1", "Alice").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	dao := &TournamentDataAccessImpl{DB: db}
+	added, err := dao.AddEntry(ctx, "tournament-1", "player-1", "Alice")
+	assert.NoError(t, err)
+	assert.True(t, added)
+	added, err = dao.AddEntry(ctx, "tournament-1", "player-1", "Alice")
+	assert.NoError(t, err)
+	assert.False(t, added, "Expected the second entry to be skipped")
+}
+
+func TestSaveTournamentRound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec("INSERT INTO tournament_rounds").
+		WithArgs("tournament-1", "player-1", 2, "round-1", 42, 10, 12, 8, 12).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	dao := &TournamentDataAccessImpl{DB: db}
+	saved, err := dao.SaveTournamentRound(ctx, "tournament-1", "player-1", 2, "round-1",
+		model.UserScoreResponse{Total: 42, InLowHigh: 10, InOpenClose: 12, InBollinger: 8, InDirection: 12})
+
+	assert.NoError(t, err)
+	assert.True(t, saved)
+}
+
+func TestSaveTournamentRound_Error(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec("INSERT INTO tournament_rounds").WillReturnError(fmt.Errorf("db error"))
+
+	dao := &TournamentDataAccessImpl{DB: db}
+	_, err = dao.SaveTournamentRound(ctx, "tournament-1", "player-1", 1, "", model.UserScoreResponse{})
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "error inserting tournament round")
+}
+
+func TestGetStandings(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	submittedAt := time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC)
+	rows := sqlmock.NewRows([]string{"entry", "name", "rounds", "total", "in_low_high", "in_open_close", "in_bollinger", "in_direction", "last_submitted_at"}).
+		AddRow(1, "Alice", 2, 80, 30, 20, 10, 20, submittedAt).
+		AddRow(2, "Bob", 0, 0, 0, 0, 0, 0, nil)
+	mock.ExpectQuery("SELECT ROW_NUMBER").
+		WithArgs("tournament-1").
+		WillReturnRows(rows)
+
+	dao := &TournamentDataAccessImpl{DB: db}
+	standings, err := dao.GetStandings(ctx, "tournament-1")
+
+	assert.NoError(t, err)
+	assert.Len(t, standings, 2)
+	assert.Equal(t, model.UserScoreResponse{Total: 80, InLowHigh: 30, InOpenClose: 20, InBollinger: 10, InDirection: 20}, standings[0].Score)
+	assert.Equal(t, submittedAt, *standings[0].LastSubmittedAt)
+	assert.Nil(t, standings[1].LastSubmittedAt)
+}
@@ -0,0 +1,79 @@
+package dataaccess
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"stockgame/internal/database"
+	"stockgame/internal/model"
+)
+
+// ErrTradingSessionNotFound is returned when no trading session has the requested id
+var ErrTradingSessionNotFound = errors.New("trading session not found")
+
+type TradingDataAccess interface {
+	CreateTradingSession(ctx context.Context, session model.TradingSession) error
+	GetTradingSession(ctx context.Context, id string) (model.TradingSession, error)
+	UpdateTradingSession(ctx context.Context, session model.TradingSession, previousDay int) (bool, error)
+}
+
+type TradingDataAccessImpl struct {
+	DB database.DBInterface
+	TradingDataAccess
+}
+
+func (s *TradingDataAccessImpl) CreateTradingSession(ctx context.Context, session model.TradingSession) error {
+	query := `
+		INSERT INTO trading_sessions (id, player_id, symbol_uuid, after_date, days, day, initial_cash, last_close, cash, shares, decisions, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, '[]', $11)
+	`
+	_, err := s.DB.ExecContext(ctx, query, session.Id, session.PlayerId, session.SymbolUUID, session.AfterDate,
+		session.Days, session.Day, session.InitialCash, session.LastClose, session.Cash, session.Shares, session.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("error inserting trading session: %v", err)
+	}
+	return nil
+}
+
+func (s *TradingDataAccessImpl) GetTradingSession(ctx context.Context, id string) (model.TradingSession, error) {
+	query := `
+		SELECT id, player_id, symbol_uuid, TO_CHAR(after_date, 'YYYY-MM-DD'), days, day, initial_cash, last_close, cash, shares, decisions, created_at
+		FROM trading_sessions
+		WHERE id = $1
+	`
+	var session model.TradingSession
+	var decisions []byte
+	err := s.DB.QueryRowContext(ctx, query, id).Scan(&session.Id, &session.PlayerId, &session.SymbolUUID, &session.AfterDate,
+		&session.Days, &session.Day, &session.InitialCash, &session.LastClose, &session.Cash, &session.Shares, &decisions, &session.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return session, ErrTradingSessionNotFound
+	}
+	if err != nil {
+		return session, fmt.Errorf("error querying trading session %s: %v", id, err)
+	}
+	if err := json.Unmarshal(decisions, &session.Decisions); err != nil {
+		return session, fmt.Errorf("error reading decisions of trading session %s: %v", id, err)
+	}
+	return session, nil
+}
+
+// UpdateTradingSession saves the session only if it is still at the previous day. It returns
+// false when another decision was saved first.
+func (s *TradingDataAccessImpl) UpdateTradingSession(ctx context.Context, session model.TradingSession, previousDay int) (bool, error) {
+	decisions, err := json.Marshal(session.Decisions)
+	if err != nil {
+		return false, fmt.Errorf("error serializing decisions: %v", err)
+	}
+	query := `
+		UPDATE trading_sessions
+		SET day = $2, cash = $3, shares = $4, decisions = $5, updated_at = NOW()
+		WHERE id = $1 AND day = $6
+	`
+	result, err := s.DB.ExecContext(ctx, query, session.Id, session.Day, session.Cash, session.Shares, string(decisions), previousDay)
+	if err != nil {
+		return false, fmt.Errorf("error updating trading session: %v", err)
+	}
+	return isAffected(result)
+}
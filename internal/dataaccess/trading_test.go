@@ -0,0 +1,105 @@
+package dataaccess
+
+import (
+	"database/sql"
+	"stockgame/internal/model"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateTradingSession(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	createdAt := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	mock.ExpectExec("INSERT INTO trading_sessions").
+		WithArgs("session-1", "player-1", "uuid-aapl", "2020-01-31", 10, 0, 10000.0, 150.0, 10000.0, 0, createdAt).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	dao := &TradingDataAccessImpl{DB: db}
+	err = dao.CreateTradingSession(ctx, model.TradingSession{
+		Id:              "session-1",
+		PlayerId:        "player-1",
+		SymbolUUID:      "uuid-aapl",
+		AfterDate:       "2020-01-31",
+		Days:            10,
+		InitialCash:     10000,
+		LastClose:       150,
+		TradingPosition: model.TradingPosition{Cash: 10000},
+		CreatedAt:       createdAt,
+	})
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetTradingSession(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	createdAt := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	rows := sqlmock.NewRows([]string{"id", "player_id", "symbol_uuid", "after_date", "days", "day", "initial_cash", "last_close", "cash", "shares", "decisions", "created_at"}).
+		AddRow("session-1", "player-1", "uuid-aapl", "2020-01-31", 10, 1, 10000.0, 150.0, 100.0, 66,
+			[]byte(`[{"day":1,"action":"buy","quantity":66,"price":150,"cash":100,"shares":66}]`), createdAt)
+	mock.ExpectQuery("SELECT id, player_id").
+		WithArgs("session-1").
+		WillReturnRows(rows)
+
+	dao := &TradingDataAccessImpl{DB: db}
+	session, err := dao.GetTradingSession(ctx, "session-1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, session.Day)
+	assert.Equal(t, model.TradingPosition{Cash: 100, Shares: 66}, session.TradingPosition)
+	if assert.Len(t, session.Decisions, 1) {
+		assert.Equal(t, 66, session.Decisions[0].Shares)
+	}
+}
+
+func TestGetTradingSession_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT id, player_id").
+		WithArgs("unknown").
+		WillReturnError(sql.ErrNoRows)
+
+	dao := &TradingDataAccessImpl{DB: db}
+	_, err = dao.GetTradingSession(ctx, "unknown")
+
+	assert.ErrorIs(t, err, ErrTradingSessionNotFound)
+}
+
+func TestUpdateTradingSession(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	session := model.TradingSession{
+		Id:              "session-1",
+		Day:             1,
+		Decisions:       []model.TradeDecision{{Day: 1, Action: model.Trade_hold, TradingPosition: model.TradingPosition{Cash: 10000}}},
+		TradingPosition: model.TradingPosition{Cash: 10000},
+	}
+	decisions := `[{"day":1,"action":"hold","quantity":0,"price":0,"cash":10000,"shares":0}]`
+	mock.ExpectExec("UPDATE trading_sessions").
+		WithArgs("session-1", 1, 10000.0, 0, decisions, 0).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE trading_sessions").
+		WithArgs("session-1", 1, 10000.0, 0, decisions, 0).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	dao := &TradingDataAccessImpl{DB: db}
+	updated, err := dao.UpdateTradingSession(ctx, session, 0)
+	assert.NoError(t, err)
+	assert.True(t, updated)
+	updated, err = dao.UpdateTradingSession(ctx, session, 0)
+	assert.NoError(t, err)
+	assert.False(t, updated, "Expected the second decision of the day to be skipped")
+}
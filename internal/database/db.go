@@ -14,6 +14,7 @@ var db *sql.DB
 var CONTEXT_TIMEOUT = 30 * time.Second
 
 type DBInterface interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
 	Query(query string, args ...interface{}) (*sql.Rows, error)
 	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
 	QueryRow(query string, args ...interface{}) *sql.Row
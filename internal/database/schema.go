@@ -0,0 +1,158 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+const TableNameRounds = "rounds"
+const TableNameTournaments = "tournaments"
+const TableNameTournamentEntries = "tournament_entries"
+const TableNameTournamentRounds = "tournament_rounds"
+const TableNameTradingSessions = "trading_sessions"
+const TableNameTickerChallenges = "ticker_challenges"
+const TableNameScenarios = "scenarios"
+const TableNameServedRounds = "served_rounds"
+const TableNamePlayerProgress = "player_progress"
+const TableNameServedBaskets = "served_baskets"
+
+// CreateGameTables creates the tables filled by the players (not by the data-loader).
+// Every statement is idempotent so it runs at each start of the API server.
+func CreateGameTables(db *sql.DB) error {
+	statements := []string{
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+			id VARCHAR PRIMARY KEY,
+			player_id VARCHAR NOT NULL DEFAULT '',
+			mode VARCHAR NOT NULL,
+			symbol VARCHAR NOT NULL,
+			symbol_uuid VARCHAR NOT NULL,
+			after_date DATE NOT NULL,
+			difficulty VARCHAR NOT NULL DEFAULT '',
+			total INT NOT NULL,
+			in_low_high INT NOT NULL,
+			in_open_close INT NOT NULL,
+			in_bollinger INT NOT NULL,
+			in_direction INT NOT NULL,
+			hint_penalty INT NOT NULL DEFAULT 0,
+			speed_bonus INT NOT NULL DEFAULT 0,
+			guesses JSONB NOT NULL,
+			history_start_date DATE,
+			future_end_date DATE,
+			last_close DOUBLE PRECISION NOT NULL DEFAULT 0,
+			actual_closes JSONB NOT NULL DEFAULT '[]',
+			hints JSONB NOT NULL DEFAULT '[]',
+			served_at TIMESTAMPTZ,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		);`, TableNameRounds),
+		// Databases loaded before the eligibility rules do not have these columns of stocks_info
+		`ALTER TABLE IF EXISTS stocks_info ADD COLUMN IF NOT EXISTS exchange VARCHAR NOT NULL DEFAULT '';`,
+		`ALTER TABLE IF EXISTS stocks_info ADD COLUMN IF NOT EXISTS is_etf BOOLEAN NOT NULL DEFAULT FALSE;`,
+		`ALTER TABLE IF EXISTS stocks_info ADD COLUMN IF NOT EXISTS sector VARCHAR NOT NULL DEFAULT '';`,
+		fmt.Sprintf(`CREATE INDEX IF NOT EXISTS idx_rounds_symbol_date ON %s (symbol, after_date);`, TableNameRounds),
+		fmt.Sprintf(`CREATE INDEX IF NOT EXISTS idx_rounds_difficulty ON %s (difficulty);`, TableNameRounds),
+		fmt.Sprintf(`CREATE INDEX IF NOT EXISTS idx_rounds_player ON %s (player_id, created_at DESC);`, TableNameRounds),
+		// The windows are stored with the tournament so it is created in a single statement
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+			id VARCHAR PRIMARY KEY,
+			name VARCHAR NOT NULL,
+			starts_at TIMESTAMPTZ NOT NULL,
+			ends_at TIMESTAMPTZ NOT NULL,
+			windows JSONB NOT NULL,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		);`, TableNameTournaments),
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+			tournament_id VARCHAR NOT NULL REFERENCES %s (id) ON DELETE CASCADE,
+			player_id VARCHAR NOT NULL,
+			name VARCHAR NOT NULL,
+			joined_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			PRIMARY KEY (tournament_id, player_id)
+		);`, TableNameTournamentEntries, TableNameTournaments),
+		// The primary key prevents a round from being played twice
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+			tournament_id VARCHAR NOT NULL,
+			player_id VARCHAR NOT NULL,
+			position INT NOT NULL,
+			round_id VARCHAR NOT NULL DEFAULT '',
+			total INT NOT NULL,
+			in_low_high INT NOT NULL,
+			in_open_close INT NOT NULL,
+			in_bollinger INT NOT NULL,
+			in_direction INT NOT NULL,
+			submitted_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			PRIMARY KEY (tournament_id, player_id, position),
+			FOREIGN KEY (tournament_id, player_id) REFERENCES %s (tournament_id, player_id) ON DELETE CASCADE
+		);`, TableNameTournamentRounds, TableNameTournamentEntries),
+		// The decisions are stored with the session, the day is checked to apply each one once
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+			id VARCHAR PRIMARY KEY,
+			player_id VARCHAR NOT NULL,
+			symbol_uuid VARCHAR NOT NULL,
+			after_date DATE NOT NULL,
+			days INT NOT NULL,
+			day INT NOT NULL DEFAULT 0,
+			initial_cash DOUBLE PRECISION NOT NULL,
+			last_close DOUBLE PRECISION NOT NULL,
+			cash DOUBLE PRECISION NOT NULL,
+			shares INT NOT NULL DEFAULT 0,
+			decisions JSONB NOT NULL DEFAULT '[]',
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		);`, TableNameTradingSessions),
+		// The answer is saved only while answered_at is empty so a challenge is answered once
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+			id VARCHAR PRIMARY KEY,
+			player_id VARCHAR NOT NULL,
+			symbol_uuid VARCHAR NOT NULL,
+			candidates JSONB NOT NULL,
+			served_at TIMESTAMPTZ NOT NULL,
+			answer VARCHAR NOT NULL DEFAULT '',
+			answered_at TIMESTAMPTZ,
+			total INT NOT NULL DEFAULT 0
+		);`, TableNameTickerChallenges),
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+			id VARCHAR PRIMARY KEY,
+			name VARCHAR NOT NULL,
+			start_date DATE NOT NULL,
+			end_date DATE NOT NULL,
+			symbols JSONB NOT NULL DEFAULT '[]',
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		);`, TableNameScenarios),
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+			id VARCHAR PRIMARY KEY,
+			player_id VARCHAR NOT NULL DEFAULT '',
+			symbol_uuid VARCHAR NOT NULL,
+			after_date DATE NOT NULL,
+			hints JSONB NOT NULL DEFAULT '[]',
+			served_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			solved_at TIMESTAMPTZ
+		);`, TableNameServedRounds),
+		fmt.Sprintf(`CREATE INDEX IF NOT EXISTS idx_served_rounds_window ON %s (player_id, symbol_uuid, after_date, served_at DESC);`, TableNameServedRounds),
+		// The version is checked at each update so concurrent rounds of a player are not lost
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+			player_id VARCHAR PRIMARY KEY,
+			xp INT NOT NULL DEFAULT 0,
+			rounds INT NOT NULL DEFAULT 0,
+			current_streak INT NOT NULL DEFAULT 0,
+			longest_streak INT NOT NULL DEFAULT 0,
+			last_played_date VARCHAR NOT NULL DEFAULT '',
+			counters JSONB NOT NULL DEFAULT '{}',
+			unlocked JSONB NOT NULL DEFAULT '{}',
+			version INT NOT NULL DEFAULT 1,
+			updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		);`, TableNamePlayerProgress),
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+			id VARCHAR PRIMARY KEY,
+			player_id VARCHAR NOT NULL DEFAULT '',
+			after_date DATE NOT NULL,
+			symbol_uuids JSONB NOT NULL DEFAULT '[]',
+			served_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			solved_at TIMESTAMPTZ
+		);`, TableNameServedBaskets),
+	}
+	for _, statement := range statements {
+		if _, err := db.Exec(statement); err != nil {
+			return fmt.Errorf("cannot create game tables: %v", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,181 @@
+package indicators
+
+import "stockgame/internal/model"
+
+// BollingerOptions configures the Bollinger bands computation
+type BollingerOptions struct {
+	Period         int
+	K              float64 // Number of standard deviations between the average and a band
+	Sample         bool    // Use the sample standard deviation (n-1) instead of the population one (n)
+	ExcludeCurrent bool    // The window ends the day before the band's date instead of on that day
+}
+
+// SMA returns the simple moving average of the close price. The first point is at
+// the index period-1 since it needs `period` values.
+func SMA(stocks []model.Stock, period int) []model.IndicatorPoint {
+	points := []model.IndicatorPoint{}
+	if period <= 0 {
+		return points
+	}
+	window := newRollingWindow(period)
+	for _, stock := range stocks {
+		window.Add(stock.Close)
+		if window.Full() {
+			points = append(points, model.IndicatorPoint{Date: stock.Date, Value: window.Mean()})
+		}
+	}
+	return points
+}
+
+// EMA returns the exponential moving average of the close price seeded with the SMA
+// of the first `period` days.
+func EMA(stocks []model.Stock, period int) []model.IndicatorPoint {
+	points := []model.IndicatorPoint{}
+	if period <= 0 {
+		return points
+	}
+	ema := newEMAStream(period)
+	for _, stock := range stocks {
+		if value, ok := ema.Next(stock.Close); ok {
+			points = append(points, model.IndicatorPoint{Date: stock.Date, Value: value})
+		}
+	}
+	return points
+}
+
+// Bollinger returns the moving average of the close price with a band K standard
+// deviations above and below. The window includes the current day unless
+// ExcludeCurrent is set, in which case the band only uses the previous days.
+func Bollinger(stocks []model.Stock, options BollingerOptions) []model.BollingerBand {
+	bands := []model.BollingerBand{}
+	if options.Period <= 0 {
+		return bands
+	}
+	window := newRollingWindow(options.Period)
+	for _, stock := range stocks {
+		if !options.ExcludeCurrent {
+			window.Add(stock.Close)
+		}
+		if window.Full() {
+			average := window.Mean()
+			standardDeviation := window.StdDev(options.Sample)
+			bands = append(bands, model.BollingerBand{
+				Date:      stock.Date,
+				UpperBand: average + options.K*standardDeviation,
+				Average:   average,
+				LowerBand: average - options.K*standardDeviation,
+			})
+		}
+		if options.ExcludeCurrent {
+			window.Add(stock.Close)
+		}
+	}
+	return bands
+}
+
+// RSI returns the relative strength index (0 to 100) using Wilder's smoothing of the
+// gains and losses. The first point needs period+1 days.
+func RSI(stocks []model.Stock, period int) []model.IndicatorPoint {
+	points := []model.IndicatorPoint{}
+	if period <= 0 {
+		return points
+	}
+	gains := newWilderStream(period)
+	losses := newWilderStream(period)
+	for i := 1; i < len(stocks); i++ {
+		change := stocks[i].Close - stocks[i-1].Close
+		gain, loss := 0.0, 0.0
+		if change > 0 {
+			gain = change
+		} else {
+			loss = -change
+		}
+		averageGain, ok := gains.Next(gain)
+		averageLoss, _ := losses.Next(loss)
+		if !ok {
+			continue
+		}
+		value := 100.0
+		if averageLoss != 0 {
+			value = 100 - 100/(1+averageGain/averageLoss)
+		} else if averageGain == 0 {
+			value = 50 // No movement at all
+		}
+		points = append(points, model.IndicatorPoint{Date: stocks[i].Date, Value: value})
+	}
+	return points
+}
+
+// MACD returns the difference between the fast and slow EMA, its signal line (EMA of
+// the MACD) and the histogram (MACD minus signal).
+func MACD(stocks []model.Stock, fast, slow, signal int) (macd, signalLine, histogram []model.IndicatorPoint) {
+	macd = []model.IndicatorPoint{}
+	signalLine = []model.IndicatorPoint{}
+	histogram = []model.IndicatorPoint{}
+	if fast <= 0 || slow <= 0 || signal <= 0 {
+		return
+	}
+	fastEMA := newEMAStream(fast)
+	slowEMA := newEMAStream(slow)
+	signalEMA := newEMAStream(signal)
+	for _, stock := range stocks {
+		fastValue, fastOk := fastEMA.Next(stock.Close)
+		slowValue, slowOk := slowEMA.Next(stock.Close)
+		if !fastOk || !slowOk {
+			continue
+		}
+		value := fastValue - slowValue
+		macd = append(macd, model.IndicatorPoint{Date: stock.Date, Value: value})
+		if signalValue, ok := signalEMA.Next(value); ok {
+			signalLine = append(signalLine, model.IndicatorPoint{Date: stock.Date, Value: signalValue})
+			histogram = append(histogram, model.IndicatorPoint{Date: stock.Date, Value: value - signalValue})
+		}
+	}
+	return
+}
+
+// ATR returns the average true range using Wilder's smoothing. The true range of the
+// first day is its high minus its low since there is no previous close.
+func ATR(stocks []model.Stock, period int) []model.IndicatorPoint {
+	points := []model.IndicatorPoint{}
+	if period <= 0 {
+		return points
+	}
+	atr := newWilderStream(period)
+	for i, stock := range stocks {
+		trueRange := stock.High - stock.Low
+		if i > 0 {
+			previousClose := stocks[i-1].Close
+			trueRange = max(trueRange, abs(stock.High-previousClose), abs(stock.Low-previousClose))
+		}
+		if value, ok := atr.Next(trueRange); ok {
+			points = append(points, model.IndicatorPoint{Date: stock.Date, Value: value})
+		}
+	}
+	return points
+}
+
+// VWAP returns the volume weighted average of the typical price ((high+low+close)/3)
+// anchored at the first day received.
+func VWAP(stocks []model.Stock) []model.IndicatorPoint {
+	points := []model.IndicatorPoint{}
+	cumulativePriceVolume := 0.0
+	cumulativeVolume := 0.0
+	for _, stock := range stocks {
+		typicalPrice := (stock.High + stock.Low + stock.Close) / 3
+		cumulativePriceVolume += typicalPrice * float64(stock.Volume)
+		cumulativeVolume += float64(stock.Volume)
+		if cumulativeVolume == 0 {
+			continue // No volume yet, the average is not defined
+		}
+		points = append(points, model.IndicatorPoint{Date: stock.Date, Value: cumulativePriceVolume / cumulativeVolume})
+	}
+	return points
+}
+
+func abs(value float64) float64 {
+	if value < 0 {
+		return -value
+	}
+	return value
+}
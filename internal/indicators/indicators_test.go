@@ -0,0 +1,222 @@
+package indicators
+
+import (
+	"fmt"
+	"math"
+	"stockgame/internal/model"
+	"testing"
+)
+
+// Golden values computed with a naive (non-streaming) implementation of each formula
+var closes = []float64{44.34, 44.09, 44.15, 43.61, 44.33, 44.83, 45.10, 45.42, 45.84, 46.08, 45.89, 46.03, 45.61, 46.28, 46.28, 46.00, 46.03, 46.41, 46.22, 45.64, 46.21, 46.25, 45.71, 46.45, 45.78, 45.35, 44.03, 44.18, 44.22, 44.57}
+
+func goldenStocks() []model.Stock {
+	stocks := []model.Stock{}
+	for i, close := range closes {
+		stocks = append(stocks, model.Stock{
+			Symbol: "TEST",
+			Date:   fmt.Sprintf("2022-01-%02d", i+1),
+			Open:   close,
+			High:   close + 0.5 + float64(i%3)*0.1,
+			Low:    close - 0.4 - float64(i%2)*0.2,
+			Close:  close,
+			Volume: 1000 + 100*(i%5),
+		})
+	}
+	return stocks
+}
+
+func findPoint(points []model.IndicatorPoint, date string) (model.IndicatorPoint, bool) {
+	for _, point := range points {
+		if point.Date == date {
+			return point, true
+		}
+	}
+	return model.IndicatorPoint{}, false
+}
+
+func assertPoint(t *testing.T, points []model.IndicatorPoint, date string, expected float64) {
+	t.Helper()
+	point, found := findPoint(points, date)
+	if !found {
+		t.Errorf("Expected a point for %s", date)
+		return
+	}
+	if !almostEqual(expected, point.Value, 0.000001) {
+		t.Errorf("Expected %f for %s and not %f", expected, date, point.Value)
+	}
+}
+
+func TestSMA(t *testing.T) {
+	points := SMA(goldenStocks(), 5)
+	if len(points) != len(closes)-4 {
+		t.Errorf("Expected %d points and not %d", len(closes)-4, len(points))
+	}
+	if points[0].Date != "2022-01-05" {
+		t.Errorf("Expected the first point to be on the 5th day and not %s", points[0].Date)
+	}
+	assertPoint(t, points, "2022-01-05", 44.104)
+	assertPoint(t, points, "2022-01-30", 44.47)
+}
+
+func TestEMA(t *testing.T) {
+	points := EMA(goldenStocks(), 10)
+	if len(points) != len(closes)-9 {
+		t.Errorf("Expected %d points and not %d", len(closes)-9, len(points))
+	}
+	assertPoint(t, points, "2022-01-10", 44.779)
+	assertPoint(t, points, "2022-01-30", 44.99946089061762)
+}
+
+func TestBollinger(t *testing.T) {
+	t.Run("Population standard deviation", func(t *testing.T) {
+		bands := Bollinger(goldenStocks(), BollingerOptions{Period: 20, K: 2})
+		if len(bands) != 11 {
+			t.Errorf("Expected 11 bands and not %d", len(bands))
+		}
+		if bands[0].Date != "2022-01-20" {
+			t.Errorf("Expected the first band to be on the 20th day and not %s", bands[0].Date)
+		}
+		if !almostEqual(47.115328221650216, bands[0].UpperBand, 0.000001) {
+			t.Errorf("Unexpected upper band %f", bands[0].UpperBand)
+		}
+		if !almostEqual(43.70267177834978, bands[0].LowerBand, 0.000001) {
+			t.Errorf("Unexpected lower band %f", bands[0].LowerBand)
+		}
+	})
+	t.Run("Sample standard deviation with 2.5 multiplier", func(t *testing.T) {
+		bands := Bollinger(goldenStocks(), BollingerOptions{Period: 20, K: 2.5, Sample: true})
+		last := bands[len(bands)-1]
+		if !almostEqual(47.60927768462756, last.UpperBand, 0.000001) {
+			t.Errorf("Unexpected upper band %f", last.UpperBand)
+		}
+		if !almostEqual(43.704722315372436, last.LowerBand, 0.000001) {
+			t.Errorf("Unexpected lower band %f", last.LowerBand)
+		}
+	})
+	t.Run("Excluding the current day", func(t *testing.T) {
+		bands := Bollinger(goldenStocks(), BollingerOptions{Period: 20, K: 2, ExcludeCurrent: true})
+		if len(bands) != 10 {
+			t.Errorf("Expected 10 bands and not %d", len(bands))
+		}
+		// The band of the 21st day uses the same window as the band of the 20th day when the current day is included
+		if bands[0].Date != "2022-01-21" || !almostEqual(47.115328221650216, bands[0].UpperBand, 0.000001) {
+			t.Errorf("Unexpected first band %+v", bands[0])
+		}
+	})
+	t.Run("Not enough data", func(t *testing.T) {
+		bands := Bollinger(goldenStocks()[:5], BollingerOptions{Period: 20, K: 2})
+		if len(bands) != 0 {
+			t.Errorf("Expected no band and not %d", len(bands))
+		}
+	})
+}
+
+func TestRSI(t *testing.T) {
+	points := RSI(goldenStocks(), 14)
+	if len(points) != len(closes)-14 {
+		t.Errorf("Expected %d points and not %d", len(closes)-14, len(points))
+	}
+	assertPoint(t, points, "2022-01-15", 70.46413502109705)
+	assertPoint(t, points, "2022-01-30", 45.499497238680405)
+}
+
+func TestRSIWithoutMovement(t *testing.T) {
+	stocks := []model.Stock{}
+	for i := 0; i < 5; i++ {
+		stocks = append(stocks, model.Stock{Date: fmt.Sprintf("2022-01-%02d", i+1), Close: 10})
+	}
+	points := RSI(stocks, 3)
+	assertPoint(t, points, "2022-01-05", 50)
+}
+
+func TestMACD(t *testing.T) {
+	macd, signal, histogram := MACD(goldenStocks(), 5, 10, 4)
+	if len(macd) != len(closes)-9 {
+		t.Errorf("Expected %d MACD points and not %d", len(closes)-9, len(macd))
+	}
+	if len(signal) != len(macd)-3 || len(histogram) != len(signal) {
+		t.Errorf("Expected %d signal and histogram points and not %d/%d", len(macd)-3, len(signal), len(histogram))
+	}
+	assertPoint(t, macd, "2022-01-10", 0.7105802469135796)
+	assertPoint(t, macd, "2022-01-30", -0.3758210507171782)
+	assertPoint(t, signal, "2022-01-13", 0.5993326172310454)
+	assertPoint(t, signal, "2022-01-30", -0.3434125495074326)
+	assertPoint(t, histogram, "2022-01-30", -0.03240850120974559)
+}
+
+func TestATR(t *testing.T) {
+	points := ATR(goldenStocks(), 14)
+	if len(points) != len(closes)-13 {
+		t.Errorf("Expected %d points and not %d", len(closes)-13, len(points))
+	}
+	assertPoint(t, points, "2022-01-14", 1.1250000000000016)
+	assertPoint(t, points, "2022-01-30", 1.172355883997415)
+}
+
+func TestVWAP(t *testing.T) {
+	points := VWAP(goldenStocks())
+	if len(points) != len(closes) {
+		t.Errorf("Expected %d points and not %d", len(closes), len(points))
+	}
+	assertPoint(t, points, "2022-01-01", 44.373333333333335)
+	assertPoint(t, points, "2022-01-30", 45.401027777777784)
+}
+
+func TestParseSpec(t *testing.T) {
+	t.Run("Default parameters", func(t *testing.T) {
+		spec, err := ParseSpec("MACD")
+		if err != nil {
+			t.Errorf("Expected no error and not %v", err)
+		}
+		if spec.Name != "macd" || len(spec.Params) != 3 || spec.Params[1] != 26 {
+			t.Errorf("Unexpected spec %+v", spec)
+		}
+	})
+	t.Run("Bollinger with sample flag", func(t *testing.T) {
+		spec, err := ParseSpec("bollinger:10:2.5:sample")
+		if err != nil {
+			t.Errorf("Expected no error and not %v", err)
+		}
+		if !spec.Sample || spec.Params[0] != 10 || spec.Params[1] != 2.5 {
+			t.Errorf("Unexpected spec %+v", spec)
+		}
+	})
+	t.Run("Invalid specs", func(t *testing.T) {
+		for _, text := range []string{"unknown", "sma:abc", "sma:0", "sma:20:3", "rsi:2.5", "sma:1000"} {
+			if _, err := ParseSpec(text); err == nil {
+				t.Errorf("Expected an error for %s", text)
+			}
+		}
+	})
+}
+
+func TestGetOverlays(t *testing.T) {
+	logic := &IndicatorLogicImpl{}
+	t.Run("Overlays keyed by spec", func(t *testing.T) {
+		overlays, err := logic.GetOverlays(goldenStocks(), []string{"sma:5", "bollinger", "macd:5:10:4"})
+		if err != nil {
+			t.Errorf("Expected no error and not %v", err)
+		}
+		if len(overlays) != 3 {
+			t.Errorf("Expected 3 overlays and not %d", len(overlays))
+		}
+		if len(overlays["bollinger"].Lines) != 3 {
+			t.Errorf("Expected 3 Bollinger lines and not %d", len(overlays["bollinger"].Lines))
+		}
+		assertPoint(t, overlays["sma:5"].Lines["sma"], "2022-01-30", 44.47)
+	})
+	t.Run("Too many indicators", func(t *testing.T) {
+		specs := make([]string, MaxIndicatorsPerRequest+1)
+		for i := range specs {
+			specs[i] = "sma"
+		}
+		if _, err := logic.GetOverlays(goldenStocks(), specs); err == nil {
+			t.Errorf("Expected an error when requesting too many indicators")
+		}
+	})
+}
+
+func almostEqual(a, b, tolerance float64) bool {
+	return math.Abs(a-b) <= tolerance
+}
@@ -0,0 +1,135 @@
+package indicators
+
+import (
+	"fmt"
+	"stockgame/internal/model"
+	"strconv"
+	"strings"
+)
+
+// Maximum number of indicators that can be requested at once
+const MaxIndicatorsPerRequest = 10
+
+// Maximum period accepted for any indicator parameter
+const maxPeriod = 200
+
+type IndicatorLogic interface {
+	GetOverlays(stocks []model.Stock, specs []string) (map[string]model.IndicatorOverlay, error)
+}
+
+type IndicatorLogicImpl struct {
+	IndicatorLogic
+}
+
+// Spec is a parsed indicator request. The text form is the name followed by its
+// parameters separated by colons, e.g. "sma:20", "bollinger:20:2:sample" or "macd:12:26:9".
+type Spec struct {
+	Name   string
+	Params []float64
+	Sample bool
+}
+
+// Default parameters used when the spec only contains the indicator name
+var defaultParams = map[string][]float64{
+	"sma":       {20},
+	"ema":       {20},
+	"bollinger": {20, 2},
+	"rsi":       {14},
+	"macd":      {12, 26, 9},
+	"atr":       {14},
+	"vwap":      {},
+}
+
+func ParseSpec(text string) (Spec, error) {
+	parts := strings.Split(strings.ToLower(strings.TrimSpace(text)), ":")
+	spec := Spec{Name: parts[0]}
+	defaults, found := defaultParams[spec.Name]
+	if !found {
+		return spec, fmt.Errorf("unknown indicator %q", text)
+	}
+	params := parts[1:]
+	if spec.Name == "bollinger" && len(params) > 0 && params[len(params)-1] == "sample" {
+		spec.Sample = true
+		params = params[:len(params)-1]
+	}
+	if len(params) > len(defaults) {
+		return spec, fmt.Errorf("too many parameters for indicator %q", text)
+	}
+	spec.Params = append([]float64{}, defaults...)
+	for i, param := range params {
+		value, err := strconv.ParseFloat(param, 64)
+		if err != nil || value <= 0 || value > maxPeriod {
+			return spec, fmt.Errorf("invalid parameter %q for indicator %q", param, text)
+		}
+		spec.Params[i] = value
+	}
+	// Every parameter is a period except the Bollinger multiplier
+	for i, value := range spec.Params {
+		if spec.Name == "bollinger" && i == 1 {
+			continue
+		}
+		if value != float64(int(value)) {
+			return spec, fmt.Errorf("period must be an integer for indicator %q", text)
+		}
+	}
+	return spec, nil
+}
+
+// GetOverlays computes every requested indicator on the stocks (sorted by date
+// ascending). The overlays are keyed by the spec text as received.
+func (l *IndicatorLogicImpl) GetOverlays(stocks []model.Stock, specs []string) (map[string]model.IndicatorOverlay, error) {
+	if len(specs) > MaxIndicatorsPerRequest {
+		return nil, fmt.Errorf("cannot request more than %d indicators", MaxIndicatorsPerRequest)
+	}
+	overlays := make(map[string]model.IndicatorOverlay)
+	for _, text := range specs {
+		spec, err := ParseSpec(text)
+		if err != nil {
+			return nil, err
+		}
+		overlays[text] = Compute(stocks, spec)
+	}
+	return overlays, nil
+}
+
+// Compute runs the indicator described by the spec
+func Compute(stocks []model.Stock, spec Spec) model.IndicatorOverlay {
+	overlay := model.IndicatorOverlay{
+		Name:  spec.Name,
+		Lines: make(map[string][]model.IndicatorPoint),
+	}
+	period := func(i int) int {
+		return int(spec.Params[i])
+	}
+	switch spec.Name {
+	case "sma":
+		overlay.Lines["sma"] = SMA(stocks, period(0))
+	case "ema":
+		overlay.Lines["ema"] = EMA(stocks, period(0))
+	case "bollinger":
+		bands := Bollinger(stocks, BollingerOptions{Period: period(0), K: spec.Params[1], Sample: spec.Sample})
+		upper := make([]model.IndicatorPoint, len(bands))
+		average := make([]model.IndicatorPoint, len(bands))
+		lower := make([]model.IndicatorPoint, len(bands))
+		for i, band := range bands {
+			upper[i] = model.IndicatorPoint{Date: band.Date, Value: band.UpperBand}
+			average[i] = model.IndicatorPoint{Date: band.Date, Value: band.Average}
+			lower[i] = model.IndicatorPoint{Date: band.Date, Value: band.LowerBand}
+		}
+		overlay.Lines["upper"] = upper
+		overlay.Lines["average"] = average
+		overlay.Lines["lower"] = lower
+	case "rsi":
+		overlay.Lines["rsi"] = RSI(stocks, period(0))
+	case "macd":
+		macd, signal, histogram := MACD(stocks, period(0), period(1), period(2))
+		overlay.Lines["macd"] = macd
+		overlay.Lines["signal"] = signal
+		overlay.Lines["histogram"] = histogram
+	case "atr":
+		overlay.Lines["atr"] = ATR(stocks, period(0))
+	case "vwap":
+		overlay.Lines["vwap"] = VWAP(stocks)
+	}
+	return overlay
+}
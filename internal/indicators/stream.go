@@ -0,0 +1,122 @@
+package indicators
+
+import "math"
+
+// rollingWindow keeps the last `size` values with their running mean and sum of
+// squared differences (Welford) so every update is O(1).
+type rollingWindow struct {
+	size   int
+	values []float64
+	next   int
+	count  int
+	mean   float64
+	m2     float64
+}
+
+func newRollingWindow(size int) *rollingWindow {
+	return &rollingWindow{size: size, values: make([]float64, size)}
+}
+
+// Add pushes a value and drops the oldest one when the window is full
+func (w *rollingWindow) Add(value float64) {
+	if w.count == w.size {
+		old := w.values[w.next]
+		w.count--
+		if w.count == 0 {
+			w.mean = 0
+			w.m2 = 0
+		} else {
+			delta := old - w.mean
+			w.mean -= delta / float64(w.count)
+			w.m2 -= delta * (old - w.mean)
+		}
+	}
+	w.values[w.next] = value
+	w.next = (w.next + 1) % w.size
+	w.count++
+	delta := value - w.mean
+	w.mean += delta / float64(w.count)
+	w.m2 += delta * (value - w.mean)
+}
+
+func (w *rollingWindow) Full() bool {
+	return w.count == w.size
+}
+
+func (w *rollingWindow) Mean() float64 {
+	return w.mean
+}
+
+// StdDev returns the population (divided by n) or sample (divided by n-1) standard deviation
+func (w *rollingWindow) StdDev(sample bool) float64 {
+	divider := float64(w.count)
+	if sample {
+		divider--
+	}
+	if divider <= 0 || w.m2 <= 0 {
+		return 0 // m2 can drift slightly below zero with floating point errors
+	}
+	return math.Sqrt(w.m2 / divider)
+}
+
+// emaStream is an exponential moving average seeded with the simple average of the
+// first `period` values.
+type emaStream struct {
+	period int
+	alpha  float64
+	count  int
+	sum    float64
+	value  float64
+}
+
+func newEMAStream(period int) *emaStream {
+	return &emaStream{period: period, alpha: 2 / float64(period+1)}
+}
+
+// Next adds a value and returns the EMA once enough values were received
+func (e *emaStream) Next(value float64) (float64, bool) {
+	e.count++
+	if e.count < e.period {
+		e.sum += value
+		return 0, false
+	}
+	if e.count == e.period {
+		e.sum += value
+		e.value = e.sum / float64(e.period)
+		return e.value, true
+	}
+	e.value = alphaSmoothing(e.value, value, e.alpha)
+	return e.value, true
+}
+
+// wilderStream is the smoothing used by RSI and ATR: seeded with the simple average of
+// the first `period` values then alpha is 1/period.
+type wilderStream struct {
+	period int
+	count  int
+	sum    float64
+	value  float64
+}
+
+func newWilderStream(period int) *wilderStream {
+	return &wilderStream{period: period}
+}
+
+func (e *wilderStream) Next(value float64) (float64, bool) {
+	e.count++
+	if e.count < e.period {
+		e.sum += value
+		return 0, false
+	}
+	if e.count == e.period {
+		e.sum += value
+		e.value = e.sum / float64(e.period)
+		return e.value, true
+	}
+	e.value = alphaSmoothing(e.value, value, 1/float64(e.period))
+	return e.value, true
+}
+
+func alphaSmoothing(previous, value, alpha float64) float64 {
+	return previous + alpha*(value-previous)
+}
@@ -0,0 +1,73 @@
+package logic
+
+import (
+	"math"
+	"stockgame/internal/model"
+)
+
+type BandScoringLogic interface {
+	GetBandScore(userBands []model.DayBand, actualStockInfo []model.Stock) model.UserBandScoreResponse
+}
+
+type BandScoringLogicImpl struct {
+	BandScoringLogic
+}
+
+// Maximum points for a day, given to a band of zero width containing the close
+const bandPoints = 10
+
+// Interval score (relative to the close) at which a day gives zero points, a wider or more
+// distant band gives negative points so the score stays proper
+const bandZeroPointIntervalScore = 0.2
+
+// GetBandScore uses the interval score, a proper scoring rule: the width of the band is
+// penalized and a close outside of the band is penalized by 2/alpha times the distance to
+// the band (alpha = 1 - coverage). Being honest about the uncertainty gives the best
+// expected score: a band too narrow misses often and a band too wide is always penalized.
+func (h *BandScoringLogicImpl) GetBandScore(userBands []model.DayBand, actualStockInfo []model.Stock) model.UserBandScoreResponse {
+	scoreObj := model.UserBandScoreResponse{
+		Total:         0,
+		InBand:        0,
+		Coverage:      0,
+		IntervalScore: 0,
+	}
+	alpha := 1 - float64(model.Band_coverage_percent)/100
+	days := 0
+	sumIntervalScore := 0.0
+	for i := range userBands {
+		if i >= len(actualStockInfo) { // In case
+			break
+		}
+		actualClose := actualStockInfo[i].Close
+		if actualClose <= 0 {
+			continue
+		}
+		lower := math.Min(userBands[i].Lower, userBands[i].Upper)
+		upper := math.Max(userBands[i].Lower, userBands[i].Upper)
+		intervalScore := IntervalScore(lower, upper, actualClose, alpha) / actualClose
+		if actualClose >= lower && actualClose <= upper {
+			scoreObj.InBand++
+		}
+		scoreObj.Total += int(math.Round(bandPoints * (1 - intervalScore/bandZeroPointIntervalScore)))
+		sumIntervalScore += intervalScore
+		days++
+	}
+	if days > 0 {
+		scoreObj.Coverage = float64(scoreObj.InBand) / float64(days)
+		scoreObj.IntervalScore = sumIntervalScore / float64(days)
+	}
+	return scoreObj
+}
+
+// IntervalScore is the Gneiting and Raftery interval score of the central (1-alpha)
+// prediction interval [lower, upper] for the observed value
+func IntervalScore(lower, upper, observed, alpha float64) float64 {
+	score := upper - lower
+	if observed < lower {
+		score += 2 / alpha * (lower - observed)
+	}
+	if observed > upper {
+		score += 2 / alpha * (observed - upper)
+	}
+	return score
+}
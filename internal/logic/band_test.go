@@ -0,0 +1,67 @@
+package logic
+
+import (
+	"stockgame/internal/model"
+	"testing"
+)
+
+func TestIntervalScore(t *testing.T) {
+	if !almostEqual(2, IntervalScore(99, 101, 100, 0.2), 0.0001) {
+		t.Errorf("Expected the width when the value is inside the interval")
+	}
+	if !almostEqual(12, IntervalScore(99, 101, 102, 0.2), 0.0001) {
+		t.Errorf("Expected the width plus 2/alpha times the distance above the interval")
+	}
+	if !almostEqual(12, IntervalScore(99, 101, 98, 0.2), 0.0001) {
+		t.Errorf("Expected the width plus 2/alpha times the distance below the interval")
+	}
+}
+
+func TestGetBandScore(t *testing.T) {
+	actualStockInfo := []model.Stock{
+		{Date: "2022-01-01", Close: 100},
+		{Date: "2022-01-02", Close: 100},
+		{Date: "2022-01-03", Close: 100},
+	}
+	t.Run("Calibrated bands", func(t *testing.T) {
+		mockService := &BandScoringLogicImpl{}
+		userBands := []model.DayBand{
+			{Day: 41, Lower: 99, Upper: 101},  // Interval score 2% -> 9 points
+			{Day: 42, Lower: 101, Upper: 99},  // Inverted, same band
+			{Day: 43, Lower: 101, Upper: 103}, // Missed by 1%: 2% + 10% -> 4 points
+		}
+		score := mockService.GetBandScore(userBands, actualStockInfo)
+		if score.Total != 22 {
+			t.Errorf("Expected Total to be 22 and not %d", score.Total)
+		}
+		if score.InBand != 2 {
+			t.Errorf("Expected InBand to be 2 and not %d", score.InBand)
+		}
+		if !almostEqual(2.0/3.0, score.Coverage, 0.0001) {
+			t.Errorf("Expected Coverage to be 0.667 and not %f", score.Coverage)
+		}
+		if !almostEqual(0.16/3, score.IntervalScore, 0.0001) {
+			t.Errorf("Expected IntervalScore to be 0.053 and not %f", score.IntervalScore)
+		}
+	})
+	t.Run("Band too wide", func(t *testing.T) {
+		mockService := &BandScoringLogicImpl{}
+		userBands := []model.DayBand{
+			{Day: 41, Lower: 50, Upper: 150}, // Interval score 100% -> -40 points
+		}
+		score := mockService.GetBandScore(userBands, actualStockInfo)
+		if score.Total != -40 {
+			t.Errorf("Expected Total to be -40 and not %d", score.Total)
+		}
+		if score.InBand != 1 {
+			t.Errorf("Expected InBand to be 1 and not %d", score.InBand)
+		}
+	})
+	t.Run("No bands", func(t *testing.T) {
+		mockService := &BandScoringLogicImpl{}
+		score := mockService.GetBandScore([]model.DayBand{}, actualStockInfo)
+		if score.Total != 0 || score.Coverage != 0 {
+			t.Errorf("Expected an empty score and not %+v", score)
+		}
+	})
+}
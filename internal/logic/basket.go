@@ -0,0 +1,151 @@
+package logic
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"stockgame/internal/model"
+)
+
+var ErrInvalidBasket = errors.New("invalid basket solution")
+
+// GetBasketScore scores the ranking or the weights of the request against the realized
+// returns of the symbols, keyed by symbol UUID
+func GetBasketScore(request model.BasketSolutionRequest, returns map[string]float64) (model.BasketScore, error) {
+	if err := ValidateBasketSolution(request); err != nil {
+		return model.BasketScore{}, err
+	}
+	symbols := BasketSymbols(request)
+	realized := make([]float64, len(symbols))
+	for i, symbol := range symbols {
+		value, ok := returns[symbol]
+		if !ok {
+			return model.BasketScore{}, fmt.Errorf("%w: no return for %s", ErrInvalidBasket, symbol)
+		}
+		realized[i] = value
+	}
+	score := model.BasketScore{
+		EqualWeightReturn: roundTo(mean(realized), 2),
+		BestReturn:        roundTo(slices.Max(realized), 2),
+		WorstReturn:       roundTo(slices.Min(realized), 2),
+	}
+	if len(request.Ranking) > 0 {
+		score.Method = model.Basket_method_rank
+		// The first symbol of the ranking is expected to have the highest return
+		predicted := make([]float64, len(symbols))
+		for i := range symbols {
+			predicted[i] = float64(len(symbols) - i)
+		}
+		score.Total = 50 // Every ranking is as good when the returns are the same
+		if correlation, ok := SpearmanCorrelation(predicted, realized); ok {
+			correlation = roundTo(correlation, 4)
+			score.Spearman = &correlation
+			score.Total = int(math.Round((correlation + 1) / 2 * 100))
+		}
+		return score, nil
+	}
+	score.Method = model.Basket_method_weights
+	sum, portfolio := 0.0, 0.0
+	for i, weight := range request.Weights {
+		sum += weight.Weight
+		portfolio += weight.Weight * realized[i]
+	}
+	portfolio /= sum
+	rounded := roundTo(portfolio, 2)
+	score.PortfolioReturn = &rounded
+	score.Total = 100
+	if best, worst := slices.Max(realized), slices.Min(realized); best > worst {
+		score.Total = int(math.Round((portfolio - worst) / (best - worst) * 100))
+	}
+	return score, nil
+}
+
+// BasketSymbols returns the symbols of the ranking or of the weights of the request
+func BasketSymbols(request model.BasketSolutionRequest) []string {
+	symbols := slices.Clone(request.Ranking)
+	for _, weight := range request.Weights {
+		symbols = append(symbols, weight.SymbolUUID)
+	}
+	return symbols
+}
+
+// ValidateBasketSolution checks the request can be scored before the returns are known
+func ValidateBasketSolution(request model.BasketSolutionRequest) error {
+	if (len(request.Ranking) == 0) == (len(request.Weights) == 0) {
+		return fmt.Errorf("%w: send either a ranking or weights", ErrInvalidBasket)
+	}
+	if err := ValidateBasketSymbols(BasketSymbols(request)); err != nil {
+		return err
+	}
+	sum := 0.0
+	for _, weight := range request.Weights {
+		if weight.Weight < 0 || math.IsNaN(weight.Weight) || math.IsInf(weight.Weight, 0) {
+			return fmt.Errorf("%w: the weights cannot be negative", ErrInvalidBasket)
+		}
+		sum += weight.Weight
+	}
+	if len(request.Weights) > 0 && sum == 0 {
+		return fmt.Errorf("%w: at least one weight must be positive", ErrInvalidBasket)
+	}
+	return nil
+}
+
+// ValidateBasketSymbols checks the number of symbols of a basket and that they are distinct
+func ValidateBasketSymbols(symbols []string) error {
+	if len(symbols) < model.Basket_min_symbols || len(symbols) > model.Basket_max_symbols {
+		return fmt.Errorf("%w: a basket has %d to %d symbols", ErrInvalidBasket, model.Basket_min_symbols, model.Basket_max_symbols)
+	}
+	seen := map[string]bool{}
+	for _, symbol := range symbols {
+		if symbol == "" || seen[symbol] {
+			return fmt.Errorf("%w: the symbols must be distinct", ErrInvalidBasket)
+		}
+		seen[symbol] = true
+	}
+	return nil
+}
+
+// SpearmanCorrelation is the Pearson correlation of the ranks of the values, the ties get
+// their average rank. It returns false when the values of one side are all the same.
+func SpearmanCorrelation(x []float64, y []float64) (float64, bool) {
+	if len(x) != len(y) || len(x) < 2 {
+		return 0, false
+	}
+	rankX, rankY := averageRanks(x), averageRanks(y)
+	meanX, meanY := mean(rankX), mean(rankY)
+	covariance, varianceX, varianceY := 0.0, 0.0, 0.0
+	for i := range rankX {
+		dx, dy := rankX[i]-meanX, rankY[i]-meanY
+		covariance += dx * dy
+		varianceX += dx * dx
+		varianceY += dy * dy
+	}
+	if varianceX == 0 || varianceY == 0 {
+		return 0, false
+	}
+	return covariance / math.Sqrt(varianceX*varianceY), true
+}
+
+// averageRanks ranks the values from 1 for the lowest
+func averageRanks(values []float64) []float64 {
+	order := make([]int, len(values))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool { return values[order[i]] < values[order[j]] })
+	ranks := make([]float64, len(values))
+	for start := 0; start < len(order); {
+		end := start
+		for end+1 < len(order) && values[order[end+1]] == values[order[start]] {
+			end++
+		}
+		rank := float64(start+end)/2 + 1
+		for k := start; k <= end; k++ {
+			ranks[order[k]] = rank
+		}
+		start = end + 1
+	}
+	return ranks
+}
@@ -0,0 +1,69 @@
+package logic
+
+import (
+	"errors"
+	"math"
+	"stockgame/internal/model"
+	"testing"
+)
+
+func TestSpearmanCorrelation(t *testing.T) {
+	cases := []struct {
+		name     string
+		x, y     []float64
+		expected float64
+		ok       bool
+	}{
+		{"Same order", []float64{1, 2, 3}, []float64{10, 20, 30}, 1, true},
+		{"Reversed", []float64{1, 2, 3, 4}, []float64{4, 3, 2, 1}, -1, true},
+		{"Monotonic but not linear", []float64{1, 2, 3}, []float64{1, 10, 1000}, 1, true},
+		{"Ties", []float64{1, 2, 3}, []float64{1, 1, 2}, 0.8660, true},
+		{"Constant", []float64{1, 2, 3}, []float64{5, 5, 5}, 0, false},
+	}
+	for _, c := range cases {
+		correlation, ok := SpearmanCorrelation(c.x, c.y)
+		if ok != c.ok || math.Abs(correlation-c.expected) > 1e-4 {
+			t.Errorf("%s: expected %v %v and not %v %v", c.name, c.expected, c.ok, correlation, ok)
+		}
+	}
+}
+
+func TestGetBasketScore(t *testing.T) {
+	returns := map[string]float64{"a": 10, "b": 0, "c": -5}
+	t.Run("Perfect ranking", func(t *testing.T) {
+		score, err := GetBasketScore(model.BasketSolutionRequest{Ranking: []string{"a", "b", "c"}}, returns)
+		if err != nil || score.Total != 100 || score.Method != model.Basket_method_rank || *score.Spearman != 1 {
+			t.Errorf("Expected a perfect score and not %+v %v", score, err)
+		}
+	})
+	t.Run("Reversed ranking", func(t *testing.T) {
+		score, _ := GetBasketScore(model.BasketSolutionRequest{Ranking: []string{"c", "b", "a"}}, returns)
+		if score.Total != 0 {
+			t.Errorf("Expected 0 and not %+v", score)
+		}
+	})
+	t.Run("Weights", func(t *testing.T) {
+		request := model.BasketSolutionRequest{Weights: []model.BasketWeight{{SymbolUUID: "a", Weight: 1}, {SymbolUUID: "b", Weight: 1}, {SymbolUUID: "c", Weight: 0}}}
+		score, err := GetBasketScore(request, returns)
+		if err != nil || *score.PortfolioReturn != 5 || score.Total != 67 {
+			t.Errorf("Expected a return of 5%% scored 67 and not %+v %v", score, err)
+		}
+		if score.EqualWeightReturn != 1.67 || score.BestReturn != 10 || score.WorstReturn != -5 {
+			t.Errorf("Unexpected references %+v", score)
+		}
+	})
+	invalids := map[string]model.BasketSolutionRequest{
+		"nothing":         {},
+		"both":            {Ranking: []string{"a", "b", "c"}, Weights: []model.BasketWeight{{SymbolUUID: "a", Weight: 1}, {SymbolUUID: "b", Weight: 1}, {SymbolUUID: "c", Weight: 1}}},
+		"too few":         {Ranking: []string{"a", "b"}},
+		"duplicate":       {Ranking: []string{"a", "a", "b"}},
+		"unknown":         {Ranking: []string{"a", "b", "d"}},
+		"negative weight": {Weights: []model.BasketWeight{{SymbolUUID: "a", Weight: -1}, {SymbolUUID: "b", Weight: 1}, {SymbolUUID: "c", Weight: 1}}},
+		"no weight":       {Weights: []model.BasketWeight{{SymbolUUID: "a", Weight: 0}, {SymbolUUID: "b", Weight: 0}, {SymbolUUID: "c", Weight: 0}}},
+	}
+	for name, request := range invalids {
+		if _, err := GetBasketScore(request, returns); !errors.Is(err, ErrInvalidBasket) {
+			t.Errorf("%s: expected ErrInvalidBasket and not %v", name, err)
+		}
+	}
+}
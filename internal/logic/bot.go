@@ -0,0 +1,143 @@
+package logic
+
+import (
+	"hash/fnv"
+	"math"
+	"math/rand/v2"
+	"stockgame/internal/model"
+)
+
+type BotLogic interface {
+	GetBotForecasts(history []model.Stock, numberOfDays int) []model.BotForecast
+}
+
+type BotLogicImpl struct {
+	BotLogic
+}
+
+// Names of the baseline bots
+const (
+	BotLastCloseFlat = "lastCloseFlat"
+	BotRandomWalk    = "randomWalk"
+	BotLinearTrend   = "linearTrend"
+	BotMeanReversion = "meanReversionSMA20"
+)
+
+// Number of days of the moving average the mean reversion bot goes back to
+const botMeanReversionPeriod = 20
+
+// Ratio of the distance to the moving average the mean reversion bot covers each day
+const botMeanReversionSpeed = 0.3
+
+// GetBotForecasts returns what naive forecasters would have drawn after seeing the same
+// history (sorted by date ascending). The random walk is seeded with the last day of the
+// history so the same window always gets the same forecast.
+func (b *BotLogicImpl) GetBotForecasts(history []model.Stock, numberOfDays int) []model.BotForecast {
+	if len(history) == 0 || numberOfDays <= 0 {
+		return []model.BotForecast{}
+	}
+	lastClose := history[len(history)-1].Close
+	return []model.BotForecast{
+		{Name: BotLastCloseFlat, Prices: botPrices(numberOfDays, func(day int) float64 {
+			return lastClose
+		})},
+		{Name: BotRandomWalk, Prices: randomWalkPrices(history, numberOfDays)},
+		{Name: BotLinearTrend, Prices: linearTrendPrices(history, numberOfDays)},
+		{Name: BotMeanReversion, Prices: meanReversionPrices(history, numberOfDays)},
+	}
+}
+
+func botPrices(numberOfDays int, priceForDay func(day int) float64) []model.DayPrice {
+	prices := make([]model.DayPrice, numberOfDays)
+	for i := range prices {
+		prices[i] = model.DayPrice{Day: model.Number_initial_stock_shown + i, Price: priceForDay(i)}
+	}
+	return prices
+}
+
+// randomWalkPrices draws daily log returns with the volatility of the history
+func randomWalkPrices(history []model.Stock, numberOfDays int) []model.DayPrice {
+	returns := []float64{}
+	for i := 1; i < len(history); i++ {
+		if history[i-1].Close > 0 && history[i].Close > 0 {
+			returns = append(returns, math.Log(history[i].Close/history[i-1].Close))
+		}
+	}
+	volatility := standardDeviation(returns)
+
+	last := history[len(history)-1]
+	hash := fnv.New64a()
+	hash.Write([]byte(last.Symbol + last.Date))
+	random := rand.New(rand.NewPCG(hash.Sum64(), uint64(len(history))))
+
+	price := last.Close
+	return botPrices(numberOfDays, func(day int) float64 {
+		price *= math.Exp(random.NormFloat64() * volatility)
+		return price
+	})
+}
+
+// linearTrendPrices extends the least squares line of the history closes
+func linearTrendPrices(history []model.Stock, numberOfDays int) []model.DayPrice {
+	n := float64(len(history))
+	sumX, sumY, sumXY, sumXX := 0.0, 0.0, 0.0, 0.0
+	for i, stock := range history {
+		x := float64(i)
+		sumX += x
+		sumY += stock.Close
+		sumXY += x * stock.Close
+		sumXX += x * x
+	}
+	slope := 0.0
+	if denominator := n*sumXX - sumX*sumX; denominator != 0 {
+		slope = (n*sumXY - sumX*sumY) / denominator
+	}
+	intercept := (sumY - slope*sumX) / n
+	return botPrices(numberOfDays, func(day int) float64 {
+		return math.Max(0, intercept+slope*(n+float64(day)))
+	})
+}
+
+// meanReversionPrices moves from the last close toward the moving average of the history
+func meanReversionPrices(history []model.Stock, numberOfDays int) []model.DayPrice {
+	window := history[max(0, len(history)-botMeanReversionPeriod):]
+	average := 0.0
+	for _, stock := range window {
+		average += stock.Close
+	}
+	average /= float64(len(window))
+
+	price := history[len(history)-1].Close
+	return botPrices(numberOfDays, func(day int) float64 {
+		price += botMeanReversionSpeed * (average - price)
+		return price
+	})
+}
+
+func standardDeviation(values []float64) float64 {
+	if len(values) < 2 {
+		return 0
+	}
+	mean := 0.0
+	for _, value := range values {
+		mean += value
+	}
+	mean /= float64(len(values))
+	sumSquares := 0.0
+	for _, value := range values {
+		sumSquares += (value - mean) * (value - mean)
+	}
+	return math.Sqrt(sumSquares / float64(len(values)-1))
+}
+
+// GetBotSummary counts the bots with a total score strictly lower than the player. The
+// score of the player is taken before the hint penalty and the speed bonus.
+func GetBotSummary(playerScore model.UserScoreResponse, bots []model.BotScore) model.BotSummary {
+	summary := model.BotSummary{Beaten: 0, Total: len(bots)}
+	for _, bot := range bots {
+		if playerScore.Total > bot.Score.Total {
+			summary.Beaten++
+		}
+	}
+	return summary
+}
@@ -0,0 +1,97 @@
+package logic
+
+import (
+	"fmt"
+	"stockgame/internal/model"
+	"testing"
+)
+
+func botHistory(closes ...float64) []model.Stock {
+	history := []model.Stock{}
+	for i, close := range closes {
+		history = append(history, model.Stock{Symbol: "AAPL", Date: fmt.Sprintf("2022-01-%02d", i+1), Close: close})
+	}
+	return history
+}
+
+func findBot(forecasts []model.BotForecast, name string) model.BotForecast {
+	for _, forecast := range forecasts {
+		if forecast.Name == name {
+			return forecast
+		}
+	}
+	return model.BotForecast{}
+}
+
+func TestGetBotForecasts(t *testing.T) {
+	mockService := &BotLogicImpl{}
+	t.Run("Every bot forecasts every day", func(t *testing.T) {
+		forecasts := mockService.GetBotForecasts(botHistory(10, 11, 12, 13), 3)
+		if len(forecasts) != 4 {
+			t.Errorf("Expected 4 bots and not %d", len(forecasts))
+		}
+		for _, forecast := range forecasts {
+			if len(forecast.Prices) != 3 {
+				t.Errorf("Expected 3 prices for %s and not %d", forecast.Name, len(forecast.Prices))
+			}
+		}
+	})
+	t.Run("Last close flat", func(t *testing.T) {
+		forecast := findBot(mockService.GetBotForecasts(botHistory(10, 11, 12, 13), 3), BotLastCloseFlat)
+		for _, price := range forecast.Prices {
+			if price.Price != 13 {
+				t.Errorf("Expected 13 and not %f", price.Price)
+			}
+		}
+	})
+	t.Run("Linear trend", func(t *testing.T) {
+		forecast := findBot(mockService.GetBotForecasts(botHistory(10, 11, 12, 13), 3), BotLinearTrend)
+		for i, expected := range []float64{14, 15, 16} {
+			if !almostEqual(expected, forecast.Prices[i].Price, 0.0001) {
+				t.Errorf("Expected %f and not %f", expected, forecast.Prices[i].Price)
+			}
+		}
+	})
+	t.Run("Mean reversion", func(t *testing.T) {
+		forecast := findBot(mockService.GetBotForecasts(botHistory(10, 10, 10, 14), 2), BotMeanReversion)
+		// The average is 11: 14 - 0.3*3 = 13.1 then 13.1 - 0.3*2.1 = 12.47
+		if !almostEqual(13.1, forecast.Prices[0].Price, 0.0001) || !almostEqual(12.47, forecast.Prices[1].Price, 0.0001) {
+			t.Errorf("Unexpected prices %v", forecast.Prices)
+		}
+	})
+	t.Run("Random walk is deterministic", func(t *testing.T) {
+		history := botHistory(10, 11, 10, 12, 11)
+		first := findBot(mockService.GetBotForecasts(history, 5), BotRandomWalk)
+		second := findBot(mockService.GetBotForecasts(history, 5), BotRandomWalk)
+		for i := range first.Prices {
+			if first.Prices[i].Price != second.Prices[i].Price {
+				t.Errorf("Expected the same random walk for the same window")
+			}
+		}
+	})
+	t.Run("Random walk without volatility stays flat", func(t *testing.T) {
+		forecast := findBot(mockService.GetBotForecasts(botHistory(10, 10, 10), 2), BotRandomWalk)
+		for _, price := range forecast.Prices {
+			if price.Price != 10 {
+				t.Errorf("Expected 10 and not %f", price.Price)
+			}
+		}
+	})
+	t.Run("No history", func(t *testing.T) {
+		if len(mockService.GetBotForecasts([]model.Stock{}, 3)) != 0 {
+			t.Errorf("Expected no bot without history")
+		}
+	})
+}
+
+func TestGetBotSummary(t *testing.T) {
+	bots := []model.BotScore{
+		{Name: BotLastCloseFlat, Score: model.UserScoreResponse{Total: 10}},
+		{Name: BotRandomWalk, Score: model.UserScoreResponse{Total: 20}},
+		{Name: BotLinearTrend, Score: model.UserScoreResponse{Total: 30}},
+	}
+	summary := GetBotSummary(model.UserScoreResponse{Total: 20}, bots)
+	if summary.Beaten != 1 || summary.Total != 3 {
+		t.Errorf("Expected to beat 1 of 3 bots and not %d of %d", summary.Beaten, summary.Total)
+	}
+}
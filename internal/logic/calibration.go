@@ -0,0 +1,107 @@
+package logic
+
+import (
+	"fmt"
+	"sort"
+	"stockgame/internal/model"
+)
+
+// Upper bounds, in percent, of the predicted moves of the calibration bins. The last bin
+// has no upper bound.
+var calibrationBinEdges = []float64{-10, -5, -2, 0, 2, 5, 10}
+
+// GetCalibration measures the bias and the dispersion of the guesses of the rounds that
+// recorded their actual closes. The moves are measured from the last close shown so the
+// days far in the future count as much as the first day.
+func GetCalibration(rounds []model.Round) model.Calibration {
+	calibration := model.Calibration{}
+	var errors, guessReturns, actualReturns []float64
+	binPredicted := make([][]float64, len(calibrationBinEdges)+1)
+	binActual := make([][]float64, len(calibrationBinEdges)+1)
+	for _, round := range rounds {
+		if round.LastClose == 0 {
+			continue
+		}
+		days := 0
+		previousGuess := round.LastClose
+		previousActual := round.LastClose
+		for i, guess := range round.DayPrice {
+			if i >= len(round.Actual) || round.Actual[i] == 0 || guess.Price <= 0 {
+				break
+			}
+			actual := round.Actual[i]
+			errors = append(errors, (guess.Price-actual)/actual*100)
+			guessReturns = append(guessReturns, (guess.Price/previousGuess-1)*100)
+			actualReturns = append(actualReturns, (actual/previousActual-1)*100)
+			predictedMove := (guess.Price/round.LastClose - 1) * 100
+			bin := sort.SearchFloat64s(calibrationBinEdges, predictedMove)
+			if bin < len(calibrationBinEdges) && calibrationBinEdges[bin] == predictedMove {
+				bin++ // The edges are exclusive upper bounds
+			}
+			binPredicted[bin] = append(binPredicted[bin], predictedMove)
+			binActual[bin] = append(binActual[bin], (actual/round.LastClose-1)*100)
+			previousGuess = guess.Price
+			previousActual = actual
+			days++
+		}
+		if days > 0 {
+			calibration.Rounds++
+			calibration.Days += days
+		}
+	}
+	calibration.Bias = roundTo(mean(errors), 2)
+	guessVolatility := standardDeviation(guessReturns)
+	realizedVolatility := standardDeviation(actualReturns)
+	calibration.GuessVolatility = roundTo(guessVolatility, 2)
+	calibration.RealizedVolatility = roundTo(realizedVolatility, 2)
+	if realizedVolatility > 0 {
+		ratio := roundTo(guessVolatility/realizedVolatility, 2)
+		calibration.DispersionRatio = &ratio
+	}
+	for bin := range binPredicted {
+		calibration.Curve = append(calibration.Curve, model.CalibrationBin{
+			Label:         calibrationBinLabel(bin),
+			Count:         len(binPredicted[bin]),
+			MeanPredicted: roundTo(mean(binPredicted[bin]), 2),
+			MeanActual:    roundTo(mean(binActual[bin]), 2),
+		})
+	}
+	return calibration
+}
+
+// GetCalibrationReport computes the calibration of every round and of each player. The
+// rounds of anonymous players are only part of the global calibration.
+func GetCalibrationReport(rounds []model.Round) model.CalibrationReport {
+	report := model.CalibrationReport{
+		Global:  GetCalibration(rounds),
+		Players: []model.PlayerCalibration{},
+	}
+	roundsByPlayer := map[string][]model.Round{}
+	for _, round := range rounds {
+		if round.PlayerId != "" {
+			roundsByPlayer[round.PlayerId] = append(roundsByPlayer[round.PlayerId], round)
+		}
+	}
+	for playerId, playerRounds := range roundsByPlayer {
+		calibration := GetCalibration(playerRounds)
+		if calibration.Days == 0 {
+			continue
+		}
+		report.Players = append(report.Players, model.PlayerCalibration{PlayerId: playerId, Calibration: calibration})
+	}
+	sort.Slice(report.Players, func(i, j int) bool {
+		return report.Players[i].PlayerId < report.Players[j].PlayerId
+	})
+	return report
+}
+
+func calibrationBinLabel(bin int) string {
+	switch {
+	case bin == 0:
+		return fmt.Sprintf("< %g%%", calibrationBinEdges[0])
+	case bin == len(calibrationBinEdges):
+		return fmt.Sprintf(">= %g%%", calibrationBinEdges[bin-1])
+	default:
+		return fmt.Sprintf("%g%% to %g%%", calibrationBinEdges[bin-1], calibrationBinEdges[bin])
+	}
+}
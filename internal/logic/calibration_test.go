@@ -0,0 +1,80 @@
+package logic
+
+import (
+	"stockgame/internal/model"
+	"testing"
+)
+
+func TestGetCalibration(t *testing.T) {
+	t.Run("No round", func(t *testing.T) {
+		calibration := GetCalibration(nil)
+		if calibration.Days != 0 || calibration.DispersionRatio != nil {
+			t.Errorf("Expected an empty calibration and not %+v", calibration)
+		}
+		if len(calibration.Curve) != len(calibrationBinEdges)+1 {
+			t.Errorf("Expected every bin even when empty and not %d", len(calibration.Curve))
+		}
+	})
+	t.Run("Bullish and conservative", func(t *testing.T) {
+		rounds := []model.Round{
+			{
+				LastClose: 100,
+				DayPrice:  []model.DayPrice{{Day: 1, Price: 101}, {Day: 2, Price: 102}, {Day: 3, Price: 103}},
+				Actual:    []float64{90, 110, 90},
+			},
+			{
+				// Without the last close shown
+				DayPrice: []model.DayPrice{{Day: 1, Price: 50}},
+			},
+		}
+		calibration := GetCalibration(rounds)
+		if calibration.Rounds != 1 || calibration.Days != 3 {
+			t.Fatalf("Expected 1 round of 3 days and not %+v", calibration)
+		}
+		if calibration.Bias <= 0 {
+			t.Errorf("Expected a positive bias and not %v", calibration.Bias)
+		}
+		if calibration.DispersionRatio == nil || *calibration.DispersionRatio >= 1 {
+			t.Errorf("Expected a dispersion ratio below 1 and not %v", calibration.DispersionRatio)
+		}
+		// Every guess predicts a move between 0% and 5%
+		counts := map[string]int{}
+		for _, bin := range calibration.Curve {
+			counts[bin.Label] = bin.Count
+		}
+		if counts["0% to 2%"] != 1 || counts["2% to 5%"] != 2 {
+			t.Errorf("Unexpected curve %+v", calibration.Curve)
+		}
+	})
+	t.Run("Edges are exclusive upper bounds", func(t *testing.T) {
+		calibration := GetCalibration([]model.Round{{
+			LastClose: 100,
+			DayPrice:  []model.DayPrice{{Day: 1, Price: 100}},
+			Actual:    []float64{100},
+		}})
+		for _, bin := range calibration.Curve {
+			if bin.Count > 0 && bin.Label != "0% to 2%" {
+				t.Errorf("Expected an unchanged price in the 0%% to 2%% bin and not %q", bin.Label)
+			}
+		}
+	})
+}
+
+func TestGetCalibrationReport(t *testing.T) {
+	round := model.Round{
+		LastClose: 100,
+		DayPrice:  []model.DayPrice{{Day: 1, Price: 101}},
+		Actual:    []float64{100},
+	}
+	playerB, playerA, anonymous := round, round, round
+	playerB.PlayerId = "player-b"
+	playerA.PlayerId = "player-a"
+	oldRound := model.Round{PlayerId: "player-c"}
+	report := GetCalibrationReport([]model.Round{playerB, anonymous, playerA, oldRound})
+	if report.Global.Rounds != 3 {
+		t.Errorf("Expected the anonymous round in the global calibration and not %d rounds", report.Global.Rounds)
+	}
+	if len(report.Players) != 2 || report.Players[0].PlayerId != "player-a" || report.Players[1].PlayerId != "player-b" {
+		t.Errorf("Expected player-a and player-b sorted and not %+v", report.Players)
+	}
+}
@@ -0,0 +1,94 @@
+package logic
+
+import (
+	"math"
+	"stockgame/internal/model"
+)
+
+type CandleScoringLogic interface {
+	GetCandleScore(userCandles []model.DayCandle, actualStockInfo []model.Stock) model.UserCandleScoreResponse
+}
+
+type CandleScoringLogicImpl struct {
+	CandleScoringLogic
+}
+
+// Points given for each candle with the right color (bullish, bearish or flat)
+const candleColorPoints = 5
+
+// GetCandleScore compares each predicted candle with the real one of the same position.
+// The range (low/high) and the body (open/close) are rewarded by their intersection over
+// union, so a candle too wide or too narrow loses points, and the color of the candle
+// gives a fixed bonus.
+func (h *CandleScoringLogicImpl) GetCandleScore(userCandles []model.DayCandle, actualStockInfo []model.Stock) model.UserCandleScoreResponse {
+	scoreObj := model.UserCandleScoreResponse{
+		Total:   0,
+		InRange: 0,
+		InBody:  0,
+		InColor: 0,
+	}
+	for i := range userCandles {
+		if i >= len(actualStockInfo) { // In case
+			break
+		}
+		userCandle := userCandles[i]
+		actualStock := actualStockInfo[i]
+		dayPoints := float64(10 + 2*i) // Bonus if the prediction is accurate the farther in the future
+
+		// The player might send a low above the open or close, the range covers the whole candle
+		userLow := math.Min(math.Min(userCandle.Low, userCandle.High), math.Min(userCandle.Open, userCandle.Close))
+		userHigh := math.Max(math.Max(userCandle.Low, userCandle.High), math.Max(userCandle.Open, userCandle.Close))
+		scoreObj.InRange += int(math.Round(dayPoints * intervalIoU(userLow, userHigh, actualStock.Low, actualStock.High)))
+
+		userBodyLow, userBodyHigh := math.Min(userCandle.Open, userCandle.Close), math.Max(userCandle.Open, userCandle.Close)
+		actualBodyLow, actualBodyHigh := math.Min(actualStock.Open, actualStock.Close), math.Max(actualStock.Open, actualStock.Close)
+		scoreObj.InBody += int(math.Round(dayPoints * intervalIoU(userBodyLow, userBodyHigh, actualBodyLow, actualBodyHigh)))
+
+		if candleColor(userCandle.Open, userCandle.Close) == candleColor(actualStock.Open, actualStock.Close) {
+			scoreObj.InColor += candleColorPoints
+		}
+	}
+	scoreObj.Total = scoreObj.InRange + scoreObj.InBody + scoreObj.InColor
+	return scoreObj
+}
+
+// Half width of an interval narrower than it, as a ratio of its price, so a doji (or a
+// guess of a single price) inside the other interval still overlaps it
+const pointIntervalRatio = 0.005
+
+// intervalIoU returns the intersection over union of two intervals (0 to 1)
+func intervalIoU(lowA, highA, lowB, highB float64) float64 {
+	lowA, highA = widenPointInterval(lowA, highA)
+	lowB, highB = widenPointInterval(lowB, highB)
+	union := math.Max(highA, highB) - math.Min(lowA, lowB)
+	if union == 0 {
+		return 1 // Both intervals are the same single price
+	}
+	intersection := math.Min(highA, highB) - math.Max(lowA, lowB)
+	if intersection <= 0 {
+		return 0
+	}
+	return intersection / union
+}
+
+// widenPointInterval widens an interval narrower than pointIntervalRatio around its middle
+func widenPointInterval(low, high float64) (float64, float64) {
+	middle := (low + high) / 2
+	halfWidth := math.Abs(middle) * pointIntervalRatio
+	if high-low >= 2*halfWidth {
+		return low, high
+	}
+	return middle - halfWidth, middle + halfWidth
+}
+
+// candleColor is 1 for a bullish candle, -1 for a bearish candle and 0 when open equals close
+func candleColor(open, close float64) int {
+	switch {
+	case close > open:
+		return 1
+	case close < open:
+		return -1
+	default:
+		return 0
+	}
+}
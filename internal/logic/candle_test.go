@@ -0,0 +1,96 @@
+package logic
+
+import (
+	"stockgame/internal/model"
+	"testing"
+)
+
+func TestGetCandleScore(t *testing.T) {
+	actualStockInfo := []model.Stock{
+		{Symbol: "AAPL", Date: "2022-01-01", Open: 100, High: 110, Low: 90, Close: 105},
+		{Symbol: "AAPL", Date: "2022-01-02", Open: 105, High: 108, Low: 98, Close: 100},
+	}
+	t.Run("Perfect candles", func(t *testing.T) {
+		mockService := &CandleScoringLogicImpl{}
+		userCandles := []model.DayCandle{
+			{Day: 41, Open: 100, High: 110, Low: 90, Close: 105},
+			{Day: 42, Open: 105, High: 108, Low: 98, Close: 100},
+		}
+		score := mockService.GetCandleScore(userCandles, actualStockInfo)
+		if score.InRange != 22 {
+			t.Errorf("Expected InRange to be 22 and not %d", score.InRange)
+		}
+		if score.InBody != 22 {
+			t.Errorf("Expected InBody to be 22 and not %d", score.InBody)
+		}
+		if score.InColor != 10 {
+			t.Errorf("Expected InColor to be 10 and not %d", score.InColor)
+		}
+		if score.Total != 54 {
+			t.Errorf("Expected Total to be 54 and not %d", score.Total)
+		}
+	})
+	t.Run("Partial overlap and wrong color", func(t *testing.T) {
+		mockService := &CandleScoringLogicImpl{}
+		userCandles := []model.DayCandle{
+			{Day: 41, Open: 105, High: 120, Low: 100, Close: 102}, // Range IoU 10/30, body IoU 3/5, bearish
+		}
+		score := mockService.GetCandleScore(userCandles, actualStockInfo)
+		if score.InRange != 3 {
+			t.Errorf("Expected InRange to be 3 and not %d", score.InRange)
+		}
+		if score.InBody != 6 {
+			t.Errorf("Expected InBody to be 6 and not %d", score.InBody)
+		}
+		if score.InColor != 0 {
+			t.Errorf("Expected InColor to be 0 and not %d", score.InColor)
+		}
+	})
+	t.Run("Low and high inverted by the player", func(t *testing.T) {
+		mockService := &CandleScoringLogicImpl{}
+		userCandles := []model.DayCandle{
+			{Day: 41, Open: 100, High: 90, Low: 110, Close: 105},
+		}
+		score := mockService.GetCandleScore(userCandles, actualStockInfo)
+		if score.InRange != 10 {
+			t.Errorf("Expected InRange to be 10 and not %d", score.InRange)
+		}
+	})
+	t.Run("No candles", func(t *testing.T) {
+		mockService := &CandleScoringLogicImpl{}
+		score := mockService.GetCandleScore([]model.DayCandle{}, actualStockInfo)
+		if score.Total != 0 {
+			t.Errorf("Expected Total to be 0 and not %d", score.Total)
+		}
+	})
+	t.Run("More candles than actual stock info", func(t *testing.T) {
+		mockService := &CandleScoringLogicImpl{}
+		userCandles := []model.DayCandle{
+			{Day: 41, Open: 100, High: 110, Low: 90, Close: 105},
+			{Day: 42, Open: 105, High: 108, Low: 98, Close: 100},
+			{Day: 43, Open: 105, High: 108, Low: 98, Close: 100},
+		}
+		score := mockService.GetCandleScore(userCandles, actualStockInfo)
+		if score.Total != 54 {
+			t.Errorf("Expected Total to be 54 and not %d", score.Total)
+		}
+	})
+}
+
+func TestIntervalIoU(t *testing.T) {
+	if !almostEqual(1, intervalIoU(5, 5, 5, 5), 0.0001) {
+		t.Errorf("Expected the same single price to fully overlap")
+	}
+	if !almostEqual(0, intervalIoU(1, 2, 3, 4), 0.0001) {
+		t.Errorf("Expected disjoint intervals to not overlap")
+	}
+	if !almostEqual(0.5, intervalIoU(0, 2, 0, 4), 0.0001) {
+		t.Errorf("Expected half overlap")
+	}
+	if !almostEqual(0.5, intervalIoU(100, 100, 99, 101), 0.0001) {
+		t.Errorf("Expected a single price inside the interval to overlap it")
+	}
+	if !almostEqual(0, intervalIoU(102, 102, 99, 101), 0.0001) {
+		t.Errorf("Expected a single price outside the interval to not overlap it")
+	}
+}
@@ -0,0 +1,152 @@
+package logic
+
+import (
+	"math"
+	"stockgame/internal/model"
+)
+
+type DifficultyLogic interface {
+	RateDifficulty(history []model.StockPublic, future []model.StockPublic) model.DifficultyRating
+}
+
+type DifficultyLogicImpl struct {
+	DifficultyLogic
+}
+
+// An open further than this ratio from the previous close is a gap (2%)
+const gapThreshold = 0.02
+
+// Weights of each feature in the difficulty score. The volatilities are around 0.01 to
+// 0.04 for a stock, the weights bring every feature to a comparable range.
+const (
+	futureVolatilityWeight   = 100
+	realizedVolatilityWeight = 50
+	maxDrawdownWeight        = 5
+	gapFrequencyWeight       = 2
+	trendStrengthWeight      = -1 // A clear trend is easier to follow
+)
+
+// Difficulty scores below the first bound are easy, from the second bound they are hard
+const (
+	difficultyMediumScore = 2
+	difficultyHardScore   = 3.5
+)
+
+// RateDifficulty computes the features of the days shown (history) and of the days to
+// guess (future), both in chronological order, and classifies the window. The future
+// volatility weights the most since it is what the player is actually facing.
+func (l *DifficultyLogicImpl) RateDifficulty(history []model.StockPublic, future []model.StockPublic) model.DifficultyRating {
+	features := model.DifficultyFeatures{
+		RealizedVolatility: standardDeviation(dailyReturns(history)),
+		MaxDrawdown:        maxDrawdown(history),
+		GapFrequency:       gapFrequency(history),
+		TrendStrength:      trendStrength(history),
+	}
+	// The first day to guess moves from the last close shown
+	futureWithLastClose := future
+	if len(history) > 0 {
+		futureWithLastClose = append([]model.StockPublic{history[len(history)-1]}, future...)
+	}
+	features.FutureVolatility = standardDeviation(dailyReturns(futureWithLastClose))
+
+	score := futureVolatilityWeight*features.FutureVolatility +
+		realizedVolatilityWeight*features.RealizedVolatility +
+		maxDrawdownWeight*features.MaxDrawdown +
+		gapFrequencyWeight*features.GapFrequency +
+		trendStrengthWeight*features.TrendStrength
+	difficulty := model.Difficulty_medium
+	if score < difficultyMediumScore {
+		difficulty = model.Difficulty_easy
+	} else if score >= difficultyHardScore {
+		difficulty = model.Difficulty_hard
+	}
+	return model.DifficultyRating{
+		Difficulty: difficulty,
+		Score:      math.Round(score*1000) / 1000,
+		Features:   features,
+	}
+}
+
+// IsValidDifficulty returns true for the difficulties a player can request
+func IsValidDifficulty(difficulty string) bool {
+	return difficulty == model.Difficulty_easy || difficulty == model.Difficulty_medium || difficulty == model.Difficulty_hard
+}
+
+// StocksToPublic converts the stored stocks to the format served to the player
+func StocksToPublic(stocks []model.Stock, symbolUUID string) []model.StockPublic {
+	publics := make([]model.StockPublic, len(stocks))
+	for i, stock := range stocks {
+		publics[i] = model.StockPublic{
+			Date:       stock.Date,
+			Open:       stock.Open,
+			High:       stock.High,
+			Low:        stock.Low,
+			Close:      stock.Close,
+			AdjClose:   stock.AdjClose,
+			Volume:     stock.Volume,
+			SymbolUUID: symbolUUID,
+		}
+	}
+	return publics
+}
+
+func dailyReturns(stocks []model.StockPublic) []float64 {
+	returns := []float64{}
+	for i := 1; i < len(stocks); i++ {
+		if stocks[i-1].Close == 0 {
+			continue
+		}
+		returns = append(returns, stocks[i].Close/stocks[i-1].Close-1)
+	}
+	return returns
+}
+
+func maxDrawdown(stocks []model.StockPublic) float64 {
+	peak := 0.0
+	drawdown := 0.0
+	for _, stock := range stocks {
+		peak = math.Max(peak, stock.Close)
+		if peak > 0 {
+			drawdown = math.Max(drawdown, (peak-stock.Close)/peak)
+		}
+	}
+	return drawdown
+}
+
+func gapFrequency(stocks []model.StockPublic) float64 {
+	if len(stocks) < 2 {
+		return 0
+	}
+	gaps := 0
+	for i := 1; i < len(stocks); i++ {
+		previousClose := stocks[i-1].Close
+		if previousClose != 0 && math.Abs(stocks[i].Open-previousClose)/previousClose > gapThreshold {
+			gaps++
+		}
+	}
+	return float64(gaps) / float64(len(stocks)-1)
+}
+
+// trendStrength is the coefficient of determination of the least squares line of the closes
+func trendStrength(stocks []model.StockPublic) float64 {
+	n := float64(len(stocks))
+	if n < 3 {
+		return 0
+	}
+	sumX, sumY, sumXY, sumXX, sumYY := 0.0, 0.0, 0.0, 0.0, 0.0
+	for i, stock := range stocks {
+		x := float64(i)
+		sumX += x
+		sumY += stock.Close
+		sumXY += x * stock.Close
+		sumXX += x * x
+		sumYY += stock.Close * stock.Close
+	}
+	covariance := n*sumXY - sumX*sumY
+	varianceX := n*sumXX - sumX*sumX
+	varianceY := n*sumYY - sumY*sumY
+	if varianceX == 0 || varianceY <= 0 {
+		return 0 // A flat line has no trend
+	}
+	return covariance * covariance / (varianceX * varianceY)
+}
@@ -0,0 +1,67 @@
+package logic
+
+import (
+	"stockgame/internal/model"
+	"testing"
+)
+
+func TestRateDifficulty(t *testing.T) {
+	mockService := &DifficultyLogicImpl{}
+	t.Run("Steady trend is easy", func(t *testing.T) {
+		history := []model.StockPublic{}
+		for i := 0; i < 40; i++ {
+			price := 100 + float64(i)*0.1
+			history = append(history, model.StockPublic{Open: price, Close: price})
+		}
+		future := []model.StockPublic{{Open: 104, Close: 104.1}, {Open: 104.1, Close: 104.2}}
+		rating := mockService.RateDifficulty(history, future)
+		if rating.Difficulty != model.Difficulty_easy {
+			t.Errorf("Expected easy and not %s (%+v)", rating.Difficulty, rating)
+		}
+		if !almostEqual(1, rating.Features.TrendStrength, 0.0001) {
+			t.Errorf("Expected a perfect trend and not %f", rating.Features.TrendStrength)
+		}
+		if rating.Features.MaxDrawdown != 0 || rating.Features.GapFrequency != 0 {
+			t.Errorf("Expected no drawdown and no gap and not %+v", rating.Features)
+		}
+	})
+	t.Run("Choppy with gaps is hard", func(t *testing.T) {
+		history := []model.StockPublic{}
+		for i := 0; i < 40; i++ {
+			// Every day opens more than 2% away from the previous close
+			open, price := 90.0, 100.0
+			if i%2 == 1 {
+				open, price = 105, 95
+			}
+			history = append(history, model.StockPublic{Open: open, Close: price})
+		}
+		future := []model.StockPublic{{Open: 110, Close: 110}, {Open: 95, Close: 95}, {Open: 110, Close: 110}}
+		rating := mockService.RateDifficulty(history, future)
+		if rating.Difficulty != model.Difficulty_hard {
+			t.Errorf("Expected hard and not %s (%+v)", rating.Difficulty, rating)
+		}
+		if !almostEqual(0.05, rating.Features.MaxDrawdown, 0.0001) {
+			t.Errorf("Expected a 5%% drawdown and not %f", rating.Features.MaxDrawdown)
+		}
+		if !almostEqual(1, rating.Features.GapFrequency, 0.0001) {
+			t.Errorf("Expected a gap every day and not %f", rating.Features.GapFrequency)
+		}
+	})
+	t.Run("No data", func(t *testing.T) {
+		rating := mockService.RateDifficulty([]model.StockPublic{}, []model.StockPublic{})
+		if rating.Difficulty != model.Difficulty_easy || rating.Score != 0 {
+			t.Errorf("Expected an easy empty rating and not %+v", rating)
+		}
+	})
+}
+
+func TestIsValidDifficulty(t *testing.T) {
+	for _, difficulty := range []string{model.Difficulty_easy, model.Difficulty_medium, model.Difficulty_hard} {
+		if !IsValidDifficulty(difficulty) {
+			t.Errorf("Expected %s to be valid", difficulty)
+		}
+	}
+	if IsValidDifficulty("extreme") {
+		t.Errorf("Expected extreme to be invalid")
+	}
+}
@@ -0,0 +1,86 @@
+package logic
+
+import (
+	"math"
+	"stockgame/internal/model"
+)
+
+type DirectionScoringLogic interface {
+	GetActualDirections(lastClose float64, actualStockInfo []model.Stock) []string
+	GetDirectionScore(userDirections []model.DayDirection, lastClose float64, actualStockInfo []model.Stock) model.UserDirectionScoreResponse
+}
+
+type DirectionScoringLogicImpl struct {
+	DirectionScoringLogic
+}
+
+// A close that moves less than this ratio from the previous close is flat (0.25%)
+const directionFlatThreshold = 0.0025
+
+// Points for each day with the right direction
+const directionPoints = 10
+
+// Additional points for each day of a streak after the first one, multiplied by the length of the streak so far
+const directionStreakPoints = 2
+
+// GetActualDirections returns the direction of each day compared to the previous close.
+// The first day is compared to the last close the player saw.
+func (h *DirectionScoringLogicImpl) GetActualDirections(lastClose float64, actualStockInfo []model.Stock) []string {
+	directions := make([]string, len(actualStockInfo))
+	previousClose := lastClose
+	for i, stock := range actualStockInfo {
+		directions[i] = getDirection(previousClose, stock.Close)
+		previousClose = stock.Close
+	}
+	return directions
+}
+
+// GetDirectionScore gives points for every day with the right direction and a bonus
+// growing with the length of each streak of consecutive right directions.
+func (h *DirectionScoringLogicImpl) GetDirectionScore(userDirections []model.DayDirection, lastClose float64, actualStockInfo []model.Stock) model.UserDirectionScoreResponse {
+	scoreObj := model.UserDirectionScoreResponse{
+		Total:         0,
+		InDirection:   0,
+		InStreak:      0,
+		Correct:       0,
+		LongestStreak: 0,
+	}
+	actualDirections := h.GetActualDirections(lastClose, actualStockInfo)
+	streak := 0
+	for i := range userDirections {
+		if i >= len(actualDirections) { // In case
+			break
+		}
+		if userDirections[i].Direction != actualDirections[i] {
+			streak = 0
+			continue
+		}
+		scoreObj.Correct++
+		scoreObj.InDirection += directionPoints
+		scoreObj.InStreak += directionStreakPoints * streak
+		streak++
+		scoreObj.LongestStreak = max(scoreObj.LongestStreak, streak)
+	}
+	scoreObj.Total = scoreObj.InDirection + scoreObj.InStreak
+	return scoreObj
+}
+
+func getDirection(previousClose, close float64) string {
+	if previousClose == 0 {
+		return model.Direction_flat
+	}
+	change := (close - previousClose) / previousClose
+	switch {
+	case math.Abs(change) < directionFlatThreshold:
+		return model.Direction_flat
+	case change > 0:
+		return model.Direction_up
+	default:
+		return model.Direction_down
+	}
+}
+
+// IsValidDirection returns true for the directions a player can submit
+func IsValidDirection(direction string) bool {
+	return direction == model.Direction_up || direction == model.Direction_down || direction == model.Direction_flat
+}
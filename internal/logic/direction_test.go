@@ -0,0 +1,82 @@
+package logic
+
+import (
+	"slices"
+	"stockgame/internal/model"
+	"testing"
+)
+
+func TestGetActualDirections(t *testing.T) {
+	mockService := &DirectionScoringLogicImpl{}
+	actualStockInfo := []model.Stock{
+		{Date: "2022-01-01", Close: 101},    // Up from 100
+		{Date: "2022-01-02", Close: 100},    // Down
+		{Date: "2022-01-03", Close: 100.2},  // Flat (0.2%)
+		{Date: "2022-01-04", Close: 99.949}, // Down (0.25%)
+	}
+	directions := mockService.GetActualDirections(100, actualStockInfo)
+	expected := []string{model.Direction_up, model.Direction_down, model.Direction_flat, model.Direction_down}
+	if !slices.Equal(expected, directions) {
+		t.Errorf("Expected %v and not %v", expected, directions)
+	}
+}
+
+func TestGetDirectionScore(t *testing.T) {
+	actualStockInfo := []model.Stock{
+		{Date: "2022-01-01", Close: 101},
+		{Date: "2022-01-02", Close: 102},
+		{Date: "2022-01-03", Close: 103},
+		{Date: "2022-01-04", Close: 100},
+		{Date: "2022-01-05", Close: 100},
+	}
+	t.Run("All correct", func(t *testing.T) {
+		mockService := &DirectionScoringLogicImpl{}
+		userDirections := []model.DayDirection{
+			{Day: 41, Direction: model.Direction_up},
+			{Day: 42, Direction: model.Direction_up},
+			{Day: 43, Direction: model.Direction_up},
+			{Day: 44, Direction: model.Direction_down},
+			{Day: 45, Direction: model.Direction_flat},
+		}
+		score := mockService.GetDirectionScore(userDirections, 100, actualStockInfo)
+		if score.InDirection != 50 {
+			t.Errorf("Expected InDirection to be 50 and not %d", score.InDirection)
+		}
+		if score.InStreak != 20 { // 0 + 2 + 4 + 6 + 8
+			t.Errorf("Expected InStreak to be 20 and not %d", score.InStreak)
+		}
+		if score.LongestStreak != 5 || score.Correct != 5 {
+			t.Errorf("Expected a streak of 5 and 5 correct days and not %d and %d", score.LongestStreak, score.Correct)
+		}
+		if score.Total != 70 {
+			t.Errorf("Expected Total to be 70 and not %d", score.Total)
+		}
+	})
+	t.Run("Streak broken", func(t *testing.T) {
+		mockService := &DirectionScoringLogicImpl{}
+		userDirections := []model.DayDirection{
+			{Day: 41, Direction: model.Direction_up},
+			{Day: 42, Direction: model.Direction_up},
+			{Day: 43, Direction: model.Direction_down},
+			{Day: 44, Direction: model.Direction_down},
+			{Day: 45, Direction: model.Direction_flat},
+		}
+		score := mockService.GetDirectionScore(userDirections, 100, actualStockInfo)
+		if score.Correct != 4 {
+			t.Errorf("Expected 4 correct days and not %d", score.Correct)
+		}
+		if score.InStreak != 4 { // (0 + 2) + (0 + 2)
+			t.Errorf("Expected InStreak to be 4 and not %d", score.InStreak)
+		}
+		if score.LongestStreak != 2 {
+			t.Errorf("Expected LongestStreak to be 2 and not %d", score.LongestStreak)
+		}
+	})
+	t.Run("No directions", func(t *testing.T) {
+		mockService := &DirectionScoringLogicImpl{}
+		score := mockService.GetDirectionScore([]model.DayDirection{}, 100, actualStockInfo)
+		if score.Total != 0 {
+			t.Errorf("Expected Total to be 0 and not %d", score.Total)
+		}
+	})
+}
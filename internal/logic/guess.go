@@ -0,0 +1,102 @@
+package logic
+
+import (
+	"fmt"
+	"stockgame/internal/model"
+)
+
+// ValidateDayPrices checks the guess has one price for each of the model.User_stock_to_guess
+// days to guess, sorted and numbered from model.Number_initial_stock_shown like the days
+// shown. The prices must be within model.Guess_price_max_ratio of the last close shown, only
+// positive when it is unknown. At most one error is returned by field.
+func ValidateDayPrices(prices []model.DayPrice, lastClose float64) []model.FieldError {
+	days := model.User_stock_to_guess
+	errors := []model.FieldError{}
+	if len(prices) != days {
+		errors = append(errors, model.FieldError{
+			Field:   "estimatedDayPrices",
+			Message: fmt.Sprintf("expected %d days, %d received", days, len(prices)),
+		})
+	}
+	seen := map[int]bool{}
+	// The extra prices are not checked, the count error is enough
+	for i, price := range prices[:min(len(prices), days)] {
+		field := fmt.Sprintf("estimatedDayPrices[%d]", i)
+		previous := 0
+		if i > 0 {
+			previous = prices[i-1].Day
+		}
+		if message := dayMessage(price.Day, previous, i, days, seen); message != "" {
+			errors = append(errors, model.FieldError{Field: field + ".day", Message: message})
+		}
+		if message := priceMessage(price.Price, lastClose); message != "" {
+			errors = append(errors, model.FieldError{Field: field + ".price", Message: message})
+		}
+	}
+	return errors
+}
+
+// ValidateDayBands checks the bands like ValidateDayPrices checks the prices, since they
+// are scored in the order of the days. The lower bound cannot be above the upper one.
+func ValidateDayBands(bands []model.DayBand, lastClose float64) []model.FieldError {
+	days := model.User_stock_to_guess
+	errors := []model.FieldError{}
+	if len(bands) != days {
+		errors = append(errors, model.FieldError{
+			Field:   "estimatedDayBands",
+			Message: fmt.Sprintf("expected %d days, %d received", days, len(bands)),
+		})
+	}
+	seen := map[int]bool{}
+	for i, band := range bands[:min(len(bands), days)] {
+		field := fmt.Sprintf("estimatedDayBands[%d]", i)
+		previous := 0
+		if i > 0 {
+			previous = bands[i-1].Day
+		}
+		if message := dayMessage(band.Day, previous, i, days, seen); message != "" {
+			errors = append(errors, model.FieldError{Field: field + ".day", Message: message})
+		}
+		lower, upper := priceMessage(band.Lower, lastClose), priceMessage(band.Upper, lastClose)
+		if lower != "" {
+			errors = append(errors, model.FieldError{Field: field + ".lower", Message: lower})
+		}
+		switch {
+		case upper != "":
+			errors = append(errors, model.FieldError{Field: field + ".upper", Message: upper})
+		case lower == "" && band.Lower > band.Upper:
+			errors = append(errors, model.FieldError{Field: field + ".upper", Message: "upper must not be below lower"})
+		}
+	}
+	return errors
+}
+
+// dayMessage is the error of the day at the index of a guess, empty when it is valid. The
+// day is added to the days seen.
+func dayMessage(day int, previous int, index int, days int, seen map[int]bool) string {
+	first := model.Number_initial_stock_shown
+	last := first + days - 1
+	message := ""
+	switch {
+	case day < first || day > last:
+		message = fmt.Sprintf("day must be between %d and %d", first, last)
+	case seen[day]:
+		message = fmt.Sprintf("day %d is repeated", day)
+	case index > 0 && day < previous:
+		message = "days must be in ascending order"
+	}
+	seen[day] = true
+	return message
+}
+
+// priceMessage is the error of a guessed price, empty when it is valid
+func priceMessage(price float64, lastClose float64) string {
+	minPrice, maxPrice := lastClose/model.Guess_price_max_ratio, lastClose*model.Guess_price_max_ratio
+	switch {
+	case price <= 0:
+		return "price must be positive"
+	case lastClose > 0 && (price < minPrice || price > maxPrice):
+		return fmt.Sprintf("price must be between %.2f and %.2f", minPrice, maxPrice)
+	}
+	return ""
+}
@@ -0,0 +1,86 @@
+package logic
+
+import (
+	"slices"
+	"stockgame/internal/model"
+	"testing"
+)
+
+// fullGuess is a guess of 100 for every day to guess, changed by edit
+func fullGuess(edit func(prices []model.DayPrice) []model.DayPrice) []model.DayPrice {
+	prices := []model.DayPrice{}
+	for i := range model.User_stock_to_guess {
+		prices = append(prices, model.DayPrice{Day: model.Number_initial_stock_shown + i, Price: 100})
+	}
+	return edit(prices)
+}
+
+func TestValidateDayPrices(t *testing.T) {
+	day := model.Number_initial_stock_shown
+	cases := []struct {
+		name   string
+		prices []model.DayPrice
+		fields []string
+	}{
+		{"Valid", fullGuess(func(p []model.DayPrice) []model.DayPrice { return p }), []string{}},
+		{"Empty", nil, []string{"estimatedDayPrices"}},
+		{"Missing day", fullGuess(func(p []model.DayPrice) []model.DayPrice { return slices.Delete(p, 1, 2) }), []string{"estimatedDayPrices"}},
+		{"Too many days", fullGuess(func(p []model.DayPrice) []model.DayPrice {
+			return append(p, model.DayPrice{Day: day + model.User_stock_to_guess, Price: 100})
+		}), []string{"estimatedDayPrices"}},
+		{"Day shown", fullGuess(func(p []model.DayPrice) []model.DayPrice { p[0].Day = day - 1; return p }), []string{"estimatedDayPrices[0].day"}},
+		{"Repeated day", fullGuess(func(p []model.DayPrice) []model.DayPrice { p[1].Day = day; return p }), []string{"estimatedDayPrices[1].day"}},
+		{"Not sorted", fullGuess(func(p []model.DayPrice) []model.DayPrice { p[0].Day, p[1].Day = day+1, day; return p }), []string{"estimatedDayPrices[1].day"}},
+		{"Absurd prices", fullGuess(func(p []model.DayPrice) []model.DayPrice {
+			p[0].Price, p[1].Price, p[2].Price = -1, 501, 19
+			return p
+		}), []string{"estimatedDayPrices[0].price", "estimatedDayPrices[1].price", "estimatedDayPrices[2].price"}},
+	}
+	for _, c := range cases {
+		fields := []string{}
+		for _, fieldError := range ValidateDayPrices(c.prices, 100) {
+			fields = append(fields, fieldError.Field)
+		}
+		if !slices.Equal(fields, c.fields) {
+			t.Errorf("%s: expected errors on %v and not %v", c.name, c.fields, fields)
+		}
+	}
+	expensive := fullGuess(func(p []model.DayPrice) []model.DayPrice { p[0].Price = 10000; return p })
+	if errors := ValidateDayPrices(expensive, 0); len(errors) != 0 {
+		t.Errorf("Expected any positive price without last close and not %v", errors)
+	}
+}
+
+func TestValidateDayBands(t *testing.T) {
+	day := model.Number_initial_stock_shown
+	// fullBands is a band from 90 to 110 for every day to guess, changed by edit
+	fullBands := func(edit func(bands []model.DayBand) []model.DayBand) []model.DayBand {
+		bands := []model.DayBand{}
+		for i := range model.User_stock_to_guess {
+			bands = append(bands, model.DayBand{Day: day + i, Lower: 90, Upper: 110})
+		}
+		return edit(bands)
+	}
+	cases := []struct {
+		name   string
+		bands  []model.DayBand
+		fields []string
+	}{
+		{"Valid", fullBands(func(b []model.DayBand) []model.DayBand { b[1].Lower, b[1].Upper = 100, 100; return b }), []string{}},
+		{"Missing day", fullBands(func(b []model.DayBand) []model.DayBand { return b[:1] }), []string{"estimatedDayBands"}},
+		{"Day shown", fullBands(func(b []model.DayBand) []model.DayBand { b[0].Day = day - 1; return b }), []string{"estimatedDayBands[0].day"}},
+		{"Repeated day", fullBands(func(b []model.DayBand) []model.DayBand { b[1].Day = day; return b }), []string{"estimatedDayBands[1].day"}},
+		{"Lower above upper", fullBands(func(b []model.DayBand) []model.DayBand { b[0].Lower, b[0].Upper = 110, 90; return b }), []string{"estimatedDayBands[0].upper"}},
+		{"Absurd bounds", fullBands(func(b []model.DayBand) []model.DayBand { b[0].Lower, b[1].Upper = 0, 501; return b }),
+			[]string{"estimatedDayBands[0].lower", "estimatedDayBands[1].upper"}},
+	}
+	for _, c := range cases {
+		fields := []string{}
+		for _, fieldError := range ValidateDayBands(c.bands, 100) {
+			fields = append(fields, fieldError.Field)
+		}
+		if !slices.Equal(fields, c.fields) {
+			t.Errorf("%s: expected errors on %v and not %v", c.name, c.fields, fields)
+		}
+	}
+}
@@ -0,0 +1,22 @@
+package logic
+
+import (
+	"math"
+	"stockgame/internal/model"
+)
+
+// PercentileRank ranks a score that is already part of the counted scores. The percentile
+// is the share of the other players with a lower score, a tie counts as half so players
+// with the same score share the same middle rank. Returns nil when nobody else was counted.
+func PercentileRank(count model.ScoreCount) *model.PercentileRank {
+	others := count.Total - 1
+	if others <= 0 {
+		return nil
+	}
+	ties := max(count.Equal-1, 0) // The score itself is not a tie
+	percentile := 100 * (float64(count.Below) + float64(ties)/2) / float64(others)
+	return &model.PercentileRank{
+		Percentile: math.Round(percentile*10) / 10,
+		Players:    others,
+	}
+}
@@ -0,0 +1,36 @@
+package logic
+
+import (
+	"stockgame/internal/model"
+	"testing"
+)
+
+func TestPercentileRank(t *testing.T) {
+	t.Run("Alone", func(t *testing.T) {
+		if rank := PercentileRank(model.ScoreCount{Below: 0, Equal: 1, Total: 1}); rank != nil {
+			t.Errorf("Expected no rank without other players and not %+v", rank)
+		}
+	})
+	t.Run("Strictly better", func(t *testing.T) {
+		rank := PercentileRank(model.ScoreCount{Below: 8, Equal: 1, Total: 12})
+		if rank == nil || rank.Players != 11 || rank.Percentile != 72.7 {
+			t.Errorf("Expected 72.7%% of 11 players and not %+v", rank)
+		}
+	})
+	t.Run("Ties count as half", func(t *testing.T) {
+		rank := PercentileRank(model.ScoreCount{Below: 0, Equal: 3, Total: 3})
+		if rank == nil || rank.Players != 2 || rank.Percentile != 50 {
+			t.Errorf("Expected 50%% of 2 players and not %+v", rank)
+		}
+		rank = PercentileRank(model.ScoreCount{Below: 2, Equal: 2, Total: 5})
+		if rank == nil || rank.Players != 4 || rank.Percentile != 62.5 {
+			t.Errorf("Expected 62.5%% of 4 players and not %+v", rank)
+		}
+	})
+	t.Run("Best score", func(t *testing.T) {
+		rank := PercentileRank(model.ScoreCount{Below: 4, Equal: 1, Total: 5})
+		if rank == nil || rank.Percentile != 100 {
+			t.Errorf("Expected 100%% and not %+v", rank)
+		}
+	})
+}
@@ -0,0 +1,146 @@
+package logic
+
+import (
+	"maps"
+	"stockgame/internal/model"
+	"time"
+)
+
+// AchievementRule counts the rounds matching it, the achievement is unlocked when the
+// count reaches its target. The progress is already updated with the round.
+type AchievementRule struct {
+	model.Achievement
+	Matches func(progress model.PlayerProgress, round model.Round) bool
+}
+
+// DefaultAchievementRules are the achievements every player can unlock
+var DefaultAchievementRules = []AchievementRule{
+	{
+		Achievement: model.Achievement{Id: "first_round", Name: "First guess", Description: "Play a round", Target: 1},
+		Matches:     func(progress model.PlayerProgress, round model.Round) bool { return true },
+	},
+	{
+		Achievement: model.Achievement{Id: "rounds_100", Name: "Centurion", Description: "Play 100 rounds", Target: 100},
+		Matches:     func(progress model.PlayerProgress, round model.Round) bool { return true },
+	},
+	{
+		Achievement: model.Achievement{Id: "perfect_direction_10", Name: "Trend reader", Description: "Guess the direction right 10 times", Target: 10},
+		Matches: func(progress model.PlayerProgress, round model.Round) bool {
+			return round.Score.InDirection > 0
+		},
+	},
+	{
+		Achievement: model.Achievement{Id: "every_band", Name: "Full house", Description: "Score in every band in a single round", Target: 1},
+		Matches: func(progress model.PlayerProgress, round model.Round) bool {
+			return round.Score.InLowHigh > 0 && round.Score.InOpenClose > 0 && round.Score.InBollinger > 0
+		},
+	},
+	{
+		Achievement: model.Achievement{Id: "hard_no_hint", Name: "Unassisted", Description: "Score 100 on a hard window without a hint", Target: 1},
+		Matches: func(progress model.PlayerProgress, round model.Round) bool {
+			return round.Difficulty == model.Difficulty_hard && len(round.Hints) == 0 && round.Score.Total >= 100
+		},
+	},
+	{
+		Achievement: model.Achievement{Id: "streak_7", Name: "Weekly habit", Description: "Play 7 days in a row", Target: 1},
+		Matches: func(progress model.PlayerProgress, round model.Round) bool {
+			return progress.CurrentStreak >= 7
+		},
+	},
+}
+
+// GetRoundXP is the XP awarded for a round: a fixed amount for playing plus the total
+// score, which already has the hint penalty removed
+func GetRoundXP(score model.UserScoreResponse) int {
+	return model.Xp_per_round + max(0, score.Total)
+}
+
+// GetLevel returns the level reached with the XP and the XP needed for the next one. Each
+// level needs model.Xp_per_level more XP than the previous one.
+func GetLevel(xp int) (level int, nextLevelXP int) {
+	level = 1
+	for levelXP(level+1) <= xp {
+		level++
+	}
+	return level, levelXP(level + 1)
+}
+
+// levelXP is the XP needed to reach the level
+func levelXP(level int) int {
+	return model.Xp_per_level * level * (level - 1) / 2
+}
+
+// ApplyRound adds the round to the progress and returns the achievements it unlocked. The
+// days of the streak are the UTC dates the rounds were created.
+func ApplyRound(progress model.PlayerProgress, round model.Round, rules []AchievementRule) (model.PlayerProgress, []model.Achievement) {
+	progress.Counters = maps.Clone(progress.Counters)
+	if progress.Counters == nil {
+		progress.Counters = map[string]int{}
+	}
+	progress.Unlocked = maps.Clone(progress.Unlocked)
+	if progress.Unlocked == nil {
+		progress.Unlocked = map[string]time.Time{}
+	}
+	progress.XP += GetRoundXP(round.Score)
+	progress.Rounds++
+	date := round.CreatedAt.UTC().Format(time.DateOnly)
+	switch {
+	case date <= progress.LastPlayedDate:
+		// The day is already counted
+	case progress.LastPlayedDate == previousDay(date):
+		progress.CurrentStreak++
+		progress.LastPlayedDate = date
+	default:
+		progress.CurrentStreak = 1
+		progress.LastPlayedDate = date
+	}
+	progress.LongestStreak = max(progress.LongestStreak, progress.CurrentStreak)
+	unlocked := []model.Achievement{}
+	for _, rule := range rules {
+		if _, done := progress.Unlocked[rule.Id]; done || !rule.Matches(progress, round) {
+			continue
+		}
+		progress.Counters[rule.Id]++
+		if progress.Counters[rule.Id] >= rule.Target {
+			progress.Unlocked[rule.Id] = round.CreatedAt
+			unlocked = append(unlocked, rule.Achievement)
+		}
+	}
+	return progress, unlocked
+}
+
+// GetProgressResponse describes the progress on the date, the current streak is broken
+// when the last day played is before the previous day
+func GetProgressResponse(progress model.PlayerProgress, rules []AchievementRule, date string) model.ProgressResponse {
+	level, nextLevelXP := GetLevel(progress.XP)
+	response := model.ProgressResponse{
+		XP:             progress.XP,
+		Level:          level,
+		NextLevelXP:    nextLevelXP,
+		Rounds:         progress.Rounds,
+		CurrentStreak:  progress.CurrentStreak,
+		LongestStreak:  progress.LongestStreak,
+		LastPlayedDate: progress.LastPlayedDate,
+		Achievements:   []model.AchievementProgress{},
+	}
+	if progress.LastPlayedDate != date && progress.LastPlayedDate != previousDay(date) {
+		response.CurrentStreak = 0
+	}
+	for _, rule := range rules {
+		achievement := model.AchievementProgress{Achievement: rule.Achievement, Count: progress.Counters[rule.Id]}
+		if unlockedAt, ok := progress.Unlocked[rule.Id]; ok {
+			achievement.UnlockedAt = &unlockedAt
+		}
+		response.Achievements = append(response.Achievements, achievement)
+	}
+	return response
+}
+
+// previousDay returns the day before a YYYY-MM-DD date, empty when the date is invalid
+func previousDay(date string) string {
+	day, err := time.Parse(time.DateOnly, date)
+	if err != nil {
+		return ""
+	}
+	return day.AddDate(0, 0, -1).Format(time.DateOnly)
+}
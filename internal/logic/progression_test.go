@@ -0,0 +1,112 @@
+package logic
+
+import (
+	"slices"
+	"stockgame/internal/model"
+	"testing"
+	"time"
+)
+
+func TestGetLevel(t *testing.T) {
+	cases := []struct {
+		xp          int
+		level       int
+		nextLevelXP int
+	}{
+		{0, 1, 100},
+		{99, 1, 100},
+		{100, 2, 300},
+		{299, 2, 300},
+		{300, 3, 600},
+	}
+	for _, c := range cases {
+		if level, next := GetLevel(c.xp); level != c.level || next != c.nextLevelXP {
+			t.Errorf("%d XP: expected level %d before %d and not %d before %d", c.xp, c.level, c.nextLevelXP, level, next)
+		}
+	}
+}
+
+func TestApplyRound(t *testing.T) {
+	day := time.Date(2024, 5, 1, 20, 0, 0, 0, time.UTC)
+	direction := model.Round{Score: model.UserScoreResponse{Total: 10, InDirection: 10}}
+	t.Run("XP and achievements", func(t *testing.T) {
+		round := direction
+		round.CreatedAt = day
+		progress, unlocked := ApplyRound(model.PlayerProgress{}, round, DefaultAchievementRules)
+		if progress.XP != 20 || progress.Rounds != 1 {
+			t.Errorf("Expected 20 XP for a round and not %+v", progress)
+		}
+		if len(unlocked) != 1 || unlocked[0].Id != "first_round" {
+			t.Errorf("Expected the first round achievement and not %+v", unlocked)
+		}
+		if progress.Counters["perfect_direction_10"] != 1 || progress.Counters["every_band"] != 0 {
+			t.Errorf("Unexpected counters %v", progress.Counters)
+		}
+		// A second first round does not unlock it again
+		if _, unlocked = ApplyRound(progress, round, DefaultAchievementRules); len(unlocked) != 0 {
+			t.Errorf("Expected no achievement and not %+v", unlocked)
+		}
+	})
+	t.Run("Target reached", func(t *testing.T) {
+		progress := model.PlayerProgress{Counters: map[string]int{"perfect_direction_10": 9}, Unlocked: map[string]time.Time{"first_round": day}}
+		round := direction
+		round.CreatedAt = day
+		updated, unlocked := ApplyRound(progress, round, DefaultAchievementRules)
+		if len(unlocked) != 1 || unlocked[0].Id != "perfect_direction_10" || !updated.Unlocked["perfect_direction_10"].Equal(day) {
+			t.Errorf("Expected the direction achievement and not %+v", unlocked)
+		}
+		if progress.Counters["perfect_direction_10"] != 9 {
+			t.Error("Expected the previous progress to be left unchanged")
+		}
+	})
+	t.Run("Streak", func(t *testing.T) {
+		progress := model.PlayerProgress{}
+		var unlocked []model.Achievement
+		dates := []time.Time{}
+		for i := 0; i < 7; i++ {
+			dates = append(dates, day.AddDate(0, 0, i))
+		}
+		// Playing twice the same day and a gap
+		dates = append(dates, dates[6], day.AddDate(0, 0, 9))
+		ids := []string{}
+		streaks := []int{}
+		for _, date := range dates {
+			progress, unlocked = ApplyRound(progress, model.Round{CreatedAt: date}, DefaultAchievementRules)
+			for _, achievement := range unlocked {
+				ids = append(ids, achievement.Id)
+			}
+			streaks = append(streaks, progress.CurrentStreak)
+		}
+		if !slices.Equal(streaks, []int{1, 2, 3, 4, 5, 6, 7, 7, 1}) || progress.LongestStreak != 7 {
+			t.Errorf("Unexpected streaks %v, longest %d", streaks, progress.LongestStreak)
+		}
+		if !slices.Contains(ids, "streak_7") {
+			t.Errorf("Expected the streak achievement and not %v", ids)
+		}
+	})
+}
+
+func TestGetProgressResponse(t *testing.T) {
+	unlockedAt := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	progress := model.PlayerProgress{
+		XP:             150,
+		CurrentStreak:  3,
+		LongestStreak:  5,
+		LastPlayedDate: "2024-05-01",
+		Counters:       map[string]int{"first_round": 1, "perfect_direction_10": 4},
+		Unlocked:       map[string]time.Time{"first_round": unlockedAt},
+	}
+	response := GetProgressResponse(progress, DefaultAchievementRules, "2024-05-02")
+	if response.Level != 2 || response.NextLevelXP != 300 || response.CurrentStreak != 3 {
+		t.Errorf("Unexpected progress %+v", response)
+	}
+	if len(response.Achievements) != len(DefaultAchievementRules) {
+		t.Fatalf("Expected every achievement and not %d", len(response.Achievements))
+	}
+	if response.Achievements[0].UnlockedAt == nil || response.Achievements[2].Count != 4 || response.Achievements[2].UnlockedAt != nil {
+		t.Errorf("Unexpected achievements %+v", response.Achievements)
+	}
+	if broken := GetProgressResponse(progress, DefaultAchievementRules, "2024-05-03"); broken.CurrentStreak != 0 {
+		t.Errorf("Expected the streak to be broken and not %d", broken.CurrentStreak)
+	}
+}
@@ -1,17 +1,42 @@
 package logic
 
 import (
-	"math"
+	"stockgame/internal/indicators"
 	"stockgame/internal/model"
 )
 
 type ScoringLogic interface {
 	CalculateBollingerBands(stockInfo []model.Stock, day int) map[string]model.BollingerBand
+	CalculateBollingerBandsWithConfig(stockInfo []model.Stock, config model.BollingerConfig) map[string]model.BollingerBand
 	GetScore(userPrices []model.DayPrice, actualStockInfo []model.Stock, bollinger20Days map[string]model.BollingerBand) model.UserScoreResponse
+	GetHintPenalty(hints []string) int
+	ApplyHintPenalty(total int, hints []string) (penalized int, penalty int)
 }
 
 type ScoringLogicImpl struct {
 	ScoringLogic
+	HintPenalties map[string]int // Nil uses model.DefaultHintPenalties
+}
+
+// GetHintPenalty is the number of points of the hints
+func (h *ScoringLogicImpl) GetHintPenalty(hints []string) int {
+	penalties := h.HintPenalties
+	if penalties == nil {
+		penalties = model.DefaultHintPenalties
+	}
+	penalty := 0
+	for _, hint := range hints {
+		penalty += penalties[hint]
+	}
+	return penalty
+}
+
+// ApplyHintPenalty removes the points of every hint from the total of a score, which cannot
+// go below zero, and returns the points removed. The components of the score are kept so
+// the player still sees how the guess was scored.
+func (h *ScoringLogicImpl) ApplyHintPenalty(total int, hints []string) (penalized int, penalty int) {
+	penalty = h.GetHintPenalty(hints)
+	return max(0, total-penalty), penalty
 }
 
 func (h *ScoringLogicImpl) GetScore(userPrices []model.DayPrice, actualStockInfo []model.Stock, bollinger20Days map[string]model.BollingerBand) model.UserScoreResponse {
@@ -62,41 +87,30 @@ func (h *ScoringLogicImpl) GetScore(userPrices []model.DayPrice, actualStockInfo
 	return scoreObj
 }
 
+// CalculateBollingerBands uses the `day` previous days (excluding the current one) and 2 standard deviations
 func (h *ScoringLogicImpl) CalculateBollingerBands(stockInfo []model.Stock, day int) map[string]model.BollingerBand {
-	if len(stockInfo) < day {
-		return map[string]model.BollingerBand{} // Return empty map if not enough data
-	}
+	return h.CalculateBollingerBandsWithConfig(stockInfo, model.BollingerConfig{
+		Period:            day,
+		Multiplier:        2,
+		IncludeCurrentDay: false,
+	})
+}
 
+// CalculateBollingerBandsWithConfig computes the bands with rolling sums in a single pass.
+// The stocks must be sorted by date ascending.
+func (h *ScoringLogicImpl) CalculateBollingerBandsWithConfig(stockInfo []model.Stock, config model.BollingerConfig) map[string]model.BollingerBand {
 	mapDayPrices := make(map[string]model.BollingerBand)
-
-	for i := day; i < len(stockInfo); i++ { // Start at 'day' for correct rolling window
-		// Compute moving average
-		sum := 0.0
-		for j := i - day; j < i; j++ { // Window spans 'i-day' to 'i-1'
-			sum += stockInfo[j].Close
-		}
-		average := sum / float64(day)
-
-		// Compute standard deviation
-		sumSquares := 0.0
-		for j := i - day; j < i; j++ {
-			diff := stockInfo[j].Close - average
-			sumSquares += diff * diff
-		}
-		standardDeviation := math.Sqrt(sumSquares / float64(day)) // Use day (population) or day-1 (sample)
-
-		// Compute bands
-		upperBand := average + 2*standardDeviation
-		lowerBand := average - 2*standardDeviation
-
-		// Store result
-		mapDayPrices[stockInfo[i].Date] = model.BollingerBand{
-			Date:      stockInfo[i].Date,
-			UpperBand: upperBand,
-			Average:   average,
-			LowerBand: lowerBand,
-		}
+	if config.Period <= 0 || len(stockInfo) < config.Period {
+		return mapDayPrices // Return empty map if not enough data
+	}
+	bands := indicators.Bollinger(stockInfo, indicators.BollingerOptions{
+		Period:         config.Period,
+		K:              config.Multiplier,
+		Sample:         false,
+		ExcludeCurrent: !config.IncludeCurrentDay,
+	})
+	for _, band := range bands {
+		mapDayPrices[band.Date] = band
 	}
-
 	return mapDayPrices
 }
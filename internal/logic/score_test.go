@@ -277,6 +277,78 @@ func TestCalculateBollingBands(t *testing.T) {
 	})
 }
 
+func TestCalculateBollingerBandsWithConfig(t *testing.T) {
+	mockService := &ScoringLogicImpl{}
+	stockInfo := []model.Stock{}
+	for i := 0; i < 10; i++ {
+		stockInfo = append(stockInfo, model.Stock{
+			Id:    10002,
+			Date:  fmt.Sprintf("2022-01-%02d", i+1),
+			Close: float64(9 + (i%2)*2), // 9 or 11
+		})
+	}
+	t.Run("Same result as the default when excluding the current day", func(t *testing.T) {
+		bands := mockService.CalculateBollingerBandsWithConfig(stockInfo, model.BollingerConfig{Period: 4, Multiplier: 2})
+		legacy := mockService.CalculateBollingerBands(stockInfo, 4)
+		if len(bands) != len(legacy) {
+			t.Errorf("Expected %d bands and not %d", len(legacy), len(bands))
+		}
+		for date, band := range legacy {
+			if !almostEqual(band.UpperBand, bands[date].UpperBand, 0.001) || !almostEqual(band.LowerBand, bands[date].LowerBand, 0.001) {
+				t.Errorf("Expected the same band for %s", date)
+			}
+		}
+	})
+	t.Run("Including the current day", func(t *testing.T) {
+		bands := mockService.CalculateBollingerBandsWithConfig(stockInfo, model.BollingerConfig{Period: 4, Multiplier: 2, IncludeCurrentDay: true})
+		if len(bands) != 7 {
+			t.Errorf("Expected 7 Bollinger bands and not %d", len(bands))
+		}
+		if _, found := bands["2022-01-04"]; !found {
+			t.Errorf("Expected the first band on the 4th day")
+		}
+	})
+	t.Run("With a multiplier of 3", func(t *testing.T) {
+		bands := mockService.CalculateBollingerBandsWithConfig(stockInfo, model.BollingerConfig{Period: 4, Multiplier: 3})
+		if !almostEqual(7.000, bands["2022-01-05"].LowerBand, 0.001) {
+			t.Errorf("Expected LowerBand must not be %f", bands["2022-01-05"].LowerBand)
+		}
+		if !almostEqual(13.000, bands["2022-01-05"].UpperBand, 0.001) {
+			t.Errorf("Expected UpperBand must not be %f", bands["2022-01-05"].UpperBand)
+		}
+	})
+	t.Run("With an invalid period", func(t *testing.T) {
+		bands := mockService.CalculateBollingerBandsWithConfig(stockInfo, model.BollingerConfig{Period: 0, Multiplier: 2})
+		if len(bands) != 0 {
+			t.Errorf("Expected 0 Bollinger band and not %d", len(bands))
+		}
+	})
+}
+
+func TestApplyHintPenalty(t *testing.T) {
+	t.Run("Default penalties", func(t *testing.T) {
+		total, penalty := (&ScoringLogicImpl{}).ApplyHintPenalty(30, []string{model.Hint_sector, model.Hint_rsi})
+		if total != 20 || penalty != 10 {
+			t.Errorf("Expected a total of 20 after a penalty of 10 and not %d after %d", total, penalty)
+		}
+	})
+	t.Run("Configured penalties", func(t *testing.T) {
+		scoringLogic := &ScoringLogicImpl{HintPenalties: map[string]int{model.Hint_sector: 0, model.Hint_next_close: 40}}
+		total, penalty := scoringLogic.ApplyHintPenalty(30, []string{model.Hint_sector, model.Hint_next_close})
+		if total != 0 || penalty != 40 {
+			t.Errorf("Expected the total to stop at 0 after a penalty of 40 and not %d after %d", total, penalty)
+		}
+		if penalty := scoringLogic.GetHintPenalty([]string{model.Hint_next_close}); penalty != 40 {
+			t.Errorf("Expected a penalty of 40 and not %d", penalty)
+		}
+	})
+	t.Run("No hint", func(t *testing.T) {
+		if total, penalty := (&ScoringLogicImpl{}).ApplyHintPenalty(30, nil); total != 30 || penalty != 0 {
+			t.Errorf("Expected the same total and not %d after %d", total, penalty)
+		}
+	})
+}
+
 func almostEqual(a, b, tolerance float64) bool {
 	return math.Abs(a-b) <= tolerance
 }
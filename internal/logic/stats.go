@@ -0,0 +1,175 @@
+package logic
+
+import (
+	"math"
+	"sort"
+	"stockgame/internal/model"
+)
+
+// RoundAccuracy compares the prices guessed in a round with the actual closes
+type RoundAccuracy struct {
+	AbsolutePercentageErrors []float64 // One per day guessed, in percent of the actual close
+	DirectionHits            int       // Days guessed in the same direction as the actual close
+	Days                     int       // Days compared
+}
+
+// GetRoundAccuracy compares each guessed price with the actual close of the same day. The
+// direction of the first day is taken from the last close shown. A round without the last
+// close shown has nothing to compare and returns zero days.
+func GetRoundAccuracy(round model.Round) RoundAccuracy {
+	accuracy := RoundAccuracy{}
+	if round.LastClose == 0 {
+		return accuracy
+	}
+	previousGuess := round.LastClose
+	previousActual := round.LastClose
+	for i, guess := range round.DayPrice {
+		if i >= len(round.Actual) {
+			break
+		}
+		actual := round.Actual[i]
+		if actual == 0 {
+			break
+		}
+		accuracy.AbsolutePercentageErrors = append(accuracy.AbsolutePercentageErrors, math.Abs(guess.Price-actual)/actual*100)
+		if getDirection(previousGuess, guess.Price) == getDirection(previousActual, actual) {
+			accuracy.DirectionHits++
+		}
+		accuracy.Days++
+		previousGuess = guess.Price
+		previousActual = actual
+	}
+	return accuracy
+}
+
+// GetPlayerStats summarizes the rounds of a player. The MAPE over time is grouped by the
+// UTC day the rounds were played, oldest first, and the sectors are sorted by name.
+func GetPlayerStats(rounds []model.Round) model.PlayerStats {
+	stats := model.PlayerStats{
+		Rounds:         len(rounds),
+		AnalyzedRounds: len(rounds),
+		MAPEOverTime:   []model.MAPEPoint{},
+		Sectors:        []model.SectorStats{},
+	}
+	if len(rounds) == 0 {
+		return stats
+	}
+	var totals, lowHighs, openCloses, bollingers, directions []float64
+	var allErrors []float64
+	hits, days := 0, 0
+	errorsByDate := map[string][]float64{}
+	roundsByDate := map[string]int{}
+	type sectorSums struct {
+		rounds int
+		score  int
+		errors []float64
+	}
+	sectors := map[string]*sectorSums{}
+	for _, round := range rounds {
+		totals = append(totals, float64(round.Score.Total))
+		lowHighs = append(lowHighs, float64(round.Score.InLowHigh))
+		openCloses = append(openCloses, float64(round.Score.InOpenClose))
+		bollingers = append(bollingers, float64(round.Score.InBollinger))
+		directions = append(directions, float64(round.Score.InDirection))
+
+		sectorName := round.Sector
+		if sectorName == "" {
+			sectorName = "Unknown"
+		}
+		sector, found := sectors[sectorName]
+		if !found {
+			sector = &sectorSums{}
+			sectors[sectorName] = sector
+		}
+		sector.rounds++
+		sector.score += round.Score.Total
+
+		accuracy := GetRoundAccuracy(round)
+		if accuracy.Days == 0 {
+			continue
+		}
+		allErrors = append(allErrors, accuracy.AbsolutePercentageErrors...)
+		hits += accuracy.DirectionHits
+		days += accuracy.Days
+		sector.errors = append(sector.errors, accuracy.AbsolutePercentageErrors...)
+		date := round.CreatedAt.UTC().Format("2006-01-02")
+		errorsByDate[date] = append(errorsByDate[date], accuracy.AbsolutePercentageErrors...)
+		roundsByDate[date]++
+	}
+	stats.Score = model.ScoreStats{
+		Total:       componentStats(totals),
+		InLowHigh:   componentStats(lowHighs),
+		InOpenClose: componentStats(openCloses),
+		InBollinger: componentStats(bollingers),
+		InDirection: componentStats(directions),
+	}
+	if days > 0 {
+		directionalAccuracy := roundTo(float64(hits)/float64(days), 3)
+		stats.DirectionalAccuracy = &directionalAccuracy
+		mape := roundTo(mean(allErrors), 2)
+		stats.MAPE = &mape
+	}
+	for date, errors := range errorsByDate {
+		stats.MAPEOverTime = append(stats.MAPEOverTime, model.MAPEPoint{
+			Date:   date,
+			MAPE:   roundTo(mean(errors), 2),
+			Rounds: roundsByDate[date],
+		})
+	}
+	sort.Slice(stats.MAPEOverTime, func(i, j int) bool {
+		return stats.MAPEOverTime[i].Date < stats.MAPEOverTime[j].Date
+	})
+	for name, sector := range sectors {
+		sectorStats := model.SectorStats{
+			Sector:       name,
+			Rounds:       sector.rounds,
+			AverageScore: roundTo(float64(sector.score)/float64(sector.rounds), 1),
+		}
+		if len(sector.errors) > 0 {
+			mape := roundTo(mean(sector.errors), 2)
+			sectorStats.MAPE = &mape
+		}
+		stats.Sectors = append(stats.Sectors, sectorStats)
+	}
+	sort.Slice(stats.Sectors, func(i, j int) bool {
+		return stats.Sectors[i].Sector < stats.Sectors[j].Sector
+	})
+	return stats
+}
+
+func componentStats(values []float64) model.ComponentStats {
+	return model.ComponentStats{
+		Average: roundTo(mean(values), 1),
+		Median:  roundTo(median(values), 1),
+	}
+}
+
+func mean(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sum := 0.0
+	for _, value := range values {
+		sum += value
+	}
+	return sum / float64(len(values))
+}
+
+// median does not modify the values
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[middle-1] + sorted[middle]) / 2
+	}
+	return sorted[middle]
+}
+
+func roundTo(value float64, decimals int) float64 {
+	factor := math.Pow(10, float64(decimals))
+	return math.Round(value*factor) / factor
+}
@@ -0,0 +1,114 @@
+package logic
+
+import (
+	"stockgame/internal/model"
+	"testing"
+	"time"
+)
+
+func TestGetRoundAccuracy(t *testing.T) {
+	t.Run("Old round without actual closes", func(t *testing.T) {
+		accuracy := GetRoundAccuracy(model.Round{DayPrice: []model.DayPrice{{Day: 1, Price: 100}}})
+		if accuracy.Days != 0 {
+			t.Errorf("Expected nothing to compare and not %+v", accuracy)
+		}
+	})
+	t.Run("Errors and directions", func(t *testing.T) {
+		accuracy := GetRoundAccuracy(model.Round{
+			LastClose: 100,
+			DayPrice:  []model.DayPrice{{Day: 1, Price: 110}, {Day: 2, Price: 105}, {Day: 3, Price: 90}},
+			Actual:    []float64{100, 105}, // Flat then up, the third day is missing
+		})
+		if accuracy.Days != 2 {
+			t.Fatalf("Expected 2 days compared and not %d", accuracy.Days)
+		}
+		if accuracy.AbsolutePercentageErrors[0] != 10 || accuracy.AbsolutePercentageErrors[1] != 0 {
+			t.Errorf("Expected errors of 10%% and 0%% and not %v", accuracy.AbsolutePercentageErrors)
+		}
+		// Guessed up then down, the actual was flat then up
+		if accuracy.DirectionHits != 0 {
+			t.Errorf("Expected no direction hit and not %d", accuracy.DirectionHits)
+		}
+	})
+}
+
+func TestGetPlayerStats(t *testing.T) {
+	t.Run("No round", func(t *testing.T) {
+		stats := GetPlayerStats(nil)
+		if stats.Rounds != 0 || stats.MAPE != nil || stats.DirectionalAccuracy != nil {
+			t.Errorf("Expected empty stats and not %+v", stats)
+		}
+		if stats.MAPEOverTime == nil || stats.Sectors == nil {
+			t.Errorf("Expected empty lists and not nil")
+		}
+	})
+	t.Run("Summary", func(t *testing.T) {
+		day1 := time.Date(2024, 3, 1, 22, 0, 0, 0, time.UTC)
+		day2 := time.Date(2024, 3, 2, 8, 0, 0, 0, time.UTC)
+		rounds := []model.Round{
+			{
+				Sector:    "Technology",
+				Score:     model.UserScoreResponse{Total: 50, InLowHigh: 20, InOpenClose: 10, InBollinger: 10, InDirection: 10},
+				LastClose: 100,
+				DayPrice:  []model.DayPrice{{Day: 1, Price: 110}},
+				Actual:    []float64{100},
+				CreatedAt: day2,
+			},
+			{
+				Sector:    "Technology",
+				Score:     model.UserScoreResponse{Total: 10, InLowHigh: 10},
+				LastClose: 100,
+				DayPrice:  []model.DayPrice{{Day: 1, Price: 110}},
+				Actual:    []float64{110},
+				CreatedAt: day1,
+			},
+			{
+				// Without the last close shown
+				Score:     model.UserScoreResponse{Total: 30, InLowHigh: 30},
+				CreatedAt: day1,
+			},
+		}
+		stats := GetPlayerStats(rounds)
+		if stats.Rounds != 3 {
+			t.Errorf("Expected 3 rounds and not %d", stats.Rounds)
+		}
+		if stats.Score.Total.Average != 30 || stats.Score.Total.Median != 30 {
+			t.Errorf("Expected an average and median total of 30 and not %+v", stats.Score.Total)
+		}
+		if stats.Score.InLowHigh.Average != 20 || stats.Score.InDirection.Median != 0 {
+			t.Errorf("Unexpected component stats %+v", stats.Score)
+		}
+		if stats.MAPE == nil || *stats.MAPE != 5 {
+			t.Errorf("Expected a MAPE of 5%% and not %v", stats.MAPE)
+		}
+		if stats.DirectionalAccuracy == nil || *stats.DirectionalAccuracy != 0.5 {
+			t.Errorf("Expected a directional accuracy of 0.5 and not %v", stats.DirectionalAccuracy)
+		}
+		expectedOverTime := []model.MAPEPoint{
+			{Date: "2024-03-01", MAPE: 0, Rounds: 1},
+			{Date: "2024-03-02", MAPE: 10, Rounds: 1},
+		}
+		if len(stats.MAPEOverTime) != 2 || stats.MAPEOverTime[0] != expectedOverTime[0] || stats.MAPEOverTime[1] != expectedOverTime[1] {
+			t.Errorf("Expected %+v and not %+v", expectedOverTime, stats.MAPEOverTime)
+		}
+		if len(stats.Sectors) != 2 {
+			t.Fatalf("Expected 2 sectors and not %+v", stats.Sectors)
+		}
+		if stats.Sectors[0].Sector != "Technology" || stats.Sectors[0].Rounds != 2 || stats.Sectors[0].AverageScore != 30 {
+			t.Errorf("Unexpected sector %+v", stats.Sectors[0])
+		}
+		if stats.Sectors[1].Sector != "Unknown" || stats.Sectors[1].MAPE != nil {
+			t.Errorf("Expected the rounds without sector under Unknown and without MAPE and not %+v", stats.Sectors[1])
+		}
+	})
+}
+
+func TestMedian(t *testing.T) {
+	values := []float64{4, 1, 3, 2}
+	if result := median(values); result != 2.5 {
+		t.Errorf("Expected 2.5 and not %v", result)
+	}
+	if values[0] != 4 {
+		t.Errorf("Expected the values to be left unsorted")
+	}
+}
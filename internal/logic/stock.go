@@ -1,44 +1,195 @@
 package logic
 
-import "stockgame/internal/model"
+import (
+	"fmt"
+	"slices"
+	"stockgame/internal/model"
+	"strings"
+	"time"
+)
 
 type StockLogic interface {
-	IsStocksValid(stocks []model.StockPublic, numberOfDays int) (isValid bool, upperBound int)
+	GetWindowBounds(stocks []model.StockPublic, windowDays int) (eligible []model.StockPublic, upperBound int, reason string)
+	CheckEligibility(info model.StockInfo, window []model.StockPublic) (reason string)
 }
 
 type StockLogicImpl struct {
 	StockLogic
+	Eligibility model.EligibilityConfig // Zero value uses model.DefaultEligibilityConfig
 }
 
-func (s *StockLogicImpl) IsStocksValid(stocks []model.StockPublic, numberOfDays int) (isValid bool, upperBound int) {
-	isValid = true
-	upperBound = 0
-	volume := 0
-	for _, stock := range stocks {
-		volume += stock.Volume
+// EligibilityRule returns the reason why a stock cannot be served, empty when it can
+type EligibilityRule func(info model.StockInfo, stocks []model.StockPublic) (reason string)
+
+func (s *StockLogicImpl) config() model.EligibilityConfig {
+	if s.Eligibility.IsZero() {
+		return model.DefaultEligibilityConfig
 	}
+	return s.Eligibility
+}
+
+// GetWindowBounds keeps the stocks inside the configured date range. The upper bound is
+// the exclusive maximum index a window of windowDays can start at in the eligible stocks.
+func (s *StockLogicImpl) GetWindowBounds(stocks []model.StockPublic, windowDays int) (eligible []model.StockPublic, upperBound int, reason string) {
+	config := s.config()
 	if len(stocks) == 0 {
-		isValid = false
-		return
+		return nil, 0, "no price"
+	}
+	eligible = FilterDateRange(stocks, config.StartDate, config.EndDate)
+	if len(eligible) < windowDays {
+		return nil, 0, fmt.Sprintf("%d days available, %d needed", len(eligible), windowDays)
+	}
+	return eligible, len(eligible) - windowDays + 1, ""
+}
+
+// CheckEligibility applies every configured rule on the window, which must contain the
+// days shown to the player and the days to guess.
+func (s *StockLogicImpl) CheckEligibility(info model.StockInfo, window []model.StockPublic) (reason string) {
+	for _, rule := range RulesFromConfig(s.config()) {
+		if reason = rule(info, window); reason != "" {
+			return reason
+		}
+	}
+	return ""
+}
+
+// RulesFromConfig builds the rules enabled by the configuration. A day opening at zero
+// is a data error so it is always rejected.
+func RulesFromConfig(config model.EligibilityConfig) []EligibilityRule {
+	rules := []EligibilityRule{NoZeroOpenRule()}
+	if config.MinPrice > 0 {
+		rules = append(rules, MinPriceRule(config.MinPrice))
+	}
+	if config.MinVolume > 0 {
+		rules = append(rules, MinVolumeRule(config.MinVolume))
+	}
+	if config.MaxMissingDays > 0 {
+		rules = append(rules, MaxMissingDaysRule(config.MaxMissingDays))
+	}
+	if len(config.ExcludedSymbols) > 0 {
+		rules = append(rules, ExcludedSymbolsRule(config.ExcludedSymbols))
+	}
+	if config.Etf == model.EtfOnly || config.Etf == model.EtfExclude {
+		rules = append(rules, EtfRule(config.Etf))
+	}
+	return rules
+}
+
+func NoZeroOpenRule() EligibilityRule {
+	return func(info model.StockInfo, stocks []model.StockPublic) string {
+		for _, stock := range stocks {
+			if stock.Open == 0 {
+				return fmt.Sprintf("open price at zero on %s", stock.Date)
+			}
+		}
+		return ""
+	}
+}
+
+func MinPriceRule(minPrice float64) EligibilityRule {
+	return func(info model.StockInfo, stocks []model.StockPublic) string {
+		for _, stock := range stocks {
+			if stock.Close < minPrice {
+				return fmt.Sprintf("close of %.2f on %s below %.2f", stock.Close, stock.Date, minPrice)
+			}
+		}
+		return ""
+	}
+}
+
+func MinVolumeRule(minVolume int) EligibilityRule {
+	return func(info model.StockInfo, stocks []model.StockPublic) string {
+		if len(stocks) == 0 {
+			return ""
+		}
+		volume := 0
+		for _, stock := range stocks {
+			volume += stock.Volume
+		}
+		if volumeAverage := volume / len(stocks); volumeAverage < minVolume {
+			return fmt.Sprintf("average volume of %d below %d", volumeAverage, minVolume)
+		}
+		return ""
+	}
+}
+
+// MaxMissingDaysRule rejects the stocks with a hole in the data, e.g. a trading halt.
+// The gap is in calendar days so a weekend is a gap of 3 days.
+func MaxMissingDaysRule(maxMissingDays int) EligibilityRule {
+	return func(info model.StockInfo, stocks []model.StockPublic) string {
+		for i := 1; i < len(stocks); i++ {
+			previous, errPrevious := parseDay(stocks[i-1].Date)
+			current, errCurrent := parseDay(stocks[i].Date)
+			if errPrevious != nil || errCurrent != nil {
+				return fmt.Sprintf("invalid date %q or %q", stocks[i-1].Date, stocks[i].Date)
+			}
+			if gap := int(current.Sub(previous).Hours() / 24); gap > maxMissingDays {
+				return fmt.Sprintf("%d days without price after %s, maximum %d", gap, stocks[i-1].Date, maxMissingDays)
+			}
+		}
+		return ""
 	}
-	volumeAverage := volume / len(stocks)
-	if volumeAverage < 25000 {
-		isValid = false
-		return
+}
+
+func ExcludedSymbolsRule(excludedSymbols []string) EligibilityRule {
+	return func(info model.StockInfo, stocks []model.StockPublic) string {
+		if slices.ContainsFunc(excludedSymbols, func(symbol string) bool { return strings.EqualFold(symbol, info.Symbol) }) {
+			return fmt.Sprintf("symbol %s excluded", info.Symbol)
+		}
+		return ""
 	}
-	upperBound = len(stocks) - numberOfDays
-	if upperBound <= 0 {
-		isValid = false
-		upperBound = 0
-		return
+}
+
+func EtfRule(etf string) EligibilityRule {
+	return func(info model.StockInfo, stocks []model.StockPublic) string {
+		if etf == model.EtfOnly && !info.IsEtf {
+			return "not an ETF"
+		}
+		if etf == model.EtfExclude && info.IsEtf {
+			return "ETF excluded"
+		}
+		return ""
 	}
-	// Check if some stock in the slice has an open price to zero
+}
+
+// FilterDateRange keeps the stocks between the two dates (inclusive). An empty date
+// does not bound the range.
+func FilterDateRange(stocks []model.StockPublic, startDate string, endDate string) []model.StockPublic {
+	if startDate == "" && endDate == "" {
+		return stocks
+	}
+	filtered := []model.StockPublic{}
 	for _, stock := range stocks {
-		if stock.Open == 0 {
-			upperBound = 0
-			isValid = false
-			return
+		day := dayPart(stock.Date)
+		if (startDate == "" || day >= startDate) && (endDate == "" || day <= endDate) {
+			filtered = append(filtered, stock)
+		}
+	}
+	return filtered
+}
+
+// SplitAtDate splits stocks sorted by date into the days until the date (inclusive) and the days after it
+func SplitAtDate(stocks []model.Stock, date string) (before []model.Stock, after []model.Stock) {
+	before = []model.Stock{}
+	after = []model.Stock{}
+	for _, stock := range stocks {
+		if dayPart(stock.Date) <= dayPart(date) {
+			before = append(before, stock)
+		} else {
+			after = append(after, stock)
 		}
 	}
 	return
 }
+
+// dayPart removes the time the database driver can add after the date
+func dayPart(date string) string {
+	if len(date) > len(time.DateOnly) {
+		return date[:len(time.DateOnly)]
+	}
+	return date
+}
+
+func parseDay(date string) (time.Time, error) {
+	return time.Parse(time.DateOnly, dayPart(date))
+}
@@ -5,69 +5,118 @@ import (
 	"testing"
 )
 
-func TestIsStocksValid(t *testing.T) {
-	t.Run("WithValidStocks", func(t *testing.T) {
+func TestCheckEligibility(t *testing.T) {
+	stocks := []model.StockPublic{
+		{Date: "2022-01-03", Open: 10, Close: 10, Volume: 30000},
+		{Date: "2022-01-04", Open: 10, Close: 12, Volume: 30000},
+		{Date: "2022-01-05", Open: 12, Close: 11, Volume: 30000},
+		{Date: "2022-01-10", Open: 11, Close: 11, Volume: 30000},
+	}
+	apple := model.StockInfo{Symbol: "AAPL"}
+	t.Run("Default configuration", func(t *testing.T) {
 		mockService := &StockLogicImpl{}
-		stocks := []model.StockPublic{
-			{Date: "2022-01-01", Open: 100, High: 105, Low: 95, Close: 100, Volume: 30000},
-			{Date: "2022-01-02", Open: 102, High: 107, Low: 97, Close: 102, Volume: 50000},
-			{Date: "2022-01-03", Open: 105, High: 110, Low: 100, Close: 105, Volume: 60000},
+		if reason := mockService.CheckEligibility(apple, stocks); reason != "" {
+			t.Errorf("Expected the stocks to be eligible and not %q", reason)
 		}
-		numberOfDays := 2
-		isValid, upperBound := mockService.IsStocksValid(stocks, numberOfDays)
-		if !isValid {
-			t.Errorf("Expected stocks to be valid")
+	})
+	t.Run("Each rule gives a reason", func(t *testing.T) {
+		configs := map[string]model.EligibilityConfig{
+			"min price":        {MinPrice: 10.5, Etf: model.EtfAny},
+			"min volume":       {MinVolume: 50000, Etf: model.EtfAny},
+			"missing days":     {MaxMissingDays: 4, Etf: model.EtfAny},
+			"excluded symbols": {ExcludedSymbols: []string{"msft", "aapl"}, Etf: model.EtfAny},
+			"etf only":         {Etf: model.EtfOnly},
 		}
-		if upperBound != len(stocks)-numberOfDays {
-			t.Errorf("Expected upper bound to be %d and not %d", len(stocks)-numberOfDays, upperBound)
+		for name, config := range configs {
+			mockService := &StockLogicImpl{Eligibility: config}
+			if reason := mockService.CheckEligibility(apple, stocks); reason == "" {
+				t.Errorf("Expected the %s rule to reject the stocks", name)
+			}
 		}
 	})
-	t.Run("with low volumne", func(t *testing.T) {
-		mockService := &StockLogicImpl{}
-		stocks := []model.StockPublic{
-			{Date: "2022-01-01", Open: 100, High: 105, Low: 95, Close: 100, Volume: 1},
-			{Date: "2022-01-02", Open: 102, High: 107, Low: 97, Close: 102, Volume: 1},
-			{Date: "2022-01-03", Open: 105, High: 110, Low: 100, Close: 105, Volume: 1},
+	t.Run("Only the window is checked", func(t *testing.T) {
+		mockService := &StockLogicImpl{Eligibility: model.EligibilityConfig{MinPrice: 10.5, MaxMissingDays: 4, Etf: model.EtfAny}}
+		// The first day is below the minimum price and the last one comes after a hole
+		if reason := mockService.CheckEligibility(apple, stocks[1:3]); reason != "" {
+			t.Errorf("Expected the window to be eligible and not %q", reason)
 		}
-		numberOfDays := 2
-		isValid, upperBound := mockService.IsStocksValid(stocks, numberOfDays)
-		if isValid {
-			t.Errorf("Expected stocks to be valid because of low volume")
-		}
-		if upperBound != 0 {
-			t.Errorf("Expected upper bound to be 0, not %d because it is invalid enough volume", upperBound)
+	})
+	t.Run("Weekends are not missing days", func(t *testing.T) {
+		mockService := &StockLogicImpl{Eligibility: model.EligibilityConfig{MaxMissingDays: 5, Etf: model.EtfAny}}
+		if reason := mockService.CheckEligibility(apple, stocks); reason != "" {
+			t.Errorf("Expected a gap of 5 days to be accepted and not %q", reason)
 		}
 	})
-	t.Run("with not enought day", func(t *testing.T) {
-		mockService := &StockLogicImpl{}
-		stocks := []model.StockPublic{
-			{Date: "2022-01-01", Open: 100, High: 105, Low: 95, Close: 100, Volume: 100000},
-			{Date: "2022-01-02", Open: 102, High: 107, Low: 97, Close: 102, Volume: 100000},
-			{Date: "2022-01-03", Open: 105, High: 110, Low: 100, Close: 105, Volume: 100000},
+	t.Run("Partial configuration", func(t *testing.T) {
+		// Without the ETF filter, the minimum price is still applied instead of the default volume
+		mockService := &StockLogicImpl{Eligibility: model.EligibilityConfig{MinPrice: 10.5}}
+		if reason := mockService.CheckEligibility(apple, stocks); reason == "" {
+			t.Errorf("Expected the min price rule to reject the stocks")
+		}
+		mockService = &StockLogicImpl{Eligibility: model.EligibilityConfig{MinPrice: 5}}
+		if reason := mockService.CheckEligibility(apple, []model.StockPublic{{Date: "2022-01-03", Open: 10, Close: 10, Volume: 100}}); reason != "" {
+			t.Errorf("Expected the default volume to be left out and not %q", reason)
 		}
-		numberOfDays := 4
-		isValid, upperBound := mockService.IsStocksValid(stocks, numberOfDays)
-		if isValid {
-			t.Errorf("Expected stocks to be invalid because not enough days")
+	})
+	t.Run("ETF excluded", func(t *testing.T) {
+		mockService := &StockLogicImpl{Eligibility: model.EligibilityConfig{Etf: model.EtfExclude}}
+		if reason := mockService.CheckEligibility(model.StockInfo{Symbol: "SPY", IsEtf: true}, stocks); reason != "ETF excluded" {
+			t.Errorf("Expected the ETF to be excluded and not %q", reason)
 		}
-		if upperBound != 0 {
-			t.Errorf("Expected upper bound to be 0, not %d because it is invalid enough day", upperBound)
+		if reason := mockService.CheckEligibility(apple, stocks); reason != "" {
+			t.Errorf("Expected the stock to be eligible and not %q", reason)
 		}
 	})
-	t.Run("with zero amount at open", func(t *testing.T) {
+}
+
+func TestGetWindowBounds(t *testing.T) {
+	stocks := []model.StockPublic{
+		{Date: "2022-01-03"},
+		{Date: "2022-01-04"},
+		{Date: "2022-01-05"},
+		{Date: "2022-01-06"},
+	}
+	t.Run("Every start with a complete window", func(t *testing.T) {
 		mockService := &StockLogicImpl{}
-		stocks := []model.StockPublic{
-			{Date: "2022-01-01", Open: 0, High: 105, Low: 95, Close: 100, Volume: 100000},
-			{Date: "2022-01-02", Open: 102, High: 107, Low: 97, Close: 102, Volume: 100000},
-			{Date: "2022-01-03", Open: 105, High: 110, Low: 100, Close: 105, Volume: 100000},
+		eligible, upperBound, reason := mockService.GetWindowBounds(stocks, 3)
+		if reason != "" || len(eligible) != 4 || upperBound != 2 {
+			t.Errorf("Expected 2 possible starts and not %d (%q)", upperBound, reason)
+		}
+		if _, upperBound, _ := mockService.GetWindowBounds(stocks, 4); upperBound != 1 {
+			t.Errorf("Expected the window to cover the whole history and not %d starts", upperBound)
 		}
-		numberOfDays := 2
-		isValid, upperBound := mockService.IsStocksValid(stocks, numberOfDays)
-		if isValid {
-			t.Errorf("Expected stocks to have all stock open above zero")
+		if _, _, reason := mockService.GetWindowBounds(stocks, 5); reason == "" {
+			t.Errorf("Expected not enough days for the window")
 		}
-		if upperBound != 0 {
-			t.Errorf("Expected upper bound to be 0, not %d.", upperBound)
+	})
+	t.Run("Date range", func(t *testing.T) {
+		mockService := &StockLogicImpl{Eligibility: model.EligibilityConfig{StartDate: "2022-01-04", EndDate: "2022-01-05", Etf: model.EtfAny}}
+		eligible, upperBound, reason := mockService.GetWindowBounds(stocks, 2)
+		if reason != "" || upperBound != 1 || len(eligible) != 2 || eligible[0].Date != "2022-01-04" {
+			t.Errorf("Expected the 2 days of the range and not %+v (%q)", eligible, reason)
 		}
 	})
 }
+
+func TestFilterDateRange(t *testing.T) {
+	stocks := []model.StockPublic{
+		{Date: "2022-01-03T00:00:00Z"},
+		{Date: "2022-01-04T00:00:00Z"},
+	}
+	filtered := FilterDateRange(stocks, "", "2022-01-03")
+	if len(filtered) != 1 || filtered[0].Date != "2022-01-03T00:00:00Z" {
+		t.Errorf("Expected only the first day and not %+v", filtered)
+	}
+}
+
+func TestSplitAtDate(t *testing.T) {
+	stocks := []model.Stock{
+		{Date: "2022-01-03T00:00:00Z"},
+		{Date: "2022-01-04T00:00:00Z"},
+		{Date: "2022-01-05T00:00:00Z"},
+	}
+	before, after := SplitAtDate(stocks, "2022-01-04")
+	if len(before) != 2 || len(after) != 1 || after[0].Date != "2022-01-05T00:00:00Z" {
+		t.Errorf("Expected 2 days until the date and 1 after and not %+v %+v", before, after)
+	}
+}
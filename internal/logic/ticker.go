@@ -0,0 +1,29 @@
+package logic
+
+import (
+	"math"
+	"stockgame/internal/model"
+	"time"
+)
+
+// Points of a correct answer, the speed bonus is added to them
+const tickerCorrectPoints = 50
+
+// Speed bonus of an immediate answer, it decreases to zero at model.Ticker_speed_seconds
+const tickerSpeedPoints = 50
+
+// GetTickerScore scores an answer given after the elapsed time. A wrong answer scores zero
+// however fast it is.
+func GetTickerScore(correct bool, elapsed time.Duration) model.TickerScore {
+	score := model.TickerScore{
+		Correct: correct,
+		Seconds: roundTo(max(elapsed.Seconds(), 0), 2),
+	}
+	if !correct {
+		return score
+	}
+	remaining := 1 - score.Seconds/model.Ticker_speed_seconds
+	score.Speed = int(math.Round(tickerSpeedPoints * max(remaining, 0)))
+	score.Total = tickerCorrectPoints + score.Speed
+	return score
+}
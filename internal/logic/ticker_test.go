@@ -0,0 +1,26 @@
+package logic
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGetTickerScore(t *testing.T) {
+	cases := []struct {
+		name     string
+		correct  bool
+		elapsed  time.Duration
+		expected int
+	}{
+		{"Immediate", true, 0, tickerCorrectPoints + tickerSpeedPoints},
+		{"Half the time", true, 15 * time.Second, tickerCorrectPoints + tickerSpeedPoints/2},
+		{"Slow", true, time.Minute, tickerCorrectPoints},
+		{"Wrong", false, time.Second, 0},
+	}
+	for _, c := range cases {
+		score := GetTickerScore(c.correct, c.elapsed)
+		if score.Total != c.expected || score.Correct != c.correct {
+			t.Errorf("%s: expected %d and not %+v", c.name, c.expected, score)
+		}
+	}
+}
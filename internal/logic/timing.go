@@ -0,0 +1,23 @@
+package logic
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+var ErrRoundExpired = errors.New("the time to submit the solution is over")
+
+// GetSpeedBonus checks a solution submitted after the elapsed time is within the limit in
+// seconds and returns its bonus, which decreases from the points to zero at the limit. A
+// limit of zero is not timed and has no bonus.
+func GetSpeedBonus(elapsed time.Duration, limit int, points int) (int, error) {
+	if limit <= 0 {
+		return 0, nil
+	}
+	seconds := max(elapsed.Seconds(), 0)
+	if seconds > float64(limit) {
+		return 0, ErrRoundExpired
+	}
+	return int(math.Round(float64(points) * (1 - seconds/float64(limit)))), nil
+}
@@ -0,0 +1,29 @@
+package logic
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestGetSpeedBonus(t *testing.T) {
+	cases := []struct {
+		name    string
+		elapsed time.Duration
+		limit   int
+		bonus   int
+		err     error
+	}{
+		{"Immediate", 0, 60, 20, nil},
+		{"Half of the limit", 30 * time.Second, 60, 10, nil},
+		{"At the limit", 60 * time.Second, 60, 0, nil},
+		{"Late", 61 * time.Second, 60, 0, ErrRoundExpired},
+		{"Not timed", time.Hour, 0, 0, nil},
+	}
+	for _, c := range cases {
+		bonus, err := GetSpeedBonus(c.elapsed, c.limit, 20)
+		if bonus != c.bonus || !errors.Is(err, c.err) {
+			t.Errorf("%s: expected %d, %v and not %d, %v", c.name, c.bonus, c.err, bonus, err)
+		}
+	}
+}
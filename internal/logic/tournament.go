@@ -0,0 +1,44 @@
+package logic
+
+import (
+	"sort"
+	"stockgame/internal/model"
+)
+
+// tieBreakComponents decide between players with the same total, in order. Landing between
+// the open and the close is the hardest so it comes first, the direction is the easiest.
+var tieBreakComponents = []func(score model.UserScoreResponse) int{
+	func(score model.UserScoreResponse) int { return score.InOpenClose },
+	func(score model.UserScoreResponse) int { return score.InLowHigh },
+	func(score model.UserScoreResponse) int { return score.InBollinger },
+	func(score model.UserScoreResponse) int { return score.InDirection },
+}
+
+// RankStandings sorts the standings by total then by the tie-break components and sets
+// their rank. Players equal on every component share the same rank and keep the order
+// in which they joined.
+func RankStandings(standings []model.TournamentStanding) {
+	sort.SliceStable(standings, func(i, j int) bool {
+		return compareStandings(standings[i], standings[j]) < 0
+	})
+	for i := range standings {
+		if i > 0 && compareStandings(standings[i-1], standings[i]) == 0 {
+			standings[i].Rank = standings[i-1].Rank
+		} else {
+			standings[i].Rank = i + 1
+		}
+	}
+}
+
+// compareStandings is negative when a ranks before b
+func compareStandings(a, b model.TournamentStanding) int {
+	if a.Score.Total != b.Score.Total {
+		return b.Score.Total - a.Score.Total
+	}
+	for _, component := range tieBreakComponents {
+		if difference := component(b.Score) - component(a.Score); difference != 0 {
+			return difference
+		}
+	}
+	return 0
+}
@@ -0,0 +1,33 @@
+package logic
+
+import (
+	"stockgame/internal/model"
+	"testing"
+)
+
+func TestRankStandings(t *testing.T) {
+	standings := []model.TournamentStanding{
+		{Entry: 1, Name: "Alice", Score: model.UserScoreResponse{Total: 50, InLowHigh: 30, InOpenClose: 10, InDirection: 10}},
+		{Entry: 2, Name: "Bob", Score: model.UserScoreResponse{Total: 80}},
+		{Entry: 3, Name: "Carol", Score: model.UserScoreResponse{Total: 50, InLowHigh: 10, InOpenClose: 20, InDirection: 20}},
+		{Entry: 4, Name: "Dave", Score: model.UserScoreResponse{Total: 50, InLowHigh: 30, InOpenClose: 10, InDirection: 10}},
+		{Entry: 5, Name: "Eve"},
+	}
+	RankStandings(standings)
+
+	expected := []struct {
+		name string
+		rank int
+	}{
+		{"Bob", 1},
+		{"Carol", 2}, // More points between the open and the close
+		{"Alice", 3},
+		{"Dave", 3}, // Tied on every component, keeps the order of entry
+		{"Eve", 5},
+	}
+	for i, standing := range standings {
+		if standing.Name != expected[i].name || standing.Rank != expected[i].rank {
+			t.Errorf("Expected %s ranked %d at %d and not %s ranked %d", expected[i].name, expected[i].rank, i, standing.Name, standing.Rank)
+		}
+	}
+}
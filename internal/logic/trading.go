@@ -0,0 +1,112 @@
+package logic
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"stockgame/internal/model"
+)
+
+var ErrInvalidTrade = errors.New("invalid trade")
+
+// Number of trading days in a year, used to annualize the Sharpe ratio
+const tradingDaysPerYear = 252
+
+// ApplyTrade executes the trade at the price. Shares are whole and there is no fee.
+func ApplyTrade(position model.TradingPosition, trade model.TradeRequest, price float64) (model.TradingPosition, int, error) {
+	if trade.Quantity < 0 {
+		return position, 0, fmt.Errorf("%w: the quantity cannot be negative", ErrInvalidTrade)
+	}
+	switch trade.Action {
+	case model.Trade_hold:
+		return position, 0, nil
+	case model.Trade_buy:
+		if price <= 0 {
+			return position, 0, fmt.Errorf("%w: no price to buy at", ErrInvalidTrade)
+		}
+		quantity := trade.Quantity
+		if quantity == 0 {
+			quantity = int(position.Cash / price)
+		}
+		cost := float64(quantity) * price
+		if cost > position.Cash {
+			return position, 0, fmt.Errorf("%w: %d shares cost %.2f, the cash is %.2f", ErrInvalidTrade, quantity, cost, position.Cash)
+		}
+		position.Cash -= cost
+		position.Shares += quantity
+		return position, quantity, nil
+	case model.Trade_sell:
+		quantity := trade.Quantity
+		if quantity == 0 {
+			quantity = position.Shares
+		}
+		if quantity > position.Shares {
+			return position, 0, fmt.Errorf("%w: %d shares held", ErrInvalidTrade, position.Shares)
+		}
+		position.Cash += float64(quantity) * price
+		position.Shares -= quantity
+		return position, quantity, nil
+	default:
+		return position, 0, fmt.Errorf("%w: the action must be buy, sell or hold", ErrInvalidTrade)
+	}
+}
+
+// GetTradingResult compares the decisions of the player with buying every share the cash
+// allows at the last close shown. The closes start with the last close shown and are
+// followed by the revealed closes, the position of decision i is held during day i+1.
+func GetTradingResult(initialCash float64, decisions []model.TradeDecision, closes []float64) model.TradingResult {
+	positions := make([]model.TradingPosition, len(decisions))
+	for i, decision := range decisions {
+		positions[i] = decision.TradingPosition
+	}
+	initial := model.TradingPosition{Cash: initialCash}
+	buyAndHold := make([]model.TradingPosition, len(decisions))
+	if len(closes) > 0 {
+		held, _, _ := ApplyTrade(initial, model.TradeRequest{Action: model.Trade_buy}, closes[0])
+		for i := range buyAndHold {
+			buyAndHold[i] = held
+		}
+	}
+	return model.TradingResult{
+		Strategy:   GetTradingPerformance(initialCash, positions, closes),
+		BuyAndHold: GetTradingPerformance(initialCash, buyAndHold, closes),
+	}
+}
+
+// GetTradingPerformance values the positions at the closes, see GetTradingResult for the
+// order of the closes
+func GetTradingPerformance(initialCash float64, positions []model.TradingPosition, closes []float64) model.TradingPerformance {
+	equity := []float64{initialCash}
+	for i, position := range positions {
+		if i+1 >= len(closes) {
+			break
+		}
+		equity = append(equity, position.Cash+float64(position.Shares)*closes[i+1])
+	}
+	final := equity[len(equity)-1]
+	performance := model.TradingPerformance{
+		FinalValue: roundTo(final, 2),
+		ProfitLoss: roundTo(final-initialCash, 2),
+		Equity:     make([]float64, len(equity)),
+	}
+	if initialCash > 0 {
+		performance.Return = roundTo((final/initialCash-1)*100, 2)
+	}
+	peak := equity[0]
+	var returns []float64
+	for i, value := range equity {
+		performance.Equity[i] = roundTo(value, 2)
+		peak = max(peak, value)
+		if peak > 0 {
+			performance.MaxDrawdown = max(performance.MaxDrawdown, roundTo((peak-value)/peak*100, 2))
+		}
+		if i > 0 && equity[i-1] > 0 {
+			returns = append(returns, value/equity[i-1]-1)
+		}
+	}
+	if deviation := standardDeviation(returns); deviation > 0 {
+		sharpe := roundTo(mean(returns)/deviation*math.Sqrt(tradingDaysPerYear), 2)
+		performance.Sharpe = &sharpe
+	}
+	return performance
+}
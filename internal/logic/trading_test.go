@@ -0,0 +1,76 @@
+package logic
+
+import (
+	"errors"
+	"slices"
+	"stockgame/internal/model"
+	"testing"
+)
+
+func TestApplyTrade(t *testing.T) {
+	position := model.TradingPosition{Cash: 1000}
+	t.Run("Buy everything", func(t *testing.T) {
+		bought, quantity, err := ApplyTrade(position, model.TradeRequest{Action: model.Trade_buy}, 300)
+		if err != nil || quantity != 3 || bought.Shares != 3 || bought.Cash != 100 {
+			t.Errorf("Expected 3 shares and 100 of cash and not %+v %d %v", bought, quantity, err)
+		}
+	})
+	t.Run("Sell a part", func(t *testing.T) {
+		sold, quantity, err := ApplyTrade(model.TradingPosition{Cash: 100, Shares: 3}, model.TradeRequest{Action: model.Trade_sell, Quantity: 2}, 50)
+		if err != nil || quantity != 2 || sold.Shares != 1 || sold.Cash != 200 {
+			t.Errorf("Expected 1 share and 200 of cash and not %+v %d %v", sold, quantity, err)
+		}
+	})
+	t.Run("Hold", func(t *testing.T) {
+		held, quantity, err := ApplyTrade(position, model.TradeRequest{Action: model.Trade_hold}, 300)
+		if err != nil || quantity != 0 || held != position {
+			t.Errorf("Expected the same position and not %+v %d %v", held, quantity, err)
+		}
+	})
+	invalids := map[string]model.TradeRequest{
+		"not enough cash":   {Action: model.Trade_buy, Quantity: 4},
+		"not enough shares": {Action: model.Trade_sell, Quantity: 1},
+		"negative quantity": {Action: model.Trade_buy, Quantity: -1},
+		"unknown action":    {Action: "short"},
+	}
+	for name, trade := range invalids {
+		if _, _, err := ApplyTrade(position, trade, 300); !errors.Is(err, ErrInvalidTrade) {
+			t.Errorf("%s: expected ErrInvalidTrade and not %v", name, err)
+		}
+	}
+}
+
+func TestGetTradingResult(t *testing.T) {
+	closes := []float64{100, 110, 90, 120}
+	// Sells at the top then buys back at the bottom
+	decisions := []model.TradeDecision{
+		{Day: 1, Action: model.Trade_buy, Quantity: 10, Price: 100, TradingPosition: model.TradingPosition{Cash: 0, Shares: 10}},
+		{Day: 2, Action: model.Trade_sell, Quantity: 10, Price: 110, TradingPosition: model.TradingPosition{Cash: 1100, Shares: 0}},
+		{Day: 3, Action: model.Trade_buy, Quantity: 12, Price: 90, TradingPosition: model.TradingPosition{Cash: 20, Shares: 12}},
+	}
+	result := GetTradingResult(1000, decisions, closes)
+
+	if !slices.Equal(result.Strategy.Equity, []float64{1000, 1100, 1100, 1460}) {
+		t.Errorf("Unexpected equity %v", result.Strategy.Equity)
+	}
+	if result.Strategy.ProfitLoss != 460 || result.Strategy.Return != 46 || result.Strategy.MaxDrawdown != 0 {
+		t.Errorf("Unexpected strategy %+v", result.Strategy)
+	}
+	if !slices.Equal(result.BuyAndHold.Equity, []float64{1000, 1100, 900, 1200}) {
+		t.Errorf("Unexpected buy and hold equity %v", result.BuyAndHold.Equity)
+	}
+	if result.BuyAndHold.MaxDrawdown != 18.18 {
+		t.Errorf("Expected a drawdown of 18.18%% and not %v", result.BuyAndHold.MaxDrawdown)
+	}
+	if result.Strategy.Sharpe == nil || result.BuyAndHold.Sharpe == nil || *result.Strategy.Sharpe <= *result.BuyAndHold.Sharpe {
+		t.Errorf("Expected a better Sharpe ratio than buy and hold and not %v %v", result.Strategy.Sharpe, result.BuyAndHold.Sharpe)
+	}
+}
+
+func TestGetTradingPerformanceFlat(t *testing.T) {
+	positions := []model.TradingPosition{{Cash: 1000}, {Cash: 1000}}
+	performance := GetTradingPerformance(1000, positions, []float64{100, 90, 80})
+	if performance.Sharpe != nil || performance.ProfitLoss != 0 || len(performance.Equity) != 3 {
+		t.Errorf("Expected a flat performance without Sharpe ratio and not %+v", performance)
+	}
+}
@@ -0,0 +1,33 @@
+package model
+
+// CalibrationBin groups the days guessed by the move predicted from the last close shown.
+// A calibrated player has a mean actual move close to the mean predicted move in every bin.
+type CalibrationBin struct {
+	Label         string  `json:"label"`
+	Count         int     `json:"count"`
+	MeanPredicted float64 `json:"meanPredicted"` // Mean predicted move from the last close shown, in percent
+	MeanActual    float64 `json:"meanActual"`    // Mean actual move from the last close shown, in percent
+}
+
+// Calibration measures how the prices guessed deviate from the actual closes. Every value
+// is in percent.
+type Calibration struct {
+	Rounds             int              `json:"rounds"`
+	Days               int              `json:"days"`
+	Bias               float64          `json:"bias"`                      // Mean signed error, positive when the guesses are too high
+	GuessVolatility    float64          `json:"guessVolatility"`           // Standard deviation of the daily returns guessed
+	RealizedVolatility float64          `json:"realizedVolatility"`        // Standard deviation of the actual daily returns of the same days
+	DispersionRatio    *float64         `json:"dispersionRatio,omitempty"` // Guessed over realized volatility, below 1 when too conservative
+	Curve              []CalibrationBin `json:"curve"`
+}
+
+type PlayerCalibration struct {
+	PlayerId string `json:"playerId"`
+	Calibration
+}
+
+// CalibrationReport is the calibration of every round and of each identified player
+type CalibrationReport struct {
+	Global  Calibration         `json:"global"`
+	Players []PlayerCalibration `json:"players"`
+}
@@ -0,0 +1,65 @@
+package model
+
+import "time"
+
+// BasketStock is the window of one symbol of a basket
+type BasketStock struct {
+	SymbolUUID string        `json:"symbolUUID"`
+	Stocks     []StockPublic `json:"stocks"`
+}
+
+// BasketWindow is a basket of symbols served over the same dates
+type BasketWindow struct {
+	Id        string        `json:"id"`        // Id of the served basket, required to submit a solution
+	AfterDate string        `json:"afterDate"` // The last day shown, the same for every symbol
+	Stocks    []BasketStock `json:"stocks"`
+}
+
+type BasketWeight struct {
+	SymbolUUID string  `json:"symbolUUID"`
+	Weight     float64 `json:"weight"`
+}
+
+// BasketSolutionRequest either ranks the symbols from the best future return to the worst
+// or allocates weights to them
+type BasketSolutionRequest struct {
+	BasketId string         `json:"basketId"`
+	Ranking  []string       `json:"ranking,omitempty"`
+	Weights  []BasketWeight `json:"weights,omitempty"`
+}
+
+// ServedBasket records the basket served to a player, only one solution is accepted for it
+type ServedBasket struct {
+	Id          string
+	PlayerId    string
+	AfterDate   string
+	SymbolUUIDs []string
+	ServedAt    time.Time
+	SolvedAt    *time.Time
+}
+
+// BasketScore scores a ranking on its Spearman correlation with the realized returns and an
+// allocation on its return between the worst and the best symbol. The returns are in percent.
+type BasketScore struct {
+	Method            string   `json:"method"` // Basket_method_rank or Basket_method_weights
+	Spearman          *float64 `json:"spearman,omitempty"`
+	PortfolioReturn   *float64 `json:"portfolioReturn,omitempty"`
+	EqualWeightReturn float64  `json:"equalWeightReturn"`
+	BestReturn        float64  `json:"bestReturn"`
+	WorstReturn       float64  `json:"worstReturn"`
+	Total             int      `json:"total"` // From 0 to 100
+}
+
+type BasketResult struct {
+	SymbolUUID string  `json:"symbolUUID"`
+	Symbol     string  `json:"symbol"`
+	Name       string  `json:"name"`
+	Return     float64 `json:"return"` // From the last close shown to the last day to guess
+	Rank       int     `json:"rank"`   // 1 for the best return
+	Stocks     []Stock `json:"stocks"`
+}
+
+type BasketSolutionResponse struct {
+	Score   BasketScore    `json:"score"`
+	Results []BasketResult `json:"results"` // From the best return to the worst
+}
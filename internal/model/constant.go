@@ -2,3 +2,44 @@ package model
 
 const Number_initial_stock_shown = 40
 const User_stock_to_guess = 10
+const Direction_up = "up"
+const Direction_down = "down"
+const Direction_flat = "flat"
+const Band_coverage_percent = 80
+const Mode_price = "price"
+const Mode_candle = "candle"
+const Mode_direction = "direction"
+const Difficulty_easy = "easy"
+const Difficulty_medium = "medium"
+const Difficulty_hard = "hard"
+const Room_state_lobby = "lobby"
+const Room_state_playing = "playing"
+const Room_state_results = "results"
+const Room_event_state = "state"
+const Room_event_round_started = "round_started"
+const Room_event_results = "results"
+const Room_event_error = "error"
+const Room_message_start = "start"
+const Room_message_submit = "submit"
+const Room_round_seconds = 60
+const Trade_buy = "buy"
+const Trade_sell = "sell"
+const Trade_hold = "hold"
+const Trading_initial_cash = 10000
+const Ticker_candidates = 4
+const Ticker_speed_seconds = 30
+const Basket_min_symbols = 3
+const Basket_max_symbols = 5
+const Basket_method_rank = "rank"
+const Basket_method_weights = "weights"
+const Hint_sector = "sector"
+const Hint_next_close = "next_close"
+const Hint_rsi = "rsi"
+const Xp_per_round = 10
+const Xp_per_level = 100
+const Error_round_required = "round_required"
+const Error_round_expired = "round_expired"
+const Error_round_solved = "round_solved"
+const Error_round_not_served = "round_not_served"
+const Error_invalid_solution = "invalid_solution"
+const Guess_price_max_ratio = 5
@@ -0,0 +1,37 @@
+package model
+
+import "time"
+
+// DefaultHintPenalties is the number of points a hint removes from the total score
+var DefaultHintPenalties = map[string]int{
+	Hint_sector:     5,
+	Hint_next_close: 15,
+	Hint_rsi:        5,
+}
+
+// ServedRound is a window served by GET /stocks, the hints are recorded against it until
+// the solution is submitted. It is solved by the first solution accepted for it.
+type ServedRound struct {
+	Id         string     `json:"id"`
+	PlayerId   string     `json:"-"`
+	SymbolUUID string     `json:"symbolUUID"`
+	AfterDate  string     `json:"afterDate"`
+	Hints      []string   `json:"hints"` // In the order they were requested, each one at most once
+	Sector     string     `json:"-"`     // Sector of the stock, only filled when reading a served round
+	ServedAt   time.Time  `json:"servedAt"`
+	SolvedAt   *time.Time `json:"solvedAt,omitempty"` // Empty until a solution is accepted
+}
+
+type HintRequest struct {
+	Type string `json:"type"`
+}
+
+// HintResponse reveals the requested hint, only the field of its type is filled
+type HintResponse struct {
+	Type      string                      `json:"type"`
+	Sector    string                      `json:"sector,omitempty"`
+	NextClose *float64                    `json:"nextClose,omitempty"`
+	Overlay   map[string]IndicatorOverlay `json:"overlay,omitempty"`
+	Penalty   int                         `json:"penalty"` // Points removed from the total score by every hint requested
+	Hints     []string                    `json:"hints"`   // Every hint requested for the round
+}
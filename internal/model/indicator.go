@@ -0,0 +1,13 @@
+package model
+
+type IndicatorPoint struct {
+	Date  string  `json:"date"`
+	Value float64 `json:"value"`
+}
+
+// IndicatorOverlay is the result of one requested indicator. Some indicators have
+// more than one line (e.g. MACD has the macd, signal and histogram lines).
+type IndicatorOverlay struct {
+	Name  string                      `json:"name"`
+	Lines map[string][]IndicatorPoint `json:"lines"`
+}
@@ -0,0 +1,42 @@
+package model
+
+import "time"
+
+// Achievement is unlocked when Target rounds matched its rule
+type Achievement struct {
+	Id          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Target      int    `json:"target"`
+}
+
+// PlayerProgress is the progression of a player, updated after each saved round
+type PlayerProgress struct {
+	XP             int                  `json:"xp"`
+	Rounds         int                  `json:"rounds"`
+	CurrentStreak  int                  `json:"currentStreak"` // Consecutive days played, UTC, up to the last day played
+	LongestStreak  int                  `json:"longestStreak"`
+	LastPlayedDate string               `json:"lastPlayedDate"` // Empty before the first round
+	Counters       map[string]int       `json:"counters"`       // Rounds matching each achievement, by achievement id
+	Unlocked       map[string]time.Time `json:"unlocked"`       // Time each achievement was unlocked, by achievement id
+	Version        int                  `json:"-"`              // Incremented at each save, 0 before the first one
+}
+
+// AchievementProgress is an achievement with the progress of the player toward it
+type AchievementProgress struct {
+	Achievement
+	Count      int        `json:"count"`
+	UnlockedAt *time.Time `json:"unlockedAt,omitempty"`
+}
+
+// ProgressResponse is the progression of a player with every achievement
+type ProgressResponse struct {
+	XP             int                   `json:"xp"`
+	Level          int                   `json:"level"`
+	NextLevelXP    int                   `json:"nextLevelXP"` // XP needed to reach the next level
+	Rounds         int                   `json:"rounds"`
+	CurrentStreak  int                   `json:"currentStreak"` // 0 when the last day played is before yesterday
+	LongestStreak  int                   `json:"longestStreak"`
+	LastPlayedDate string                `json:"lastPlayedDate"`
+	Achievements   []AchievementProgress `json:"achievements"`
+}
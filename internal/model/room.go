@@ -0,0 +1,74 @@
+package model
+
+import "time"
+
+// RoomPlayer is a player of a room as seen by the other players. The player id is not
+// shared since it gives access to the statistics of the player.
+type RoomPlayer struct {
+	Seat      int    `json:"seat"`
+	Name      string `json:"name"`
+	Host      bool   `json:"host"`
+	Connected bool   `json:"connected"`
+	Submitted bool   `json:"submitted"` // Submitted a guess for the current round
+	Total     int    `json:"total"`     // Sum of the scores of the rounds played in the room
+}
+
+// RoomRound is the window every player of the room has to guess from
+type RoomRound struct {
+	Number   int           `json:"number"`
+	Stocks   []StockPublic `json:"stocks"`
+	Deadline time.Time     `json:"deadline"`
+}
+
+// RoomRanking is the rank of a player on the total of the rounds played in the room.
+// Players with the same total share the same rank.
+type RoomRanking struct {
+	Rank  int    `json:"rank"`
+	Seat  int    `json:"seat"`
+	Name  string `json:"name"`
+	Total int    `json:"total"`
+}
+
+type RoomState struct {
+	Code    string        `json:"code"`
+	State   string        `json:"state"`
+	Players []RoomPlayer  `json:"players"`
+	Round   *RoomRound    `json:"round,omitempty"`
+	Ranking []RoomRanking `json:"ranking"`
+}
+
+// RoomScore is the score of a player for one round, a player who did not submit scores 0
+type RoomScore struct {
+	Seat      int               `json:"seat"`
+	Name      string            `json:"name"`
+	Submitted bool              `json:"submitted"`
+	Score     UserScoreResponse `json:"score"`
+	DayPrice  []DayPrice        `json:"estimatedDayPrices"`
+}
+
+// RoomResults reveals the stock and the scores of a round, the best score first
+type RoomResults struct {
+	Round  int         `json:"round"`
+	Symbol string      `json:"symbol"`
+	Name   string      `json:"name"`
+	Stocks []Stock     `json:"stocks"`
+	Scores []RoomScore `json:"scores"`
+}
+
+// RoomEvent is sent to every player of a room when the room changes
+type RoomEvent struct {
+	Type    string       `json:"type"`
+	Room    RoomState    `json:"room"`
+	Results *RoomResults `json:"results,omitempty"`
+	Error   string       `json:"error,omitempty"`
+}
+
+// RoomMessage is sent by a player to start a round (host only) or submit a guess
+type RoomMessage struct {
+	Type     string     `json:"type"`
+	DayPrice []DayPrice `json:"estimatedDayPrices"`
+}
+
+type CreateRoomRequest struct {
+	Name string `json:"name"`
+}
@@ -0,0 +1,167 @@
+package model
+
+import "time"
+
+// Round is a solution submitted by a player and its score
+type Round struct {
+	Id               string            `json:"id"`
+	PlayerId         string            `json:"playerId"`
+	Mode             string            `json:"mode"`
+	Symbol           string            `json:"symbol"`
+	SymbolUUID       string            `json:"symbolUUID"`
+	AfterDate        string            `json:"afterDate"`
+	HistoryStartDate string            `json:"historyStartDate"` // First day shown
+	FutureEndDate    string            `json:"futureEndDate"`    // Last day guessed
+	Difficulty       string            `json:"difficulty"`
+	Score            UserScoreResponse `json:"score"`
+	DayPrice         []DayPrice        `json:"estimatedDayPrices"`
+	LastClose        float64           `json:"lastClose"`          // Last close shown
+	Actual           []float64         `json:"actualCloses"`       // Real closes of the days guessed
+	Sector           string            `json:"sector"`             // Sector of the stock, only filled when reading a round
+	Hints            []string          `json:"hints"`              // Hints requested before the solution
+	ServedAt         *time.Time        `json:"servedAt,omitempty"` // Time the window was served, empty for the tournament rounds
+	CreatedAt        time.Time         `json:"createdAt"`
+}
+
+// ScoreCount is the distribution of the stored scores around one score
+type ScoreCount struct {
+	Below int // Number of scores strictly lower
+	Equal int // Number of scores equal, including the score itself
+	Total int // Number of scores, including the score itself
+}
+
+// PercentileRank is the rank of a score among the other players
+type PercentileRank struct {
+	Percentile float64 `json:"percentile"` // Percentage of the other players with a lower score, a tie counts as half
+	Players    int     `json:"players"`    // Number of other players compared with
+}
+
+// SolutionPercentiles ranks a score among the players of the same window and of the
+// same difficulty. A rank is missing when nobody else played it yet.
+type SolutionPercentiles struct {
+	Window     *PercentileRank `json:"window,omitempty"`
+	Difficulty *PercentileRank `json:"difficulty,omitempty"`
+}
+
+// DifficultyFeatures describes how hard a window is to predict. The returns are daily
+// close to close returns.
+type DifficultyFeatures struct {
+	RealizedVolatility float64 `json:"realizedVolatility"` // Standard deviation of the returns of the days shown
+	MaxDrawdown        float64 `json:"maxDrawdown"`        // Largest drop from a peak of the days shown, as a ratio of the peak
+	GapFrequency       float64 `json:"gapFrequency"`       // Ratio of the days shown opening far from the previous close
+	TrendStrength      float64 `json:"trendStrength"`      // R² of a linear fit of the closes shown, 1 is a perfect trend
+	FutureVolatility   float64 `json:"-"`                  // Standard deviation of the returns of the days to guess, never sent before the solution
+}
+
+// DifficultyRating is the difficulty bucket of a window with the features it comes from.
+// The score weights the future volatility so it is not sent either.
+type DifficultyRating struct {
+	Difficulty string             `json:"difficulty"`
+	Score      float64            `json:"-"`
+	Features   DifficultyFeatures `json:"features"`
+}
+
+// StockWindow is the days shown to the player and the days to guess
+type StockWindow struct {
+	Stocks []StockPublic
+	Future []StockPublic
+	Rating DifficultyRating
+}
+
+// RoundMetadata describes the round served with the stocks
+type RoundMetadata struct {
+	DifficultyRating
+	Id         string         `json:"id,omitempty"`         // Id of the served round, sent back with the solution and to request hints
+	Scenario   string         `json:"scenario,omitempty"`   // Name of the scenario the window was drawn from
+	TimeLimits map[string]int `json:"timeLimits,omitempty"` // Seconds to submit the solution by mode, only the timed modes
+}
+
+// RoundTimingConfig limits the time between serving a window and submitting its solution
+type RoundTimingConfig struct {
+	Limits     map[string]int // Seconds by mode, a mode without limit is not timed
+	SpeedBonus int            // Points of an immediate solution, decreasing to zero at the limit
+}
+
+type StocksResponse struct {
+	Round  RoundMetadata `json:"round"`
+	Stocks []StockPublic `json:"stocks"`
+}
+
+// RoundReplayResponse is everything needed to draw a round again
+type RoundReplayResponse struct {
+	Id         string                      `json:"id"`
+	Mode       string                      `json:"mode"`
+	Symbol     string                      `json:"symbol"`
+	Name       string                      `json:"name"`
+	AfterDate  string                      `json:"afterDate"`
+	Difficulty string                      `json:"difficulty"`
+	CreatedAt  time.Time                   `json:"createdAt"`
+	History    []Stock                     `json:"history"` // Days shown to the player
+	Stocks     []Stock                     `json:"stocks"`  // Days the player had to guess
+	DayPrice   []DayPrice                  `json:"estimatedDayPrices"`
+	Score      UserScoreResponse           `json:"score"`
+	BB20       map[string]BollingerBand    `json:"bb20"`
+	Indicators map[string]IndicatorOverlay `json:"indicators,omitempty"`
+}
+
+// RoundCard is the data drawn on the shareable result card of a round
+type RoundCard struct {
+	Symbol     string
+	Name       string
+	AfterDate  string
+	Difficulty string
+	Score      UserScoreResponse
+	History    []float64 // Closes shown to the player
+	Guess      []float64 // Prices guessed by the player
+	Actual     []float64 // Real closes of the days to guess
+}
+
+// PlayerRoundsResponse is a page of the rounds of a player, the most recent first
+type PlayerRoundsResponse struct {
+	Rounds   []Round `json:"rounds"`
+	Page     int     `json:"page"`
+	PageSize int     `json:"pageSize"`
+	Total    int     `json:"total"`
+}
+
+// ComponentStats summarizes one component of the score over the rounds of a player
+type ComponentStats struct {
+	Average float64 `json:"average"`
+	Median  float64 `json:"median"`
+}
+
+// ScoreStats summarizes every component of the score
+type ScoreStats struct {
+	Total       ComponentStats `json:"total"`
+	InLowHigh   ComponentStats `json:"inLowHigh"`
+	InOpenClose ComponentStats `json:"inOpenClose"`
+	InBollinger ComponentStats `json:"inBollinger"`
+	InDirection ComponentStats `json:"inDirection"`
+}
+
+// MAPEPoint is the mean absolute percentage error of the rounds played on one day
+type MAPEPoint struct {
+	Date   string  `json:"date"`
+	MAPE   float64 `json:"mape"`
+	Rounds int     `json:"rounds"`
+}
+
+// SectorStats is the performance of a player on the stocks of one sector
+type SectorStats struct {
+	Sector       string   `json:"sector"`
+	Rounds       int      `json:"rounds"`
+	AverageScore float64  `json:"averageScore"`
+	MAPE         *float64 `json:"mape,omitempty"` // Missing when no round of the sector recorded its actual closes
+}
+
+// PlayerStats summarizes the rounds of a player. The accuracy measures only use the rounds
+// that recorded the actual closes.
+type PlayerStats struct {
+	Rounds              int           `json:"rounds"`
+	AnalyzedRounds      int           `json:"analyzedRounds"` // Most recent rounds the other stats are computed on
+	Score               ScoreStats    `json:"score"`
+	DirectionalAccuracy *float64      `json:"directionalAccuracy,omitempty"` // Ratio of the days guessed in the right direction
+	MAPE                *float64      `json:"mape,omitempty"`                // Mean absolute percentage error of the prices guessed
+	MAPEOverTime        []MAPEPoint   `json:"mapeOverTime"`
+	Sectors             []SectorStats `json:"sectors"`
+}
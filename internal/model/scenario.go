@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+// Scenario is a historical event the windows can be drawn from. The days to guess start
+// between the two dates.
+type Scenario struct {
+	Id        string    `json:"id"` // Slug used in GET /stocks?scenario=
+	Name      string    `json:"name"`
+	StartDate string    `json:"startDate"`
+	EndDate   string    `json:"endDate"`
+	Symbols   []string  `json:"symbols"` // Every symbol when empty
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type ScenarioRequest struct {
+	Id        string   `json:"id"` // Ignored when updating, the id is in the path
+	Name      string   `json:"name"`
+	StartDate string   `json:"startDate"`
+	EndDate   string   `json:"endDate"`
+	Symbols   []string `json:"symbols"`
+}
@@ -26,10 +26,34 @@ type DayPrice struct {
 	Day   int     `json:"day"`
 	Price float64 `json:"price"`
 }
+
+// DayBand is the range a player expects the close price to be in for one day with a
+// probability of Band_coverage_percent
+type DayBand struct {
+	Day   int     `json:"day"`
+	Lower float64 `json:"lower"`
+	Upper float64 `json:"upper"`
+}
 type UserSolutionRequest struct {
 	SymbolUUID string     `json:"symbolUUID"`
 	AfterDate  string     `json:"afterDate"`
 	DayPrice   []DayPrice `json:"estimatedDayPrices"`
+	DayBands   []DayBand  `json:"estimatedDayBands,omitempty"`
+	Indicators []string   `json:"indicators,omitempty"`
+	RoundId    string     `json:"roundId,omitempty"` // Id of the round served by GET /stocks, required when the mode is timed. Without it, the last round served for the window is used.
+}
+
+// FieldError is a field of a request that failed the validation
+type FieldError struct {
+	Field   string `json:"field"` // Path of the field in the request, e.g. estimatedDayPrices[2].price
+	Message string `json:"message"`
+}
+
+// ValidationErrorResponse lists every field of a request that failed the validation
+type ValidationErrorResponse struct {
+	Error  string       `json:"error"`
+	Code   string       `json:"code"`
+	Fields []FieldError `json:"fields"`
 }
 
 type UserScoreResponse struct {
@@ -38,13 +62,162 @@ type UserScoreResponse struct {
 	InOpenClose int `json:"inOpenClose"`
 	InBollinger int `json:"inBollinger"`
 	InDirection int `json:"inDirection"`
+	HintPenalty int `json:"hintPenalty,omitempty"` // Points of the hints already removed from the total
+	SpeedBonus  int `json:"speedBonus,omitempty"`  // Points of a fast timed solution already added to the total
+}
+type UserBandScoreResponse struct {
+	Total         int     `json:"total"`
+	InBand        int     `json:"inBand"`        // Number of days the close was inside the band
+	Coverage      float64 `json:"coverage"`      // Ratio of days the close was inside the band
+	IntervalScore float64 `json:"intervalScore"` // Mean interval score relative to the close, lower is better
+}
+
+// BotForecast is what a naive forecaster would have drawn for the same window
+type BotForecast struct {
+	Name   string     `json:"name"`
+	Prices []DayPrice `json:"prices"`
+}
+type BotScore struct {
+	Name   string            `json:"name"`
+	Score  UserScoreResponse `json:"score"`
+	Prices []DayPrice        `json:"prices"`
+}
+
+// BotSummary is the number of bots the player scored strictly more than ("beat N of M bots")
+type BotSummary struct {
+	Beaten int `json:"beaten"`
+	Total  int `json:"total"`
 }
 type UserSolutionResponse struct {
-	Symbol string                   `json:"symbol"`
-	Name   string                   `json:"name"`
-	Score  UserScoreResponse        `json:"score"`
-	Stocks []Stock                  `json:"stocks"`
-	BB20   map[string]BollingerBand `json:"bb20"`
+	Symbol       string                      `json:"symbol"`
+	Name         string                      `json:"name"`
+	Score        UserScoreResponse           `json:"score"`
+	Stocks       []Stock                     `json:"stocks"`
+	BB20         map[string]BollingerBand    `json:"bb20"`
+	Bands        []DayBand                   `json:"bands,omitempty"`
+	BandScore    *UserBandScoreResponse      `json:"bandScore,omitempty"`
+	Bots         []BotScore                  `json:"bots"`
+	BotSummary   BotSummary                  `json:"botSummary"`
+	Indicators   map[string]IndicatorOverlay `json:"indicators,omitempty"`
+	Difficulty   string                      `json:"difficulty,omitempty"`
+	RoundId      string                      `json:"roundId,omitempty"` // Empty when the window was not served to the player
+	Percentiles  *SolutionPercentiles        `json:"percentiles,omitempty"`
+	Achievements []Achievement               `json:"achievements,omitempty"` // Unlocked by the round
+}
+
+// DayCandle is the candle a player predicts for one day in the candlestick mode
+type DayCandle struct {
+	Day   int     `json:"day"`
+	Open  float64 `json:"open"`
+	High  float64 `json:"high"`
+	Low   float64 `json:"low"`
+	Close float64 `json:"close"`
+}
+type UserCandleSolutionRequest struct {
+	SymbolUUID string      `json:"symbolUUID"`
+	AfterDate  string      `json:"afterDate"`
+	DayCandles []DayCandle `json:"estimatedDayCandles"`
+	RoundId    string      `json:"roundId,omitempty"` // Id of the round served by GET /stocks, required when the mode is timed. Without it, the last round served for the window is used.
+}
+type UserCandleScoreResponse struct {
+	Total       int `json:"total"`
+	InRange     int `json:"inRange"`               // Overlap of the low/high ranges
+	InBody      int `json:"inBody"`                // Overlap of the open/close bodies
+	InColor     int `json:"inColor"`               // Bullish or bearish candle guessed correctly
+	HintPenalty int `json:"hintPenalty,omitempty"` // Points of the hints already removed from the total
+	SpeedBonus  int `json:"speedBonus,omitempty"`  // Points of a fast timed solution already added to the total
+}
+type UserCandleSolutionResponse struct {
+	Symbol string                  `json:"symbol"`
+	Name   string                  `json:"name"`
+	Score  UserCandleScoreResponse `json:"score"`
+	Stocks []Stock                 `json:"stocks"`
+}
+
+// DayDirection is the move a player predicts for one day in the direction mode
+type DayDirection struct {
+	Day       int    `json:"day"`
+	Direction string `json:"direction"` // Direction_up, Direction_down or Direction_flat
+}
+type UserDirectionSolutionRequest struct {
+	SymbolUUID    string         `json:"symbolUUID"`
+	AfterDate     string         `json:"afterDate"`
+	DayDirections []DayDirection `json:"estimatedDayDirections"`
+	RoundId       string         `json:"roundId,omitempty"` // Id of the round served by GET /stocks, required when the mode is timed
+}
+type UserDirectionScoreResponse struct {
+	Total         int `json:"total"`
+	InDirection   int `json:"inDirection"`           // Points for each day with the right direction
+	InStreak      int `json:"inStreak"`              // Bonus for consecutive days with the right direction
+	Correct       int `json:"correct"`               // Number of days with the right direction
+	LongestStreak int `json:"longestStreak"`         // Longest run of consecutive days with the right direction
+	HintPenalty   int `json:"hintPenalty,omitempty"` // Points of the hints already removed from the total
+	SpeedBonus    int `json:"speedBonus,omitempty"`  // Points of a fast timed solution already added to the total
+}
+type UserDirectionSolutionResponse struct {
+	Symbol     string                     `json:"symbol"`
+	Name       string                     `json:"name"`
+	Score      UserDirectionScoreResponse `json:"score"`
+	Stocks     []Stock                    `json:"stocks"`
+	Directions []string                   `json:"directions"` // Real direction of each day
+}
+
+// BollingerConfig configures the Bollinger bands used to score a solution
+type BollingerConfig struct {
+	Period            int
+	Multiplier        float64
+	IncludeCurrentDay bool
+}
+
+// DefaultBollingerConfig is the historical scoring configuration: 20 days before the
+// scored day (excluding it) and 2 standard deviations
+var DefaultBollingerConfig = BollingerConfig{
+	Period:            20,
+	Multiplier:        2,
+	IncludeCurrentDay: false,
+}
+
+// WithDefaults fills the period and the multiplier left at zero from DefaultBollingerConfig
+func (c BollingerConfig) WithDefaults() BollingerConfig {
+	if c.Period == 0 {
+		c.Period = DefaultBollingerConfig.Period
+	}
+	if c.Multiplier == 0 {
+		c.Multiplier = DefaultBollingerConfig.Multiplier
+	}
+	return c
+}
+
+// Filters on the exchange traded funds
+const (
+	EtfAny     = "any"     // Stocks and ETF
+	EtfExclude = "exclude" // Only stocks
+	EtfOnly    = "only"    // Only ETF
+)
+
+// EligibilityConfig configures which stocks can be served. A zero value disables the
+// matching rule except for the ETF filter which must be one of the Etf constants.
+type EligibilityConfig struct {
+	MinPrice        float64  // Minimum close of every day
+	MinVolume       int      // Minimum average volume
+	MaxMissingDays  int      // Maximum number of calendar days between two consecutive trading days
+	ExcludedSymbols []string // Symbols never served
+	StartDate       string   // First date that can be served (YYYY-MM-DD)
+	EndDate         string   // Last date that can be served (YYYY-MM-DD)
+	Etf             string
+}
+
+// DefaultEligibilityConfig is the historical eligibility: an average volume of 25,000
+var DefaultEligibilityConfig = EligibilityConfig{
+	MinVolume: 25000,
+	Etf:       EtfAny,
+}
+
+// IsZero reports whether no field is set. A zero field disables its rule so only a config
+// without any field falls back to DefaultEligibilityConfig.
+func (c EligibilityConfig) IsZero() bool {
+	return c.MinPrice == 0 && c.MinVolume == 0 && c.MaxMissingDays == 0 && len(c.ExcludedSymbols) == 0 &&
+		c.StartDate == "" && c.EndDate == "" && c.Etf == ""
 }
 
 type BollingerBand struct {
@@ -58,4 +231,6 @@ type StockInfo struct {
 	Symbol     string `json:"symbol"`
 	Name       string `json:"name"`
 	SymbolUUID string `json:"symbol_uuid"`
+	Exchange   string `json:"exchange"`
+	IsEtf      bool   `json:"is_etf"`
 }
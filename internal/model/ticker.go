@@ -0,0 +1,50 @@
+package model
+
+import "time"
+
+// TickerCandidate is one of the companies the player picks from. The id is only valid in
+// its challenge so the answer does not reveal the stock to read its prices.
+type TickerCandidate struct {
+	Id     string `json:"id"`
+	Symbol string `json:"symbol"`
+	Name   string `json:"name"`
+}
+
+// TickerOption maps a candidate to its stock, it is kept on the server
+type TickerOption struct {
+	TickerCandidate
+	SymbolUUID string `json:"symbolUUID"`
+}
+
+// TickerChallenge is an anonymized window served to the player with the candidates
+type TickerChallenge struct {
+	Id         string
+	PlayerId   string
+	SymbolUUID string // The company of the window
+	Candidates []TickerOption
+	ServedAt   time.Time
+}
+
+type TickerChallengeResponse struct {
+	Id         string            `json:"id"`
+	Stocks     []StockPublic     `json:"stocks"` // Without the symbol UUID
+	Candidates []TickerCandidate `json:"candidates"`
+	ServedAt   time.Time         `json:"servedAt"`
+}
+
+type TickerAnswerRequest struct {
+	OptionId string `json:"optionId"` // Id of one of the candidates
+}
+
+// TickerScore rewards a correct answer, and a fast one more
+type TickerScore struct {
+	Correct bool    `json:"correct"`
+	Seconds float64 `json:"seconds"` // From the time the challenge was served
+	Speed   int     `json:"speed"`
+	Total   int     `json:"total"`
+}
+
+type TickerAnswerResponse struct {
+	Answer TickerCandidate `json:"answer"`
+	Score  TickerScore     `json:"score"`
+}
@@ -0,0 +1,67 @@
+package model
+
+import "time"
+
+// TournamentWindow is one of the windows every player of a tournament guesses, in order.
+// It is not sent to the players since it reveals the stock.
+type TournamentWindow struct {
+	SymbolUUID string `json:"symbolUUID"`
+	AfterDate  string `json:"afterDate"`
+}
+
+// Tournament is a competition on a fixed set of windows, open between two dates
+type Tournament struct {
+	Id        string             `json:"id"`
+	Name      string             `json:"name"`
+	StartsAt  time.Time          `json:"startsAt"`
+	EndsAt    time.Time          `json:"endsAt"`
+	Windows   []TournamentWindow `json:"-"`
+	Rounds    int                `json:"rounds"`  // Number of windows
+	Entries   int                `json:"entries"` // Number of players who joined
+	CreatedAt time.Time          `json:"createdAt"`
+}
+
+type CreateTournamentRequest struct {
+	Name       string    `json:"name"`
+	StartsAt   time.Time `json:"startsAt"`
+	EndsAt     time.Time `json:"endsAt"`
+	Rounds     int       `json:"rounds"`
+	Difficulty string    `json:"difficulty"` // Optional, every difficulty when empty
+}
+
+type JoinTournamentRequest struct {
+	Name string `json:"name"`
+}
+
+// TournamentRoundResponse is the next window a player of the tournament has to guess
+type TournamentRoundResponse struct {
+	TournamentId string        `json:"tournamentId"`
+	Position     int           `json:"position"` // From 1 to the number of rounds
+	Rounds       int           `json:"rounds"`
+	Stocks       []StockPublic `json:"stocks"`
+}
+
+type TournamentSolutionRequest struct {
+	DayPrice []DayPrice `json:"estimatedDayPrices"`
+}
+
+type TournamentSolutionResponse struct {
+	Position     int               `json:"position"`
+	RoundId      string            `json:"roundId,omitempty"`
+	Symbol       string            `json:"symbol"`
+	Name         string            `json:"name"`
+	Score        UserScoreResponse `json:"score"`
+	Stocks       []Stock           `json:"stocks"`
+	Achievements []Achievement     `json:"achievements,omitempty"` // Unlocked by the round
+}
+
+// TournamentStanding is the sum of the scores of a player in a tournament. The entry is the
+// order in which the players joined, the player id is not shared.
+type TournamentStanding struct {
+	Rank            int               `json:"rank"`
+	Entry           int               `json:"entry"`
+	Name            string            `json:"name"`
+	Rounds          int               `json:"rounds"` // Number of rounds played
+	Score           UserScoreResponse `json:"score"`
+	LastSubmittedAt *time.Time        `json:"lastSubmittedAt,omitempty"`
+}
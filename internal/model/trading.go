@@ -0,0 +1,76 @@
+package model
+
+import "time"
+
+// TradeRequest is the decision of the player before the next day is revealed. It is
+// executed at the last close revealed.
+type TradeRequest struct {
+	Action   string `json:"action"`             // Trade_buy, Trade_sell or Trade_hold
+	Quantity int    `json:"quantity,omitempty"` // Zero buys as many shares as the cash allows or sells every share
+}
+
+// TradingPosition is the cash and the shares held by the player
+type TradingPosition struct {
+	Cash   float64 `json:"cash"`
+	Shares int     `json:"shares"`
+}
+
+// TradeDecision is an executed trade and the position held during the day revealed after it
+type TradeDecision struct {
+	Day      int     `json:"day"` // From 1 to the number of days to reveal
+	Action   string  `json:"action"`
+	Quantity int     `json:"quantity"` // Number of shares bought or sold
+	Price    float64 `json:"price"`
+	TradingPosition
+}
+
+// TradingSession is a window of a stock whose hidden days are revealed one at a time
+type TradingSession struct {
+	Id          string          `json:"id"`
+	PlayerId    string          `json:"-"`
+	SymbolUUID  string          `json:"symbolUUID"`
+	AfterDate   string          `json:"afterDate"`
+	Days        int             `json:"days"` // Number of days to reveal
+	Day         int             `json:"day"`  // Number of days revealed
+	InitialCash float64         `json:"initialCash"`
+	LastClose   float64         `json:"-"` // Close of the last day shown, the price of the first trade
+	Decisions   []TradeDecision `json:"decisions"`
+	TradingPosition
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// Finished returns true once every day was revealed
+func (s TradingSession) Finished() bool {
+	return s.Day >= s.Days
+}
+
+// TradingPerformance measures a strategy over the revealed days. The returns are in percent.
+type TradingPerformance struct {
+	FinalValue  float64   `json:"finalValue"`
+	ProfitLoss  float64   `json:"profitLoss"`
+	Return      float64   `json:"return"`
+	MaxDrawdown float64   `json:"maxDrawdown"`
+	Sharpe      *float64  `json:"sharpe"` // Annualized, nil when the value never changed
+	Equity      []float64 `json:"equity"` // Value at the last close shown then at each revealed close
+}
+
+type TradingResult struct {
+	Strategy   TradingPerformance `json:"strategy"`
+	BuyAndHold TradingPerformance `json:"buyAndHold"`
+}
+
+type StartTradingRequest struct {
+	Difficulty string `json:"difficulty"` // Optional, every difficulty when empty
+}
+
+// TradingResponse is the state of a session. The symbol and the result are only sent once
+// every day was revealed.
+type TradingResponse struct {
+	Session  TradingSession `json:"session"`
+	Stocks   []StockPublic  `json:"stocks,omitempty"` // The days shown before the first trade, not sent after a trade
+	Revealed []Stock        `json:"revealed"`         // The days revealed so far, in order
+	Finished bool           `json:"finished"`
+	Symbol   string         `json:"symbol,omitempty"`
+	Name     string         `json:"name,omitempty"`
+	Result   *TradingResult `json:"result,omitempty"`
+}
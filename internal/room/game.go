@@ -0,0 +1,84 @@
+package room
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"stockgame/internal/logic"
+	"stockgame/internal/model"
+	"stockgame/internal/service"
+)
+
+// ErrNoWindow is returned when no stock window can be served
+var (
+	ErrNoWindow         = errors.New("no stock window available")
+	ErrIncompleteWindow = errors.New("the window does not have every day to guess")
+)
+
+// Answer is what the players of a round had to guess, kept secret until the results
+type Answer struct {
+	Info      model.StockInfo
+	LastClose float64 // Close of the last day shown
+	After     []model.Stock
+	Bands     map[string]model.BollingerBand
+}
+
+// Game picks the windows played in the rooms and scores the guesses
+type Game interface {
+	NewWindow() ([]model.StockPublic, error)
+	LoadAnswer(window []model.StockPublic) (Answer, error)
+	Validate(prices []model.DayPrice, answer Answer) []model.FieldError
+	Score(prices []model.DayPrice, answer Answer) model.UserScoreResponse
+}
+
+// GameImpl serves and scores the same windows as a single player round
+type GameImpl struct {
+	StockService    service.StockService
+	ScoringLogic    logic.ScoringLogic
+	BollingerConfig model.BollingerConfig // Zero fields use model.DefaultBollingerConfig
+	Game
+}
+
+func (g *GameImpl) NewWindow() ([]model.StockPublic, error) {
+	window := g.StockService.GetRandomStockWithRandomDayRange(model.Number_initial_stock_shown)
+	if len(window) == 0 {
+		return nil, ErrNoWindow
+	}
+	return window, nil
+}
+
+// LoadAnswer fetches the days following the window and the Bollinger Bands to score them.
+// A window without every day to guess returns ErrIncompleteWindow.
+func (g *GameImpl) LoadAnswer(window []model.StockPublic) (Answer, error) {
+	answer := Answer{}
+	if len(window) == 0 {
+		return answer, ErrNoWindow
+	}
+	info, err := g.StockService.GetStockInfo(window[0].SymbolUUID)
+	if err != nil {
+		return answer, fmt.Errorf("cannot find the stock information: %v", err)
+	}
+	answer.Info = info
+	afterDate := window[len(window)-1].Date
+	// The stocks before the date are sorted from the most recent
+	before := slices.Clone(g.StockService.GetStocksBeforeEqualDate(info.Symbol, afterDate))
+	slices.Reverse(before)
+	if len(before) > 0 {
+		answer.LastClose = before[len(before)-1].Close
+	}
+	answer.After = g.StockService.GetStocksAfterDate(info.Symbol, afterDate)
+	if len(answer.After) < model.User_stock_to_guess {
+		return Answer{}, fmt.Errorf("%w: %d days after %s", ErrIncompleteWindow, len(answer.After), afterDate)
+	}
+	answer.Bands = g.ScoringLogic.CalculateBollingerBandsWithConfig(append(before, answer.After...), g.BollingerConfig.WithDefaults())
+	return answer, nil
+}
+
+// Validate checks the guess like a single player solution
+func (g *GameImpl) Validate(prices []model.DayPrice, answer Answer) []model.FieldError {
+	return logic.ValidateDayPrices(prices, answer.LastClose)
+}
+
+func (g *GameImpl) Score(prices []model.DayPrice, answer Answer) model.UserScoreResponse {
+	return g.ScoringLogic.GetScore(prices, answer.After, answer.Bands)
+}
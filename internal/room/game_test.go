@@ -0,0 +1,100 @@
+package room
+
+import (
+	"errors"
+	"fmt"
+	"stockgame/internal/logic"
+	"stockgame/internal/model"
+	"stockgame/internal/service"
+	"testing"
+)
+
+// StockServiceMockImpl only implements the methods used by the game
+type StockServiceMockImpl struct {
+	service.StockService
+	Window []model.StockPublic
+	After  []model.Stock
+}
+
+func (s *StockServiceMockImpl) GetRandomStockWithRandomDayRange(numberOfDays int) []model.StockPublic {
+	return s.Window
+}
+
+func (s *StockServiceMockImpl) GetStockInfo(symbolUUID string) (model.StockInfo, error) {
+	return model.StockInfo{Symbol: "AAPL", Name: "Apple Inc.", SymbolUUID: symbolUUID}, nil
+}
+
+// GetStocksBeforeEqualDate returns the most recent first like the database
+func (s *StockServiceMockImpl) GetStocksBeforeEqualDate(symbol, date string) []model.Stock {
+	return []model.Stock{{Date: "2023-01-03", Close: 101}, {Date: "2023-01-02", Close: 100}}
+}
+
+func (s *StockServiceMockImpl) GetStocksAfterDate(symbol, date string) []model.Stock {
+	return s.After
+}
+
+// fullAfter is every day to guess, each one ranging from 99 to 103
+func fullAfter() []model.Stock {
+	after := []model.Stock{}
+	for i := range model.User_stock_to_guess {
+		after = append(after, model.Stock{Date: fmt.Sprintf("2023-02-%02d", i+1), Open: 101, Low: 99, High: 103, Close: 102})
+	}
+	return after
+}
+
+// fullGuess is the same price for every day to guess
+func fullGuess(price float64) []model.DayPrice {
+	guess := []model.DayPrice{}
+	for i := range model.User_stock_to_guess {
+		guess = append(guess, model.DayPrice{Day: model.Number_initial_stock_shown + i, Price: price})
+	}
+	return guess
+}
+
+func TestGameImpl(t *testing.T) {
+	t.Run("No window", func(t *testing.T) {
+		game := &GameImpl{StockService: &StockServiceMockImpl{}}
+		if _, err := game.NewWindow(); !errors.Is(err, ErrNoWindow) {
+			t.Errorf("Expected ErrNoWindow and not %v", err)
+		}
+	})
+	t.Run("Answer and score", func(t *testing.T) {
+		window := []model.StockPublic{{Date: "2023-01-03", Close: 101, SymbolUUID: "uuid-aapl"}}
+		game := &GameImpl{StockService: &StockServiceMockImpl{Window: window, After: fullAfter()}, ScoringLogic: &logic.ScoringLogicImpl{}}
+		served, err := game.NewWindow()
+		if err != nil || len(served) != 1 {
+			t.Fatalf("Expected the window and not %v %v", served, err)
+		}
+		answer, err := game.LoadAnswer(served)
+		if err != nil {
+			t.Fatalf("Expected no error and not %v", err)
+		}
+		if answer.Info.Symbol != "AAPL" || len(answer.After) != model.User_stock_to_guess || answer.LastClose != 101 {
+			t.Errorf("Unexpected answer %+v", answer)
+		}
+		guess := fullGuess(101.5)
+		if fields := game.Validate(guess, answer); len(fields) != 0 {
+			t.Errorf("Expected a valid guess and not %v", fields)
+		}
+		score := game.Score(guess, answer)
+		if score.InLowHigh == 0 || score.InOpenClose == 0 {
+			t.Errorf("Expected a guess in the range of the day to score and not %+v", score)
+		}
+		// The guesses are checked like a single player solution
+		absurd := fullGuess(101.5)
+		absurd[0].Day, absurd[1].Price = 1, 10000
+		if fields := game.Validate(absurd, answer); len(fields) != 2 {
+			t.Errorf("Expected the day and the price to be refused and not %v", fields)
+		}
+		if fields := game.Validate(guess[:1], answer); len(fields) != 1 {
+			t.Errorf("Expected a short guess to be refused and not %v", fields)
+		}
+	})
+	t.Run("Incomplete window", func(t *testing.T) {
+		window := []model.StockPublic{{Date: "2023-01-03", Close: 101, SymbolUUID: "uuid-aapl"}}
+		game := &GameImpl{StockService: &StockServiceMockImpl{Window: window, After: fullAfter()[:1]}, ScoringLogic: &logic.ScoringLogicImpl{}}
+		if _, err := game.LoadAnswer(window); !errors.Is(err, ErrIncompleteWindow) {
+			t.Errorf("Expected ErrIncompleteWindow and not %v", err)
+		}
+	})
+}
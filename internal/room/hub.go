@@ -0,0 +1,454 @@
+package room
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"sort"
+	"stockgame/internal/model"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	ErrRoomNotFound     = errors.New("room not found")
+	ErrRoomFull         = errors.New("room is full")
+	ErrTooManyRooms     = errors.New("too many rooms open")
+	ErrNotHost          = errors.New("only the host can start a round")
+	ErrRoundInProgress  = errors.New("a round is already in progress")
+	ErrNoRoundPlaying   = errors.New("no round is in progress")
+	ErrAlreadySubmitted = errors.New("guess already submitted for this round")
+	ErrNotInRoom        = errors.New("player is not in the room")
+	ErrInvalidGuess     = errors.New("invalid guess")
+)
+
+const maxPlayersPerRoom = 12
+
+const maxRooms = 1000
+
+const maxNameLength = 24
+
+// Rooms without any connected player for this long are removed when a room is created
+const roomIdleTimeout = 10 * time.Minute
+
+// Events buffered per connection, a connection too slow to read them is dropped
+const subscriptionBuffer = 32
+
+// Letters of the room codes, without the ones easily confused (0/O, 1/I)
+const codeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+const codeLength = 6
+
+// Hub holds the rooms and broadcasts their changes to the connected players
+type Hub interface {
+	CreateRoom(playerId string, name string) (model.RoomState, error)
+	GetRoom(code string) (model.RoomState, error)
+	Join(code string, playerId string, name string) (*Subscription, error)
+	StartRound(code string, playerId string) error
+	Submit(code string, playerId string, prices []model.DayPrice) error
+}
+
+// Subscription receives the events of a room until Leave is called. The events channel
+// is closed when the subscription ends, including when the connection is too slow.
+type Subscription struct {
+	Events <-chan model.RoomEvent
+	Seat   int
+	leave  func()
+}
+
+// Leave stops the events, the player keeps its seat and can join again
+func (s *Subscription) Leave() {
+	s.leave()
+}
+
+// HubImpl keeps the rooms in memory. Each room has its own lock so a room loading a
+// window from the database does not block the others.
+type HubImpl struct {
+	Game          Game
+	RoundDuration time.Duration    // Zero uses model.Room_round_seconds
+	Now           func() time.Time // Nil uses time.Now
+	mu            sync.Mutex
+	rooms         map[string]*room
+	Hub
+}
+
+type player struct {
+	id          string
+	seat        int
+	name        string
+	total       int
+	submitted   bool
+	prices      []model.DayPrice
+	score       model.UserScoreResponse
+	subscribers map[chan model.RoomEvent]bool
+}
+
+type room struct {
+	mu         sync.Mutex
+	code       string
+	hostId     string
+	state      string
+	players    []*player // By seat
+	round      *model.RoomRound
+	answer     Answer
+	timer      *time.Timer
+	starting   bool      // A window is being loaded
+	lastActive time.Time // Last time a player left, for the rooms nobody is connected to
+}
+
+func (h *HubImpl) CreateRoom(playerId string, name string) (model.RoomState, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.rooms == nil {
+		h.rooms = map[string]*room{}
+	}
+	h.removeIdleRooms()
+	if len(h.rooms) >= maxRooms {
+		return model.RoomState{}, ErrTooManyRooms
+	}
+	code := newRoomCode()
+	for h.rooms[code] != nil {
+		code = newRoomCode()
+	}
+	r := &room{code: code, hostId: playerId, state: model.Room_state_lobby, lastActive: h.now()}
+	r.addPlayer(playerId, name)
+	h.rooms[code] = r
+	return r.snapshot(), nil
+}
+
+func (h *HubImpl) GetRoom(code string) (model.RoomState, error) {
+	r, err := h.getRoom(code)
+	if err != nil {
+		return model.RoomState{}, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.snapshot(), nil
+}
+
+// Join adds the player to the room, or gives back its seat when it is already in, and
+// subscribes to the events of the room
+func (h *HubImpl) Join(code string, playerId string, name string) (*Subscription, error) {
+	r, err := h.getRoom(code)
+	if err != nil {
+		return nil, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	p := r.player(playerId)
+	if p == nil {
+		if len(r.players) >= maxPlayersPerRoom {
+			return nil, ErrRoomFull
+		}
+		p = r.addPlayer(playerId, name)
+	}
+	events := make(chan model.RoomEvent, subscriptionBuffer)
+	p.subscribers[events] = true
+	r.broadcast(model.RoomEvent{Type: model.Room_event_state})
+	return &Subscription{
+		Events: events,
+		Seat:   p.seat,
+		leave: func() {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			if p.subscribers[events] {
+				delete(p.subscribers, events)
+				close(events)
+				r.lastActive = h.now()
+				if p.id == r.hostId {
+					r.handOverHost()
+				}
+				// The players still connected do not wait for the one who left
+				if r.state == model.Room_state_playing && r.hasSubscribers() && r.everyConnectedSubmitted() {
+					r.finishRound()
+					return
+				}
+				r.broadcast(model.RoomEvent{Type: model.Room_event_state})
+			}
+		},
+	}, nil
+}
+
+// StartRound serves a new window to every player of the room. The round ends at the
+// deadline or as soon as every connected player submitted.
+func (h *HubImpl) StartRound(code string, playerId string) error {
+	r, err := h.getRoom(code)
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	if r.hostId != playerId {
+		r.mu.Unlock()
+		return ErrNotHost
+	}
+	if r.state == model.Room_state_playing || r.starting {
+		r.mu.Unlock()
+		return ErrRoundInProgress
+	}
+	r.starting = true
+	r.mu.Unlock()
+
+	// The window is loaded without holding the lock, the players can still join meanwhile
+	window, answer, err := h.loadRound()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.starting = false
+	if err != nil {
+		return err
+	}
+	number := 1
+	if r.round != nil {
+		number = r.round.Number + 1
+	}
+	r.state = model.Room_state_playing
+	r.round = &model.RoomRound{Number: number, Stocks: window, Deadline: h.now().Add(h.roundDuration())}
+	r.answer = answer
+	for _, p := range r.players {
+		p.submitted = false
+		p.prices = nil
+		p.score = model.UserScoreResponse{}
+	}
+	r.timer = time.AfterFunc(h.roundDuration(), func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		if r.state == model.Room_state_playing && r.round.Number == number {
+			r.finishRound()
+		}
+	})
+	r.broadcast(model.RoomEvent{Type: model.Room_event_round_started})
+	return nil
+}
+
+// Submit scores the guess of a player, the score is revealed with the results. An invalid
+// guess is refused with the first invalid field and can be submitted again.
+func (h *HubImpl) Submit(code string, playerId string, prices []model.DayPrice) error {
+	r, err := h.getRoom(code)
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	p := r.player(playerId)
+	if p == nil {
+		return ErrNotInRoom
+	}
+	if r.state != model.Room_state_playing {
+		return ErrNoRoundPlaying
+	}
+	if p.submitted {
+		return ErrAlreadySubmitted
+	}
+	if fields := h.Game.Validate(prices, r.answer); len(fields) > 0 {
+		return fmt.Errorf("%w: %s %s", ErrInvalidGuess, fields[0].Field, fields[0].Message)
+	}
+	p.submitted = true
+	p.prices = prices
+	p.score = h.Game.Score(prices, r.answer)
+	if r.everyConnectedSubmitted() {
+		r.finishRound()
+		return nil
+	}
+	r.broadcast(model.RoomEvent{Type: model.Room_event_state})
+	return nil
+}
+
+func (h *HubImpl) loadRound() ([]model.StockPublic, Answer, error) {
+	window, err := h.Game.NewWindow()
+	if err != nil {
+		return nil, Answer{}, err
+	}
+	answer, err := h.Game.LoadAnswer(window)
+	if err != nil {
+		return nil, Answer{}, err
+	}
+	return window, answer, nil
+}
+
+func (h *HubImpl) getRoom(code string) (*room, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	r := h.rooms[strings.ToUpper(code)]
+	if r == nil {
+		return nil, ErrRoomNotFound
+	}
+	return r, nil
+}
+
+// removeIdleRooms must be called with the hub lock
+func (h *HubImpl) removeIdleRooms() {
+	for code, r := range h.rooms {
+		r.mu.Lock()
+		idle := !r.hasSubscribers() && !r.starting && h.now().Sub(r.lastActive) > roomIdleTimeout
+		if idle && r.timer != nil {
+			r.timer.Stop()
+		}
+		r.mu.Unlock()
+		if idle {
+			delete(h.rooms, code)
+		}
+	}
+}
+
+func (h *HubImpl) roundDuration() time.Duration {
+	if h.RoundDuration == 0 {
+		return model.Room_round_seconds * time.Second
+	}
+	return h.RoundDuration
+}
+
+func (h *HubImpl) now() time.Time {
+	if h.Now == nil {
+		return time.Now()
+	}
+	return h.Now()
+}
+
+func newRoomCode() string {
+	bytes := make([]byte, codeLength)
+	if _, err := rand.Read(bytes); err != nil {
+		panic(fmt.Sprintf("cannot generate a room code: %v", err))
+	}
+	code := make([]byte, codeLength)
+	for i, b := range bytes {
+		code[i] = codeAlphabet[int(b)%len(codeAlphabet)]
+	}
+	return string(code)
+}
+
+// The methods of room must be called with the room lock
+
+func (r *room) addPlayer(playerId string, name string) *player {
+	seat := len(r.players) + 1
+	name = strings.TrimSpace(name)
+	if runes := []rune(name); len(runes) > maxNameLength {
+		name = string(runes[:maxNameLength])
+	}
+	if name == "" {
+		name = fmt.Sprintf("Player %d", seat)
+	}
+	p := &player{id: playerId, seat: seat, name: name, subscribers: map[chan model.RoomEvent]bool{}}
+	r.players = append(r.players, p)
+	return p
+}
+
+func (r *room) player(playerId string) *player {
+	for _, p := range r.players {
+		if p.id == playerId {
+			return p
+		}
+	}
+	return nil
+}
+
+func (r *room) hasSubscribers() bool {
+	for _, p := range r.players {
+		if len(p.subscribers) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// handOverHost gives the host to the first connected player when the host is gone. A room
+// nobody is connected to keeps its host and is removed once idle.
+func (r *room) handOverHost() {
+	if host := r.player(r.hostId); host != nil && len(host.subscribers) > 0 {
+		return
+	}
+	for _, p := range r.players {
+		if len(p.subscribers) > 0 {
+			r.hostId = p.id
+			return
+		}
+	}
+}
+
+func (r *room) everyConnectedSubmitted() bool {
+	for _, p := range r.players {
+		if len(p.subscribers) > 0 && !p.submitted {
+			return false
+		}
+	}
+	return true
+}
+
+// finishRound adds the scores to the totals and broadcasts the results
+func (r *room) finishRound() {
+	if r.timer != nil {
+		r.timer.Stop()
+	}
+	r.state = model.Room_state_results
+	results := &model.RoomResults{
+		Round:  r.round.Number,
+		Symbol: r.answer.Info.Symbol,
+		Name:   r.answer.Info.Name,
+		Stocks: r.answer.After,
+		Scores: []model.RoomScore{},
+	}
+	for _, p := range r.players {
+		p.total += p.score.Total
+		results.Scores = append(results.Scores, model.RoomScore{
+			Seat:      p.seat,
+			Name:      p.name,
+			Submitted: p.submitted,
+			Score:     p.score,
+			DayPrice:  p.prices,
+		})
+	}
+	sort.SliceStable(results.Scores, func(i, j int) bool {
+		return results.Scores[i].Score.Total > results.Scores[j].Score.Total
+	})
+	r.broadcast(model.RoomEvent{Type: model.Room_event_results, Results: results})
+}
+
+// broadcast sends the event with the current state of the room to every connection
+func (r *room) broadcast(event model.RoomEvent) {
+	event.Room = r.snapshot()
+	for _, p := range r.players {
+		for events := range p.subscribers {
+			select {
+			case events <- event:
+			default:
+				delete(p.subscribers, events)
+				close(events)
+			}
+		}
+	}
+}
+
+func (r *room) snapshot() model.RoomState {
+	state := model.RoomState{
+		Code:    r.code,
+		State:   r.state,
+		Players: []model.RoomPlayer{},
+		Ranking: []model.RoomRanking{},
+	}
+	if r.round != nil {
+		round := *r.round
+		state.Round = &round
+	}
+	for _, p := range r.players {
+		state.Players = append(state.Players, model.RoomPlayer{
+			Seat:      p.seat,
+			Name:      p.name,
+			Host:      p.id == r.hostId,
+			Connected: len(p.subscribers) > 0,
+			Submitted: p.submitted,
+			Total:     p.total,
+		})
+		state.Ranking = append(state.Ranking, model.RoomRanking{Seat: p.seat, Name: p.name, Total: p.total})
+	}
+	sort.SliceStable(state.Ranking, func(i, j int) bool {
+		return state.Ranking[i].Total > state.Ranking[j].Total
+	})
+	for i := range state.Ranking {
+		if i > 0 && state.Ranking[i].Total == state.Ranking[i-1].Total {
+			state.Ranking[i].Rank = state.Ranking[i-1].Rank
+		} else {
+			state.Ranking[i].Rank = i + 1
+		}
+	}
+	return state
+}
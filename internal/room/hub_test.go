@@ -0,0 +1,377 @@
+package room
+
+import (
+	"errors"
+	"fmt"
+	"stockgame/internal/model"
+	"sync"
+	"testing"
+	"time"
+)
+
+// GameMockImpl serves a window of one stock, refuses the prices that are not positive and
+// scores the first price guessed
+type GameMockImpl struct {
+	NewWindowErr error
+}
+
+func (g *GameMockImpl) NewWindow() ([]model.StockPublic, error) {
+	if g.NewWindowErr != nil {
+		return nil, g.NewWindowErr
+	}
+	return []model.StockPublic{{Date: "2023-01-02", Close: 100, SymbolUUID: "uuid-aapl"}}, nil
+}
+
+func (g *GameMockImpl) LoadAnswer(window []model.StockPublic) (Answer, error) {
+	return Answer{
+		Info:  model.StockInfo{Symbol: "AAPL", Name: "Apple Inc.", SymbolUUID: "uuid-aapl"},
+		After: []model.Stock{{Date: "2023-01-03", Close: 101}},
+	}, nil
+}
+
+func (g *GameMockImpl) Validate(prices []model.DayPrice, answer Answer) []model.FieldError {
+	for i, price := range prices {
+		if price.Price <= 0 {
+			return []model.FieldError{{Field: fmt.Sprintf("estimatedDayPrices[%d].price", i), Message: "price must be positive"}}
+		}
+	}
+	return []model.FieldError{}
+}
+
+func (g *GameMockImpl) Score(prices []model.DayPrice, answer Answer) model.UserScoreResponse {
+	if len(prices) == 0 {
+		return model.UserScoreResponse{}
+	}
+	return model.UserScoreResponse{Total: int(prices[0].Price)}
+}
+
+// nextEvent waits for the next event of the subscription
+func nextEvent(t *testing.T, subscription *Subscription) model.RoomEvent {
+	t.Helper()
+	select {
+	case event, ok := <-subscription.Events:
+		if !ok {
+			t.Fatalf("Expected an event and not a closed subscription")
+		}
+		return event
+	case <-time.After(time.Second):
+		t.Fatalf("Expected an event before the timeout")
+	}
+	return model.RoomEvent{}
+}
+
+// waitForEvent skips the events until one of the type
+func waitForEvent(t *testing.T, subscription *Subscription, eventType string) model.RoomEvent {
+	t.Helper()
+	for {
+		if event := nextEvent(t, subscription); event.Type == eventType {
+			return event
+		}
+	}
+}
+
+func newHub() *HubImpl {
+	return &HubImpl{Game: &GameMockImpl{}, RoundDuration: time.Minute}
+}
+
+func TestCreateAndJoin(t *testing.T) {
+	hub := newHub()
+	room, err := hub.CreateRoom("host-id", "Alice")
+	if err != nil {
+		t.Fatalf("Expected no error and not %v", err)
+	}
+	if len(room.Code) != codeLength || room.State != model.Room_state_lobby {
+		t.Errorf("Unexpected room %+v", room)
+	}
+	host, err := hub.Join(room.Code, "host-id", "")
+	if err != nil {
+		t.Fatalf("Expected no error and not %v", err)
+	}
+	waitForEvent(t, host, model.Room_event_state)
+	guest, err := hub.Join(room.Code, "guest-id", "  Bob  ")
+	if err != nil {
+		t.Fatalf("Expected no error and not %v", err)
+	}
+	if guest.Seat != 2 {
+		t.Errorf("Expected seat 2 and not %d", guest.Seat)
+	}
+	event := waitForEvent(t, host, model.Room_event_state)
+	if len(event.Room.Players) != 2 || event.Room.Players[1].Name != "Bob" || !event.Room.Players[0].Host {
+		t.Errorf("Unexpected players %+v", event.Room.Players)
+	}
+	t.Run("Code is case insensitive", func(t *testing.T) {
+		lower := []byte(room.Code)
+		for i := range lower {
+			if lower[i] >= 'A' && lower[i] <= 'Z' {
+				lower[i] += 'a' - 'A'
+			}
+		}
+		if _, err := hub.GetRoom(string(lower)); err != nil {
+			t.Errorf("Expected the room and not %v", err)
+		}
+	})
+	t.Run("Unknown room", func(t *testing.T) {
+		if _, err := hub.Join("NOPE00", "guest-id", "Bob"); !errors.Is(err, ErrRoomNotFound) {
+			t.Errorf("Expected ErrRoomNotFound and not %v", err)
+		}
+	})
+	t.Run("Rejoin keeps the seat", func(t *testing.T) {
+		guest.Leave()
+		again, err := hub.Join(room.Code, "guest-id", "Other name")
+		if err != nil {
+			t.Fatalf("Expected no error and not %v", err)
+		}
+		if again.Seat != 2 {
+			t.Errorf("Expected seat 2 and not %d", again.Seat)
+		}
+		state, _ := hub.GetRoom(room.Code)
+		if len(state.Players) != 2 || state.Players[1].Name != "Bob" {
+			t.Errorf("Unexpected players %+v", state.Players)
+		}
+	})
+}
+
+func TestRoomFull(t *testing.T) {
+	hub := newHub()
+	room, _ := hub.CreateRoom("player-1", "")
+	for i := 2; i <= maxPlayersPerRoom; i++ {
+		if _, err := hub.Join(room.Code, fmt.Sprintf("player-%d", i), ""); err != nil {
+			t.Fatalf("Expected no error and not %v", err)
+		}
+	}
+	if _, err := hub.Join(room.Code, "one-too-many", ""); !errors.Is(err, ErrRoomFull) {
+		t.Errorf("Expected ErrRoomFull and not %v", err)
+	}
+}
+
+func TestRound(t *testing.T) {
+	hub := newHub()
+	room, _ := hub.CreateRoom("host-id", "Alice")
+	host, _ := hub.Join(room.Code, "host-id", "")
+	guest, _ := hub.Join(room.Code, "guest-id", "Bob")
+
+	if err := hub.StartRound(room.Code, "guest-id"); !errors.Is(err, ErrNotHost) {
+		t.Errorf("Expected ErrNotHost and not %v", err)
+	}
+	if err := hub.Submit(room.Code, "guest-id", nil); !errors.Is(err, ErrNoRoundPlaying) {
+		t.Errorf("Expected ErrNoRoundPlaying and not %v", err)
+	}
+	if err := hub.StartRound(room.Code, "host-id"); err != nil {
+		t.Fatalf("Expected no error and not %v", err)
+	}
+	for _, subscription := range []*Subscription{host, guest} {
+		event := waitForEvent(t, subscription, model.Room_event_round_started)
+		if event.Room.Round == nil || event.Room.Round.Number != 1 || len(event.Room.Round.Stocks) != 1 {
+			t.Errorf("Expected the window of round 1 and not %+v", event.Room.Round)
+		}
+	}
+	if err := hub.StartRound(room.Code, "host-id"); !errors.Is(err, ErrRoundInProgress) {
+		t.Errorf("Expected ErrRoundInProgress and not %v", err)
+	}
+
+	if err := hub.Submit(room.Code, "guest-id", []model.DayPrice{{Day: 1, Price: -30}}); !errors.Is(err, ErrInvalidGuess) {
+		t.Errorf("Expected ErrInvalidGuess and not %v", err)
+	}
+	// An invalid guess is not counted as submitted
+	if err := hub.Submit(room.Code, "guest-id", []model.DayPrice{{Day: 1, Price: 30}}); err != nil {
+		t.Fatalf("Expected no error and not %v", err)
+	}
+	event := waitForEvent(t, host, model.Room_event_state)
+	if !event.Room.Players[1].Submitted || event.Room.Players[0].Submitted {
+		t.Errorf("Expected only the guest to have submitted and not %+v", event.Room.Players)
+	}
+	if err := hub.Submit(room.Code, "guest-id", nil); !errors.Is(err, ErrAlreadySubmitted) {
+		t.Errorf("Expected ErrAlreadySubmitted and not %v", err)
+	}
+	if err := hub.Submit(room.Code, "stranger-id", nil); !errors.Is(err, ErrNotInRoom) {
+		t.Errorf("Expected ErrNotInRoom and not %v", err)
+	}
+
+	// The last submission ends the round without waiting for the deadline
+	if err := hub.Submit(room.Code, "host-id", []model.DayPrice{{Day: 1, Price: 20}}); err != nil {
+		t.Fatalf("Expected no error and not %v", err)
+	}
+	event = waitForEvent(t, guest, model.Room_event_results)
+	if event.Results == nil || event.Results.Symbol != "AAPL" || len(event.Results.Stocks) != 1 {
+		t.Fatalf("Expected the stock to be revealed and not %+v", event.Results)
+	}
+	if event.Results.Scores[0].Name != "Bob" || event.Results.Scores[0].Score.Total != 30 {
+		t.Errorf("Expected Bob first with 30 and not %+v", event.Results.Scores)
+	}
+	if event.Room.State != model.Room_state_results || event.Room.Ranking[0].Seat != 2 || event.Room.Ranking[1].Rank != 2 {
+		t.Errorf("Unexpected ranking %+v", event.Room.Ranking)
+	}
+
+	t.Run("Next round keeps the totals", func(t *testing.T) {
+		if err := hub.StartRound(room.Code, "host-id"); err != nil {
+			t.Fatalf("Expected no error and not %v", err)
+		}
+		event := waitForEvent(t, guest, model.Room_event_round_started)
+		if event.Room.Round.Number != 2 || event.Room.Players[1].Submitted || event.Room.Players[1].Total != 30 {
+			t.Errorf("Unexpected room %+v", event.Room)
+		}
+	})
+}
+
+func TestRoundDeadline(t *testing.T) {
+	hub := newHub()
+	hub.RoundDuration = 20 * time.Millisecond
+	room, _ := hub.CreateRoom("host-id", "Alice")
+	host, _ := hub.Join(room.Code, "host-id", "")
+	if _, err := hub.Join(room.Code, "guest-id", "Bob"); err != nil {
+		t.Fatalf("Expected no error and not %v", err)
+	}
+	if err := hub.StartRound(room.Code, "host-id"); err != nil {
+		t.Fatalf("Expected no error and not %v", err)
+	}
+	if err := hub.Submit(room.Code, "host-id", []model.DayPrice{{Day: 1, Price: 10}}); err != nil {
+		t.Fatalf("Expected no error and not %v", err)
+	}
+	event := waitForEvent(t, host, model.Room_event_results)
+	if event.Results.Scores[1].Submitted || event.Results.Scores[1].Score.Total != 0 {
+		t.Errorf("Expected the guest who did not submit to score 0 and not %+v", event.Results.Scores[1])
+	}
+}
+
+func TestLeaveEndsTheRound(t *testing.T) {
+	hub := newHub()
+	room, _ := hub.CreateRoom("host-id", "Alice")
+	host, _ := hub.Join(room.Code, "host-id", "")
+	guest, _ := hub.Join(room.Code, "guest-id", "Bob")
+	hub.StartRound(room.Code, "host-id")
+	hub.Submit(room.Code, "host-id", []model.DayPrice{{Day: 1, Price: 10}})
+
+	guest.Leave()
+	waitForEvent(t, host, model.Room_event_results)
+	// The events already buffered are still delivered before the close
+	for event := range guest.Events {
+		if event.Type == model.Room_event_results {
+			t.Errorf("Expected no results after leaving")
+		}
+	}
+}
+
+func TestHostLeaves(t *testing.T) {
+	hub := newHub()
+	room, _ := hub.CreateRoom("host-id", "Alice")
+	host, _ := hub.Join(room.Code, "host-id", "")
+	guest, _ := hub.Join(room.Code, "guest-id", "Bob")
+	other, _ := hub.Join(room.Code, "other-id", "Carol")
+	other.Leave()
+	if err := hub.StartRound(room.Code, "guest-id"); !errors.Is(err, ErrNotHost) {
+		t.Errorf("Expected the host to stay when a guest leaves and not %v", err)
+	}
+
+	host.Leave()
+	state, _ := hub.GetRoom(room.Code)
+	if state.Players[0].Host || !state.Players[1].Host {
+		t.Errorf("Expected the host handed over to the guest and not %+v", state.Players)
+	}
+	if err := hub.StartRound(room.Code, "guest-id"); err != nil {
+		t.Errorf("Expected the new host to start a round and not %v", err)
+	}
+	if err := hub.StartRound(room.Code, "host-id"); !errors.Is(err, ErrNotHost) {
+		t.Errorf("Expected ErrNotHost for the former host and not %v", err)
+	}
+	guest.Leave()
+}
+
+func TestStartRoundError(t *testing.T) {
+	hub := &HubImpl{Game: &GameMockImpl{NewWindowErr: ErrNoWindow}}
+	room, _ := hub.CreateRoom("host-id", "Alice")
+	if err := hub.StartRound(room.Code, "host-id"); !errors.Is(err, ErrNoWindow) {
+		t.Errorf("Expected ErrNoWindow and not %v", err)
+	}
+	state, _ := hub.GetRoom(room.Code)
+	if state.State != model.Room_state_lobby {
+		t.Errorf("Expected the room to stay in the lobby and not %q", state.State)
+	}
+}
+
+func TestSlowSubscriberIsDropped(t *testing.T) {
+	hub := newHub()
+	room, _ := hub.CreateRoom("host-id", "Alice")
+	slow, _ := hub.Join(room.Code, "host-id", "")
+	for i := 0; i < subscriptionBuffer+1; i++ {
+		hub.Join(room.Code, fmt.Sprintf("player-%d", i%(maxPlayersPerRoom-1)), "")
+	}
+	received := 0
+	for range slow.Events {
+		received++
+	}
+	if received != subscriptionBuffer {
+		t.Errorf("Expected the %d buffered events before the close and not %d", subscriptionBuffer, received)
+	}
+	slow.Leave() // Leaving a dropped subscription does nothing
+}
+
+func TestIdleRoomsAreRemoved(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	hub := newHub()
+	hub.Now = func() time.Time { return now }
+	idle, _ := hub.CreateRoom("host-id", "Alice")
+	active, _ := hub.CreateRoom("other-host-id", "Bob")
+	hub.Join(active.Code, "other-host-id", "")
+
+	now = now.Add(roomIdleTimeout + time.Second)
+	hub.CreateRoom("third-host-id", "Carol")
+
+	if _, err := hub.GetRoom(idle.Code); !errors.Is(err, ErrRoomNotFound) {
+		t.Errorf("Expected the idle room to be removed and not %v", err)
+	}
+	if _, err := hub.GetRoom(active.Code); err != nil {
+		t.Errorf("Expected the room with a connected player to stay and not %v", err)
+	}
+}
+
+func TestConcurrentSubmissions(t *testing.T) {
+	hub := newHub()
+	room, _ := hub.CreateRoom("player-0", "")
+	subscriptions := []*Subscription{}
+	for i := 0; i < maxPlayersPerRoom; i++ {
+		subscription, err := hub.Join(room.Code, fmt.Sprintf("player-%d", i), "")
+		if err != nil {
+			t.Fatalf("Expected no error and not %v", err)
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+	// Drain the events so no subscription is dropped
+	var drained sync.WaitGroup
+	results := make(chan model.RoomEvent, maxPlayersPerRoom)
+	for _, subscription := range subscriptions {
+		drained.Add(1)
+		go func(subscription *Subscription) {
+			defer drained.Done()
+			for event := range subscription.Events {
+				if event.Type == model.Room_event_results {
+					results <- event
+					return
+				}
+			}
+		}(subscription)
+	}
+	if err := hub.StartRound(room.Code, "player-0"); err != nil {
+		t.Fatalf("Expected no error and not %v", err)
+	}
+	var submitted sync.WaitGroup
+	for i := 0; i < maxPlayersPerRoom; i++ {
+		submitted.Add(1)
+		go func(i int) {
+			defer submitted.Done()
+			hub.Submit(room.Code, fmt.Sprintf("player-%d", i), []model.DayPrice{{Day: 1, Price: float64(i + 1)}})
+		}(i)
+	}
+	submitted.Wait()
+	drained.Wait()
+	close(results)
+	count := 0
+	for event := range results {
+		count++
+		if len(event.Results.Scores) != maxPlayersPerRoom || !event.Results.Scores[0].Submitted {
+			t.Errorf("Unexpected results %+v", event.Results)
+		}
+	}
+	if count != maxPlayersPerRoom {
+		t.Errorf("Expected every player to receive the results and not %d", count)
+	}
+}
@@ -0,0 +1,77 @@
+package room
+
+import (
+	"encoding/json"
+	"fmt"
+	"stockgame/internal/model"
+
+	"golang.org/x/net/websocket"
+)
+
+// Errors buffered for a connection while an event is being written
+const socketErrorBuffer = 8
+
+// Serve joins the room and relays the messages of the connection to the hub and the
+// events of the room to the connection until either side closes. The errors of the
+// messages are only sent to the connection that sent them.
+func Serve(hub Hub, ws *websocket.Conn, code string, playerId string, name string) {
+	defer ws.Close()
+	subscription, err := hub.Join(code, playerId, name)
+	if err != nil {
+		websocket.JSON.Send(ws, model.RoomEvent{Type: model.Room_event_error, Error: err.Error()})
+		return
+	}
+	defer subscription.Leave()
+
+	errs := make(chan string, socketErrorBuffer)
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			var data []byte
+			if err := websocket.Message.Receive(ws, &data); err != nil {
+				return
+			}
+			if err := handleMessage(hub, code, playerId, data); err != nil {
+				select {
+				case errs <- err.Error():
+				default:
+				}
+			}
+		}
+	}()
+
+	// Only this goroutine writes to the connection
+	for {
+		select {
+		case event, ok := <-subscription.Events:
+			if !ok {
+				return
+			}
+			if err := websocket.JSON.Send(ws, event); err != nil {
+				return
+			}
+		case message := <-errs:
+			if err := websocket.JSON.Send(ws, model.RoomEvent{Type: model.Room_event_error, Error: message}); err != nil {
+				return
+			}
+		case <-closed:
+			return
+		}
+	}
+}
+
+func handleMessage(hub Hub, code string, playerId string, data []byte) error {
+	var message model.RoomMessage
+	if err := json.Unmarshal(data, &message); err != nil {
+		return fmt.Errorf("invalid message: %v", err)
+	}
+	switch message.Type {
+	case model.Room_message_start:
+		return hub.StartRound(code, playerId)
+	case model.Room_message_submit:
+		return hub.Submit(code, playerId, message.DayPrice)
+	default:
+		return fmt.Errorf("unknown message type %q", message.Type)
+	}
+}
@@ -0,0 +1,97 @@
+package room
+
+import (
+	"net/http/httptest"
+	"stockgame/internal/model"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/net/websocket"
+)
+
+// dialRoom connects a player to the room through a test server
+func dialRoom(t *testing.T, server *httptest.Server, playerId string) *websocket.Conn {
+	t.Helper()
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/?playerId=" + playerId
+	ws, err := websocket.Dial(url, "", server.URL)
+	if err != nil {
+		t.Fatalf("Cannot connect: %v", err)
+	}
+	return ws
+}
+
+// receiveEvent skips the events until one of the type
+func receiveEvent(t *testing.T, ws *websocket.Conn, eventType string) model.RoomEvent {
+	t.Helper()
+	ws.SetReadDeadline(time.Now().Add(time.Second))
+	for {
+		var event model.RoomEvent
+		if err := websocket.JSON.Receive(ws, &event); err != nil {
+			t.Fatalf("Expected a %s event and not %v", eventType, err)
+		}
+		if event.Type == eventType {
+			return event
+		}
+	}
+}
+
+func TestServe(t *testing.T) {
+	hub := newHub()
+	room, _ := hub.CreateRoom("host-id", "Alice")
+	server := httptest.NewServer(websocket.Handler(func(ws *websocket.Conn) {
+		Serve(hub, ws, room.Code, ws.Request().URL.Query().Get("playerId"), "")
+	}))
+	defer server.Close()
+
+	host := dialRoom(t, server, "host-id")
+	defer host.Close()
+	guest := dialRoom(t, server, "guest-id")
+	defer guest.Close()
+	receiveEvent(t, host, model.Room_event_state)
+
+	t.Run("Errors go to the sender only", func(t *testing.T) {
+		websocket.Message.Send(guest, `{"type": "start"}`)
+		event := receiveEvent(t, guest, model.Room_event_error)
+		if event.Error != ErrNotHost.Error() {
+			t.Errorf("Expected %q and not %q", ErrNotHost, event.Error)
+		}
+		websocket.Message.Send(guest, `not json`)
+		event = receiveEvent(t, guest, model.Room_event_error)
+		if !strings.Contains(event.Error, "invalid message") {
+			t.Errorf("Expected an invalid message error and not %q", event.Error)
+		}
+	})
+	t.Run("Round", func(t *testing.T) {
+		websocket.Message.Send(host, `{"type": "start"}`)
+		receiveEvent(t, guest, model.Room_event_round_started)
+		websocket.Message.Send(host, `{"type": "submit", "estimatedDayPrices": [{"day": 1, "price": 12}]}`)
+		websocket.Message.Send(guest, `{"type": "submit", "estimatedDayPrices": [{"day": 1, "price": 8}]}`)
+		event := receiveEvent(t, host, model.Room_event_results)
+		if len(event.Results.Scores) != 2 || event.Results.Scores[0].Score.Total != 12 {
+			t.Errorf("Unexpected results %+v", event.Results)
+		}
+	})
+	t.Run("Closing leaves the room", func(t *testing.T) {
+		guest.Close()
+		event := receiveEvent(t, host, model.Room_event_state)
+		if event.Room.Players[1].Connected {
+			t.Errorf("Expected the guest to be disconnected")
+		}
+	})
+}
+
+func TestServeUnknownRoom(t *testing.T) {
+	hub := newHub()
+	server := httptest.NewServer(websocket.Handler(func(ws *websocket.Conn) {
+		Serve(hub, ws, "NOPE00", "player-id", "")
+	}))
+	defer server.Close()
+
+	ws := dialRoom(t, server, "player-id")
+	defer ws.Close()
+	event := receiveEvent(t, ws, model.Room_event_error)
+	if event.Error != ErrRoomNotFound.Error() {
+		t.Errorf("Expected %q and not %q", ErrRoomNotFound, event.Error)
+	}
+}
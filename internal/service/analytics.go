@@ -0,0 +1,43 @@
+package service
+
+import (
+	"context"
+	"stockgame/internal/dataaccess"
+	"stockgame/internal/database"
+	"stockgame/internal/logic"
+	"stockgame/internal/model"
+)
+
+// maxAnalyticsRounds bounds the number of recent rounds read to compute the global analytics
+const maxAnalyticsRounds = 50000
+
+type AnalyticsService interface {
+	GetPlayerCalibration(playerId string) (model.Calibration, error)
+	GetCalibrationReport() (model.CalibrationReport, error)
+}
+
+type AnalyticsServiceImpl struct {
+	RoundDataAccess dataaccess.RoundDataAccess
+}
+
+// GetPlayerCalibration measures the calibration of the most recent rounds of a player
+func (s *AnalyticsServiceImpl) GetPlayerCalibration(playerId string) (model.Calibration, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), database.CONTEXT_TIMEOUT)
+	defer cancel()
+	rounds, err := s.RoundDataAccess.GetPlayerRounds(ctx, playerId, maxStatsRounds, 0)
+	if err != nil {
+		return model.Calibration{}, err
+	}
+	return logic.GetCalibration(rounds), nil
+}
+
+// GetCalibrationReport measures the calibration of the most recent rounds of every player
+func (s *AnalyticsServiceImpl) GetCalibrationReport() (model.CalibrationReport, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), database.CONTEXT_TIMEOUT)
+	defer cancel()
+	rounds, err := s.RoundDataAccess.GetScoredRounds(ctx, maxAnalyticsRounds)
+	if err != nil {
+		return model.CalibrationReport{}, err
+	}
+	return logic.GetCalibrationReport(rounds), nil
+}
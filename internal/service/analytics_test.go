@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"stockgame/internal/model"
+	"testing"
+)
+
+func TestGetPlayerCalibration(t *testing.T) {
+	mockDataAccess := &RoundDataAccessMockImpl{
+		GetPlayerRoundsFunc: func(ctx context.Context, playerId string, limit, offset int) ([]model.Round, error) {
+			if playerId != "player-1" {
+				t.Errorf("Expected the rounds of player-1 and not %q", playerId)
+			}
+			return []model.Round{{
+				PlayerId:  "player-1",
+				LastClose: 100,
+				DayPrice:  []model.DayPrice{{Day: 1, Price: 110}},
+				Actual:    []float64{100},
+			}}, nil
+		},
+	}
+	mockService := &AnalyticsServiceImpl{RoundDataAccess: mockDataAccess}
+	calibration, err := mockService.GetPlayerCalibration("player-1")
+	if err != nil {
+		t.Fatalf("Expected no error and not %v", err)
+	}
+	if calibration.Days != 1 || calibration.Bias != 10 {
+		t.Errorf("Expected 1 day with a bias of 10%% and not %+v", calibration)
+	}
+}
+
+func TestGetCalibrationReport(t *testing.T) {
+	t.Run("Report", func(t *testing.T) {
+		mockDataAccess := &RoundDataAccessMockImpl{
+			GetScoredRoundsFunc: func(ctx context.Context, limit int) ([]model.Round, error) {
+				if limit != maxAnalyticsRounds {
+					t.Errorf("Expected a limit of %d and not %d", maxAnalyticsRounds, limit)
+				}
+				return []model.Round{{
+					PlayerId:  "player-1",
+					LastClose: 100,
+					DayPrice:  []model.DayPrice{{Day: 1, Price: 90}},
+					Actual:    []float64{100},
+				}}, nil
+			},
+		}
+		mockService := &AnalyticsServiceImpl{RoundDataAccess: mockDataAccess}
+		report, err := mockService.GetCalibrationReport()
+		if err != nil {
+			t.Fatalf("Expected no error and not %v", err)
+		}
+		if report.Global.Bias != -10 || len(report.Players) != 1 {
+			t.Errorf("Unexpected report %+v", report)
+		}
+	})
+	t.Run("Error", func(t *testing.T) {
+		mockDataAccess := &RoundDataAccessMockImpl{
+			GetScoredRoundsFunc: func(ctx context.Context, limit int) ([]model.Round, error) {
+				return nil, fmt.Errorf("db error")
+			},
+		}
+		mockService := &AnalyticsServiceImpl{RoundDataAccess: mockDataAccess}
+		if _, err := mockService.GetCalibrationReport(); err == nil {
+			t.Errorf("Expected an error")
+		}
+	})
+}
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"stockgame/internal/dataaccess"
+	"stockgame/internal/database"
+	"stockgame/internal/model"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrServedBasketSolved  = errors.New("a solution was already accepted for this basket")
+	ErrServedBasketSymbols = errors.New("the solution is not for the symbols of the basket")
+)
+
+type BasketService interface {
+	ServeBasket(playerId string, basket model.BasketWindow) (model.ServedBasket, error)
+	GetBasket(id string, playerId string, symbolUUIDs []string) (model.ServedBasket, error)
+	SolveBasket(id string, playerId string, symbolUUIDs []string) (model.ServedBasket, error)
+}
+
+type BasketServiceImpl struct {
+	BasketDataAccess dataaccess.BasketDataAccess
+	Now              func() time.Time // Nil uses time.Now
+	BasketService
+}
+
+// ServeBasket records the symbols and the date of the basket served to the player
+func (s *BasketServiceImpl) ServeBasket(playerId string, basket model.BasketWindow) (model.ServedBasket, error) {
+	served := model.ServedBasket{
+		Id:          uuid.New().String(),
+		PlayerId:    playerId,
+		AfterDate:   basket.AfterDate,
+		SymbolUUIDs: []string{},
+		ServedAt:    s.now(),
+	}
+	for _, stock := range basket.Stocks {
+		served.SymbolUUIDs = append(served.SymbolUUIDs, stock.SymbolUUID)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), database.CONTEXT_TIMEOUT)
+	defer cancel()
+	if err := s.BasketDataAccess.CreateServedBasket(ctx, served); err != nil {
+		return model.ServedBasket{}, err
+	}
+	return served, nil
+}
+
+// GetBasket returns the basket served to the player that a solution on all of its symbols,
+// in any order, can still solve. It returns the errors of SolveBasket without solving it.
+func (s *BasketServiceImpl) GetBasket(id string, playerId string, symbolUUIDs []string) (model.ServedBasket, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), database.CONTEXT_TIMEOUT)
+	defer cancel()
+	basket, err := s.getBasket(ctx, id, playerId, symbolUUIDs)
+	if err != nil {
+		return model.ServedBasket{}, err
+	}
+	if basket.SolvedAt != nil {
+		return model.ServedBasket{}, ErrServedBasketSolved
+	}
+	return basket, nil
+}
+
+// SolveBasket marks the basket solved by a solution on all of its symbols, in any order,
+// so the future prices are only revealed once for the symbols served. It returns
+// dataaccess.ErrServedBasketNotFound when the basket was not served to the player,
+// ErrServedBasketSymbols when the symbols are not the ones of the basket and
+// ErrServedBasketSolved when a solution was already accepted for it.
+func (s *BasketServiceImpl) SolveBasket(id string, playerId string, symbolUUIDs []string) (model.ServedBasket, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), database.CONTEXT_TIMEOUT)
+	defer cancel()
+	basket, err := s.getBasket(ctx, id, playerId, symbolUUIDs)
+	if err != nil {
+		return model.ServedBasket{}, err
+	}
+	solvedAt := s.now()
+	solved, err := s.BasketDataAccess.SolveServedBasket(ctx, id, solvedAt)
+	if err != nil {
+		return model.ServedBasket{}, err
+	}
+	if !solved {
+		return model.ServedBasket{}, ErrServedBasketSolved
+	}
+	basket.SolvedAt = &solvedAt
+	return basket, nil
+}
+
+// getBasket reads the basket served to the player and checks the symbols are its own
+func (s *BasketServiceImpl) getBasket(ctx context.Context, id string, playerId string, symbolUUIDs []string) (model.ServedBasket, error) {
+	basket, err := s.BasketDataAccess.GetServedBasket(ctx, id)
+	if err != nil {
+		return model.ServedBasket{}, err
+	}
+	// The basket of another player is not found so its id cannot be guessed
+	if basket.PlayerId != playerId {
+		return model.ServedBasket{}, dataaccess.ErrServedBasketNotFound
+	}
+	served := slices.Sorted(slices.Values(basket.SymbolUUIDs))
+	if !slices.Equal(served, slices.Sorted(slices.Values(symbolUUIDs))) {
+		return model.ServedBasket{}, ErrServedBasketSymbols
+	}
+	return basket, nil
+}
+
+func (s *BasketServiceImpl) now() time.Time {
+	if s.Now == nil {
+		return time.Now().UTC()
+	}
+	return s.Now()
+}
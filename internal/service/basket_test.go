@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"stockgame/internal/dataaccess"
+	"stockgame/internal/model"
+	"testing"
+	"time"
+)
+
+type BasketDataAccessMockImpl struct {
+	Basket *model.ServedBasket
+}
+
+func (s *BasketDataAccessMockImpl) CreateServedBasket(ctx context.Context, basket model.ServedBasket) error {
+	s.Basket = &basket
+	return nil
+}
+
+func (s *BasketDataAccessMockImpl) GetServedBasket(ctx context.Context, id string) (model.ServedBasket, error) {
+	if s.Basket == nil || s.Basket.Id != id {
+		return model.ServedBasket{}, dataaccess.ErrServedBasketNotFound
+	}
+	return *s.Basket, nil
+}
+
+func (s *BasketDataAccessMockImpl) SolveServedBasket(ctx context.Context, id string, solvedAt time.Time) (bool, error) {
+	if s.Basket.SolvedAt != nil {
+		return false, nil
+	}
+	s.Basket.SolvedAt = &solvedAt
+	return true, nil
+}
+
+func TestSolveBasket(t *testing.T) {
+	now := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	dataAccess := &BasketDataAccessMockImpl{}
+	mockService := &BasketServiceImpl{BasketDataAccess: dataAccess, Now: func() time.Time { return now }}
+	basket, err := mockService.ServeBasket("player-1", model.BasketWindow{
+		AfterDate: "2023-10-01",
+		Stocks:    []model.BasketStock{{SymbolUUID: "uuid-aapl"}, {SymbolUUID: "uuid-msft"}, {SymbolUUID: "uuid-goog"}},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error and not %v", err)
+	}
+	if basket.Id == "" || len(dataAccess.Basket.SymbolUUIDs) != 3 || dataAccess.Basket.AfterDate != "2023-10-01" {
+		t.Fatalf("Expected the basket to be saved and not %+v", dataAccess.Basket)
+	}
+
+	t.Run("Another player", func(t *testing.T) {
+		if _, err := mockService.SolveBasket(basket.Id, "player-2", []string{"uuid-aapl", "uuid-msft", "uuid-goog"}); !errors.Is(err, dataaccess.ErrServedBasketNotFound) {
+			t.Errorf("Expected ErrServedBasketNotFound and not %v", err)
+		}
+	})
+	t.Run("Other symbols", func(t *testing.T) {
+		for _, symbols := range [][]string{{"uuid-aapl", "uuid-msft"}, {"uuid-aapl", "uuid-msft", "uuid-tsla"}} {
+			if _, err := mockService.SolveBasket(basket.Id, "player-1", symbols); !errors.Is(err, ErrServedBasketSymbols) {
+				t.Errorf("%v: expected ErrServedBasketSymbols and not %v", symbols, err)
+			}
+		}
+	})
+	t.Run("Get before solving", func(t *testing.T) {
+		served, err := mockService.GetBasket(basket.Id, "player-1", []string{"uuid-msft", "uuid-goog", "uuid-aapl"})
+		if err != nil || served.AfterDate != "2023-10-01" || served.SolvedAt != nil {
+			t.Fatalf("Expected the unsolved basket and not %+v %v", served, err)
+		}
+		if dataAccess.Basket.SolvedAt != nil {
+			t.Errorf("Expected the basket not to be solved by reading it")
+		}
+		if _, err := mockService.GetBasket(basket.Id, "player-2", []string{"uuid-aapl", "uuid-msft", "uuid-goog"}); !errors.Is(err, dataaccess.ErrServedBasketNotFound) {
+			t.Errorf("Expected ErrServedBasketNotFound and not %v", err)
+		}
+	})
+	t.Run("Solved once", func(t *testing.T) {
+		solved, err := mockService.SolveBasket(basket.Id, "player-1", []string{"uuid-goog", "uuid-aapl", "uuid-msft"})
+		if err != nil {
+			t.Fatalf("Expected no error and not %v", err)
+		}
+		if solved.AfterDate != "2023-10-01" || solved.SolvedAt == nil {
+			t.Errorf("Unexpected basket %+v", solved)
+		}
+		if _, err := mockService.SolveBasket(basket.Id, "player-1", []string{"uuid-aapl", "uuid-msft", "uuid-goog"}); !errors.Is(err, ErrServedBasketSolved) {
+			t.Errorf("Expected ErrServedBasketSolved and not %v", err)
+		}
+		if _, err := mockService.GetBasket(basket.Id, "player-1", []string{"uuid-aapl", "uuid-msft", "uuid-goog"}); !errors.Is(err, ErrServedBasketSolved) {
+			t.Errorf("Expected ErrServedBasketSolved when reading it and not %v", err)
+		}
+	})
+}
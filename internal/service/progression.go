@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"stockgame/internal/dataaccess"
+	"stockgame/internal/database"
+	"stockgame/internal/logic"
+	"stockgame/internal/model"
+	"time"
+)
+
+// ErrProgressConflict is returned when the progress kept being saved by other rounds of the player
+var ErrProgressConflict = errors.New("the progress was updated by another round")
+
+// progressSaveTries bounds the number of times a round is applied to a progress saved meanwhile
+const progressSaveTries = 3
+
+type ProgressionService interface {
+	RecordRound(round model.Round) ([]model.Achievement, error)
+	GetProgress(playerId string) (model.ProgressResponse, error)
+	ListAchievements() []model.Achievement
+}
+
+type ProgressionServiceImpl struct {
+	ProgressionDataAccess dataaccess.ProgressionDataAccess
+	Rules                 []logic.AchievementRule // Nil uses logic.DefaultAchievementRules
+	Now                   func() time.Time        // Nil uses time.Now
+	ProgressionService
+}
+
+// RecordRound awards the XP of a saved round, updates the streak and returns the
+// achievements it unlocked. Rounds without player are not recorded.
+func (s *ProgressionServiceImpl) RecordRound(round model.Round) ([]model.Achievement, error) {
+	if round.PlayerId == "" {
+		return nil, nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), database.CONTEXT_TIMEOUT)
+	defer cancel()
+	for range progressSaveTries {
+		progress, err := s.ProgressionDataAccess.GetProgress(ctx, round.PlayerId)
+		if err != nil {
+			return nil, err
+		}
+		updated, unlocked := logic.ApplyRound(progress, round, s.rules())
+		saved, err := s.ProgressionDataAccess.SaveProgress(ctx, round.PlayerId, updated)
+		if err != nil {
+			return nil, err
+		}
+		if saved {
+			return unlocked, nil
+		}
+	}
+	return nil, ErrProgressConflict
+}
+
+// GetProgress returns the level, the streak and every achievement of the player
+func (s *ProgressionServiceImpl) GetProgress(playerId string) (model.ProgressResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), database.CONTEXT_TIMEOUT)
+	defer cancel()
+	progress, err := s.ProgressionDataAccess.GetProgress(ctx, playerId)
+	if err != nil {
+		return model.ProgressResponse{}, err
+	}
+	return logic.GetProgressResponse(progress, s.rules(), s.now().Format(time.DateOnly)), nil
+}
+
+func (s *ProgressionServiceImpl) ListAchievements() []model.Achievement {
+	rules := s.rules()
+	achievements := make([]model.Achievement, len(rules))
+	for i, rule := range rules {
+		achievements[i] = rule.Achievement
+	}
+	return achievements
+}
+
+func (s *ProgressionServiceImpl) rules() []logic.AchievementRule {
+	if s.Rules == nil {
+		return logic.DefaultAchievementRules
+	}
+	return s.Rules
+}
+
+func (s *ProgressionServiceImpl) now() time.Time {
+	if s.Now == nil {
+		return time.Now().UTC()
+	}
+	return s.Now()
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"stockgame/internal/model"
+	"testing"
+	"time"
+)
+
+type ProgressionDataAccessMockImpl struct {
+	Progress  model.PlayerProgress
+	Conflicts int // Number of saves that fail because another round was saved first
+	Saves     int
+}
+
+func (s *ProgressionDataAccessMockImpl) GetProgress(ctx context.Context, playerId string) (model.PlayerProgress, error) {
+	return s.Progress, nil
+}
+
+func (s *ProgressionDataAccessMockImpl) SaveProgress(ctx context.Context, playerId string, progress model.PlayerProgress) (bool, error) {
+	s.Saves++
+	if s.Saves <= s.Conflicts {
+		return false, nil
+	}
+	progress.Version++
+	s.Progress = progress
+	return true, nil
+}
+
+func TestRecordRoundProgress(t *testing.T) {
+	round := model.Round{PlayerId: "player-1", CreatedAt: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC), Score: model.UserScoreResponse{Total: 30}}
+	t.Run("First round", func(t *testing.T) {
+		dataAccess := &ProgressionDataAccessMockImpl{Conflicts: 1}
+		progressionService := &ProgressionServiceImpl{ProgressionDataAccess: dataAccess}
+		unlocked, err := progressionService.RecordRound(round)
+		if err != nil {
+			t.Fatalf("Expected no error and not %v", err)
+		}
+		if len(unlocked) != 1 || unlocked[0].Id != "first_round" {
+			t.Errorf("Expected the first round achievement and not %+v", unlocked)
+		}
+		if dataAccess.Saves != 2 || dataAccess.Progress.XP != 40 || dataAccess.Progress.LastPlayedDate != "2024-05-01" {
+			t.Errorf("Expected the round to be saved after the conflict and not %d saves of %+v", dataAccess.Saves, dataAccess.Progress)
+		}
+	})
+	t.Run("Conflicts", func(t *testing.T) {
+		dataAccess := &ProgressionDataAccessMockImpl{Conflicts: progressSaveTries}
+		progressionService := &ProgressionServiceImpl{ProgressionDataAccess: dataAccess}
+		if _, err := progressionService.RecordRound(round); !errors.Is(err, ErrProgressConflict) {
+			t.Errorf("Expected ErrProgressConflict and not %v", err)
+		}
+	})
+	t.Run("Anonymous round", func(t *testing.T) {
+		dataAccess := &ProgressionDataAccessMockImpl{}
+		progressionService := &ProgressionServiceImpl{ProgressionDataAccess: dataAccess}
+		anonymous := round
+		anonymous.PlayerId = ""
+		if _, err := progressionService.RecordRound(anonymous); err != nil || dataAccess.Saves != 0 {
+			t.Errorf("Expected the round not to be recorded and not %d saves, %v", dataAccess.Saves, err)
+		}
+	})
+}
+
+func TestGetPlayerProgress(t *testing.T) {
+	dataAccess := &ProgressionDataAccessMockImpl{Progress: model.PlayerProgress{XP: 120, CurrentStreak: 2, LastPlayedDate: "2024-05-01"}}
+	progressionService := &ProgressionServiceImpl{
+		ProgressionDataAccess: dataAccess,
+		Now:                   func() time.Time { return time.Date(2024, 5, 2, 8, 0, 0, 0, time.UTC) },
+	}
+	progress, err := progressionService.GetProgress("player-1")
+	if err != nil {
+		t.Fatalf("Expected no error and not %v", err)
+	}
+	if progress.Level != 2 || progress.CurrentStreak != 2 || len(progress.Achievements) != len(progressionService.ListAchievements()) {
+		t.Errorf("Unexpected progress %+v", progress)
+	}
+}
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"stockgame/internal/dataaccess"
+	"stockgame/internal/database"
+	"stockgame/internal/logic"
+	"stockgame/internal/model"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type RoundService interface {
+	SaveRound(round model.Round) (model.Round, []model.Achievement, error)
+	GetRound(id string) (model.Round, error)
+	GetPercentiles(round model.Round) model.SolutionPercentiles
+	GetPlayerRounds(playerId string, page int, pageSize int) (model.PlayerRoundsResponse, error)
+	GetPlayerStats(playerId string) (model.PlayerStats, error)
+}
+
+// maxStatsRounds bounds the number of recent rounds read to compute the stats of a player
+const maxStatsRounds = 5000
+
+type RoundServiceImpl struct {
+	RoundDataAccess    dataaccess.RoundDataAccess
+	ProgressionService ProgressionService // Nil does not record the progression of the players
+}
+
+// SaveRound assigns an id to the round when it has none and persists it. The progression of the player is
+// then updated and the achievements it unlocked are returned, the round is still saved when
+// it cannot be. The caller saves a round once, for the first solution of a served window.
+func (s *RoundServiceImpl) SaveRound(round model.Round) (model.Round, []model.Achievement, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), database.CONTEXT_TIMEOUT)
+	defer cancel()
+	if round.Id == "" {
+		round.Id = uuid.New().String()
+	}
+	round.CreatedAt = time.Now().UTC()
+	if err := s.RoundDataAccess.SaveRound(ctx, round); err != nil {
+		return round, nil, err
+	}
+	if s.ProgressionService == nil {
+		return round, nil, nil
+	}
+	unlocked, err := s.ProgressionService.RecordRound(round)
+	if err != nil {
+		fmt.Println("SaveRound Error recording progression: ", err)
+	}
+	return round, unlocked, nil
+}
+
+// GetRound returns dataaccess.ErrRoundNotFound when no round has the id
+func (s *RoundServiceImpl) GetRound(id string) (model.Round, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), database.CONTEXT_TIMEOUT)
+	defer cancel()
+	return s.RoundDataAccess.GetRound(ctx, id)
+}
+
+// GetPercentiles ranks a saved round among the rounds of the same window and, when the
+// difficulty of the window is known, among the rounds of the same difficulty. Only the
+// first solution of a served window is ranked, a round without it is left unranked. A rank
+// that cannot be computed is left empty: the score is still valid without it.
+func (s *RoundServiceImpl) GetPercentiles(round model.Round) model.SolutionPercentiles {
+	percentiles := model.SolutionPercentiles{}
+	if round.ServedAt == nil {
+		return percentiles
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), database.CONTEXT_TIMEOUT)
+	defer cancel()
+	count, err := s.RoundDataAccess.CountScoresForWindow(ctx, round.Symbol, round.AfterDate, round.Score.Total)
+	if err != nil {
+		fmt.Println("GetPercentiles Error counting window scores: ", err)
+	} else {
+		percentiles.Window = logic.PercentileRank(count)
+	}
+	if round.Difficulty == "" {
+		return percentiles
+	}
+	count, err = s.RoundDataAccess.CountScoresForDifficulty(ctx, round.Difficulty, round.Score.Total)
+	if err != nil {
+		fmt.Println("GetPercentiles Error counting difficulty scores: ", err)
+	} else {
+		percentiles.Difficulty = logic.PercentileRank(count)
+	}
+	return percentiles
+}
+
+// GetPlayerRounds returns one page of the rounds of a player, the first page is 1
+func (s *RoundServiceImpl) GetPlayerRounds(playerId string, page int, pageSize int) (model.PlayerRoundsResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), database.CONTEXT_TIMEOUT)
+	defer cancel()
+	response := model.PlayerRoundsResponse{Page: page, PageSize: pageSize, Rounds: []model.Round{}}
+	total, err := s.RoundDataAccess.CountPlayerRounds(ctx, playerId)
+	if err != nil {
+		return response, err
+	}
+	response.Total = total
+	// Past the last page, also keeps the offset from overflowing
+	if total == 0 || page > (total+pageSize-1)/pageSize {
+		return response, nil
+	}
+	rounds, err := s.RoundDataAccess.GetPlayerRounds(ctx, playerId, pageSize, (page-1)*pageSize)
+	if err != nil {
+		return response, err
+	}
+	response.Rounds = rounds
+	return response, nil
+}
+
+// GetPlayerStats summarizes the most recent rounds of a player, the count covers every round
+func (s *RoundServiceImpl) GetPlayerStats(playerId string) (model.PlayerStats, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), database.CONTEXT_TIMEOUT)
+	defer cancel()
+	total, err := s.RoundDataAccess.CountPlayerRounds(ctx, playerId)
+	if err != nil {
+		return model.PlayerStats{}, err
+	}
+	rounds, err := s.RoundDataAccess.GetPlayerRounds(ctx, playerId, maxStatsRounds, 0)
+	if err != nil {
+		return model.PlayerStats{}, err
+	}
+	stats := logic.GetPlayerStats(rounds)
+	stats.Rounds = total
+	return stats, nil
+}
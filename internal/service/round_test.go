@@ -0,0 +1,267 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"stockgame/internal/model"
+	"testing"
+	"time"
+)
+
+type RoundDataAccessMockImpl struct {
+	SaveRoundFunc                func(ctx context.Context, round model.Round) error
+	GetRoundFunc                 func(ctx context.Context, id string) (model.Round, error)
+	CountScoresForWindowFunc     func(ctx context.Context, symbol, afterDate string, total int) (model.ScoreCount, error)
+	CountScoresForDifficultyFunc func(ctx context.Context, difficulty string, total int) (model.ScoreCount, error)
+	CountScoresForDifficultyCall int
+	GetPlayerRoundsFunc          func(ctx context.Context, playerId string, limit, offset int) ([]model.Round, error)
+	CountPlayerRoundsFunc        func(ctx context.Context, playerId string) (int, error)
+	GetPlayerRoundsCall          int
+	GetScoredRoundsFunc          func(ctx context.Context, limit int) ([]model.Round, error)
+}
+
+func (s *RoundDataAccessMockImpl) SaveRound(ctx context.Context, round model.Round) error {
+	if s.SaveRoundFunc != nil {
+		return s.SaveRoundFunc(ctx, round)
+	}
+	return nil
+}
+
+func (s *RoundDataAccessMockImpl) GetRound(ctx context.Context, id string) (model.Round, error) {
+	if s.GetRoundFunc != nil {
+		return s.GetRoundFunc(ctx, id)
+	}
+	return model.Round{}, nil
+}
+
+func (s *RoundDataAccessMockImpl) CountScoresForWindow(ctx context.Context, symbol, afterDate string, total int) (model.ScoreCount, error) {
+	if s.CountScoresForWindowFunc != nil {
+		return s.CountScoresForWindowFunc(ctx, symbol, afterDate, total)
+	}
+	return model.ScoreCount{}, nil
+}
+
+func (s *RoundDataAccessMockImpl) CountScoresForDifficulty(ctx context.Context, difficulty string, total int) (model.ScoreCount, error) {
+	s.CountScoresForDifficultyCall++
+	if s.CountScoresForDifficultyFunc != nil {
+		return s.CountScoresForDifficultyFunc(ctx, difficulty, total)
+	}
+	return model.ScoreCount{}, nil
+}
+
+func (s *RoundDataAccessMockImpl) GetPlayerRounds(ctx context.Context, playerId string, limit, offset int) ([]model.Round, error) {
+	s.GetPlayerRoundsCall++
+	if s.GetPlayerRoundsFunc != nil {
+		return s.GetPlayerRoundsFunc(ctx, playerId, limit, offset)
+	}
+	return []model.Round{}, nil
+}
+
+func (s *RoundDataAccessMockImpl) CountPlayerRounds(ctx context.Context, playerId string) (int, error) {
+	if s.CountPlayerRoundsFunc != nil {
+		return s.CountPlayerRoundsFunc(ctx, playerId)
+	}
+	return 0, nil
+}
+
+func (s *RoundDataAccessMockImpl) GetScoredRounds(ctx context.Context, limit int) ([]model.Round, error) {
+	if s.GetScoredRoundsFunc != nil {
+		return s.GetScoredRoundsFunc(ctx, limit)
+	}
+	return []model.Round{}, nil
+}
+
+func TestSaveRound(t *testing.T) {
+	var saved model.Round
+	mockDataAccess := &RoundDataAccessMockImpl{
+		SaveRoundFunc: func(ctx context.Context, round model.Round) error {
+			saved = round
+			return nil
+		},
+	}
+	mockService := &RoundServiceImpl{RoundDataAccess: mockDataAccess}
+	round, _, err := mockService.SaveRound(model.Round{Symbol: "AAPL"})
+	if err != nil {
+		t.Errorf("Expected no error and not %v", err)
+	}
+	if round.Id == "" || round.Id != saved.Id {
+		t.Errorf("Expected the saved round to receive an id and not %q", round.Id)
+	}
+	if round.CreatedAt.IsZero() {
+		t.Errorf("Expected the round to have a creation date")
+	}
+	round, _, _ = mockService.SaveRound(model.Round{Id: "round-1"})
+	if round.Id != "round-1" || saved.Id != "round-1" {
+		t.Errorf("Expected the round to keep its id and not %q", round.Id)
+	}
+}
+
+func TestSaveRoundProgression(t *testing.T) {
+	progression := &ProgressionDataAccessMockImpl{}
+	mockService := &RoundServiceImpl{
+		RoundDataAccess:    &RoundDataAccessMockImpl{},
+		ProgressionService: &ProgressionServiceImpl{ProgressionDataAccess: progression},
+	}
+	_, unlocked, err := mockService.SaveRound(model.Round{PlayerId: "player-1"})
+	if err != nil {
+		t.Fatalf("Expected no error and not %v", err)
+	}
+	if progression.Progress.Rounds != 1 {
+		t.Errorf("Expected the round to be recorded in the progression and not %+v", progression.Progress)
+	}
+	if len(unlocked) != 1 || unlocked[0].Id != "first_round" {
+		t.Errorf("Expected the first round achievement to be returned and not %+v", unlocked)
+	}
+
+	failing := &RoundDataAccessMockImpl{
+		SaveRoundFunc: func(ctx context.Context, round model.Round) error { return fmt.Errorf("database down") },
+	}
+	mockService.RoundDataAccess = failing
+	if _, _, err := mockService.SaveRound(model.Round{PlayerId: "player-1"}); err == nil {
+		t.Fatal("Expected the error of the database")
+	}
+	if progression.Progress.Rounds != 1 {
+		t.Errorf("Expected a round not saved to be left out of the progression")
+	}
+}
+
+func TestGetPercentiles(t *testing.T) {
+	servedAt := time.Date(2023, 2, 1, 0, 0, 0, 0, time.UTC)
+	t.Run("Unknown difficulty", func(t *testing.T) {
+		mockDataAccess := &RoundDataAccessMockImpl{
+			CountScoresForWindowFunc: func(ctx context.Context, symbol, afterDate string, total int) (model.ScoreCount, error) {
+				return model.ScoreCount{Below: 3, Equal: 1, Total: 5}, nil
+			},
+		}
+		mockService := &RoundServiceImpl{RoundDataAccess: mockDataAccess}
+		percentiles := mockService.GetPercentiles(model.Round{Symbol: "AAPL", AfterDate: "2023-01-31", ServedAt: &servedAt})
+		if percentiles.Window == nil || percentiles.Window.Percentile != 75 {
+			t.Errorf("Expected 75%% for the window and not %+v", percentiles.Window)
+		}
+		if percentiles.Difficulty != nil || mockDataAccess.CountScoresForDifficultyCall != 0 {
+			t.Errorf("Expected no difficulty rank when the difficulty is unknown")
+		}
+	})
+	t.Run("Counting error", func(t *testing.T) {
+		mockDataAccess := &RoundDataAccessMockImpl{
+			CountScoresForWindowFunc: func(ctx context.Context, symbol, afterDate string, total int) (model.ScoreCount, error) {
+				return model.ScoreCount{}, fmt.Errorf("db error")
+			},
+			CountScoresForDifficultyFunc: func(ctx context.Context, difficulty string, total int) (model.ScoreCount, error) {
+				return model.ScoreCount{Below: 1, Equal: 1, Total: 3}, nil
+			},
+		}
+		mockService := &RoundServiceImpl{RoundDataAccess: mockDataAccess}
+		percentiles := mockService.GetPercentiles(model.Round{Difficulty: "hard", ServedAt: &servedAt})
+		if percentiles.Window != nil {
+			t.Errorf("Expected no window rank on error and not %+v", percentiles.Window)
+		}
+		if percentiles.Difficulty == nil || percentiles.Difficulty.Percentile != 50 {
+			t.Errorf("Expected 50%% for the difficulty and not %+v", percentiles.Difficulty)
+		}
+	})
+	t.Run("Window not served", func(t *testing.T) {
+		mockDataAccess := &RoundDataAccessMockImpl{}
+		mockService := &RoundServiceImpl{RoundDataAccess: mockDataAccess}
+		percentiles := mockService.GetPercentiles(model.Round{Symbol: "AAPL", AfterDate: "2023-01-31", Difficulty: "hard"})
+		if percentiles.Window != nil || percentiles.Difficulty != nil || mockDataAccess.CountScoresForDifficultyCall != 0 {
+			t.Errorf("Expected a round without a served window to be left unranked and not %+v", percentiles)
+		}
+	})
+}
+
+func TestGetPlayerRounds(t *testing.T) {
+	t.Run("Second page", func(t *testing.T) {
+		var gotLimit, gotOffset int
+		mockDataAccess := &RoundDataAccessMockImpl{
+			CountPlayerRoundsFunc: func(ctx context.Context, playerId string) (int, error) {
+				return 25, nil
+			},
+			GetPlayerRoundsFunc: func(ctx context.Context, playerId string, limit, offset int) ([]model.Round, error) {
+				gotLimit, gotOffset = limit, offset
+				return []model.Round{{Id: "round-11"}}, nil
+			},
+		}
+		mockService := &RoundServiceImpl{RoundDataAccess: mockDataAccess}
+		response, err := mockService.GetPlayerRounds("player-1", 2, 10)
+		if err != nil {
+			t.Fatalf("Expected no error and not %v", err)
+		}
+		if gotLimit != 10 || gotOffset != 10 {
+			t.Errorf("Expected limit 10 offset 10 and not limit %d offset %d", gotLimit, gotOffset)
+		}
+		if response.Total != 25 || len(response.Rounds) != 1 || response.Page != 2 {
+			t.Errorf("Unexpected response %+v", response)
+		}
+	})
+	t.Run("Past the last page skips the query", func(t *testing.T) {
+		mockDataAccess := &RoundDataAccessMockImpl{
+			CountPlayerRoundsFunc: func(ctx context.Context, playerId string) (int, error) {
+				return 25, nil
+			},
+		}
+		mockService := &RoundServiceImpl{RoundDataAccess: mockDataAccess}
+		response, err := mockService.GetPlayerRounds("player-1", math.MaxInt, 10)
+		if err != nil {
+			t.Fatalf("Expected no error and not %v", err)
+		}
+		if mockDataAccess.GetPlayerRoundsCall != 0 {
+			t.Errorf("Expected no rounds query")
+		}
+		if response.Total != 25 || len(response.Rounds) != 0 {
+			t.Errorf("Unexpected response %+v", response)
+		}
+	})
+	t.Run("No round skips the query", func(t *testing.T) {
+		mockDataAccess := &RoundDataAccessMockImpl{}
+		mockService := &RoundServiceImpl{RoundDataAccess: mockDataAccess}
+		response, err := mockService.GetPlayerRounds("player-1", 1, 10)
+		if err != nil {
+			t.Fatalf("Expected no error and not %v", err)
+		}
+		if mockDataAccess.GetPlayerRoundsCall != 0 {
+			t.Errorf("Expected no rounds query")
+		}
+		if response.Rounds == nil {
+			t.Errorf("Expected an empty list and not nil")
+		}
+	})
+	t.Run("Count error", func(t *testing.T) {
+		mockDataAccess := &RoundDataAccessMockImpl{
+			CountPlayerRoundsFunc: func(ctx context.Context, playerId string) (int, error) {
+				return 0, fmt.Errorf("db error")
+			},
+		}
+		mockService := &RoundServiceImpl{RoundDataAccess: mockDataAccess}
+		_, err := mockService.GetPlayerRounds("player-1", 1, 10)
+		if err == nil {
+			t.Errorf("Expected an error")
+		}
+	})
+}
+
+func TestGetPlayerStats(t *testing.T) {
+	mockDataAccess := &RoundDataAccessMockImpl{
+		CountPlayerRoundsFunc: func(ctx context.Context, playerId string) (int, error) {
+			return maxStatsRounds + 1, nil
+		},
+		GetPlayerRoundsFunc: func(ctx context.Context, playerId string, limit, offset int) ([]model.Round, error) {
+			if offset != 0 || limit != maxStatsRounds {
+				t.Errorf("Expected the most recent %d rounds and not limit %d offset %d", maxStatsRounds, limit, offset)
+			}
+			return []model.Round{
+				{Score: model.UserScoreResponse{Total: 10}},
+				{Score: model.UserScoreResponse{Total: 30}},
+			}, nil
+		},
+	}
+	mockService := &RoundServiceImpl{RoundDataAccess: mockDataAccess}
+	stats, err := mockService.GetPlayerStats("player-1")
+	if err != nil {
+		t.Fatalf("Expected no error and not %v", err)
+	}
+	if stats.Rounds != maxStatsRounds+1 || stats.AnalyzedRounds != 2 || stats.Score.Total.Average != 20 {
+		t.Errorf("Unexpected stats %+v", stats)
+	}
+}
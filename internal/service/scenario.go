@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"stockgame/internal/dataaccess"
+	"stockgame/internal/database"
+	"stockgame/internal/model"
+	"strings"
+	"time"
+)
+
+var ErrInvalidScenario = errors.New("invalid scenario")
+
+const maxScenarioNameLength = 64
+
+const maxScenarioSymbols = 100
+
+var scenarioIdPattern = regexp.MustCompile(`^[a-z0-9-]{1,64}$`)
+
+type ScenarioService interface {
+	CreateScenario(request model.ScenarioRequest) (model.Scenario, error)
+	UpdateScenario(id string, request model.ScenarioRequest) (model.Scenario, error)
+	DeleteScenario(id string) error
+	GetScenario(id string) (model.Scenario, error)
+	ListScenarios() ([]model.Scenario, error)
+}
+
+type ScenarioServiceImpl struct {
+	ScenarioDataAccess dataaccess.ScenarioDataAccess
+	Now                func() time.Time // Nil uses time.Now
+	ScenarioService
+}
+
+// CreateScenario returns dataaccess.ErrScenarioExists when the id is already used
+func (s *ScenarioServiceImpl) CreateScenario(request model.ScenarioRequest) (model.Scenario, error) {
+	scenario, err := newScenario(request.Id, request)
+	if err != nil {
+		return scenario, err
+	}
+	scenario.CreatedAt = s.now()
+	ctx, cancel := context.WithTimeout(context.Background(), database.CONTEXT_TIMEOUT)
+	defer cancel()
+	if err := s.ScenarioDataAccess.CreateScenario(ctx, scenario); err != nil {
+		return model.Scenario{}, err
+	}
+	return scenario, nil
+}
+
+// UpdateScenario replaces the name, the dates and the symbols of the scenario
+func (s *ScenarioServiceImpl) UpdateScenario(id string, request model.ScenarioRequest) (model.Scenario, error) {
+	scenario, err := newScenario(id, request)
+	if err != nil {
+		return scenario, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), database.CONTEXT_TIMEOUT)
+	defer cancel()
+	if err := s.ScenarioDataAccess.UpdateScenario(ctx, scenario); err != nil {
+		return model.Scenario{}, err
+	}
+	return s.ScenarioDataAccess.GetScenario(ctx, id)
+}
+
+func (s *ScenarioServiceImpl) DeleteScenario(id string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), database.CONTEXT_TIMEOUT)
+	defer cancel()
+	return s.ScenarioDataAccess.DeleteScenario(ctx, id)
+}
+
+// GetScenario returns dataaccess.ErrScenarioNotFound when no scenario has the id
+func (s *ScenarioServiceImpl) GetScenario(id string) (model.Scenario, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), database.CONTEXT_TIMEOUT)
+	defer cancel()
+	return s.ScenarioDataAccess.GetScenario(ctx, id)
+}
+
+func (s *ScenarioServiceImpl) ListScenarios() ([]model.Scenario, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), database.CONTEXT_TIMEOUT)
+	defer cancel()
+	return s.ScenarioDataAccess.ListScenarios(ctx)
+}
+
+// newScenario validates the request. The symbols are trimmed, upper cased and deduplicated.
+func newScenario(id string, request model.ScenarioRequest) (model.Scenario, error) {
+	name := strings.TrimSpace(request.Name)
+	start, startErr := time.Parse(time.DateOnly, request.StartDate)
+	end, endErr := time.Parse(time.DateOnly, request.EndDate)
+	switch {
+	case !scenarioIdPattern.MatchString(id):
+		return model.Scenario{}, fmt.Errorf("%w: the id must have 1 to 64 lowercase letters, digits or dashes", ErrInvalidScenario)
+	case name == "" || len(name) > maxScenarioNameLength:
+		return model.Scenario{}, fmt.Errorf("%w: the name must have 1 to %d characters", ErrInvalidScenario, maxScenarioNameLength)
+	case startErr != nil || endErr != nil:
+		return model.Scenario{}, fmt.Errorf("%w: startDate and endDate must be formatted as YYYY-MM-DD", ErrInvalidScenario)
+	case !end.After(start):
+		return model.Scenario{}, fmt.Errorf("%w: endDate must be after startDate", ErrInvalidScenario)
+	case len(request.Symbols) > maxScenarioSymbols:
+		return model.Scenario{}, fmt.Errorf("%w: a scenario has at most %d symbols", ErrInvalidScenario, maxScenarioSymbols)
+	}
+	symbols := []string{}
+	seen := map[string]bool{}
+	for _, symbol := range request.Symbols {
+		symbol = strings.ToUpper(strings.TrimSpace(symbol))
+		if symbol == "" {
+			return model.Scenario{}, fmt.Errorf("%w: a symbol cannot be empty", ErrInvalidScenario)
+		}
+		if !seen[symbol] {
+			seen[symbol] = true
+			symbols = append(symbols, symbol)
+		}
+	}
+	return model.Scenario{
+		Id:        id,
+		Name:      name,
+		StartDate: request.StartDate,
+		EndDate:   request.EndDate,
+		Symbols:   symbols,
+	}, nil
+}
+
+func (s *ScenarioServiceImpl) now() time.Time {
+	if s.Now == nil {
+		return time.Now().UTC()
+	}
+	return s.Now()
+}
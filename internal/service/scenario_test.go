@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"stockgame/internal/dataaccess"
+	"stockgame/internal/model"
+	"testing"
+	"time"
+)
+
+type ScenarioDataAccessMockImpl struct {
+	Scenarios map[string]model.Scenario
+}
+
+func (s *ScenarioDataAccessMockImpl) CreateScenario(ctx context.Context, scenario model.Scenario) error {
+	if _, ok := s.Scenarios[scenario.Id]; ok {
+		return dataaccess.ErrScenarioExists
+	}
+	s.Scenarios[scenario.Id] = scenario
+	return nil
+}
+
+func (s *ScenarioDataAccessMockImpl) UpdateScenario(ctx context.Context, scenario model.Scenario) error {
+	previous, ok := s.Scenarios[scenario.Id]
+	if !ok {
+		return dataaccess.ErrScenarioNotFound
+	}
+	scenario.CreatedAt = previous.CreatedAt
+	s.Scenarios[scenario.Id] = scenario
+	return nil
+}
+
+func (s *ScenarioDataAccessMockImpl) DeleteScenario(ctx context.Context, id string) error {
+	if _, ok := s.Scenarios[id]; !ok {
+		return dataaccess.ErrScenarioNotFound
+	}
+	delete(s.Scenarios, id)
+	return nil
+}
+
+func (s *ScenarioDataAccessMockImpl) GetScenario(ctx context.Context, id string) (model.Scenario, error) {
+	scenario, ok := s.Scenarios[id]
+	if !ok {
+		return scenario, dataaccess.ErrScenarioNotFound
+	}
+	return scenario, nil
+}
+
+func (s *ScenarioDataAccessMockImpl) ListScenarios(ctx context.Context) ([]model.Scenario, error) {
+	scenarios := []model.Scenario{}
+	for _, scenario := range s.Scenarios {
+		scenarios = append(scenarios, scenario)
+	}
+	return scenarios, nil
+}
+
+func TestCreateScenario(t *testing.T) {
+	now := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	mockDataAccess := &ScenarioDataAccessMockImpl{Scenarios: map[string]model.Scenario{}}
+	scenarioService := &ScenarioServiceImpl{ScenarioDataAccess: mockDataAccess, Now: func() time.Time { return now }}
+
+	scenario, err := scenarioService.CreateScenario(model.ScenarioRequest{
+		Id:        "covid-2020",
+		Name:      " COVID drop ",
+		StartDate: "2020-02-19",
+		EndDate:   "2020-03-23",
+		Symbols:   []string{"aapl", " MSFT", "AAPL"},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if scenario.Name != "COVID drop" || !slices.Equal(scenario.Symbols, []string{"AAPL", "MSFT"}) || !scenario.CreatedAt.Equal(now) {
+		t.Errorf("Unexpected scenario %+v", scenario)
+	}
+	if _, ok := mockDataAccess.Scenarios["covid-2020"]; !ok {
+		t.Error("Expected the scenario to be saved")
+	}
+	_, err = scenarioService.CreateScenario(model.ScenarioRequest{Id: "covid-2020", Name: "Again", StartDate: "2020-02-19", EndDate: "2020-03-23"})
+	if !errors.Is(err, dataaccess.ErrScenarioExists) {
+		t.Errorf("Expected ErrScenarioExists and not %v", err)
+	}
+}
+
+func TestCreateScenario_Invalid(t *testing.T) {
+	scenarioService := &ScenarioServiceImpl{ScenarioDataAccess: &ScenarioDataAccessMockImpl{Scenarios: map[string]model.Scenario{}}}
+	valid := model.ScenarioRequest{Id: "crash-2008", Name: "2008 crash", StartDate: "2008-09-01", EndDate: "2009-03-09"}
+	invalids := map[string]func(request *model.ScenarioRequest){
+		"uppercase id":     func(request *model.ScenarioRequest) { request.Id = "Crash" },
+		"empty name":       func(request *model.ScenarioRequest) { request.Name = " " },
+		"invalid date":     func(request *model.ScenarioRequest) { request.StartDate = "09/01/2008" },
+		"dates reversed":   func(request *model.ScenarioRequest) { request.EndDate = "2008-01-01" },
+		"empty symbol":     func(request *model.ScenarioRequest) { request.Symbols = []string{"AAPL", ""} },
+		"too many symbols": func(request *model.ScenarioRequest) { request.Symbols = make([]string, maxScenarioSymbols+1) },
+	}
+	for name, invalidate := range invalids {
+		request := valid
+		invalidate(&request)
+		if _, err := scenarioService.CreateScenario(request); !errors.Is(err, ErrInvalidScenario) {
+			t.Errorf("%s: expected ErrInvalidScenario and not %v", name, err)
+		}
+	}
+}
+
+func TestUpdateScenario(t *testing.T) {
+	createdAt := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	mockDataAccess := &ScenarioDataAccessMockImpl{Scenarios: map[string]model.Scenario{
+		"crash-2008": {Id: "crash-2008", Name: "2008", StartDate: "2008-09-01", EndDate: "2009-03-09", CreatedAt: createdAt},
+	}}
+	scenarioService := &ScenarioServiceImpl{ScenarioDataAccess: mockDataAccess}
+
+	scenario, err := scenarioService.UpdateScenario("crash-2008", model.ScenarioRequest{Id: "ignored", Name: "2008 crash", StartDate: "2008-09-15", EndDate: "2009-03-09"})
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if scenario.Id != "crash-2008" || scenario.StartDate != "2008-09-15" || !scenario.CreatedAt.Equal(createdAt) {
+		t.Errorf("Unexpected scenario %+v", scenario)
+	}
+	_, err = scenarioService.UpdateScenario("unknown", model.ScenarioRequest{Name: "Unknown", StartDate: "2008-09-15", EndDate: "2009-03-09"})
+	if !errors.Is(err, dataaccess.ErrScenarioNotFound) {
+		t.Errorf("Expected ErrScenarioNotFound and not %v", err)
+	}
+}
@@ -0,0 +1,191 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"stockgame/internal/dataaccess"
+	"stockgame/internal/database"
+	"stockgame/internal/indicators"
+	"stockgame/internal/logic"
+	"stockgame/internal/model"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidHint       = errors.New("the hint must be sector, next_close or rsi")
+	ErrHintUnavailable   = errors.New("the hint cannot be computed for this round")
+	ErrServedRoundSolved = errors.New("a solution was already accepted for this round")
+	ErrServedRoundWindow = errors.New("the solution is not for the window of the round")
+)
+
+type ServedRoundService interface {
+	ServeRound(playerId string, stocks []model.StockPublic) (model.ServedRound, error)
+	SolveServedRound(id string, playerId string, symbolUUID string, afterDate string, limit int) (model.ServedRound, error)
+	RequestHint(id string, playerId string, hint string) (model.HintResponse, error)
+}
+
+type ServedRoundServiceImpl struct {
+	ServedRoundDataAccess dataaccess.ServedRoundDataAccess
+	StockService          StockService
+	IndicatorLogic        indicators.IndicatorLogic
+	ScoringLogic          logic.ScoringLogic // Computes the penalty shown with a hint
+	Now                   func() time.Time   // Nil uses time.Now
+	ServedRoundService
+}
+
+// ServeRound records the window served to the player, the last stock is the date to guess from
+func (s *ServedRoundServiceImpl) ServeRound(playerId string, stocks []model.StockPublic) (model.ServedRound, error) {
+	if len(stocks) == 0 {
+		return model.ServedRound{}, fmt.Errorf("cannot serve a round without stocks")
+	}
+	last := stocks[len(stocks)-1]
+	round := model.ServedRound{
+		Id:         uuid.New().String(),
+		PlayerId:   playerId,
+		SymbolUUID: last.SymbolUUID,
+		AfterDate:  dateOnly(last.Date),
+		Hints:      []string{},
+		ServedAt:   s.now(),
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), database.CONTEXT_TIMEOUT)
+	defer cancel()
+	if err := s.ServedRoundDataAccess.CreateServedRound(ctx, round); err != nil {
+		return model.ServedRound{}, err
+	}
+	return round, nil
+}
+
+// SolveServedRound marks the round solved by a solution of the window and returns it with
+// its hints. Without an id, the round is the last one served to the player for the window
+// so the hints cannot be skipped by leaving the id out. The round must be solved within the
+// limit in seconds after it was served, zero is not timed. It returns
+// dataaccess.ErrServedRoundNotFound when the window was not served to the player,
+// ErrServedRoundSolved when a solution was already accepted for the round and
+// logic.ErrRoundExpired when the solution is late.
+func (s *ServedRoundServiceImpl) SolveServedRound(id string, playerId string, symbolUUID string, afterDate string, limit int) (model.ServedRound, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), database.CONTEXT_TIMEOUT)
+	defer cancel()
+	// The date shown to the player keeps the time the served round does not have
+	afterDate = dateOnly(afterDate)
+	var round model.ServedRound
+	var err error
+	if id == "" {
+		round, err = s.ServedRoundDataAccess.FindServedRound(ctx, playerId, symbolUUID, afterDate)
+	} else {
+		round, err = s.getServedRound(ctx, id, playerId)
+	}
+	if err != nil {
+		return model.ServedRound{}, err
+	}
+	if round.SymbolUUID != symbolUUID || round.AfterDate != afterDate {
+		return model.ServedRound{}, ErrServedRoundWindow
+	}
+	solvedAt := s.now()
+	servedAfter := time.Time{}
+	if limit > 0 {
+		servedAfter = solvedAt.Add(-time.Duration(limit) * time.Second)
+	}
+	solved, err := s.ServedRoundDataAccess.SolveServedRound(ctx, round.Id, solvedAt, servedAfter)
+	if err != nil {
+		return model.ServedRound{}, err
+	}
+	if !solved {
+		// The round read before can be stale, read it again to tell why it was refused
+		round, err = s.ServedRoundDataAccess.GetServedRound(ctx, round.Id)
+		if err != nil {
+			return model.ServedRound{}, err
+		}
+		if round.SolvedAt != nil {
+			return model.ServedRound{}, ErrServedRoundSolved
+		}
+		return model.ServedRound{}, logic.ErrRoundExpired
+	}
+	round.SolvedAt = &solvedAt
+	return round, nil
+}
+
+// RequestHint reveals the hint and records it against the round. A hint requested again
+// is revealed without being counted twice.
+func (s *ServedRoundServiceImpl) RequestHint(id string, playerId string, hint string) (model.HintResponse, error) {
+	if _, ok := model.DefaultHintPenalties[hint]; !ok {
+		return model.HintResponse{}, ErrInvalidHint
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), database.CONTEXT_TIMEOUT)
+	defer cancel()
+	round, err := s.getServedRound(ctx, id, playerId)
+	if err != nil {
+		return model.HintResponse{}, err
+	}
+	if round.SolvedAt != nil {
+		return model.HintResponse{}, ErrServedRoundSolved
+	}
+	response, err := s.reveal(round, hint)
+	if err != nil {
+		return model.HintResponse{}, err
+	}
+	if _, err := s.ServedRoundDataAccess.AddHint(ctx, id, hint); err != nil {
+		return model.HintResponse{}, err
+	}
+	response.Hints = round.Hints
+	if !slices.Contains(response.Hints, hint) {
+		response.Hints = append(response.Hints, hint)
+	}
+	response.Penalty = s.ScoringLogic.GetHintPenalty(response.Hints)
+	return response, nil
+}
+
+// reveal computes the hint from the days shown, only the next close reveals a day to guess
+func (s *ServedRoundServiceImpl) reveal(round model.ServedRound, hint string) (model.HintResponse, error) {
+	response := model.HintResponse{Type: hint}
+	if hint == model.Hint_sector {
+		if round.Sector == "" {
+			return response, ErrHintUnavailable
+		}
+		response.Sector = round.Sector
+		return response, nil
+	}
+	info, err := s.StockService.GetStockInfo(round.SymbolUUID)
+	if err != nil {
+		return response, fmt.Errorf("cannot find the stock of served round %s: %v", round.Id, err)
+	}
+	switch hint {
+	case model.Hint_next_close:
+		after := s.StockService.GetStocksAfterDate(info.Symbol, round.AfterDate)
+		if len(after) == 0 {
+			return response, ErrHintUnavailable
+		}
+		response.NextClose = &after[0].Close
+	case model.Hint_rsi:
+		// The stocks before the date are sorted from the most recent
+		shown := slices.Clone(s.StockService.GetStocksBeforeEqualDate(info.Symbol, round.AfterDate))
+		slices.Reverse(shown)
+		overlays, err := s.IndicatorLogic.GetOverlays(shown, []string{model.Hint_rsi})
+		if err != nil {
+			return response, fmt.Errorf("cannot compute the RSI of served round %s: %v", round.Id, err)
+		}
+		response.Overlay = overlays
+	}
+	return response, nil
+}
+
+func (s *ServedRoundServiceImpl) getServedRound(ctx context.Context, id string, playerId string) (model.ServedRound, error) {
+	round, err := s.ServedRoundDataAccess.GetServedRound(ctx, id)
+	if err != nil {
+		return round, err
+	}
+	if round.PlayerId != playerId {
+		return model.ServedRound{}, dataaccess.ErrServedRoundNotFound
+	}
+	return round, nil
+}
+
+func (s *ServedRoundServiceImpl) now() time.Time {
+	if s.Now == nil {
+		return time.Now().UTC()
+	}
+	return s.Now()
+}
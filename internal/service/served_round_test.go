@@ -0,0 +1,216 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"stockgame/internal/dataaccess"
+	"stockgame/internal/indicators"
+	"stockgame/internal/logic"
+	"stockgame/internal/model"
+	"testing"
+	"time"
+)
+
+type ServedRoundDataAccessMockImpl struct {
+	Round   model.ServedRound // Returned by GetServedRound when its id matches
+	Created *model.ServedRound
+	Added   []string
+}
+
+func (s *ServedRoundDataAccessMockImpl) FindServedRound(ctx context.Context, playerId string, symbolUUID string, afterDate string) (model.ServedRound, error) {
+	if s.Round.Id == "" || s.Round.PlayerId != playerId || s.Round.SymbolUUID != symbolUUID || s.Round.AfterDate != afterDate {
+		return model.ServedRound{}, dataaccess.ErrServedRoundNotFound
+	}
+	return s.Round, nil
+}
+
+func (s *ServedRoundDataAccessMockImpl) SolveServedRound(ctx context.Context, id string, solvedAt time.Time, servedAfter time.Time) (bool, error) {
+	if s.Round.SolvedAt != nil || s.Round.ServedAt.Before(servedAfter) {
+		return false, nil
+	}
+	s.Round.SolvedAt = &solvedAt
+	return true, nil
+}
+
+func (s *ServedRoundDataAccessMockImpl) CreateServedRound(ctx context.Context, round model.ServedRound) error {
+	s.Created = &round
+	return nil
+}
+
+func (s *ServedRoundDataAccessMockImpl) GetServedRound(ctx context.Context, id string) (model.ServedRound, error) {
+	if id != s.Round.Id {
+		return model.ServedRound{}, dataaccess.ErrServedRoundNotFound
+	}
+	return s.Round, nil
+}
+
+func (s *ServedRoundDataAccessMockImpl) AddHint(ctx context.Context, id string, hint string) (bool, error) {
+	if slices.Contains(s.Round.Hints, hint) {
+		return false, nil
+	}
+	s.Added = append(s.Added, hint)
+	return true, nil
+}
+
+func newServedRoundService(dataAccess *ServedRoundDataAccessMockImpl, stockService StockService) *ServedRoundServiceImpl {
+	return &ServedRoundServiceImpl{
+		ServedRoundDataAccess: dataAccess,
+		StockService:          stockService,
+		IndicatorLogic:        &indicators.IndicatorLogicImpl{},
+		ScoringLogic:          &logic.ScoringLogicImpl{},
+	}
+}
+
+func TestServeRound(t *testing.T) {
+	servedAt := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	dataAccess := &ServedRoundDataAccessMockImpl{}
+	servedRoundService := newServedRoundService(dataAccess, &StockServiceMockImpl{})
+	servedRoundService.Now = func() time.Time { return servedAt }
+
+	round, err := servedRoundService.ServeRound("player-1", []model.StockPublic{
+		{Date: "2020-01-30T00:00:00Z", SymbolUUID: "uuid-aapl"},
+		{Date: "2020-01-31T00:00:00Z", SymbolUUID: "uuid-aapl"},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if round.Id == "" || round.AfterDate != "2020-01-31" || round.SymbolUUID != "uuid-aapl" || !round.ServedAt.Equal(servedAt) {
+		t.Errorf("Unexpected served round %+v", round)
+	}
+	if dataAccess.Created == nil || dataAccess.Created.PlayerId != "player-1" {
+		t.Errorf("Expected the round of the player to be saved and not %+v", dataAccess.Created)
+	}
+}
+
+func TestSolveServedRound(t *testing.T) {
+	served := model.ServedRound{Id: "served-1", PlayerId: "player-1", SymbolUUID: "uuid-aapl", AfterDate: "2020-01-31", Hints: []string{model.Hint_rsi}}
+	t.Run("By id", func(t *testing.T) {
+		dataAccess := &ServedRoundDataAccessMockImpl{Round: served}
+		round, err := newServedRoundService(dataAccess, &StockServiceMockImpl{}).SolveServedRound("served-1", "player-1", "uuid-aapl", "2020-01-31", 0)
+		if err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+		if round.SolvedAt == nil || !slices.Equal(round.Hints, []string{model.Hint_rsi}) {
+			t.Errorf("Expected the solved round with its hints and not %+v", round)
+		}
+	})
+	t.Run("By window", func(t *testing.T) {
+		dataAccess := &ServedRoundDataAccessMockImpl{Round: served}
+		round, err := newServedRoundService(dataAccess, &StockServiceMockImpl{}).SolveServedRound("", "player-1", "uuid-aapl", "2020-01-31", 0)
+		if err != nil || round.Id != "served-1" || len(round.Hints) != 1 {
+			t.Errorf("Expected the round served for the window with its hints and not %+v %v", round, err)
+		}
+	})
+	t.Run("Date with time", func(t *testing.T) {
+		// The client sends back the date of the last stock shown as read from the database
+		for _, id := range []string{"served-1", ""} {
+			dataAccess := &ServedRoundDataAccessMockImpl{Round: served}
+			round, err := newServedRoundService(dataAccess, &StockServiceMockImpl{}).SolveServedRound(id, "player-1", "uuid-aapl", "2020-01-31T00:00:00Z", 0)
+			if err != nil || round.Id != "served-1" {
+				t.Errorf("Expected the round served for the window with the id %q and not %+v %v", id, round, err)
+			}
+		}
+	})
+	t.Run("Solved once", func(t *testing.T) {
+		dataAccess := &ServedRoundDataAccessMockImpl{Round: served}
+		servedRoundService := newServedRoundService(dataAccess, &StockServiceMockImpl{})
+		if _, err := servedRoundService.SolveServedRound("served-1", "player-1", "uuid-aapl", "2020-01-31", 0); err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+		if _, err := servedRoundService.SolveServedRound("", "player-1", "uuid-aapl", "2020-01-31", 0); !errors.Is(err, ErrServedRoundSolved) {
+			t.Errorf("Expected ErrServedRoundSolved and not %v", err)
+		}
+		if _, err := servedRoundService.RequestHint("served-1", "player-1", model.Hint_sector); !errors.Is(err, ErrServedRoundSolved) {
+			t.Errorf("Expected no hint once solved and not %v", err)
+		}
+	})
+	t.Run("Timed", func(t *testing.T) {
+		servedAt := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+		timed := served
+		timed.ServedAt = servedAt
+		dataAccess := &ServedRoundDataAccessMockImpl{Round: timed}
+		servedRoundService := newServedRoundService(dataAccess, &StockServiceMockImpl{})
+		servedRoundService.Now = func() time.Time { return servedAt.Add(2 * time.Minute) }
+		if _, err := servedRoundService.SolveServedRound("served-1", "player-1", "uuid-aapl", "2020-01-31", 60); !errors.Is(err, logic.ErrRoundExpired) {
+			t.Errorf("Expected ErrRoundExpired and not %v", err)
+		}
+		if dataAccess.Round.SolvedAt != nil {
+			t.Errorf("Expected a late round to stay unsolved")
+		}
+		round, err := servedRoundService.SolveServedRound("served-1", "player-1", "uuid-aapl", "2020-01-31", 180)
+		if err != nil || round.SolvedAt == nil || round.SolvedAt.Sub(round.ServedAt) != 2*time.Minute {
+			t.Errorf("Expected the round to be solved in time and not %+v %v", round, err)
+		}
+	})
+	t.Run("Errors", func(t *testing.T) {
+		dataAccess := &ServedRoundDataAccessMockImpl{Round: served}
+		servedRoundService := newServedRoundService(dataAccess, &StockServiceMockImpl{})
+		if _, err := servedRoundService.SolveServedRound("served-1", "player-1", "uuid-aapl", "2020-01-30", 0); !errors.Is(err, ErrServedRoundWindow) {
+			t.Errorf("Expected ErrServedRoundWindow and not %v", err)
+		}
+		if _, err := servedRoundService.SolveServedRound("served-1", "player-2", "uuid-aapl", "2020-01-31", 0); !errors.Is(err, dataaccess.ErrServedRoundNotFound) {
+			t.Errorf("Expected the round of another player to be not found and not %v", err)
+		}
+		if _, err := servedRoundService.SolveServedRound("", "player-1", "uuid-msft", "2020-01-31", 0); !errors.Is(err, dataaccess.ErrServedRoundNotFound) {
+			t.Errorf("Expected a window not served to be not found and not %v", err)
+		}
+		if dataAccess.Round.SolvedAt != nil {
+			t.Errorf("Expected the round to stay unsolved")
+		}
+	})
+}
+
+func TestRequestHint(t *testing.T) {
+	served := model.ServedRound{Id: "served-1", PlayerId: "player-1", SymbolUUID: "uuid-aapl", AfterDate: "2020-01-31", Hints: []string{model.Hint_sector}, Sector: "Technology"}
+	stockService := &StockServiceMockImpl{After: []model.Stock{{Date: "2020-02-03", Close: 3}}}
+	t.Run("Next close", func(t *testing.T) {
+		dataAccess := &ServedRoundDataAccessMockImpl{Round: served}
+		response, err := newServedRoundService(dataAccess, stockService).RequestHint("served-1", "player-1", model.Hint_next_close)
+		if err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+		if response.NextClose == nil || *response.NextClose != 3 {
+			t.Errorf("Expected the next close of 3 and not %v", response.NextClose)
+		}
+		if !slices.Equal(response.Hints, []string{model.Hint_sector, model.Hint_next_close}) || response.Penalty != 20 {
+			t.Errorf("Expected both hints for 20 points and not %v %d", response.Hints, response.Penalty)
+		}
+		if !slices.Equal(dataAccess.Added, []string{model.Hint_next_close}) {
+			t.Errorf("Expected the hint to be recorded and not %v", dataAccess.Added)
+		}
+	})
+	t.Run("Requested again", func(t *testing.T) {
+		dataAccess := &ServedRoundDataAccessMockImpl{Round: served}
+		response, err := newServedRoundService(dataAccess, stockService).RequestHint("served-1", "player-1", model.Hint_sector)
+		if err != nil || response.Sector != "Technology" || response.Penalty != 5 || len(response.Hints) != 1 {
+			t.Errorf("Expected the sector counted once and not %+v %v", response, err)
+		}
+	})
+	t.Run("RSI of the days shown", func(t *testing.T) {
+		dataAccess := &ServedRoundDataAccessMockImpl{Round: served}
+		response, err := newServedRoundService(dataAccess, stockService).RequestHint("served-1", "player-1", model.Hint_rsi)
+		if err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+		if _, ok := response.Overlay[model.Hint_rsi]; !ok {
+			t.Errorf("Expected the RSI overlay and not %+v", response.Overlay)
+		}
+	})
+	t.Run("Errors", func(t *testing.T) {
+		dataAccess := &ServedRoundDataAccessMockImpl{Round: served}
+		servedRoundService := newServedRoundService(dataAccess, &StockServiceMockImpl{})
+		if _, err := servedRoundService.RequestHint("served-1", "player-1", "answer"); !errors.Is(err, ErrInvalidHint) {
+			t.Errorf("Expected ErrInvalidHint and not %v", err)
+		}
+		if _, err := servedRoundService.RequestHint("served-1", "player-2", model.Hint_sector); !errors.Is(err, dataaccess.ErrServedRoundNotFound) {
+			t.Errorf("Expected the round of another player to be not found and not %v", err)
+		}
+		if _, err := servedRoundService.RequestHint("served-1", "player-1", model.Hint_next_close); !errors.Is(err, ErrHintUnavailable) {
+			t.Errorf("Expected ErrHintUnavailable without a day to guess and not %v", err)
+		}
+		if len(dataAccess.Added) != 0 {
+			t.Errorf("Expected no hint to be recorded and not %v", dataAccess.Added)
+		}
+	})
+}
@@ -2,11 +2,14 @@ package service
 
 import (
 	"context"
+	"fmt"
 	"math/rand/v2"
+	"slices"
 	"stockgame/internal/dataaccess"
 	"stockgame/internal/database"
 	"stockgame/internal/logic"
 	"stockgame/internal/model"
+	"time"
 )
 
 type StockService interface {
@@ -15,6 +18,9 @@ type StockService interface {
 	GetStocksAfterDate(symbol, date string) []model.Stock
 	GetStockPriceForTimeRange(symbol string, startDate string, endDate string) []model.Stock
 	GetRandomStockWithRandomDayRange(numberOfDays int) []model.StockPublic
+	GetRandomWindow(numberOfDays int, difficulty string) model.StockWindow
+	GetRandomBasket(size int) model.BasketWindow
+	GetScenarioWindow(scenario model.Scenario, numberOfDays int, difficulty string) model.StockWindow
 	GetRandomStockFromPersistence() []model.StockPublic
 	GetRandomStock(symbol []string) string
 }
@@ -23,9 +29,32 @@ type StockServiceImpl struct {
 	GetRandomStockSelectorFunc                func(choices []string) string
 	GetRandomStockFromPersistenceSelectorFunc func() []model.StockPublic
 	StockLogic                                logic.StockLogic
+	DifficultyLogic                           logic.DifficultyLogic // Optional, the windows are not rated without it
 }
 
+// Number of windows tried in the same stock before loading another stock
+const windowsPerStock = 10
+
+// Number of calendar days loaded per trading day around a scenario, weekends and holidays
+// included
+const calendarDaysPerTradingDay = 2
+
+// Number of baskets tried before giving up, each one starts from a new random window
+const basketTries = 5
+
+// Number of symbols loaded per symbol missing in a basket, some are not eligible
+const basketCandidatesPerSymbol = 3
+
 func (s *StockServiceImpl) GetRandomStockWithRandomDayRange(numberOfDays int) []model.StockPublic {
+	return s.GetRandomWindow(numberOfDays, "").Stocks
+}
+
+// GetRandomWindow picks a random window of a random stock with the days to guess
+// following it. The eligibility is checked on the days served and the days to guess,
+// so every window has a complete future to score. When a difficulty is requested,
+// windows are drawn until one is rated with it.
+func (s *StockServiceImpl) GetRandomWindow(numberOfDays int, difficulty string) model.StockWindow {
+	windowDays := numberOfDays + model.User_stock_to_guess
 OuterLoop:
 	for numberOfTry := 0; numberOfTry < 15; numberOfTry++ {
 		var stocks []model.StockPublic
@@ -34,14 +63,161 @@ OuterLoop:
 		} else {
 			stocks = s.GetRandomStockFromPersistence()
 		}
-		isValid, upperBound := s.StockLogic.IsStocksValid(stocks, numberOfDays)
-		if !isValid {
+		stocks, upperBound, reason := s.StockLogic.GetWindowBounds(stocks, windowDays)
+		if reason != "" {
+			fmt.Printf("Candidate discarded: %s\n", reason)
 			continue OuterLoop // Try again
 		}
-		lowerBound := rand.IntN(upperBound)
-		return stocks[lowerBound : lowerBound+numberOfDays] // Found a good candidate
+		info, err := s.GetStockInfo(stocks[0].SymbolUUID)
+		if err != nil {
+			fmt.Printf("Candidate %s discarded: %v\n", stocks[0].SymbolUUID, err)
+			continue OuterLoop
+		}
+		for numberOfWindow := 0; numberOfWindow < windowsPerStock; numberOfWindow++ {
+			lowerBound := rand.IntN(upperBound)
+			served := stocks[lowerBound : lowerBound+windowDays]
+			if reason = s.StockLogic.CheckEligibility(info, served); reason != "" {
+				continue
+			}
+			window := model.StockWindow{
+				Stocks: served[:numberOfDays],
+				Future: served[numberOfDays:],
+			}
+			if s.DifficultyLogic != nil {
+				window.Rating = s.DifficultyLogic.RateDifficulty(window.Stocks, window.Future)
+			}
+			if difficulty == "" || window.Rating.Difficulty == difficulty {
+				return window // Found a good candidate
+			}
+			reason = fmt.Sprintf("not %s", difficulty)
+		}
+		fmt.Printf("Candidate %s discarded after %d windows: %s\n", info.Symbol, windowsPerStock, reason)
 	}
-	return []model.StockPublic{}
+	return model.StockWindow{Stocks: []model.StockPublic{}}
+}
+
+// GetRandomBasket picks a random window then other symbols with a price for each of its
+// days, served and to guess. The symbols are in a random order.
+func (s *StockServiceImpl) GetRandomBasket(size int) model.BasketWindow {
+	for numberOfTry := 0; numberOfTry < basketTries; numberOfTry++ {
+		window := s.GetRandomWindow(model.Number_initial_stock_shown, "")
+		if len(window.Stocks) == 0 || len(window.Future) == 0 {
+			break // No eligible window at all
+		}
+		served := append(slices.Clone(window.Stocks), window.Future...)
+		startDate := dateOnly(served[0].Date)
+		endDate := dateOnly(served[len(served)-1].Date)
+		anchor, err := s.GetStockInfo(served[0].SymbolUUID)
+		if err != nil {
+			fmt.Printf("Basket %s discarded: %v\n", served[0].SymbolUUID, err)
+			continue
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), database.CONTEXT_TIMEOUT)
+		candidates := s.StockDataAccess.GetSymbolsWithCompleteWindow(ctx, startDate, endDate, len(served), anchor.Symbol, size*basketCandidatesPerSymbol)
+		cancel()
+		basket := []model.BasketStock{{SymbolUUID: anchor.SymbolUUID, Stocks: window.Stocks}}
+		for _, info := range candidates {
+			if len(basket) == size {
+				break
+			}
+			stocks := logic.StocksToPublic(s.GetStockPriceForTimeRange(info.Symbol, startDate, endDate), info.SymbolUUID)
+			if len(stocks) != len(served) || s.StockLogic.CheckEligibility(info, stocks) != "" {
+				continue
+			}
+			basket = append(basket, model.BasketStock{SymbolUUID: info.SymbolUUID, Stocks: stocks[:len(window.Stocks)]})
+		}
+		if len(basket) < size {
+			fmt.Printf("Basket of %s discarded: %d symbols found\n", anchor.Symbol, len(basket))
+			continue
+		}
+		rand.Shuffle(len(basket), func(i, j int) {
+			basket[i], basket[j] = basket[j], basket[i]
+		})
+		return model.BasketWindow{
+			AfterDate: dateOnly(window.Stocks[len(window.Stocks)-1].Date),
+			Stocks:    basket,
+		}
+	}
+	return model.BasketWindow{Stocks: []model.BasketStock{}}
+}
+
+// GetScenarioWindow picks a window whose last day shown is between the dates of the
+// scenario, in one of its symbols or in any symbol when it has none. The eligibility and
+// the difficulty are checked like in GetRandomWindow, the configured date range is not
+// since the scenario chooses the dates.
+func (s *StockServiceImpl) GetScenarioWindow(scenario model.Scenario, numberOfDays int, difficulty string) model.StockWindow {
+	start, err := time.Parse(time.DateOnly, scenario.StartDate)
+	if err != nil {
+		fmt.Printf("Scenario %s discarded: %v\n", scenario.Id, err)
+		return model.StockWindow{Stocks: []model.StockPublic{}}
+	}
+	end, err := time.Parse(time.DateOnly, scenario.EndDate)
+	if err != nil {
+		fmt.Printf("Scenario %s discarded: %v\n", scenario.Id, err)
+		return model.StockWindow{Stocks: []model.StockPublic{}}
+	}
+	symbols := scenario.Symbols
+	if len(symbols) == 0 {
+		ctx, cancel := context.WithTimeout(context.Background(), database.CONTEXT_TIMEOUT)
+		symbols = s.StockDataAccess.GetUniqueStockSymbols(ctx)
+		cancel()
+	}
+	if len(symbols) == 0 {
+		return model.StockWindow{Stocks: []model.StockPublic{}}
+	}
+	loadStart := start.AddDate(0, 0, -numberOfDays*calendarDaysPerTradingDay).Format(time.DateOnly)
+	loadEnd := end.AddDate(0, 0, model.User_stock_to_guess*calendarDaysPerTradingDay).Format(time.DateOnly)
+	for numberOfTry := 0; numberOfTry < 15; numberOfTry++ {
+		var symbol string
+		if s.GetRandomStockSelectorFunc != nil {
+			symbol = s.GetRandomStockSelectorFunc(symbols)
+		} else {
+			symbol = s.GetRandomStock(symbols)
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), database.CONTEXT_TIMEOUT)
+		info, err := s.StockDataAccess.GetStockInfoBySymbol(ctx, symbol)
+		cancel()
+		if err != nil {
+			fmt.Printf("Candidate %s discarded: %v\n", symbol, err)
+			continue
+		}
+		stocks := logic.StocksToPublic(s.GetStockPriceForTimeRange(symbol, loadStart, loadEnd), info.SymbolUUID)
+		// Index of the last day shown of every window ending in the scenario
+		var lastDays []int
+		for i := numberOfDays - 1; i+model.User_stock_to_guess < len(stocks); i++ {
+			date := dateOnly(stocks[i].Date)
+			if date >= scenario.StartDate && date <= scenario.EndDate {
+				lastDays = append(lastDays, i)
+			}
+		}
+		if len(lastDays) == 0 {
+			fmt.Printf("Candidate %s discarded: no complete window in scenario %s\n", symbol, scenario.Id)
+			continue
+		}
+		rand.Shuffle(len(lastDays), func(i, j int) {
+			lastDays[i], lastDays[j] = lastDays[j], lastDays[i]
+		})
+		reason := ""
+		for _, lastDay := range lastDays[:min(len(lastDays), windowsPerStock)] {
+			served := stocks[lastDay-numberOfDays+1 : lastDay+1+model.User_stock_to_guess]
+			if reason = s.StockLogic.CheckEligibility(info, served); reason != "" {
+				continue
+			}
+			window := model.StockWindow{
+				Stocks: served[:numberOfDays],
+				Future: served[numberOfDays:],
+			}
+			if s.DifficultyLogic != nil {
+				window.Rating = s.DifficultyLogic.RateDifficulty(window.Stocks, window.Future)
+			}
+			if difficulty == "" || window.Rating.Difficulty == difficulty {
+				return window
+			}
+			reason = fmt.Sprintf("not %s", difficulty)
+		}
+		fmt.Printf("Candidate %s discarded in scenario %s: %s\n", symbol, scenario.Id, reason)
+	}
+	return model.StockWindow{Stocks: []model.StockPublic{}}
 }
 
 func (s *StockServiceImpl) GetStockPriceForTimeRange(symbol string, startDate string, endDate string) []model.Stock {
@@ -90,3 +266,11 @@ func (s *StockServiceImpl) GetRandomStock(symbol []string) string {
 	index := rand.IntN(len(symbol))
 	return symbol[index]
 }
+
+// dateOnly removes the time of a date read from the database
+func dateOnly(date string) string {
+	if len(date) > len(time.DateOnly) {
+		return date[:len(time.DateOnly)]
+	}
+	return date
+}
@@ -7,6 +7,7 @@ import (
 	"stockgame/internal/logic"
 	"stockgame/internal/model"
 	"testing"
+	"time"
 )
 
 var ctx = context.Background()
@@ -33,6 +34,8 @@ type StockDataAccessMockImpl struct {
 	GetStocksAfterDateFunc           func(ctx context.Context, symbol, afterDate string) []model.Stock
 	GetStocksBeforeEqualDateFunc     func(ctx context.Context, symbol, beforeDate string) []model.Stock
 	GetStockInfoFunc                 func(ctx context.Context, symbolUUID string) (model.StockInfo, error)
+	GetStockInfoBySymbolFunc         func(ctx context.Context, symbol string) (model.StockInfo, error)
+	GetSymbolsWithCompleteWindowFunc func(ctx context.Context, startDate, endDate string, days int, excludedSymbol string, limit int) []model.StockInfo
 }
 
 func (s *StockDataAccessMockImpl) GetPricesForStock(ctx context.Context, symbol string) []model.StockPublic {
@@ -78,6 +81,20 @@ func (s *StockDataAccessMockImpl) GetStockInfo(ctx context.Context, symbolUUID s
 	return model.StockInfo{}, nil
 }
 
+func (s *StockDataAccessMockImpl) GetStockInfoBySymbol(ctx context.Context, symbol string) (model.StockInfo, error) {
+	if s.GetStockInfoBySymbolFunc != nil {
+		return s.GetStockInfoBySymbolFunc(ctx, symbol)
+	}
+	return model.StockInfo{Symbol: symbol}, nil
+}
+
+func (s *StockDataAccessMockImpl) GetSymbolsWithCompleteWindow(ctx context.Context, startDate, endDate string, days int, excludedSymbol string, limit int) []model.StockInfo {
+	if s.GetSymbolsWithCompleteWindowFunc != nil {
+		return s.GetSymbolsWithCompleteWindowFunc(ctx, startDate, endDate, days, excludedSymbol, limit)
+	}
+	return nil
+}
+
 func TestGetRandomStockFromPersistence(t *testing.T) {
 	// Create a mockDataAccess object with a function GetUniqueStockSymbols that return fake symboles
 	mockDataAccess := &StockDataAccessMockImpl{
@@ -131,11 +148,7 @@ func TestGetRandomStockWithRandomDayRange(t *testing.T) {
 			StockDataAccess: mockDataAccess,
 			StockLogic:      mockStockLogic,
 			GetRandomStockFromPersistenceSelectorFunc: func() []model.StockPublic {
-				return []model.StockPublic{
-					{SymbolUUID: "AAPL", Volume: 50000, Date: "2023-01-01", Open: 100, High: 110, Low: 90, Close: 105, AdjClose: 1233},
-					{SymbolUUID: "AAPL", Volume: 50000, Date: "2023-01-02", Open: 100, High: 110, Low: 90, Close: 105, AdjClose: 1233},
-					{SymbolUUID: "AAPL", Volume: 50000, Date: "2023-01-03", Open: 100, High: 110, Low: 90, Close: 105, AdjClose: 1233},
-				}
+				return generateStocks(2+model.User_stock_to_guess, model.StockPublic{SymbolUUID: "AAPL", Volume: 50000, Open: 100, High: 110, Low: 90, Close: 105, AdjClose: 1233})
 			},
 		}
 		stocks := mockService.GetRandomStockWithRandomDayRange(2)
@@ -169,11 +182,9 @@ func TestGetRandomStockWithRandomDayRange(t *testing.T) {
 			StockDataAccess: mockDataAccess,
 			StockLogic:      mockStockLogic,
 			GetRandomStockFromPersistenceSelectorFunc: func() []model.StockPublic {
-				return []model.StockPublic{
-					{SymbolUUID: "AAPL", Volume: 50000, Date: "2023-01-01", Open: 0, High: 110, Low: 90, Close: 105, AdjClose: 1233},
-					{SymbolUUID: "AAPL", Volume: 50000, Date: "2023-01-02", Open: 100, High: 110, Low: 90, Close: 105, AdjClose: 1233},
-					{SymbolUUID: "AAPL", Volume: 50000, Date: "2023-01-03", Open: 100, High: 110, Low: 90, Close: 105, AdjClose: 1233},
-				}
+				stocks := generateStocks(2+model.User_stock_to_guess, model.StockPublic{SymbolUUID: "AAPL", Volume: 50000, Open: 100, High: 110, Low: 90, Close: 105, AdjClose: 1233})
+				stocks[0].Open = 0
+				return stocks
 			},
 		}
 		stocks := mockService.GetRandomStockWithRandomDayRange(2)
@@ -188,11 +199,7 @@ func TestGetRandomStockWithRandomDayRange(t *testing.T) {
 			StockDataAccess: mockDataAccess,
 			StockLogic:      mockStockLogic,
 			GetRandomStockFromPersistenceSelectorFunc: func() []model.StockPublic {
-				return []model.StockPublic{
-					{SymbolUUID: "AAPL", Volume: 24000, Date: "2023-01-01", Open: 100, High: 110, Low: 90, Close: 105, AdjClose: 1233},
-					{SymbolUUID: "AAPL", Volume: 24000, Date: "2023-01-02", Open: 100, High: 110, Low: 90, Close: 105, AdjClose: 1233},
-					{SymbolUUID: "AAPL", Volume: 24000, Date: "2023-01-03", Open: 100, High: 110, Low: 90, Close: 105, AdjClose: 1233},
-				}
+				return generateStocks(2+model.User_stock_to_guess, model.StockPublic{SymbolUUID: "AAPL", Volume: 24000, Open: 100, High: 110, Low: 90, Close: 105, AdjClose: 1233})
 			},
 		}
 		stocks := mockService.GetRandomStockWithRandomDayRange(2)
@@ -234,11 +241,7 @@ func TestGetRandomStockWithRandomDayRange(t *testing.T) {
 					}
 				} else {
 					// Good data
-					return []model.StockPublic{
-						{SymbolUUID: "AAPL", Volume: 50000, Date: "2023-01-01", Open: 100, High: 110, Low: 90, Close: 105, AdjClose: 1233},
-						{SymbolUUID: "AAPL", Volume: 50000, Date: "2023-01-02", Open: 100, High: 110, Low: 90, Close: 105, AdjClose: 1233},
-						{SymbolUUID: "AAPL", Volume: 50000, Date: "2023-01-02", Open: 100, High: 110, Low: 90, Close: 105, AdjClose: 1233},
-					}
+					return generateStocks(2+model.User_stock_to_guess, model.StockPublic{SymbolUUID: "AAPL", Volume: 50000, Open: 100, High: 110, Low: 90, Close: 105, AdjClose: 1233})
 				}
 
 			},
@@ -249,3 +252,236 @@ func TestGetRandomStockWithRandomDayRange(t *testing.T) {
 		}
 	})
 }
+
+// generateStocks copies the stock on consecutive days
+func generateStocks(count int, stock model.StockPublic) []model.StockPublic {
+	stocks := []model.StockPublic{}
+	day := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < count; i++ {
+		stock.Date = day.AddDate(0, 0, i).Format(time.DateOnly)
+		stocks = append(stocks, stock)
+	}
+	return stocks
+}
+
+func TestGetRandomWindow(t *testing.T) {
+	mockStockLogic := &logic.StockLogicImpl{}
+	// A flat stock is always rated easy
+	flatStocks := func() []model.StockPublic {
+		return generateStocks(3+model.User_stock_to_guess, model.StockPublic{SymbolUUID: "AAPL", Volume: 50000, Open: 100, High: 110, Low: 90, Close: 100})
+	}
+	t.Run("Rated window with the following days", func(t *testing.T) {
+		mockService := &StockServiceImpl{
+			StockDataAccess: &StockDataAccessMockImpl{},
+			StockLogic:      mockStockLogic,
+			DifficultyLogic: &logic.DifficultyLogicImpl{},
+			GetRandomStockFromPersistenceSelectorFunc: flatStocks,
+		}
+		window := mockService.GetRandomWindow(2, model.Difficulty_easy)
+		if len(window.Stocks) != 2 {
+			t.Errorf("Expected 2 stocks and not %d", len(window.Stocks))
+		}
+		if len(window.Future) != model.User_stock_to_guess {
+			t.Errorf("Expected %d days to guess and not %d", model.User_stock_to_guess, len(window.Future))
+		}
+		if window.Rating.Difficulty != model.Difficulty_easy {
+			t.Errorf("Expected an easy window and not %s", window.Rating.Difficulty)
+		}
+	})
+	t.Run("No window with the difficulty", func(t *testing.T) {
+		count := 0
+		mockService := &StockServiceImpl{
+			StockDataAccess: &StockDataAccessMockImpl{},
+			StockLogic:      mockStockLogic,
+			DifficultyLogic: &logic.DifficultyLogicImpl{},
+			GetRandomStockFromPersistenceSelectorFunc: func() []model.StockPublic {
+				count++
+				return flatStocks()
+			},
+		}
+		window := mockService.GetRandomWindow(2, model.Difficulty_hard)
+		if len(window.Stocks) != 0 {
+			t.Errorf("Expected no stock and not %d", len(window.Stocks))
+		}
+		if count != 15 {
+			t.Errorf("Expected to try 15 stocks but tried %d", count)
+		}
+	})
+	t.Run("Candidate rejected by its stock information", func(t *testing.T) {
+		count := 0
+		mockDataAccess := &StockDataAccessMockImpl{
+			GetStockInfoFunc: func(ctx context.Context, symbolUUID string) (model.StockInfo, error) {
+				return model.StockInfo{Symbol: "SPY", SymbolUUID: symbolUUID, IsEtf: true}, nil
+			},
+		}
+		mockService := &StockServiceImpl{
+			StockDataAccess: mockDataAccess,
+			StockLogic:      &logic.StockLogicImpl{Eligibility: model.EligibilityConfig{Etf: model.EtfExclude}},
+			GetRandomStockFromPersistenceSelectorFunc: func() []model.StockPublic {
+				count++
+				return flatStocks()
+			},
+		}
+		window := mockService.GetRandomWindow(2, "")
+		if len(window.Stocks) != 0 || count != 15 {
+			t.Errorf("Expected the ETF to be rejected 15 times and not %d stocks after %d tries", len(window.Stocks), count)
+		}
+	})
+	t.Run("Zero open in the days to guess", func(t *testing.T) {
+		mockService := &StockServiceImpl{
+			StockDataAccess: &StockDataAccessMockImpl{},
+			StockLogic:      mockStockLogic,
+			GetRandomStockFromPersistenceSelectorFunc: func() []model.StockPublic {
+				// Only one window: the last day to guess is invalid
+				stocks := generateStocks(2+model.User_stock_to_guess, model.StockPublic{SymbolUUID: "AAPL", Volume: 50000, Open: 100, Close: 100})
+				stocks[len(stocks)-1].Open = 0
+				return stocks
+			},
+		}
+		if window := mockService.GetRandomWindow(2, ""); len(window.Stocks) != 0 {
+			t.Errorf("Expected the window to be rejected because of its future and not %d stocks", len(window.Stocks))
+		}
+	})
+	t.Run("Window not eligible but another one is", func(t *testing.T) {
+		mockService := &StockServiceImpl{
+			StockDataAccess: &StockDataAccessMockImpl{},
+			StockLogic:      mockStockLogic,
+			GetRandomStockFromPersistenceSelectorFunc: func() []model.StockPublic {
+				// The history starts illiquid, only the windows after it can be served. The volume
+				// is just above the minimum so a window with a single illiquid day is rejected.
+				stocks := generateStocks(100, model.StockPublic{SymbolUUID: "AAPL", Volume: 26000, Open: 100, Close: 100})
+				for i := 0; i < 50; i++ {
+					stocks[i].Volume = 1
+				}
+				return stocks
+			},
+		}
+		window := mockService.GetRandomWindow(2, "")
+		if len(window.Stocks) != 2 || window.Stocks[0].Volume != 26000 || window.Future[len(window.Future)-1].Volume != 26000 {
+			t.Errorf("Expected a liquid window and not %+v", window)
+		}
+	})
+}
+
+func TestGetRandomBasket(t *testing.T) {
+	windowDays := model.Number_initial_stock_shown + model.User_stock_to_guess
+	template := model.StockPublic{Volume: 50000, Open: 100, High: 110, Low: 90, Close: 100}
+	anchor := func() []model.StockPublic {
+		template.SymbolUUID = "uuid-aapl"
+		return generateStocks(windowDays, template)
+	}
+	prices := func(count int) []model.Stock {
+		stocks := []model.Stock{}
+		for _, stock := range generateStocks(count, template) {
+			stocks = append(stocks, model.Stock{Date: stock.Date, Open: 100, High: 110, Low: 90, Close: 100, Volume: 50000})
+		}
+		return stocks
+	}
+	var requestedDays int
+	mockDataAccess := &StockDataAccessMockImpl{
+		GetStockInfoFunc: func(ctx context.Context, symbolUUID string) (model.StockInfo, error) {
+			return model.StockInfo{Symbol: "AAPL", SymbolUUID: symbolUUID}, nil
+		},
+		GetSymbolsWithCompleteWindowFunc: func(ctx context.Context, startDate, endDate string, days int, excludedSymbol string, limit int) []model.StockInfo {
+			requestedDays = days
+			return []model.StockInfo{
+				{Symbol: "TSLA", SymbolUUID: "uuid-tsla"}, // Missing days
+				{Symbol: "MSFT", SymbolUUID: "uuid-msft"},
+				{Symbol: "GOOG", SymbolUUID: "uuid-goog"},
+				{Symbol: "AMZN", SymbolUUID: "uuid-amzn"},
+			}
+		},
+		GetPricesForStockInTimeRangeFunc: func(ctx context.Context, symbol, startDate, endDate string) []model.Stock {
+			if symbol == "TSLA" {
+				return prices(windowDays - 1)
+			}
+			return prices(windowDays)
+		},
+	}
+	mockService := &StockServiceImpl{
+		StockDataAccess: mockDataAccess,
+		StockLogic:      &logic.StockLogicImpl{},
+		GetRandomStockFromPersistenceSelectorFunc: anchor,
+	}
+
+	basket := mockService.GetRandomBasket(3)
+
+	if len(basket.Stocks) != 3 {
+		t.Fatalf("Expected 3 symbols and not %+v", basket)
+	}
+	if requestedDays != windowDays {
+		t.Errorf("Expected windows of %d days and not %d", windowDays, requestedDays)
+	}
+	symbols := []string{}
+	for _, stock := range basket.Stocks {
+		symbols = append(symbols, stock.SymbolUUID)
+		if len(stock.Stocks) != model.Number_initial_stock_shown {
+			t.Errorf("Expected only the days shown and not %d", len(stock.Stocks))
+		}
+	}
+	slices.Sort(symbols)
+	if !slices.Equal(symbols, []string{"uuid-aapl", "uuid-goog", "uuid-msft"}) {
+		t.Errorf("Unexpected symbols %v", symbols)
+	}
+	if basket.AfterDate != basket.Stocks[0].Stocks[model.Number_initial_stock_shown-1].Date {
+		t.Errorf("Expected the last day shown and not %s", basket.AfterDate)
+	}
+}
+
+func TestGetScenarioWindow(t *testing.T) {
+	template := model.StockPublic{Volume: 50000, Open: 100, High: 110, Low: 90, Close: 100}
+	var loaded []string
+	mockDataAccess := &StockDataAccessMockImpl{
+		GetStockInfoBySymbolFunc: func(ctx context.Context, symbol string) (model.StockInfo, error) {
+			return model.StockInfo{Symbol: symbol, SymbolUUID: "uuid-" + symbol}, nil
+		},
+		GetPricesForStockInTimeRangeFunc: func(ctx context.Context, symbol, startDate, endDate string) []model.Stock {
+			loaded = append(loaded, symbol)
+			stocks := []model.Stock{}
+			for _, stock := range generateStocks(60, template) {
+				stocks = append(stocks, model.Stock{Symbol: symbol, Date: stock.Date, Open: 100, High: 110, Low: 90, Close: 100, Volume: 50000})
+			}
+			return stocks
+		},
+	}
+	mockService := &StockServiceImpl{
+		StockDataAccess: mockDataAccess,
+		StockLogic:      &logic.StockLogicImpl{},
+		DifficultyLogic: &logic.DifficultyLogicImpl{},
+	}
+	t.Run("Last day shown in the scenario", func(t *testing.T) {
+		scenario := model.Scenario{Id: "crash", StartDate: "2023-01-20", EndDate: "2023-01-25", Symbols: []string{"AAPL"}}
+		window := mockService.GetScenarioWindow(scenario, 5, model.Difficulty_easy)
+		if len(window.Stocks) != 5 || len(window.Future) != model.User_stock_to_guess {
+			t.Fatalf("Expected 5 stocks and %d days to guess and not %d %d", model.User_stock_to_guess, len(window.Stocks), len(window.Future))
+		}
+		last := window.Stocks[len(window.Stocks)-1].Date
+		if last < scenario.StartDate || last > scenario.EndDate {
+			t.Errorf("Expected the last day shown in the scenario and not %s", last)
+		}
+		if window.Stocks[0].SymbolUUID != "uuid-AAPL" {
+			t.Errorf("Expected the symbol of the scenario and not %s", window.Stocks[0].SymbolUUID)
+		}
+	})
+	t.Run("No future after the scenario", func(t *testing.T) {
+		loaded = nil
+		scenario := model.Scenario{Id: "late", StartDate: "2023-02-25", EndDate: "2023-03-31", Symbols: []string{"AAPL"}}
+		window := mockService.GetScenarioWindow(scenario, 5, "")
+		if len(window.Stocks) != 0 {
+			t.Errorf("Expected no window and not %d stocks", len(window.Stocks))
+		}
+		if len(loaded) != 15 {
+			t.Errorf("Expected 15 tries and not %d", len(loaded))
+		}
+	})
+	t.Run("Every symbol without a list", func(t *testing.T) {
+		loaded = nil
+		mockDataAccess.GetUniqueStockSymbolsFunc = func(ctx context.Context) []string {
+			return []string{"MSFT"}
+		}
+		window := mockService.GetScenarioWindow(model.Scenario{Id: "all", StartDate: "2023-01-20", EndDate: "2023-01-25"}, 5, "")
+		if len(window.Stocks) != 5 || !slices.Equal(loaded, []string{"MSFT"}) {
+			t.Errorf("Expected a window of MSFT and not %d stocks of %v", len(window.Stocks), loaded)
+		}
+	})
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"stockgame/internal/dataaccess"
+	"stockgame/internal/database"
+	"stockgame/internal/logic"
+	"stockgame/internal/model"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrNoTickerChallenge     = errors.New("no stock window available for a ticker challenge")
+	ErrInvalidTickerAnswer   = errors.New("the answer is not one of the candidates")
+	ErrTickerAlreadyAnswered = errors.New("ticker challenge already answered")
+)
+
+type TickerService interface {
+	NewChallenge(playerId string) (model.TickerChallengeResponse, error)
+	Answer(id string, playerId string, optionId string) (model.TickerAnswerResponse, error)
+}
+
+type TickerServiceImpl struct {
+	TickerDataAccess dataaccess.TickerDataAccess
+	StockService     StockService
+	Now              func() time.Time // Nil uses time.Now
+	TickerService
+}
+
+// NewChallenge serves a window without its symbol with the company and other candidates
+// in a random order. The candidates get new ids, their stocks stay in the challenge.
+func (s *TickerServiceImpl) NewChallenge(playerId string) (model.TickerChallengeResponse, error) {
+	stocks := s.StockService.GetRandomStockWithRandomDayRange(model.Number_initial_stock_shown)
+	if len(stocks) == 0 {
+		return model.TickerChallengeResponse{}, ErrNoTickerChallenge
+	}
+	info, err := s.StockService.GetStockInfo(stocks[0].SymbolUUID)
+	if err != nil {
+		return model.TickerChallengeResponse{}, fmt.Errorf("cannot find the stock of the window: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), database.CONTEXT_TIMEOUT)
+	defer cancel()
+	others, err := s.TickerDataAccess.GetTickerCandidates(ctx, info.SymbolUUID, model.Ticker_candidates-1)
+	if err != nil {
+		return model.TickerChallengeResponse{}, err
+	}
+	if len(others) < model.Ticker_candidates-1 {
+		return model.TickerChallengeResponse{}, ErrNoTickerChallenge
+	}
+	options := append(others, model.TickerOption{
+		TickerCandidate: model.TickerCandidate{Symbol: info.Symbol, Name: info.Name},
+		SymbolUUID:      info.SymbolUUID,
+	})
+	rand.Shuffle(len(options), func(i, j int) {
+		options[i], options[j] = options[j], options[i]
+	})
+	candidates := make([]model.TickerCandidate, len(options))
+	for i := range options {
+		options[i].Id = uuid.New().String()
+		candidates[i] = options[i].TickerCandidate
+	}
+	challenge := model.TickerChallenge{
+		Id:         uuid.New().String(),
+		PlayerId:   playerId,
+		SymbolUUID: info.SymbolUUID,
+		Candidates: options,
+		ServedAt:   s.now(),
+	}
+	if err := s.TickerDataAccess.CreateTickerChallenge(ctx, challenge); err != nil {
+		return model.TickerChallengeResponse{}, err
+	}
+	anonymized := slices.Clone(stocks)
+	for i := range anonymized {
+		anonymized[i].SymbolUUID = ""
+	}
+	return model.TickerChallengeResponse{
+		Id:         challenge.Id,
+		Stocks:     anonymized,
+		Candidates: candidates,
+		ServedAt:   challenge.ServedAt,
+	}, nil
+}
+
+// Answer scores the first answer of the player to the challenge, the option is one of the
+// candidates of the challenge
+func (s *TickerServiceImpl) Answer(id string, playerId string, optionId string) (model.TickerAnswerResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), database.CONTEXT_TIMEOUT)
+	defer cancel()
+	challenge, err := s.TickerDataAccess.GetTickerChallenge(ctx, id)
+	if err != nil {
+		return model.TickerAnswerResponse{}, err
+	}
+	// The challenge of another player is not found so its id cannot be guessed
+	if challenge.PlayerId != playerId {
+		return model.TickerAnswerResponse{}, dataaccess.ErrTickerChallengeNotFound
+	}
+	option := slices.IndexFunc(challenge.Candidates, func(candidate model.TickerOption) bool {
+		return candidate.Id != "" && candidate.Id == optionId
+	})
+	if option < 0 {
+		return model.TickerAnswerResponse{}, ErrInvalidTickerAnswer
+	}
+	symbolUUID := challenge.Candidates[option].SymbolUUID
+	answeredAt := s.now()
+	score := logic.GetTickerScore(symbolUUID == challenge.SymbolUUID, answeredAt.Sub(challenge.ServedAt))
+	saved, err := s.TickerDataAccess.SaveTickerAnswer(ctx, id, symbolUUID, answeredAt, score.Total)
+	if err != nil {
+		return model.TickerAnswerResponse{}, err
+	}
+	if !saved {
+		return model.TickerAnswerResponse{}, ErrTickerAlreadyAnswered
+	}
+	response := model.TickerAnswerResponse{Score: score}
+	for _, candidate := range challenge.Candidates {
+		if candidate.SymbolUUID == challenge.SymbolUUID {
+			response.Answer = candidate.TickerCandidate
+		}
+	}
+	return response, nil
+}
+
+func (s *TickerServiceImpl) now() time.Time {
+	if s.Now == nil {
+		return time.Now().UTC()
+	}
+	return s.Now()
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"stockgame/internal/dataaccess"
+	"stockgame/internal/model"
+	"testing"
+	"time"
+)
+
+type TickerDataAccessMockImpl struct {
+	Candidates []model.TickerOption
+	Challenge  *model.TickerChallenge
+	Answered   bool
+}
+
+func (s *TickerDataAccessMockImpl) GetTickerCandidates(ctx context.Context, symbolUUID string, count int) ([]model.TickerOption, error) {
+	return s.Candidates[:min(count, len(s.Candidates))], nil
+}
+
+func (s *TickerDataAccessMockImpl) CreateTickerChallenge(ctx context.Context, challenge model.TickerChallenge) error {
+	s.Challenge = &challenge
+	return nil
+}
+
+func (s *TickerDataAccessMockImpl) GetTickerChallenge(ctx context.Context, id string) (model.TickerChallenge, error) {
+	if s.Challenge == nil || s.Challenge.Id != id {
+		return model.TickerChallenge{}, dataaccess.ErrTickerChallengeNotFound
+	}
+	return *s.Challenge, nil
+}
+
+func (s *TickerDataAccessMockImpl) SaveTickerAnswer(ctx context.Context, id string, answer string, answeredAt time.Time, total int) (bool, error) {
+	if s.Answered {
+		return false, nil
+	}
+	s.Answered = true
+	return true, nil
+}
+
+func newTickerService(dataAccess *TickerDataAccessMockImpl, now *time.Time) *TickerServiceImpl {
+	return &TickerServiceImpl{
+		TickerDataAccess: dataAccess,
+		StockService: &StockServiceMockImpl{Range: []model.StockPublic{
+			{Date: "2020-01-30", Close: 90, SymbolUUID: "uuid-aapl"},
+			{Date: "2020-01-31", Close: 100, SymbolUUID: "uuid-aapl"},
+		}},
+		Now: func() time.Time { return *now },
+	}
+}
+
+func TestTickerChallenge(t *testing.T) {
+	now := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	dataAccess := &TickerDataAccessMockImpl{Candidates: []model.TickerOption{
+		{TickerCandidate: model.TickerCandidate{Symbol: "MSFT"}, SymbolUUID: "uuid-msft"},
+		{TickerCandidate: model.TickerCandidate{Symbol: "GOOG"}, SymbolUUID: "uuid-goog"},
+		{TickerCandidate: model.TickerCandidate{Symbol: "AMZN"}, SymbolUUID: "uuid-amzn"},
+	}}
+	mockService := newTickerService(dataAccess, &now)
+
+	challenge, err := mockService.NewChallenge("player-1")
+	if err != nil {
+		t.Fatalf("Expected no error and not %v", err)
+	}
+	if len(challenge.Candidates) != model.Ticker_candidates {
+		t.Errorf("Expected %d candidates and not %+v", model.Ticker_candidates, challenge.Candidates)
+	}
+	for _, stock := range challenge.Stocks {
+		if stock.SymbolUUID != "" {
+			t.Fatalf("Expected an anonymized window and not %+v", stock)
+		}
+	}
+	// The options map the ids served to the stocks
+	optionIds := map[string]string{}
+	for i, option := range dataAccess.Challenge.Candidates {
+		if option.Id == "" || option.Id != challenge.Candidates[i].Id {
+			t.Fatalf("Expected the candidates served to have the ids of the options and not %+v", challenge.Candidates)
+		}
+		optionIds[option.SymbolUUID] = option.Id
+	}
+
+	t.Run("Not a candidate", func(t *testing.T) {
+		if _, err := mockService.Answer(challenge.Id, "player-1", "uuid-aapl"); !errors.Is(err, ErrInvalidTickerAnswer) {
+			t.Errorf("Expected ErrInvalidTickerAnswer and not %v", err)
+		}
+	})
+	t.Run("Another player", func(t *testing.T) {
+		if _, err := mockService.Answer(challenge.Id, "player-2", optionIds["uuid-aapl"]); !errors.Is(err, dataaccess.ErrTickerChallengeNotFound) {
+			t.Errorf("Expected ErrTickerChallengeNotFound and not %v", err)
+		}
+	})
+	t.Run("Correct", func(t *testing.T) {
+		now = now.Add(15 * time.Second)
+		response, err := mockService.Answer(challenge.Id, "player-1", optionIds["uuid-aapl"])
+		if err != nil {
+			t.Fatalf("Expected no error and not %v", err)
+		}
+		if !response.Score.Correct || response.Score.Seconds != 15 || response.Answer.Symbol != "AAPL" {
+			t.Errorf("Unexpected answer %+v", response)
+		}
+	})
+	t.Run("Answered twice", func(t *testing.T) {
+		if _, err := mockService.Answer(challenge.Id, "player-1", optionIds["uuid-msft"]); !errors.Is(err, ErrTickerAlreadyAnswered) {
+			t.Errorf("Expected ErrTickerAlreadyAnswered and not %v", err)
+		}
+	})
+}
+
+func TestTickerChallengeNotEnoughCandidates(t *testing.T) {
+	now := time.Now()
+	dataAccess := &TickerDataAccessMockImpl{Candidates: []model.TickerOption{{SymbolUUID: "uuid-msft"}}}
+	mockService := newTickerService(dataAccess, &now)
+	if _, err := mockService.NewChallenge("player-1"); !errors.Is(err, ErrNoTickerChallenge) {
+		t.Errorf("Expected ErrNoTickerChallenge and not %v", err)
+	}
+}
@@ -0,0 +1,246 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"stockgame/internal/dataaccess"
+	"stockgame/internal/database"
+	"stockgame/internal/logic"
+	"stockgame/internal/model"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidTournament    = errors.New("invalid tournament")
+	ErrTournamentNotStarted = errors.New("tournament has not started")
+	ErrTournamentEnded      = errors.New("tournament has ended")
+	ErrNotEntered           = errors.New("player has not joined the tournament")
+	ErrAlreadyEntered       = errors.New("player already joined the tournament")
+	ErrTournamentCompleted  = errors.New("every round of the tournament was played")
+	ErrWrongPosition        = errors.New("the rounds of a tournament are played in order")
+	ErrRoundAlreadyPlayed   = errors.New("round already played")
+	ErrNoTournamentWindow   = errors.New("no stock window available for the tournament")
+)
+
+const maxTournamentRounds = 20
+
+const maxTournamentNameLength = 64
+
+// Random windows drawn per round of a tournament before giving up on finding distinct ones
+const tournamentWindowAttempts = 10
+
+type TournamentService interface {
+	CreateTournament(request model.CreateTournamentRequest) (model.Tournament, error)
+	ListTournaments() ([]model.Tournament, error)
+	GetTournament(id string) (model.Tournament, error)
+	JoinTournament(id string, playerId string, name string) error
+	GetNextRound(id string, playerId string) (model.TournamentRoundResponse, error)
+	GetRoundWindow(id string, playerId string, position int) (model.TournamentWindow, error)
+	RecordRound(id string, playerId string, position int, roundId string, score model.UserScoreResponse) error
+	GetStandings(id string) ([]model.TournamentStanding, error)
+}
+
+type TournamentServiceImpl struct {
+	TournamentDataAccess dataaccess.TournamentDataAccess
+	StockService         StockService
+	Now                  func() time.Time // Nil uses time.Now
+	TournamentService
+}
+
+// CreateTournament picks the windows of the tournament, every player guesses the same ones
+func (s *TournamentServiceImpl) CreateTournament(request model.CreateTournamentRequest) (model.Tournament, error) {
+	name := strings.TrimSpace(request.Name)
+	switch {
+	case name == "" || len(name) > maxTournamentNameLength:
+		return model.Tournament{}, fmt.Errorf("%w: the name must have 1 to %d characters", ErrInvalidTournament, maxTournamentNameLength)
+	case !request.EndsAt.After(request.StartsAt):
+		return model.Tournament{}, fmt.Errorf("%w: endsAt must be after startsAt", ErrInvalidTournament)
+	case request.Rounds < 1 || request.Rounds > maxTournamentRounds:
+		return model.Tournament{}, fmt.Errorf("%w: rounds must be between 1 and %d", ErrInvalidTournament, maxTournamentRounds)
+	case request.Difficulty != "" && !logic.IsValidDifficulty(request.Difficulty):
+		return model.Tournament{}, fmt.Errorf("%w: difficulty must be easy, medium or hard", ErrInvalidTournament)
+	}
+	tournament := model.Tournament{
+		Id:        uuid.New().String(),
+		Name:      name,
+		StartsAt:  request.StartsAt.UTC(),
+		EndsAt:    request.EndsAt.UTC(),
+		Rounds:    request.Rounds,
+		CreatedAt: s.now(),
+	}
+	for attempts := 0; len(tournament.Windows) < request.Rounds; attempts++ {
+		if attempts >= request.Rounds*tournamentWindowAttempts {
+			return model.Tournament{}, fmt.Errorf("%w: %d distinct windows found for %d rounds", ErrNoTournamentWindow, len(tournament.Windows), request.Rounds)
+		}
+		window := s.StockService.GetRandomWindow(model.Number_initial_stock_shown, request.Difficulty)
+		if len(window.Stocks) == 0 {
+			return model.Tournament{}, ErrNoTournamentWindow
+		}
+		afterDate := dateOnly(window.Stocks[len(window.Stocks)-1].Date)
+		candidate := model.TournamentWindow{SymbolUUID: window.Stocks[0].SymbolUUID, AfterDate: afterDate}
+		if !slices.Contains(tournament.Windows, candidate) {
+			tournament.Windows = append(tournament.Windows, candidate)
+		}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), database.CONTEXT_TIMEOUT)
+	defer cancel()
+	if err := s.TournamentDataAccess.CreateTournament(ctx, tournament); err != nil {
+		return model.Tournament{}, err
+	}
+	return tournament, nil
+}
+
+func (s *TournamentServiceImpl) ListTournaments() ([]model.Tournament, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), database.CONTEXT_TIMEOUT)
+	defer cancel()
+	return s.TournamentDataAccess.ListTournaments(ctx)
+}
+
+// GetTournament returns dataaccess.ErrTournamentNotFound when no tournament has the id
+func (s *TournamentServiceImpl) GetTournament(id string) (model.Tournament, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), database.CONTEXT_TIMEOUT)
+	defer cancel()
+	return s.TournamentDataAccess.GetTournament(ctx, id)
+}
+
+// JoinTournament adds the player to the tournament until it ends
+func (s *TournamentServiceImpl) JoinTournament(id string, playerId string, name string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), database.CONTEXT_TIMEOUT)
+	defer cancel()
+	tournament, err := s.TournamentDataAccess.GetTournament(ctx, id)
+	if err != nil {
+		return err
+	}
+	if s.now().After(tournament.EndsAt) {
+		return ErrTournamentEnded
+	}
+	name = strings.TrimSpace(name)
+	if runes := []rune(name); len(runes) > maxTournamentNameLength {
+		name = string(runes[:maxTournamentNameLength])
+	}
+	if name == "" {
+		name = fmt.Sprintf("Player %d", tournament.Entries+1)
+	}
+	added, err := s.TournamentDataAccess.AddEntry(ctx, id, playerId, name)
+	if err != nil {
+		return err
+	}
+	if !added {
+		return ErrAlreadyEntered
+	}
+	return nil
+}
+
+// GetNextRound returns the days shown of the first window the player did not play
+func (s *TournamentServiceImpl) GetNextRound(id string, playerId string) (model.TournamentRoundResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), database.CONTEXT_TIMEOUT)
+	defer cancel()
+	tournament, position, err := s.nextPosition(ctx, id, playerId)
+	if err != nil {
+		return model.TournamentRoundResponse{}, err
+	}
+	window := tournament.Windows[position-1]
+	info, err := s.StockService.GetStockInfo(window.SymbolUUID)
+	if err != nil {
+		return model.TournamentRoundResponse{}, fmt.Errorf("cannot find the stock of round %d: %v", position, err)
+	}
+	// The stocks before the date are sorted from the most recent
+	shown := slices.Clone(s.StockService.GetStocksBeforeEqualDate(info.Symbol, window.AfterDate))
+	slices.Reverse(shown)
+	return model.TournamentRoundResponse{
+		TournamentId: tournament.Id,
+		Position:     position,
+		Rounds:       tournament.Rounds,
+		Stocks:       logic.StocksToPublic(shown, info.SymbolUUID),
+	}, nil
+}
+
+// GetRoundWindow returns the window of the round the player submits, which must be the
+// next one
+func (s *TournamentServiceImpl) GetRoundWindow(id string, playerId string, position int) (model.TournamentWindow, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), database.CONTEXT_TIMEOUT)
+	defer cancel()
+	tournament, next, err := s.nextPosition(ctx, id, playerId)
+	if err != nil {
+		return model.TournamentWindow{}, err
+	}
+	if position < next {
+		return model.TournamentWindow{}, ErrRoundAlreadyPlayed
+	}
+	if position != next {
+		return model.TournamentWindow{}, ErrWrongPosition
+	}
+	return tournament.Windows[position-1], nil
+}
+
+// RecordRound adds the score of a round to the standings of the tournament
+func (s *TournamentServiceImpl) RecordRound(id string, playerId string, position int, roundId string, score model.UserScoreResponse) error {
+	ctx, cancel := context.WithTimeout(context.Background(), database.CONTEXT_TIMEOUT)
+	defer cancel()
+	saved, err := s.TournamentDataAccess.SaveTournamentRound(ctx, id, playerId, position, roundId, score)
+	if err != nil {
+		return err
+	}
+	if !saved {
+		return ErrRoundAlreadyPlayed
+	}
+	return nil
+}
+
+// GetStandings ranks the players of the tournament, see logic.RankStandings for the ties
+func (s *TournamentServiceImpl) GetStandings(id string) ([]model.TournamentStanding, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), database.CONTEXT_TIMEOUT)
+	defer cancel()
+	if _, err := s.TournamentDataAccess.GetTournament(ctx, id); err != nil {
+		return nil, err
+	}
+	standings, err := s.TournamentDataAccess.GetStandings(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	logic.RankStandings(standings)
+	return standings, nil
+}
+
+// nextPosition checks the player can play the tournament now and returns the position of
+// the first round not played
+func (s *TournamentServiceImpl) nextPosition(ctx context.Context, id string, playerId string) (model.Tournament, int, error) {
+	tournament, err := s.TournamentDataAccess.GetTournament(ctx, id)
+	if err != nil {
+		return tournament, 0, err
+	}
+	now := s.now()
+	if now.Before(tournament.StartsAt) {
+		return tournament, 0, ErrTournamentNotStarted
+	}
+	if now.After(tournament.EndsAt) {
+		return tournament, 0, ErrTournamentEnded
+	}
+	entered, err := s.TournamentDataAccess.HasEntry(ctx, id, playerId)
+	if err != nil {
+		return tournament, 0, err
+	}
+	if !entered {
+		return tournament, 0, ErrNotEntered
+	}
+	played, err := s.TournamentDataAccess.CountPlayedRounds(ctx, id, playerId)
+	if err != nil {
+		return tournament, 0, err
+	}
+	if played >= tournament.Rounds {
+		return tournament, 0, ErrTournamentCompleted
+	}
+	return tournament, played + 1, nil
+}
+
+func (s *TournamentServiceImpl) now() time.Time {
+	if s.Now == nil {
+		return time.Now().UTC()
+	}
+	return s.Now()
+}
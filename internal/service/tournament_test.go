@@ -0,0 +1,297 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"stockgame/internal/dataaccess"
+	"stockgame/internal/model"
+	"testing"
+	"time"
+)
+
+type TournamentDataAccessMockImpl struct {
+	Tournament        model.Tournament // Returned by GetTournament when its id matches
+	Entered           bool
+	Played            int
+	SkipInsert        bool // AddEntry and SaveTournamentRound find a conflict
+	Standings         []model.TournamentStanding
+	Created           *model.Tournament
+	SavedPosition     int
+	GetTournamentFunc func(ctx context.Context, id string) (model.Tournament, error)
+}
+
+func (s *TournamentDataAccessMockImpl) CreateTournament(ctx context.Context, tournament model.Tournament) error {
+	s.Created = &tournament
+	return nil
+}
+
+func (s *TournamentDataAccessMockImpl) GetTournament(ctx context.Context, id string) (model.Tournament, error) {
+	if s.GetTournamentFunc != nil {
+		return s.GetTournamentFunc(ctx, id)
+	}
+	if id != s.Tournament.Id {
+		return model.Tournament{}, dataaccess.ErrTournamentNotFound
+	}
+	return s.Tournament, nil
+}
+
+func (s *TournamentDataAccessMockImpl) ListTournaments(ctx context.Context) ([]model.Tournament, error) {
+	return []model.Tournament{s.Tournament}, nil
+}
+
+func (s *TournamentDataAccessMockImpl) AddEntry(ctx context.Context, tournamentId, playerId, name string) (bool, error) {
+	return !s.SkipInsert, nil
+}
+
+func (s *TournamentDataAccessMockImpl) HasEntry(ctx context.Context, tournamentId, playerId string) (bool, error) {
+	return s.Entered, nil
+}
+
+func (s *TournamentDataAccessMockImpl) CountPlayedRounds(ctx context.Context, tournamentId, playerId string) (int, error) {
+	return s.Played, nil
+}
+
+func (s *TournamentDataAccessMockImpl) SaveTournamentRound(ctx context.Context, tournamentId, playerId string, position int, roundId string, score model.UserScoreResponse) (bool, error) {
+	s.SavedPosition = position
+	return !s.SkipInsert, nil
+}
+
+func (s *TournamentDataAccessMockImpl) GetStandings(ctx context.Context, tournamentId string) ([]model.TournamentStanding, error) {
+	return s.Standings, nil
+}
+
+// StockServiceMockImpl only implements the methods used by the tournaments, the trading, the
+// ticker challenges and the hints
+type StockServiceMockImpl struct {
+	StockService
+	Windows []model.StockWindow // Served in order by GetRandomWindow
+	After   []model.Stock
+	Range   []model.StockPublic // Served by GetRandomStockWithRandomDayRange
+}
+
+func (s *StockServiceMockImpl) GetRandomStockWithRandomDayRange(numberOfDays int) []model.StockPublic {
+	return s.Range
+}
+
+func (s *StockServiceMockImpl) GetRandomWindow(numberOfDays int, difficulty string) model.StockWindow {
+	if len(s.Windows) == 0 {
+		return model.StockWindow{}
+	}
+	window := s.Windows[0]
+	s.Windows = s.Windows[1:]
+	return window
+}
+
+func (s *StockServiceMockImpl) GetStockInfo(symbolUUID string) (model.StockInfo, error) {
+	return model.StockInfo{Symbol: "AAPL", SymbolUUID: symbolUUID}, nil
+}
+
+func (s *StockServiceMockImpl) GetStocksAfterDate(symbol, date string) []model.Stock {
+	return s.After
+}
+
+func (s *StockServiceMockImpl) GetStocksBeforeEqualDate(symbol, date string) []model.Stock {
+	return []model.Stock{{Date: "2020-01-31", Close: 2}, {Date: "2020-01-30", Close: 1}}
+}
+
+var tournamentNow = time.Date(2024, 5, 3, 12, 0, 0, 0, time.UTC)
+
+func newTournamentService(dataAccess *TournamentDataAccessMockImpl) *TournamentServiceImpl {
+	return &TournamentServiceImpl{
+		TournamentDataAccess: dataAccess,
+		StockService:         &StockServiceMockImpl{},
+		Now:                  func() time.Time { return tournamentNow },
+	}
+}
+
+func openTournament() model.Tournament {
+	return model.Tournament{
+		Id:       "tournament-1",
+		StartsAt: tournamentNow.Add(-time.Hour),
+		EndsAt:   tournamentNow.Add(time.Hour),
+		Windows: []model.TournamentWindow{
+			{SymbolUUID: "uuid-aapl", AfterDate: "2020-01-31"},
+			{SymbolUUID: "uuid-msft", AfterDate: "2019-06-28"},
+		},
+		Rounds: 2,
+	}
+}
+
+func TestCreateTournament(t *testing.T) {
+	request := model.CreateTournamentRequest{
+		Name:     " Spring cup ",
+		StartsAt: tournamentNow,
+		EndsAt:   tournamentNow.Add(24 * time.Hour),
+		Rounds:   2,
+	}
+	t.Run("Distinct windows", func(t *testing.T) {
+		dataAccess := &TournamentDataAccessMockImpl{}
+		mockService := newTournamentService(dataAccess)
+		aapl := model.StockWindow{Stocks: []model.StockPublic{{Date: "2020-01-30T00:00:00Z", SymbolUUID: "uuid-aapl"}, {Date: "2020-01-31T00:00:00Z", SymbolUUID: "uuid-aapl"}}}
+		msft := model.StockWindow{Stocks: []model.StockPublic{{Date: "2019-06-28", SymbolUUID: "uuid-msft"}}}
+		mockService.StockService = &StockServiceMockImpl{Windows: []model.StockWindow{aapl, aapl, msft}}
+		tournament, err := mockService.CreateTournament(request)
+		if err != nil {
+			t.Fatalf("Expected no error and not %v", err)
+		}
+		expected := []model.TournamentWindow{{SymbolUUID: "uuid-aapl", AfterDate: "2020-01-31"}, {SymbolUUID: "uuid-msft", AfterDate: "2019-06-28"}}
+		if len(tournament.Windows) != 2 || tournament.Windows[0] != expected[0] || tournament.Windows[1] != expected[1] {
+			t.Errorf("Expected %+v and not %+v", expected, tournament.Windows)
+		}
+		if tournament.Name != "Spring cup" || dataAccess.Created == nil || dataAccess.Created.Id != tournament.Id {
+			t.Errorf("Expected the tournament to be saved and not %+v", dataAccess.Created)
+		}
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		invalids := map[string]model.CreateTournamentRequest{}
+		noName := request
+		noName.Name = " "
+		invalids["no name"] = noName
+		ended := request
+		ended.EndsAt = request.StartsAt
+		invalids["ends before it starts"] = ended
+		tooMany := request
+		tooMany.Rounds = maxTournamentRounds + 1
+		invalids["too many rounds"] = tooMany
+		difficulty := request
+		difficulty.Difficulty = "extreme"
+		invalids["unknown difficulty"] = difficulty
+		for name, invalid := range invalids {
+			mockService := newTournamentService(&TournamentDataAccessMockImpl{})
+			if _, err := mockService.CreateTournament(invalid); !errors.Is(err, ErrInvalidTournament) {
+				t.Errorf("%s: expected ErrInvalidTournament and not %v", name, err)
+			}
+		}
+	})
+	t.Run("No window", func(t *testing.T) {
+		mockService := newTournamentService(&TournamentDataAccessMockImpl{})
+		if _, err := mockService.CreateTournament(request); !errors.Is(err, ErrNoTournamentWindow) {
+			t.Errorf("Expected ErrNoTournamentWindow and not %v", err)
+		}
+	})
+	t.Run("Same window every time", func(t *testing.T) {
+		aapl := model.StockWindow{Stocks: []model.StockPublic{{Date: "2020-01-31", SymbolUUID: "uuid-aapl"}}}
+		stockService := &StockServiceMockImpl{Windows: slices.Repeat([]model.StockWindow{aapl}, 100)}
+		mockService := newTournamentService(&TournamentDataAccessMockImpl{})
+		mockService.StockService = stockService
+		if _, err := mockService.CreateTournament(request); !errors.Is(err, ErrNoTournamentWindow) {
+			t.Errorf("Expected ErrNoTournamentWindow and not %v", err)
+		}
+		if drawn := 100 - len(stockService.Windows); drawn != request.Rounds*tournamentWindowAttempts {
+			t.Errorf("Expected %d windows drawn and not %d", request.Rounds*tournamentWindowAttempts, drawn)
+		}
+	})
+}
+
+func TestJoinTournament(t *testing.T) {
+	t.Run("Joined", func(t *testing.T) {
+		mockService := newTournamentService(&TournamentDataAccessMockImpl{Tournament: openTournament()})
+		if err := mockService.JoinTournament("tournament-1", "player-1", "Alice"); err != nil {
+			t.Errorf("Expected no error and not %v", err)
+		}
+	})
+	t.Run("Already joined", func(t *testing.T) {
+		mockService := newTournamentService(&TournamentDataAccessMockImpl{Tournament: openTournament(), SkipInsert: true})
+		if err := mockService.JoinTournament("tournament-1", "player-1", "Alice"); !errors.Is(err, ErrAlreadyEntered) {
+			t.Errorf("Expected ErrAlreadyEntered and not %v", err)
+		}
+	})
+	t.Run("Ended", func(t *testing.T) {
+		tournament := openTournament()
+		tournament.EndsAt = tournamentNow.Add(-time.Minute)
+		mockService := newTournamentService(&TournamentDataAccessMockImpl{Tournament: tournament})
+		if err := mockService.JoinTournament("tournament-1", "player-1", "Alice"); !errors.Is(err, ErrTournamentEnded) {
+			t.Errorf("Expected ErrTournamentEnded and not %v", err)
+		}
+	})
+	t.Run("Not found", func(t *testing.T) {
+		mockService := newTournamentService(&TournamentDataAccessMockImpl{Tournament: openTournament()})
+		if err := mockService.JoinTournament("unknown", "player-1", "Alice"); !errors.Is(err, dataaccess.ErrTournamentNotFound) {
+			t.Errorf("Expected ErrTournamentNotFound and not %v", err)
+		}
+	})
+}
+
+func TestGetNextRound(t *testing.T) {
+	t.Run("Second round", func(t *testing.T) {
+		mockService := newTournamentService(&TournamentDataAccessMockImpl{Tournament: openTournament(), Entered: true, Played: 1})
+		round, err := mockService.GetNextRound("tournament-1", "player-1")
+		if err != nil {
+			t.Fatalf("Expected no error and not %v", err)
+		}
+		if round.Position != 2 || round.Rounds != 2 {
+			t.Errorf("Expected round 2 of 2 and not %+v", round)
+		}
+		if len(round.Stocks) != 2 || round.Stocks[0].Date != "2020-01-30" || round.Stocks[0].SymbolUUID != "uuid-msft" {
+			t.Errorf("Expected the days shown in chronological order and not %+v", round.Stocks)
+		}
+	})
+	errorCases := []struct {
+		name       string
+		dataAccess *TournamentDataAccessMockImpl
+		expected   error
+	}{
+		{"Not entered", &TournamentDataAccessMockImpl{Tournament: openTournament()}, ErrNotEntered},
+		{"Completed", &TournamentDataAccessMockImpl{Tournament: openTournament(), Entered: true, Played: 2}, ErrTournamentCompleted},
+	}
+	notStarted := openTournament()
+	notStarted.StartsAt = tournamentNow.Add(time.Minute)
+	errorCases = append(errorCases, struct {
+		name       string
+		dataAccess *TournamentDataAccessMockImpl
+		expected   error
+	}{"Not started", &TournamentDataAccessMockImpl{Tournament: notStarted, Entered: true}, ErrTournamentNotStarted})
+	for _, errorCase := range errorCases {
+		t.Run(errorCase.name, func(t *testing.T) {
+			mockService := newTournamentService(errorCase.dataAccess)
+			if _, err := mockService.GetNextRound("tournament-1", "player-1"); !errors.Is(err, errorCase.expected) {
+				t.Errorf("Expected %v and not %v", errorCase.expected, err)
+			}
+		})
+	}
+}
+
+func TestGetRoundWindow(t *testing.T) {
+	mockService := newTournamentService(&TournamentDataAccessMockImpl{Tournament: openTournament(), Entered: true, Played: 1})
+	window, err := mockService.GetRoundWindow("tournament-1", "player-1", 2)
+	if err != nil || window.SymbolUUID != "uuid-msft" {
+		t.Errorf("Expected the second window and not %+v %v", window, err)
+	}
+	if _, err := mockService.GetRoundWindow("tournament-1", "player-1", 1); !errors.Is(err, ErrRoundAlreadyPlayed) {
+		t.Errorf("Expected ErrRoundAlreadyPlayed and not %v", err)
+	}
+	mockService = newTournamentService(&TournamentDataAccessMockImpl{Tournament: openTournament(), Entered: true})
+	if _, err := mockService.GetRoundWindow("tournament-1", "player-1", 2); !errors.Is(err, ErrWrongPosition) {
+		t.Errorf("Expected ErrWrongPosition and not %v", err)
+	}
+}
+
+func TestRecordRound(t *testing.T) {
+	dataAccess := &TournamentDataAccessMockImpl{}
+	mockService := newTournamentService(dataAccess)
+	if err := mockService.RecordRound("tournament-1", "player-1", 1, "round-1", model.UserScoreResponse{Total: 10}); err != nil {
+		t.Errorf("Expected no error and not %v", err)
+	}
+	dataAccess.SkipInsert = true
+	if err := mockService.RecordRound("tournament-1", "player-1", 1, "round-2", model.UserScoreResponse{Total: 10}); !errors.Is(err, ErrRoundAlreadyPlayed) {
+		t.Errorf("Expected ErrRoundAlreadyPlayed and not %v", err)
+	}
+}
+
+func TestGetStandings(t *testing.T) {
+	mockService := newTournamentService(&TournamentDataAccessMockImpl{
+		Tournament: openTournament(),
+		Standings: []model.TournamentStanding{
+			{Entry: 1, Name: "Alice", Score: model.UserScoreResponse{Total: 10}},
+			{Entry: 2, Name: "Bob", Score: model.UserScoreResponse{Total: 20}},
+		},
+	})
+	standings, err := mockService.GetStandings("tournament-1")
+	if err != nil {
+		t.Fatalf("Expected no error and not %v", err)
+	}
+	if standings[0].Name != "Bob" || standings[0].Rank != 1 || standings[1].Rank != 2 {
+		t.Errorf("Expected Bob first and not %+v", standings)
+	}
+}
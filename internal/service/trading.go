@@ -0,0 +1,194 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"stockgame/internal/dataaccess"
+	"stockgame/internal/database"
+	"stockgame/internal/logic"
+	"stockgame/internal/model"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrTradingSessionFinished = errors.New("every day of the trading session was revealed")
+	ErrTradeConflict          = errors.New("another trade was saved for this day")
+	ErrNoTradingWindow        = errors.New("no stock window available for trading")
+)
+
+type TradingService interface {
+	StartSession(playerId string, difficulty string) (model.TradingResponse, error)
+	GetSession(id string, playerId string) (model.TradingResponse, error)
+	Trade(id string, playerId string, trade model.TradeRequest) (model.TradingResponse, error)
+}
+
+type TradingServiceImpl struct {
+	TradingDataAccess dataaccess.TradingDataAccess
+	StockService      StockService
+	Now               func() time.Time // Nil uses time.Now
+	TradingService
+}
+
+// StartSession picks a window and shows its days, the days after it are hidden until traded
+func (s *TradingServiceImpl) StartSession(playerId string, difficulty string) (model.TradingResponse, error) {
+	window := s.StockService.GetRandomWindow(model.Number_initial_stock_shown, difficulty)
+	if len(window.Stocks) == 0 {
+		return model.TradingResponse{}, ErrNoTradingWindow
+	}
+	last := window.Stocks[len(window.Stocks)-1]
+	afterDate := dateOnly(last.Date)
+	info, err := s.StockService.GetStockInfo(last.SymbolUUID)
+	if err != nil {
+		return model.TradingResponse{}, fmt.Errorf("cannot find the stock of the window: %v", err)
+	}
+	bars := s.StockService.GetStocksAfterDate(info.Symbol, afterDate)
+	if len(bars) == 0 {
+		return model.TradingResponse{}, ErrNoTradingWindow
+	}
+	session := model.TradingSession{
+		Id:              uuid.New().String(),
+		PlayerId:        playerId,
+		SymbolUUID:      info.SymbolUUID,
+		AfterDate:       afterDate,
+		Days:            len(bars),
+		InitialCash:     model.Trading_initial_cash,
+		LastClose:       last.Close,
+		Decisions:       []model.TradeDecision{},
+		TradingPosition: model.TradingPosition{Cash: model.Trading_initial_cash},
+		CreatedAt:       s.now(),
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), database.CONTEXT_TIMEOUT)
+	defer cancel()
+	if err := s.TradingDataAccess.CreateTradingSession(ctx, session); err != nil {
+		return model.TradingResponse{}, err
+	}
+	response := tradingResponse(session, info, bars)
+	response.Stocks = window.Stocks
+	return response, nil
+}
+
+// GetSession returns the days shown and the days revealed so far, with the result once
+// every day was revealed
+func (s *TradingServiceImpl) GetSession(id string, playerId string) (model.TradingResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), database.CONTEXT_TIMEOUT)
+	defer cancel()
+	session, err := s.getSession(ctx, id, playerId)
+	if err != nil {
+		return model.TradingResponse{}, err
+	}
+	info, bars, err := s.loadBars(session)
+	if err != nil {
+		return model.TradingResponse{}, err
+	}
+	// The stocks before the date are sorted from the most recent
+	shown := slices.Clone(s.StockService.GetStocksBeforeEqualDate(info.Symbol, session.AfterDate))
+	slices.Reverse(shown)
+	response := tradingResponse(session, info, bars)
+	response.Stocks = logic.StocksToPublic(shown, info.SymbolUUID)
+	return response, nil
+}
+
+// Trade executes the trade at the last close revealed then reveals the next day
+func (s *TradingServiceImpl) Trade(id string, playerId string, trade model.TradeRequest) (model.TradingResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), database.CONTEXT_TIMEOUT)
+	defer cancel()
+	session, err := s.getSession(ctx, id, playerId)
+	if err != nil {
+		return model.TradingResponse{}, err
+	}
+	if session.Finished() {
+		return model.TradingResponse{}, ErrTradingSessionFinished
+	}
+	info, bars, err := s.loadBars(session)
+	if err != nil {
+		return model.TradingResponse{}, err
+	}
+	price := session.LastClose
+	if session.Day > 0 {
+		price = bars[session.Day-1].Close
+	}
+	position, quantity, err := logic.ApplyTrade(session.TradingPosition, trade, price)
+	if err != nil {
+		return model.TradingResponse{}, err
+	}
+	previousDay := session.Day
+	session.Day++
+	session.TradingPosition = position
+	session.Decisions = append(session.Decisions, model.TradeDecision{
+		Day:             session.Day,
+		Action:          trade.Action,
+		Quantity:        quantity,
+		Price:           price,
+		TradingPosition: position,
+	})
+	updated, err := s.TradingDataAccess.UpdateTradingSession(ctx, session, previousDay)
+	if err != nil {
+		return model.TradingResponse{}, err
+	}
+	if !updated {
+		return model.TradingResponse{}, ErrTradeConflict
+	}
+	return tradingResponse(session, info, bars), nil
+}
+
+// getSession returns dataaccess.ErrTradingSessionNotFound for the session of another
+// player so its id cannot be guessed
+func (s *TradingServiceImpl) getSession(ctx context.Context, id string, playerId string) (model.TradingSession, error) {
+	session, err := s.TradingDataAccess.GetTradingSession(ctx, id)
+	if err != nil {
+		return session, err
+	}
+	if session.PlayerId != playerId {
+		return model.TradingSession{}, dataaccess.ErrTradingSessionNotFound
+	}
+	return session, nil
+}
+
+// loadBars returns the days to reveal of the session
+func (s *TradingServiceImpl) loadBars(session model.TradingSession) (model.StockInfo, []model.Stock, error) {
+	info, err := s.StockService.GetStockInfo(session.SymbolUUID)
+	if err != nil {
+		return info, nil, fmt.Errorf("cannot find the stock of trading session %s: %v", session.Id, err)
+	}
+	bars := s.StockService.GetStocksAfterDate(info.Symbol, session.AfterDate)
+	if len(bars) < session.Days {
+		return info, nil, fmt.Errorf("trading session %s has %d days to reveal, %d found", session.Id, session.Days, len(bars))
+	}
+	return info, bars[:session.Days], nil
+}
+
+// tradingResponse reveals the days traded. The symbol is removed from the bars until the
+// session is finished.
+func tradingResponse(session model.TradingSession, info model.StockInfo, bars []model.Stock) model.TradingResponse {
+	response := model.TradingResponse{
+		Session:  session,
+		Revealed: slices.Clone(bars[:session.Day]),
+		Finished: session.Finished(),
+	}
+	if !response.Finished {
+		for i := range response.Revealed {
+			response.Revealed[i].Symbol = ""
+		}
+		return response
+	}
+	closes := []float64{session.LastClose}
+	for _, bar := range bars {
+		closes = append(closes, bar.Close)
+	}
+	result := logic.GetTradingResult(session.InitialCash, session.Decisions, closes)
+	response.Symbol = info.Symbol
+	response.Name = info.Name
+	response.Result = &result
+	return response
+}
+
+func (s *TradingServiceImpl) now() time.Time {
+	if s.Now == nil {
+		return time.Now().UTC()
+	}
+	return s.Now()
+}
@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"stockgame/internal/dataaccess"
+	"stockgame/internal/logic"
+	"stockgame/internal/model"
+	"testing"
+)
+
+// TradingDataAccessMockImpl keeps a single session in memory
+type TradingDataAccessMockImpl struct {
+	Session   *model.TradingSession
+	Conflicts bool // UpdateTradingSession finds another decision saved first
+}
+
+func (s *TradingDataAccessMockImpl) CreateTradingSession(ctx context.Context, session model.TradingSession) error {
+	s.Session = &session
+	return nil
+}
+
+func (s *TradingDataAccessMockImpl) GetTradingSession(ctx context.Context, id string) (model.TradingSession, error) {
+	if s.Session == nil || s.Session.Id != id {
+		return model.TradingSession{}, dataaccess.ErrTradingSessionNotFound
+	}
+	return *s.Session, nil
+}
+
+func (s *TradingDataAccessMockImpl) UpdateTradingSession(ctx context.Context, session model.TradingSession, previousDay int) (bool, error) {
+	if s.Conflicts || s.Session.Day != previousDay {
+		return false, nil
+	}
+	s.Session = &session
+	return true, nil
+}
+
+func newTradingService() (*TradingServiceImpl, *TradingDataAccessMockImpl) {
+	dataAccess := &TradingDataAccessMockImpl{}
+	return &TradingServiceImpl{
+		TradingDataAccess: dataAccess,
+		StockService: &StockServiceMockImpl{
+			Windows: []model.StockWindow{{Stocks: []model.StockPublic{
+				{Date: "2020-01-30T00:00:00Z", Close: 90, SymbolUUID: "uuid-aapl"},
+				{Date: "2020-01-31T00:00:00Z", Close: 100, SymbolUUID: "uuid-aapl"},
+			}}},
+			After: []model.Stock{
+				{Symbol: "AAPL", Date: "2020-02-03", Close: 110},
+				{Symbol: "AAPL", Date: "2020-02-04", Close: 120},
+			},
+		},
+	}, dataAccess
+}
+
+func TestTradingSession(t *testing.T) {
+	mockService, dataAccess := newTradingService()
+	started, err := mockService.StartSession("player-1", "")
+	if err != nil {
+		t.Fatalf("Expected no error and not %v", err)
+	}
+	if started.Session.Days != 2 || started.Session.AfterDate != "2020-01-31" || len(started.Stocks) != 2 || len(started.Revealed) != 0 {
+		t.Fatalf("Unexpected start %+v", started)
+	}
+	id := started.Session.Id
+
+	t.Run("Another player", func(t *testing.T) {
+		if _, err := mockService.Trade(id, "player-2", model.TradeRequest{Action: model.Trade_hold}); !errors.Is(err, dataaccess.ErrTradingSessionNotFound) {
+			t.Errorf("Expected ErrTradingSessionNotFound and not %v", err)
+		}
+	})
+	t.Run("Invalid trade", func(t *testing.T) {
+		if _, err := mockService.Trade(id, "player-1", model.TradeRequest{Action: model.Trade_sell, Quantity: 1}); !errors.Is(err, logic.ErrInvalidTrade) {
+			t.Errorf("Expected ErrInvalidTrade and not %v", err)
+		}
+		if dataAccess.Session.Day != 0 {
+			t.Errorf("Expected the invalid trade not to reveal a day")
+		}
+	})
+	t.Run("First day", func(t *testing.T) {
+		response, err := mockService.Trade(id, "player-1", model.TradeRequest{Action: model.Trade_buy})
+		if err != nil {
+			t.Fatalf("Expected no error and not %v", err)
+		}
+		if response.Session.Shares != 100 || response.Session.Decisions[0].Price != 100 {
+			t.Errorf("Expected 100 shares bought at the last close shown and not %+v", response.Session)
+		}
+		if len(response.Revealed) != 1 || response.Revealed[0].Close != 110 || response.Revealed[0].Symbol != "" {
+			t.Errorf("Expected the first day revealed without symbol and not %+v", response.Revealed)
+		}
+		if response.Finished || response.Result != nil || response.Symbol != "" {
+			t.Errorf("Expected the session to go on and not %+v", response)
+		}
+	})
+	t.Run("Conflict", func(t *testing.T) {
+		dataAccess.Conflicts = true
+		defer func() { dataAccess.Conflicts = false }()
+		if _, err := mockService.Trade(id, "player-1", model.TradeRequest{Action: model.Trade_hold}); !errors.Is(err, ErrTradeConflict) {
+			t.Errorf("Expected ErrTradeConflict and not %v", err)
+		}
+	})
+	t.Run("Last day", func(t *testing.T) {
+		response, err := mockService.Trade(id, "player-1", model.TradeRequest{Action: model.Trade_sell})
+		if err != nil {
+			t.Fatalf("Expected no error and not %v", err)
+		}
+		if !response.Finished || response.Symbol != "AAPL" || response.Result == nil {
+			t.Fatalf("Expected the result and not %+v", response)
+		}
+		if response.Result.Strategy.FinalValue != 11000 || response.Result.BuyAndHold.FinalValue != 12000 {
+			t.Errorf("Unexpected result %+v", response.Result)
+		}
+	})
+	t.Run("Finished", func(t *testing.T) {
+		if _, err := mockService.Trade(id, "player-1", model.TradeRequest{Action: model.Trade_hold}); !errors.Is(err, ErrTradingSessionFinished) {
+			t.Errorf("Expected ErrTradingSessionFinished and not %v", err)
+		}
+		response, err := mockService.GetSession(id, "player-1")
+		if err != nil || len(response.Revealed) != 2 || len(response.Stocks) != 2 || response.Result == nil {
+			t.Errorf("Expected the finished session and not %+v %v", response, err)
+		}
+	})
+}
+
+func TestStartTradingSessionNoWindow(t *testing.T) {
+	mockService, _ := newTradingService()
+	mockService.StockService = &StockServiceMockImpl{}
+	if _, err := mockService.StartSession("player-1", ""); !errors.Is(err, ErrNoTradingWindow) {
+		t.Errorf("Expected ErrNoTradingWindow and not %v", err)
+	}
+}
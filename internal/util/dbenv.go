@@ -3,7 +3,12 @@ package util
 import (
 	"fmt"
 	"log"
+	"maps"
 	"os"
+	"stockgame/internal/model"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -36,3 +41,97 @@ func GetWebEnv() (port string) {
 	}
 	return
 }
+
+// GetBollingerEnv returns the Bollinger configuration used to score the solutions.
+// Every variable is optional and falls back to model.DefaultBollingerConfig.
+func GetBollingerEnv() (config model.BollingerConfig) {
+	config = model.DefaultBollingerConfig
+	if period, err := strconv.Atoi(os.Getenv("BOLLINGER_PERIOD")); err == nil && period > 0 {
+		config.Period = period
+	}
+	if multiplier, err := strconv.ParseFloat(os.Getenv("BOLLINGER_MULTIPLIER"), 64); err == nil && multiplier > 0 {
+		config.Multiplier = multiplier
+	}
+	if includeCurrentDay, err := strconv.ParseBool(os.Getenv("BOLLINGER_INCLUDE_CURRENT_DAY")); err == nil {
+		config.IncludeCurrentDay = includeCurrentDay
+	}
+	return
+}
+
+// GetEligibilityEnv returns the rules selecting the stocks that can be served. Every
+// variable is optional and falls back to model.DefaultEligibilityConfig.
+func GetEligibilityEnv() (config model.EligibilityConfig) {
+	config = model.DefaultEligibilityConfig
+	if minPrice, err := strconv.ParseFloat(os.Getenv("ELIGIBILITY_MIN_PRICE"), 64); err == nil && minPrice >= 0 {
+		config.MinPrice = minPrice
+	}
+	if minVolume, err := strconv.Atoi(os.Getenv("ELIGIBILITY_MIN_VOLUME")); err == nil && minVolume >= 0 {
+		config.MinVolume = minVolume
+	}
+	if maxMissingDays, err := strconv.Atoi(os.Getenv("ELIGIBILITY_MAX_MISSING_DAYS")); err == nil && maxMissingDays >= 0 {
+		config.MaxMissingDays = maxMissingDays
+	}
+	for _, symbol := range strings.Split(os.Getenv("ELIGIBILITY_EXCLUDED_SYMBOLS"), ",") {
+		if symbol = strings.TrimSpace(symbol); symbol != "" {
+			config.ExcludedSymbols = append(config.ExcludedSymbols, symbol)
+		}
+	}
+	if startDate := os.Getenv("ELIGIBILITY_START_DATE"); isDate(startDate) {
+		config.StartDate = startDate
+	}
+	if endDate := os.Getenv("ELIGIBILITY_END_DATE"); isDate(endDate) {
+		config.EndDate = endDate
+	}
+	switch etf := strings.ToLower(os.Getenv("ELIGIBILITY_ETF")); etf {
+	case model.EtfAny, model.EtfExclude, model.EtfOnly:
+		config.Etf = etf
+	}
+	return
+}
+
+func isDate(value string) bool {
+	_, err := time.Parse(time.DateOnly, value)
+	return err == nil
+}
+
+// GetHintPenaltiesEnv returns the points removed from the score by each hint. Every
+// variable is optional and falls back to model.DefaultHintPenalties.
+func GetHintPenaltiesEnv() map[string]int {
+	penalties := maps.Clone(model.DefaultHintPenalties)
+	for hint := range penalties {
+		variable := "HINT_PENALTY_" + strings.ToUpper(hint)
+		if penalty, err := strconv.Atoi(os.Getenv(variable)); err == nil && penalty >= 0 {
+			penalties[hint] = penalty
+		}
+	}
+	return penalties
+}
+
+// GetRoundTimingEnv returns the time limit of each mode served by GET /stocks and the
+// speed bonus. The modes are not timed unless their variable is set.
+func GetRoundTimingEnv() (config model.RoundTimingConfig) {
+	config.Limits = map[string]int{}
+	for _, mode := range []string{model.Mode_price, model.Mode_candle, model.Mode_direction} {
+		variable := "ROUND_TIME_LIMIT_" + strings.ToUpper(mode)
+		if limit, err := strconv.Atoi(os.Getenv(variable)); err == nil && limit > 0 {
+			config.Limits[mode] = limit
+		}
+	}
+	if bonus, err := strconv.Atoi(os.Getenv("ROUND_SPEED_BONUS")); err == nil && bonus >= 0 {
+		config.SpeedBonus = bonus
+	}
+	return
+}
+
+// GetCardCacheEnv returns the directory where the result cards are cached
+func GetCardCacheEnv() string {
+	if dir := os.Getenv("CARD_CACHE_DIR"); dir != "" {
+		return dir
+	}
+	return "./cache/cards"
+}
+
+// GetAdminTokenEnv returns the token of the admin endpoints, empty when they are disabled
+func GetAdminTokenEnv() string {
+	return os.Getenv("ADMIN_TOKEN")
+}